@@ -9,8 +9,8 @@ import (
 
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
-	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
 	"github.com/kaspanet/kaspad/infrastructure/os/execenv"
 	"github.com/kaspanet/kaspad/infrastructure/os/limits"
 	"github.com/kaspanet/kaspad/infrastructure/os/signal"
@@ -20,8 +20,6 @@ import (
 	"github.com/kaspanet/kaspad/version"
 )
 
-const leveldbCacheSizeMiB = 256
-
 var desiredLimits = &limits.DesiredLimits{
 	FileLimitWant: 2048,
 	FileLimitMin:  1024,
@@ -85,6 +83,15 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 		profiling.Start(app.cfg.Profile, log)
 	}
 
+	// Enable the metrics server if requested.
+	if app.cfg.Metrics != "" {
+		err := metrics.Start(app.cfg.Metrics)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
 	// Return now if an interrupt signal was triggered.
 	if signal.InterruptRequested(interrupt) {
 		return nil
@@ -98,6 +105,14 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 		}
 	}
 
+	if app.cfg.CheckDatabaseMigrations {
+		err := checkDatabaseMigrations(app.cfg)
+		if err != nil {
+			log.Error(err)
+		}
+		return err
+	}
+
 	// Open the database
 	databaseContext, err := openDB(app.cfg)
 	if err != nil {
@@ -113,6 +128,9 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 		}
 	}()
 
+	stopDatabaseStatsReports := startDatabaseStatsReports(app.cfg, databaseContext)
+	defer stopDatabaseStatsReports()
+
 	// Return now if an interrupt signal was triggered.
 	if signal.InterruptRequested(interrupt) {
 		return nil
@@ -170,16 +188,52 @@ func removeDatabase(cfg *config.Config) error {
 func openDB(cfg *config.Config) (database.Database, error) {
 	dbPath := databasePath(cfg)
 
-	err := checkDatabaseVersion(dbPath)
+	databaseVersion, err := readDatabaseVersion(dbPath)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("Loading database from '%s'", dbPath)
-	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	log.Infof("Loading %s database from '%s'", cfg.DbType, dbPath)
+	db, err := openDatabaseBackend(cfg, dbPath)
 	if err != nil {
 		return nil, err
 	}
 
+	err = databaseMigrations.Migrate(db, databaseVersion, currentDatabaseVersion, func(version int) error {
+		return writeDatabaseVersion(dbPath, version)
+	})
+	if err != nil {
+		closeErr := db.Close()
+		if closeErr != nil {
+			log.Errorf("Failed to close the database: %s", closeErr)
+		}
+		return nil, err
+	}
+
 	return db, nil
 }
+
+// checkDatabaseMigrations logs the database migrations that openDB would run,
+// without running them
+func checkDatabaseMigrations(cfg *config.Config) error {
+	dbPath := databasePath(cfg)
+
+	versionFileName := versionFilePath(dbPath)
+	if _, err := os.Stat(versionFileName); os.IsNotExist(err) {
+		log.Infof("There's no database in '%s'. No migration is needed", dbPath)
+		return nil
+	}
+	databaseVersion, err := readDatabaseVersion(dbPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabaseBackendReadOnly(cfg, dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = databaseMigrations.Check(db, databaseVersion, currentDatabaseVersion)
+	return err
+}
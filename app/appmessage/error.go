@@ -6,6 +6,7 @@ package appmessage
 
 import (
 	"fmt"
+	"time"
 )
 
 // MessageError describes an issue with a message.
@@ -36,6 +37,42 @@ func messageError(f string, desc string) *MessageError {
 // RPCError represents an error arriving from the RPC
 type RPCError struct {
 	Message string
+
+	// Code classifies the error, for clients that handle some
+	// errors programmatically
+	Code RPCErrorCode
+
+	// RetryAfter is set for RPCErrorCodeRateLimited errors. It's
+	// the time after which the request may be sent again
+	RetryAfter time.Duration
+}
+
+// RPCErrorCode classifies an RPCError
+type RPCErrorCode byte
+
+// RPCErrorCode constants
+const (
+	// RPCErrorCodeUnspecified is the code of errors that are not classified
+	RPCErrorCodeUnspecified RPCErrorCode = 0
+
+	// RPCErrorCodePermissionDenied means that the client may not call the
+	// requested method, either because of its credentials or because of the
+	// RPC server's policy
+	RPCErrorCodePermissionDenied RPCErrorCode = 1
+
+	// RPCErrorCodeRateLimited means that the client called the requested
+	// method too often
+	RPCErrorCodeRateLimited RPCErrorCode = 2
+)
+
+var rpcErrorCodeToString = map[RPCErrorCode]string{
+	RPCErrorCodeUnspecified:      "Unspecified",
+	RPCErrorCodePermissionDenied: "Permission denied",
+	RPCErrorCodeRateLimited:      "Rate limited",
+}
+
+func (code RPCErrorCode) String() string {
+	return rpcErrorCodeToString[code]
 }
 
 // RPCErrorf formats according to a format specifier and returns the string
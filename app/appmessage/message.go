@@ -140,6 +140,22 @@ const (
 	CmdNotifyVirtualDaaScoreChangedRequestMessage
 	CmdNotifyVirtualDaaScoreChangedResponseMessage
 	CmdVirtualDaaScoreChangedNotificationMessage
+	CmdGetUTXOHistoryByAddressesRequestMessage
+	CmdGetUTXOHistoryByAddressesResponseMessage
+	CmdGetTransactionRequestMessage
+	CmdGetTransactionResponseMessage
+	CmdGetTransactionsByAddressesRequestMessage
+	CmdGetTransactionsByAddressesResponseMessage
+	CmdGetFeeEstimateRequestMessage
+	CmdGetFeeEstimateResponseMessage
+	CmdGetLogLevelsRequestMessage
+	CmdGetLogLevelsResponseMessage
+	CmdSetLogLevelRequestMessage
+	CmdSetLogLevelResponseMessage
+	CmdGetBannedPeersRequestMessage
+	CmdGetBannedPeersResponseMessage
+	CmdBackupDatabaseRequestMessage
+	CmdBackupDatabaseResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -254,6 +270,22 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdNotifyVirtualDaaScoreChangedRequestMessage:                 "NotifyVirtualDaaScoreChangedRequest",
 	CmdNotifyVirtualDaaScoreChangedResponseMessage:                "NotifyVirtualDaaScoreChangedResponse",
 	CmdVirtualDaaScoreChangedNotificationMessage:                  "VirtualDaaScoreChangedNotification",
+	CmdGetUTXOHistoryByAddressesRequestMessage:                    "GetUTXOHistoryByAddressesRequest",
+	CmdGetUTXOHistoryByAddressesResponseMessage:                   "GetUTXOHistoryByAddressesResponse",
+	CmdGetTransactionRequestMessage:                               "GetTransactionRequest",
+	CmdGetTransactionResponseMessage:                              "GetTransactionResponse",
+	CmdGetTransactionsByAddressesRequestMessage:                   "GetTransactionsByAddressesRequest",
+	CmdGetTransactionsByAddressesResponseMessage:                  "GetTransactionsByAddressesResponse",
+	CmdGetFeeEstimateRequestMessage:                               "GetFeeEstimateRequest",
+	CmdGetFeeEstimateResponseMessage:                              "GetFeeEstimateResponse",
+	CmdGetLogLevelsRequestMessage:                                 "GetLogLevelsRequest",
+	CmdGetLogLevelsResponseMessage:                                "GetLogLevelsResponse",
+	CmdSetLogLevelRequestMessage:                                  "SetLogLevelRequest",
+	CmdSetLogLevelResponseMessage:                                 "SetLogLevelResponse",
+	CmdGetBannedPeersRequestMessage:                               "GetBannedPeersRequest",
+	CmdGetBannedPeersResponseMessage:                              "GetBannedPeersResponse",
+	CmdBackupDatabaseRequestMessage:                               "BackupDatabaseRequest",
+	CmdBackupDatabaseResponseMessage:                              "BackupDatabaseResponse",
 }
 
 // Message is an interface that describes a kaspa message. A type that
@@ -0,0 +1,45 @@
+package appmessage
+
+// BackupDatabaseRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type BackupDatabaseRequestMessage struct {
+	baseMessage
+	TargetDirectory string
+}
+
+// Command returns the protocol command string for the message
+func (msg *BackupDatabaseRequestMessage) Command() MessageCommand {
+	return CmdBackupDatabaseRequestMessage
+}
+
+// NewBackupDatabaseRequestMessage returns an instance of the message
+func NewBackupDatabaseRequestMessage(targetDirectory string) *BackupDatabaseRequestMessage {
+	return &BackupDatabaseRequestMessage{
+		TargetDirectory: targetDirectory,
+	}
+}
+
+// BackupDatabaseResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type BackupDatabaseResponseMessage struct {
+	baseMessage
+	DatabasePath string
+	RecordCount  uint64
+	Size         uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *BackupDatabaseResponseMessage) Command() MessageCommand {
+	return CmdBackupDatabaseResponseMessage
+}
+
+// NewBackupDatabaseResponseMessage returns an instance of the message
+func NewBackupDatabaseResponseMessage(databasePath string, recordCount uint64, size uint64) *BackupDatabaseResponseMessage {
+	return &BackupDatabaseResponseMessage{
+		DatabasePath: databasePath,
+		RecordCount:  recordCount,
+		Size:         size,
+	}
+}
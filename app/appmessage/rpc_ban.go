@@ -5,7 +5,8 @@ package appmessage
 type BanRequestMessage struct {
 	baseMessage
 
-	IP string
+	IP              string
+	DurationSeconds uint64
 }
 
 // Command returns the protocol command string for the message
@@ -14,9 +15,10 @@ func (msg *BanRequestMessage) Command() MessageCommand {
 }
 
 // NewBanRequestMessage returns an instance of the message
-func NewBanRequestMessage(ip string) *BanRequestMessage {
+func NewBanRequestMessage(ip string, durationSeconds uint64) *BanRequestMessage {
 	return &BanRequestMessage{
-		IP: ip,
+		IP:              ip,
+		DurationSeconds: durationSeconds,
 	}
 }
 
@@ -0,0 +1,48 @@
+package appmessage
+
+// GetBannedPeersRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBannedPeersRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBannedPeersRequestMessage) Command() MessageCommand {
+	return CmdGetBannedPeersRequestMessage
+}
+
+// NewGetBannedPeersRequestMessage returns a instance of the message
+func NewGetBannedPeersRequestMessage() *GetBannedPeersRequestMessage {
+	return &GetBannedPeersRequestMessage{}
+}
+
+// GetBannedPeersResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBannedPeersResponseMessage struct {
+	baseMessage
+	BannedPeers []*RPCBannedPeer
+
+	Error *RPCError
+}
+
+// RPCBannedPeer describes a single ban. IP is either a single IP
+// or a subnet in CIDR notation. CreatedAt and ExpiresAt are in
+// milliseconds since the epoch
+type RPCBannedPeer struct {
+	IP        string
+	Reason    string
+	CreatedAt int64
+	ExpiresAt int64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBannedPeersResponseMessage) Command() MessageCommand {
+	return CmdGetBannedPeersResponseMessage
+}
+
+// NewGetBannedPeersResponseMessage returns a instance of the message
+func NewGetBannedPeersResponseMessage(bannedPeers []*RPCBannedPeer) *GetBannedPeersResponseMessage {
+	return &GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+	}
+}
@@ -47,4 +47,7 @@ type GetConnectedPeerInfoMessage struct {
 	AdvertisedProtocolVersion uint32
 	TimeConnected             int64
 	IsIBDPeer                 bool
+	BanScore                  uint32
+	NetworkGroup              string
+	IsProtectedFromEviction   bool
 }
@@ -23,18 +23,46 @@ type GetInfoResponseMessage struct {
 	P2PID       string
 	MempoolSize uint64
 
+	// DatabaseStats is nil unless the node uses the leveldb backend
+	DatabaseStats *RPCDatabaseStats
+
 	Error *RPCError
 }
 
+// RPCDatabaseStats are the statistics of the leveldb database of the node
+// since it was started. Durations are in milliseconds
+type RPCDatabaseStats struct {
+	Levels                   []*RPCDatabaseLevelStats
+	MemoryCompactionCount    uint32
+	Level0CompactionCount    uint32
+	NonLevel0CompactionCount uint32
+	SeekCompactionCount      uint32
+	WriteDelayCount          uint32
+	WriteDelayDuration       int64
+}
+
+// RPCDatabaseLevelStats are the statistics of a single level of the
+// leveldb database of the node. ReadBytes, WriteBytes and CompactionDuration
+// are the totals of the compactions into the level
+type RPCDatabaseLevelStats struct {
+	Level              uint32
+	TableCount         uint32
+	Size               int64
+	ReadBytes          int64
+	WriteBytes         int64
+	CompactionDuration int64
+}
+
 // Command returns the protocol command string for the message
 func (msg *GetInfoResponseMessage) Command() MessageCommand {
 	return CmdGetInfoResponseMessage
 }
 
 // NewGetInfoResponseMessage returns a instance of the message
-func NewGetInfoResponseMessage(p2pID string, mempoolSize uint64) *GetInfoResponseMessage {
+func NewGetInfoResponseMessage(p2pID string, mempoolSize uint64, databaseStats *RPCDatabaseStats) *GetInfoResponseMessage {
 	return &GetInfoResponseMessage{
-		P2PID:       p2pID,
-		MempoolSize: mempoolSize,
+		P2PID:         p2pID,
+		MempoolSize:   mempoolSize,
+		DatabaseStats: databaseStats,
 	}
 }
@@ -0,0 +1,44 @@
+package appmessage
+
+// GetLogLevelsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetLogLevelsRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetLogLevelsRequestMessage) Command() MessageCommand {
+	return CmdGetLogLevelsRequestMessage
+}
+
+// NewGetLogLevelsRequestMessage returns a instance of the message
+func NewGetLogLevelsRequestMessage() *GetLogLevelsRequestMessage {
+	return &GetLogLevelsRequestMessage{}
+}
+
+// GetLogLevelsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetLogLevelsResponseMessage struct {
+	baseMessage
+	LogLevels []*RPCSubsystemLogLevel
+
+	Error *RPCError
+}
+
+// RPCSubsystemLogLevel holds the logging level of a single logging subsystem
+type RPCSubsystemLogLevel struct {
+	Subsystem string
+	Level     string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetLogLevelsResponseMessage) Command() MessageCommand {
+	return CmdGetLogLevelsResponseMessage
+}
+
+// NewGetLogLevelsResponseMessage returns a instance of the message
+func NewGetLogLevelsResponseMessage(logLevels []*RPCSubsystemLogLevel) *GetLogLevelsResponseMessage {
+	return &GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+	}
+}
@@ -0,0 +1,66 @@
+package appmessage
+
+// GetTransactionsByAddressesRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionsByAddressesRequestMessage struct {
+	baseMessage
+	Addresses  []string
+	StartAfter *TransactionsByAddressesCursor
+	Limit      uint32
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionsByAddressesRequestMessage) Command() MessageCommand {
+	return CmdGetTransactionsByAddressesRequestMessage
+}
+
+// NewGetTransactionsByAddressesRequestMessage returns a instance of the message
+func NewGetTransactionsByAddressesRequestMessage(addresses []string, startAfter *TransactionsByAddressesCursor,
+	limit uint32) *GetTransactionsByAddressesRequestMessage {
+
+	return &GetTransactionsByAddressesRequestMessage{
+		Addresses:  addresses,
+		StartAfter: startAfter,
+		Limit:      limit,
+	}
+}
+
+// TransactionsByAddressesCursor identifies the position of an entry in the
+// results of GetTransactionsByAddresses
+type TransactionsByAddressesCursor struct {
+	AcceptingBlockBlueScore uint64
+	TransactionID           string
+}
+
+// GetTransactionsByAddressesResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionsByAddressesResponseMessage struct {
+	baseMessage
+	Entries []*TransactionsByAddressesEntry
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionsByAddressesResponseMessage) Command() MessageCommand {
+	return CmdGetTransactionsByAddressesResponseMessage
+}
+
+// NewGetTransactionsByAddressesResponseMessage returns a instance of the message
+func NewGetTransactionsByAddressesResponseMessage(entries []*TransactionsByAddressesEntry) *GetTransactionsByAddressesResponseMessage {
+	return &GetTransactionsByAddressesResponseMessage{
+		Entries: entries,
+	}
+}
+
+// TransactionsByAddressesEntry represents a transaction accepted by the
+// selected parent chain that paid or spent from some address
+type TransactionsByAddressesEntry struct {
+	Address                 string
+	TransactionID           string
+	IncludingBlockHash      string
+	AcceptingBlockHash      string
+	AcceptingBlockBlueScore uint64
+	Received                uint64
+	Sent                    uint64
+}
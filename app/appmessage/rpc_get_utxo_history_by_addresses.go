@@ -0,0 +1,57 @@
+package appmessage
+
+// GetUTXOHistoryByAddressesRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetUTXOHistoryByAddressesRequestMessage struct {
+	baseMessage
+	Addresses []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetUTXOHistoryByAddressesRequestMessage) Command() MessageCommand {
+	return CmdGetUTXOHistoryByAddressesRequestMessage
+}
+
+// NewGetUTXOHistoryByAddressesRequestMessage returns a instance of the message
+func NewGetUTXOHistoryByAddressesRequestMessage(addresses []string) *GetUTXOHistoryByAddressesRequestMessage {
+	return &GetUTXOHistoryByAddressesRequestMessage{
+		Addresses: addresses,
+	}
+}
+
+// GetUTXOHistoryByAddressesResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetUTXOHistoryByAddressesResponseMessage struct {
+	baseMessage
+	Entries []*UTXOHistoryByAddressesEntry
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetUTXOHistoryByAddressesResponseMessage) Command() MessageCommand {
+	return CmdGetUTXOHistoryByAddressesResponseMessage
+}
+
+// NewGetUTXOHistoryByAddressesResponseMessage returns a instance of the message
+func NewGetUTXOHistoryByAddressesResponseMessage(entries []*UTXOHistoryByAddressesEntry) *GetUTXOHistoryByAddressesResponseMessage {
+	return &GetUTXOHistoryByAddressesResponseMessage{
+		Entries: entries,
+	}
+}
+
+// UTXOHistoryByAddressesEntry represents an output that paid some address, along with
+// the chain blocks that accepted its creation and its spending
+type UTXOHistoryByAddressesEntry struct {
+	Address   string
+	Outpoint  *RPCOutpoint
+	UTXOEntry *RPCUTXOEntry
+
+	AcceptingBlockHash      string
+	AcceptingBlockBlueScore uint64
+
+	SpendingTransactionID  string
+	SpendingBlockHash      string
+	SpendingBlockBlueScore uint64
+	SpendingTransactionFee uint64
+}
@@ -0,0 +1,43 @@
+package appmessage
+
+// SetLogLevelRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type SetLogLevelRequestMessage struct {
+	baseMessage
+
+	// Subsystem is the logging subsystem whose level to set. An empty
+	// subsystem sets the level of all subsystems
+	Subsystem string
+	Level     string
+}
+
+// Command returns the protocol command string for the message
+func (msg *SetLogLevelRequestMessage) Command() MessageCommand {
+	return CmdSetLogLevelRequestMessage
+}
+
+// NewSetLogLevelRequestMessage returns an instance of the message
+func NewSetLogLevelRequestMessage(subsystem string, level string) *SetLogLevelRequestMessage {
+	return &SetLogLevelRequestMessage{
+		Subsystem: subsystem,
+		Level:     level,
+	}
+}
+
+// SetLogLevelResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type SetLogLevelResponseMessage struct {
+	baseMessage
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *SetLogLevelResponseMessage) Command() MessageCommand {
+	return CmdSetLogLevelResponseMessage
+}
+
+// NewSetLogLevelResponseMessage returns a instance of the message
+func NewSetLogLevelResponseMessage() *SetLogLevelResponseMessage {
+	return &SetLogLevelResponseMessage{}
+}
@@ -0,0 +1,16 @@
+package app
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/db/migration"
+)
+
+// databaseMigrations holds the steps that migrate the database between
+// versions. Whenever currentDatabaseVersion is bumped, a step that migrates
+// the database from the previous version must be registered here, e.g.:
+//
+//	databaseMigrations.Register(&migration.Step{
+//		Version:     2,
+//		Description: "...",
+//		Migrate:     migrateDatabaseToVersion2,
+//	})
+var databaseMigrations = migration.NewRegistry()
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+)
+
+// startDatabaseStatsReports periodically logs the statistics of db, if it's a
+// leveldb instance and reports are enabled. The returned function stops the
+// reports, and must be called before db is closed
+func startDatabaseStatsReports(cfg *config.Config, db database.Database) (stop func()) {
+	levelDB, ok := db.(*ldb.LevelDB)
+	if !ok || cfg.LevelDBStatsInterval == 0 {
+		return func() {}
+	}
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	spawn("startDatabaseStatsReports", func() {
+		defer close(done)
+
+		ticker := time.NewTicker(cfg.LevelDBStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				stats, err := levelDB.Stats()
+				if err != nil {
+					log.Warnf("Failed to get the database stats: %s", err)
+					continue
+				}
+				log.Infof("Database stats:\n%s", stats)
+			case <-quit:
+				return
+			}
+		}
+	})
+
+	return func() {
+		close(quit)
+		<-done
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/logdb"
+	"github.com/pkg/errors"
+)
+
+const (
+	dbTypeLevelDB = "leveldb"
+	dbTypeLogDB   = "logdb"
+)
+
+// existingDatabaseType returns the type of the database in dbPath, or false
+// if there's no database there yet
+func existingDatabaseType(dbPath string) (string, bool) {
+	switch {
+	case ldb.IsLevelDB(dbPath):
+		return dbTypeLevelDB, true
+	case logdb.IsLogDB(dbPath):
+		return dbTypeLogDB, true
+	default:
+		return "", false
+	}
+}
+
+// openDatabaseBackend opens the database in dbPath with the backend that
+// cfg selects. It fails if the database was created by a different backend
+func openDatabaseBackend(cfg *config.Config, dbPath string) (database.Database, error) {
+	dbType := cfg.DbType
+	err := checkDatabaseType(dbPath, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dbType {
+	case dbTypeLevelDB:
+		return ldb.NewLevelDBWithConfig(dbPath, &ldb.Config{
+			CacheSizeMiB:       cfg.LevelDBCacheSizeMiB,
+			WriteBufferSizeMiB: cfg.LevelDBWriteBufferSizeMiB,
+			Compression:        cfg.LevelDBCompression,
+			SyncWrites:         cfg.LevelDBSyncWrites,
+		})
+	case dbTypeLogDB:
+		return logdb.NewLogDB(dbPath)
+	default:
+		return nil, errors.Errorf("unknown database type %s", dbType)
+	}
+}
+
+// openDatabaseBackendReadOnly opens the existing database in dbPath for
+// reading only, with the backend that cfg selects. It fails if the database
+// was created by a different backend
+func openDatabaseBackendReadOnly(cfg *config.Config, dbPath string) (database.Database, error) {
+	dbType := cfg.DbType
+	err := checkDatabaseType(dbPath, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dbType {
+	case dbTypeLevelDB:
+		return ldb.NewLevelDBReadOnly(dbPath, cfg.LevelDBCacheSizeMiB)
+	case dbTypeLogDB:
+		return logdb.NewLogDBReadOnly(dbPath)
+	default:
+		return nil, errors.Errorf("unknown database type %s", dbType)
+	}
+}
+
+// checkDatabaseType returns an error if the database in dbPath was created
+// by a backend other than dbType
+func checkDatabaseType(dbPath string, dbType string) error {
+	existingType, ok := existingDatabaseType(dbPath)
+	if ok && existingType != dbType {
+		return errors.Errorf("the database in '%s' was created with --dbtype=%s, and can't be "+
+			"opened with --dbtype=%s. Either run with --dbtype=%s, or with --reset-db to resync the "+
+			"node from scratch", dbPath, existingType, dbType, existingType)
+	}
+	return nil
+}
@@ -4,51 +4,63 @@ import (
 	"os"
 	"path"
 	"strconv"
-
-	"github.com/pkg/errors"
 )
 
 const currentDatabaseVersion = 1
 
-func checkDatabaseVersion(dbPath string) (err error) {
+// readDatabaseVersion returns the version of the database in dbPath. If the
+// version file doesn't exist, the database is assumed to be new, and a version
+// file with the current database version is created
+func readDatabaseVersion(dbPath string) (int, error) {
 	versionFileName := versionFilePath(dbPath)
 
 	versionBytes, err := os.ReadFile(versionFileName)
 	if err != nil {
 		if os.IsNotExist(err) { // If version file doesn't exist, we assume that the database is new
-			return createDatabaseVersionFile(dbPath, versionFileName)
+			err := os.MkdirAll(dbPath, 0700)
+			if err != nil {
+				return 0, err
+			}
+			err = writeDatabaseVersion(dbPath, currentDatabaseVersion)
+			if err != nil {
+				return 0, err
+			}
+			return currentDatabaseVersion, nil
 		}
-		return err
+		return 0, err
 	}
 
-	databaseVersion, err := strconv.Atoi(string(versionBytes))
+	return strconv.Atoi(string(versionBytes))
+}
+
+// writeDatabaseVersion sets the version of the database in dbPath. The new
+// version is written to a temporary file that then replaces the version
+// file, so that a crash never leaves a partially written version file
+func writeDatabaseVersion(dbPath string, version int) error {
+	versionFileName := versionFilePath(dbPath)
+	tempVersionFileName := versionFileName + ".tmp"
+
+	versionFile, err := os.Create(tempVersionFileName)
 	if err != nil {
 		return err
 	}
-
-	if databaseVersion != currentDatabaseVersion {
-		// TODO: Once there's more then one database version, it might make sense to add upgrade logic at this point
-		return errors.Errorf("Invalid database version %d. Expected version: %d", databaseVersion, currentDatabaseVersion)
+	versionString := strconv.Itoa(version)
+	_, err = versionFile.Write([]byte(versionString))
+	if err != nil {
+		versionFile.Close()
+		return err
 	}
-
-	return nil
-}
-
-func createDatabaseVersionFile(dbPath string, versionFileName string) error {
-	err := os.MkdirAll(dbPath, 0700)
+	err = versionFile.Sync()
 	if err != nil {
+		versionFile.Close()
 		return err
 	}
-
-	versionFile, err := os.Create(versionFileName)
+	err = versionFile.Close()
 	if err != nil {
-		return nil
+		return err
 	}
-	defer versionFile.Close()
 
-	versionString := strconv.Itoa(currentDatabaseVersion)
-	_, err = versionFile.Write([]byte(versionString))
-	return err
+	return os.Rename(tempVersionFileName, versionFileName)
 }
 
 func versionFilePath(dbPath string) string {
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/kaspanet/kaspad/infrastructure/network/connmanager"
+)
+
+var (
+	mempoolTransactionsMetric = metrics.NewGaugeFunc("kaspad_mempool_transactions",
+		"Number of transactions in the mempool, excluding orphans")
+	mempoolOrphansMetric = metrics.NewGaugeFunc("kaspad_mempool_orphans",
+		"Number of orphan transactions in the mempool")
+	inboundPeersMetric = metrics.NewGaugeFunc("kaspad_inbound_peers",
+		"Number of connected inbound peers")
+	outboundPeersMetric = metrics.NewGaugeFunc("kaspad_outbound_peers",
+		"Number of connected outbound peers")
+)
+
+// setupMetrics binds the metrics that are computed on collection to
+// the components they are computed from
+func setupMetrics(domain domain.Domain, connectionManager *connmanager.ConnectionManager) {
+	mempoolTransactionsMetric.SetFunction(func() float64 {
+		return float64(domain.MiningManager().TransactionCount())
+	})
+	mempoolOrphansMetric.SetFunction(func() float64 {
+		return float64(domain.MiningManager().OrphanCount())
+	})
+	inboundPeersMetric.SetFunction(func() float64 {
+		inboundCount, _ := connectionManager.InboundAndOutboundConnectionCounts()
+		return float64(inboundCount)
+	})
+	outboundPeersMetric.SetFunction(func() float64 {
+		_, outboundCount := connectionManager.InboundAndOutboundConnectionCounts()
+		return float64(outboundCount)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	peerpkg "github.com/kaspanet/kaspad/app/protocol/peer"
 	"github.com/kaspanet/kaspad/app/protocol/protocolerrors"
 	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/pkg/errors"
 
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
@@ -117,7 +118,7 @@ func (f *FlowContext) AddBlock(block *externalapi.DomainBlock) error {
 	blockInsertionResult, err := f.Domain().Consensus().ValidateAndInsertBlock(block)
 	if err != nil {
 		if errors.As(err, &ruleerrors.RuleError{}) {
-			log.Warnf("Validation failed for block %s: %s", consensushashing.BlockHash(block), err)
+			log.Warnf("Validation failed for block %s: %s", logger.Attr("blockHash", consensushashing.BlockHash(block)), err)
 		}
 		return err
 	}
@@ -146,6 +147,7 @@ func (f *FlowContext) TrySetIBDRunning(ibdPeer *peerpkg.Peer) bool {
 		return false
 	}
 	f.ibdPeer = ibdPeer
+	ibdRunningMetric.Set(1)
 	log.Infof("IBD started")
 
 	return true
@@ -161,6 +163,7 @@ func (f *FlowContext) UnsetIBDRunning() {
 	}
 
 	f.ibdPeer = nil
+	ibdRunningMetric.Set(0)
 	log.Infof("IBD finished")
 }
 
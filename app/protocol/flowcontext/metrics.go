@@ -0,0 +1,6 @@
+package flowcontext
+
+import "github.com/kaspanet/kaspad/infrastructure/metrics"
+
+var ibdRunningMetric = metrics.NewGauge("kaspad_ibd_running",
+	"Whether the node is currently in IBD (1) or not (0)")
@@ -34,7 +34,7 @@ func (f *FlowContext) AddOrphan(orphanBlock *externalapi.DomainBlock) {
 		f.evictRandomOrphan()
 	}
 
-	log.Infof("Received a block with missing parents, adding to orphan pool: %s", orphanHash)
+	log.Infof("Received a block with missing parents, adding to orphan pool: %s", logger.Attr("blockHash", orphanHash))
 }
 
 func (f *FlowContext) evictRandomOrphan() {
@@ -153,13 +153,13 @@ func (f *FlowContext) unorphanBlock(orphanHash externalapi.DomainHash) (*externa
 	blockInsertionResult, err := f.domain.Consensus().ValidateAndInsertBlock(orphanBlock)
 	if err != nil {
 		if errors.As(err, &ruleerrors.RuleError{}) {
-			log.Warnf("Validation failed for orphan block %s: %s", orphanHash, err)
+			log.Warnf("Validation failed for orphan block %s: %s", logger.Attr("blockHash", orphanHash), err)
 			return nil, false, nil
 		}
 		return nil, false, err
 	}
 
-	log.Infof("Unorphaned block %s", orphanHash)
+	log.Infof("Unorphaned block %s", logger.Attr("blockHash", orphanHash))
 	return blockInsertionResult, true, nil
 }
 
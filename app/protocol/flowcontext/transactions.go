@@ -19,12 +19,33 @@ func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction) error {
 		return err
 	}
 
+	f.removeReplacedTransactionsToRebroadcast(tx)
+
 	transactionID := consensushashing.TransactionID(tx)
 	f.transactionsToRebroadcast[*transactionID] = tx
 	inv := appmessage.NewMsgInvTransaction([]*externalapi.DomainTransactionID{transactionID})
 	return f.Broadcast(inv)
 }
 
+// removeReplacedTransactionsToRebroadcast stops rebroadcasting the transactions that
+// the given transaction replaced in the mempool, since they were evicted from it.
+// It must be called while holding transactionsToRebroadcastLock.
+func (f *FlowContext) removeReplacedTransactionsToRebroadcast(tx *externalapi.DomainTransaction) {
+	spentOutpoints := make(map[externalapi.DomainOutpoint]struct{}, len(tx.Inputs))
+	for _, input := range tx.Inputs {
+		spentOutpoints[input.PreviousOutpoint] = struct{}{}
+	}
+
+	for transactionID, transactionToRebroadcast := range f.transactionsToRebroadcast {
+		for _, input := range transactionToRebroadcast.Inputs {
+			if _, ok := spentOutpoints[input.PreviousOutpoint]; ok {
+				delete(f.transactionsToRebroadcast, transactionID)
+				break
+			}
+		}
+	}
+}
+
 func (f *FlowContext) updateTransactionsToRebroadcast(addedBlocks []*externalapi.DomainBlock) {
 	f.transactionsToRebroadcastLock.Lock()
 	defer f.transactionsToRebroadcastLock.Unlock()
@@ -10,6 +10,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
 )
@@ -73,7 +74,7 @@ func (flow *handleRelayInvsFlow) start() error {
 		}
 		if blockInfo.Exists && blockInfo.BlockStatus != externalapi.StatusHeaderOnly {
 			if blockInfo.BlockStatus == externalapi.StatusInvalid {
-				return protocolerrors.Errorf(true, "sent inv of an invalid block %s",
+				return protocolerrors.Offensef(protocolerrors.OffenseInvalidBlock, "sent inv of an invalid block %s",
 					inv.Hash)
 			}
 			log.Debugf("Block %s already exists. continuing...", inv.Hash)
@@ -138,7 +139,8 @@ func (flow *handleRelayInvsFlow) start() error {
 		if err != nil {
 			return err
 		}
-		log.Infof("Accepted block %s via relay", inv.Hash)
+		log.Infof("Accepted block %s via relay", logger.Attr("blockHash", inv.Hash))
+		flow.peer.MarkBlockRelayed()
 		err = flow.OnNewBlock(block, blockInsertionResult)
 		if err != nil {
 			return err
@@ -169,7 +171,7 @@ func (flow *handleRelayInvsFlow) readInv() (*appmessage.MsgInvRelayBlock, error)
 
 	inv, ok := msg.(*appmessage.MsgInvRelayBlock)
 	if !ok {
-		return nil, protocolerrors.Errorf(true, "unexpected %s message in the block relay handleRelayInvsFlow while "+
+		return nil, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "unexpected %s message in the block relay handleRelayInvsFlow while "+
 			"expecting an inv message", msg.Command())
 	}
 	return inv, nil
@@ -199,7 +201,7 @@ func (flow *handleRelayInvsFlow) requestBlock(requestHash *externalapi.DomainHas
 	block := appmessage.MsgBlockToDomainBlock(msgBlock)
 	blockHash := consensushashing.BlockHash(block)
 	if !blockHash.Equal(requestHash) {
-		return nil, false, protocolerrors.Errorf(true, "got unrequested block %s", blockHash)
+		return nil, false, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "got unrequested block %s", blockHash)
 	}
 
 	return block, false, nil
@@ -238,8 +240,8 @@ func (flow *handleRelayInvsFlow) processBlock(block *externalapi.DomainBlock) ([
 		if errors.As(err, missingParentsError) {
 			return missingParentsError.MissingParentHashes, nil, nil
 		}
-		log.Warnf("Rejected block %s from %s: %s", blockHash, flow.peer, err)
-		return nil, nil, protocolerrors.Wrapf(true, err, "got invalid block %s from relay", blockHash)
+		log.Warnf("Rejected block %s from %s: %s", logger.Attr("blockHash", blockHash), logger.Attr("peer", flow.peer), err)
+		return nil, nil, protocolerrors.WrapOffensef(protocolerrors.OffenseInvalidBlock, err, "got invalid block %s from relay", blockHash)
 	}
 	return nil, blockInsertionResult, nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
 )
 
@@ -203,7 +204,7 @@ func (flow *handleRelayInvsFlow) fetchHighestHash(
 			"This should only happen if there's a DAG split deeper than the pruning point.", flow.peer)
 		return nil, false, nil
 	default:
-		return nil, false, protocolerrors.Errorf(true, "received unexpected message type. "+
+		return nil, false, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 			"expected: %s, got: %s", appmessage.CmdIBDBlockLocatorHighestHash, message.Command())
 	}
 }
@@ -280,7 +281,7 @@ func (flow *handleRelayInvsFlow) receiveHeaders() (msgIBDBlock *appmessage.Block
 		return nil, true, nil
 	default:
 		return nil, false,
-			protocolerrors.Errorf(true, "received unexpected message type. "+
+			protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 				"expected: %s or %s, got: %s", appmessage.CmdHeader, appmessage.CmdDoneHeaders, message.Command())
 	}
 }
@@ -310,10 +311,13 @@ func (flow *handleRelayInvsFlow) processHeader(msgBlockHeader *appmessage.MsgBlo
 		if errors.Is(err, ruleerrors.ErrDuplicateBlock) {
 			log.Debugf("Skipping block header %s as it is a duplicate", blockHash)
 		} else {
-			log.Infof("Rejected block header %s from %s during IBD: %s", blockHash, flow.peer, err)
-			return protocolerrors.Wrapf(true, err, "got invalid block header %s during IBD", blockHash)
+			log.Infof("Rejected block header %s from %s during IBD: %s", logger.Attr("blockHash", blockHash),
+				logger.Attr("peer", flow.peer), err)
+			return protocolerrors.WrapOffensef(protocolerrors.OffenseInvalidBlock, err, "got invalid block header %s during IBD", blockHash)
 		}
+		return nil
 	}
+	ibdHeadersProcessedMetric.Inc()
 
 	return nil
 }
@@ -330,7 +334,7 @@ func (flow *handleRelayInvsFlow) syncPruningPointUTXOSet() (bool, error) {
 	}
 	msgPruningPointHash, ok := message.(*appmessage.MsgPruningPointHashMessage)
 	if !ok {
-		return false, protocolerrors.Errorf(true, "received unexpected message type. "+
+		return false, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 			"expected: %s, got: %s", appmessage.CmdPruningPointHash, message.Command())
 	}
 
@@ -429,7 +433,7 @@ func (flow *handleRelayInvsFlow) receivePruningPointBlock() (*externalapi.Domain
 
 	ibdBlockMessage, ok := message.(*appmessage.MsgIBDBlock)
 	if !ok {
-		return nil, protocolerrors.Errorf(true, "received unexpected message type. "+
+		return nil, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 			"expected: %s, got: %s", appmessage.CmdIBDBlock, message.Command())
 	}
 	block := appmessage.MsgBlockToDomainBlock(ibdBlockMessage.MsgBlock)
@@ -456,6 +460,7 @@ func (flow *handleRelayInvsFlow) receiveAndInsertPruningPointUTXOSet(
 		switch message := message.(type) {
 		case *appmessage.MsgPruningPointUTXOSetChunk:
 			receivedUTXOCount += len(message.OutpointAndUTXOEntryPairs)
+			ibdPruningPointUTXOsReceivedMetric.Add(uint64(len(message.OutpointAndUTXOEntryPairs)))
 			domainOutpointAndUTXOEntryPairs :=
 				appmessage.OutpointAndUTXOEntryPairsToDomainOutpointAndUTXOEntryPairs(message.OutpointAndUTXOEntryPairs)
 
@@ -486,7 +491,7 @@ func (flow *handleRelayInvsFlow) receiveAndInsertPruningPointUTXOSet(
 			return false, nil
 
 		default:
-			return false, protocolerrors.Errorf(true, "received unexpected message type. "+
+			return false, protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 				"expected: %s or %s or %s, got: %s", appmessage.CmdPruningPointUTXOSetChunk,
 				appmessage.CmdDonePruningPointUTXOSetChunks, appmessage.CmdUnexpectedPruningPoint, message.Command(),
 			)
@@ -507,6 +512,9 @@ func (flow *handleRelayInvsFlow) syncMissingBlockBodies(highHash *externalapi.Do
 		return nil
 	}
 
+	ibdMissingBlockBodiesMetric.Set(int64(len(hashes)))
+	defer ibdMissingBlockBodiesMetric.Set(0)
+
 	for offset := 0; offset < len(hashes); offset += ibdBatchSize {
 		var hashesToRequest []*externalapi.DomainHash
 		if offset+ibdBatchSize < len(hashes) {
@@ -528,15 +536,16 @@ func (flow *handleRelayInvsFlow) syncMissingBlockBodies(highHash *externalapi.Do
 
 			msgIBDBlock, ok := message.(*appmessage.MsgIBDBlock)
 			if !ok {
-				return protocolerrors.Errorf(true, "received unexpected message type. "+
+				return protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "received unexpected message type. "+
 					"expected: %s, got: %s", appmessage.CmdIBDBlock, message.Command())
 			}
 
 			block := appmessage.MsgBlockToDomainBlock(msgIBDBlock.MsgBlock)
 			blockHash := consensushashing.BlockHash(block)
 			if !expectedHash.Equal(blockHash) {
-				return protocolerrors.Errorf(true, "expected block %s but got %s", expectedHash, blockHash)
+				return protocolerrors.Offensef(protocolerrors.OffenseUnrequestedMessage, "expected block %s but got %s", expectedHash, blockHash)
 			}
+			ibdMissingBlockBodiesMetric.Add(-1)
 
 			err = flow.banIfBlockIsHeaderOnly(block)
 			if err != nil {
@@ -563,11 +572,16 @@ func (flow *handleRelayInvsFlow) syncMissingBlockBodies(highHash *externalapi.Do
 
 // dequeueIncomingMessageAndSkipInvs is a convenience method to be used during
 // IBD. Inv messages are expected to arrive at any given moment, but should be
-// ignored while we're in IBD
+// ignored while we're in IBD. A peer that doesn't respond in time is
+// penalized for being slow
 func (flow *handleRelayInvsFlow) dequeueIncomingMessageAndSkipInvs(timeout time.Duration) (appmessage.Message, error) {
 	for {
 		message, err := flow.incomingRoute.DequeueWithTimeout(timeout)
 		if err != nil {
+			if errors.Is(err, router.ErrTimeout) {
+				return nil, protocolerrors.WrapOffensef(protocolerrors.OffenseSlowIBDResponse, err,
+					"timed out waiting for an IBD response")
+			}
 			return nil, err
 		}
 		if _, ok := message.(*appmessage.MsgInvRelayBlock); !ok {
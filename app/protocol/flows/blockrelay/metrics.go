@@ -0,0 +1,12 @@
+package blockrelay
+
+import "github.com/kaspanet/kaspad/infrastructure/metrics"
+
+var (
+	ibdHeadersProcessedMetric = metrics.NewCounter("kaspad_ibd_headers_processed_total",
+		"Number of block headers that were received and inserted during IBD")
+	ibdPruningPointUTXOsReceivedMetric = metrics.NewCounter("kaspad_ibd_pruning_point_utxos_received_total",
+		"Number of pruning point UTXOs that were received during IBD")
+	ibdMissingBlockBodiesMetric = metrics.NewGauge("kaspad_ibd_missing_block_bodies",
+		"Number of block bodies that the current IBD has yet to receive")
+)
@@ -34,9 +34,25 @@ func NewManager(cfg *config.Config, domain domain.Domain, netAdapter *netadapter
 	}
 
 	netAdapter.SetP2PRouterInitializer(manager.routerInitializer)
+	connectionManager.SetPeerInfoProvider(manager.peerInfo)
 	return &manager, nil
 }
 
+// peerInfo returns the information about the peer of the given connection
+// that the connection manager needs in order to decide which peers to evict
+func (m *Manager) peerInfo(netConnection *netadapter.NetConnection) (*connmanager.PeerInfo, bool) {
+	for _, peer := range m.context.Peers() {
+		if peer.Connection() == netConnection {
+			return &connmanager.PeerInfo{
+				TimeConnected:      peer.TimeConnected(),
+				LastPingDuration:   peer.LastPingDuration(),
+				LastBlockRelayTime: peer.LastBlockRelayTime(),
+			}, true
+		}
+	}
+	return nil, false
+}
+
 // Close closes the protocol manager and waits until all p2p flows
 // finish.
 func (m *Manager) Close() {
@@ -35,6 +35,9 @@ type Peer struct {
 	lastPingNonce    uint64        // The nonce of the last ping we sent
 	lastPingTime     time.Time     // Time we sent last ping
 	lastPingDuration time.Duration // Time for last ping to return
+
+	lastBlockRelayLock sync.RWMutex
+	lastBlockRelayTime time.Time // Time the peer last relayed a new block to us
 }
 
 // New returns a new Peer
@@ -142,3 +145,21 @@ func (p *Peer) LastPingDuration() time.Duration {
 
 	return p.lastPingDuration
 }
+
+// MarkBlockRelayed records that the peer has just relayed
+// a new block to us
+func (p *Peer) MarkBlockRelayed() {
+	p.lastBlockRelayLock.Lock()
+	defer p.lastBlockRelayLock.Unlock()
+
+	p.lastBlockRelayTime = time.Now()
+}
+
+// LastBlockRelayTime returns the time the peer last relayed a new
+// block to us, or the zero time if it never has
+func (p *Peer) LastBlockRelayTime() time.Time {
+	p.lastBlockRelayLock.RLock()
+	defer p.lastBlockRelayLock.RUnlock()
+
+	return p.lastBlockRelayTime
+}
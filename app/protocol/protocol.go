@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"github.com/kaspanet/kaspad/app/protocol/flows/rejects"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/infrastructure/network/connmanager"
 	"sync"
 	"sync/atomic"
@@ -53,14 +54,18 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 			panic(err)
 		}
 		if isBanned {
-			log.Infof("Peer %s is banned. Disconnecting...", netConnection)
+			log.Infof("Peer %s is banned. Disconnecting...", logger.Attr("peer", netConnection))
+			netConnection.Disconnect()
+			return
+		}
+		if m.context.ConnectionManager().IsOverGroupLimit(netConnection) {
 			netConnection.Disconnect()
 			return
 		}
 
 		netConnection.SetOnInvalidMessageHandler(func(err error) {
 			if atomic.AddUint32(&isStopping, 1) == 1 {
-				errChan <- protocolerrors.Wrap(true, err, "received bad message")
+				errChan <- protocolerrors.WrapOffensef(protocolerrors.OffenseMalformedMessage, err, "received bad message")
 			}
 		})
 
@@ -74,7 +79,7 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 				if errors.Is(err, routerpkg.ErrRouteClosed) {
 					m.handleError(innerError, netConnection, router.OutgoingRoute())
 				} else {
-					log.Errorf("Peer %s sent invalid message: %s", netConnection, innerError)
+					log.Errorf("Peer %s sent invalid message: %s", logger.Attr("peer", netConnection), innerError)
 					m.handleError(err, netConnection, router.OutgoingRoute())
 				}
 			default:
@@ -103,24 +108,15 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection, outgoingRoute *routerpkg.Route) {
 	if protocolErr := (protocolerrors.ProtocolError{}); errors.As(err, &protocolErr) {
 		if !m.context.Config().DisableBanning && protocolErr.ShouldBan {
-			log.Warnf("Banning %s (reason: %s)", netConnection, protocolErr.Cause)
-
-			err := m.context.ConnectionManager().Ban(netConnection)
-			if !errors.Is(err, connmanager.ErrCannotBanPermanent) {
-				panic(err)
-			}
-
-			err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
-			if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
-				panic(err)
-			}
+			m.increaseBanScore(protocolErr, netConnection, outgoingRoute)
 		}
-		log.Infof("Disconnecting from %s (reason: %s)", netConnection, protocolErr.Cause)
+		log.Infof("Disconnecting from %s (reason: %s)", logger.Attr("peer", netConnection),
+			logger.Attr("reason", protocolErr.Cause))
 		netConnection.Disconnect()
 		return
 	}
 	if errors.Is(err, routerpkg.ErrTimeout) {
-		log.Warnf("Got timeout from %s. Disconnecting...", netConnection)
+		log.Warnf("Got timeout from %s. Disconnecting...", logger.Attr("peer", netConnection))
 		netConnection.Disconnect()
 		return
 	}
@@ -130,6 +126,32 @@ func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection
 	panic(err)
 }
 
+// increaseBanScore increases the ban score of the peer by the weight of the
+// offense it committed, and rejects it if that got it banned
+func (m *Manager) increaseBanScore(protocolErr protocolerrors.ProtocolError,
+	netConnection *netadapter.NetConnection, outgoingRoute *routerpkg.Route) {
+
+	banScore, isBanned, err := m.context.ConnectionManager().IncreaseBanScore(netConnection,
+		protocolErr.Offense.Weight(), protocolErr.Error())
+	if err != nil {
+		if errors.Is(err, connmanager.ErrCannotBanPermanent) {
+			return
+		}
+		panic(err)
+	}
+	if !isBanned {
+		log.Infof("Increased the ban score of %s to %d (offense: %s)", logger.Attr("peer", netConnection),
+			banScore, logger.Attr("offense", protocolErr.Offense))
+		return
+	}
+
+	log.Warnf("Banning %s (reason: %s)", logger.Attr("peer", netConnection), logger.Attr("reason", protocolErr.Cause))
+	err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
+	if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
+		panic(err)
+	}
+}
+
 func (m *Manager) registerFlows(router *routerpkg.Router, errChan chan error, isStopping *uint32) (flows []*flow) {
 	flows = m.registerAddressFlows(router, isStopping, errChan)
 	flows = append(flows, m.registerBlockRelayFlows(router, isStopping, errChan)...)
@@ -0,0 +1,64 @@
+package protocolerrors
+
+// Offense is a kind of peer misbehavior. Every offense adds its weight to
+// the ban score of the offending peer, which is banned once its score
+// reaches the ban threshold.
+type Offense uint8
+
+// Offense constants
+const (
+	// OffenseNone is the offense of protocol errors that only
+	// disconnect the peer
+	OffenseNone Offense = iota
+
+	// OffenseProtocolViolation is the offense of banning protocol
+	// errors that weren't given a more specific offense
+	OffenseProtocolViolation
+
+	// OffenseInvalidBlock is sending a block or a header that breaks
+	// the consensus rules
+	OffenseInvalidBlock
+
+	// OffenseMalformedMessage is sending a message that can't be
+	// decoded
+	OffenseMalformedMessage
+
+	// OffenseUnrequestedMessage is sending a message that wasn't
+	// requested, or that doesn't match what was requested
+	OffenseUnrequestedMessage
+
+	// OffenseSlowIBDResponse is failing to respond to an IBD request
+	// in time
+	OffenseSlowIBDResponse
+)
+
+var offenseWeights = map[Offense]uint32{
+	OffenseNone:               0,
+	OffenseProtocolViolation:  100,
+	OffenseInvalidBlock:       100,
+	OffenseMalformedMessage:   50,
+	OffenseUnrequestedMessage: 20,
+	OffenseSlowIBDResponse:    10,
+}
+
+var offenseStrings = map[Offense]string{
+	OffenseNone:               "None",
+	OffenseProtocolViolation:  "ProtocolViolation",
+	OffenseInvalidBlock:       "InvalidBlock",
+	OffenseMalformedMessage:   "MalformedMessage",
+	OffenseUnrequestedMessage: "UnrequestedMessage",
+	OffenseSlowIBDResponse:    "SlowIBDResponse",
+}
+
+// Weight returns the amount by which the offense increases the ban score
+// of the offending peer
+func (o Offense) Weight() uint32 {
+	return offenseWeights[o]
+}
+
+func (o Offense) String() string {
+	if offenseString, ok := offenseStrings[o]; ok {
+		return offenseString
+	}
+	return "Unknown"
+}
@@ -8,10 +8,23 @@ import (
 // ProtocolError is an error that signifies a violation
 // of the peer-to-peer protocol
 type ProtocolError struct {
+	// ShouldBan is set if the violation counts as misbehavior, in
+	// which case the ban score of the peer is increased by the weight
+	// of Offense
 	ShouldBan bool
+	Offense   Offense
 	Cause     error
 }
 
+// offenseForShouldBan returns the offense of errors that were created
+// without specifying one
+func offenseForShouldBan(shouldBan bool) Offense {
+	if shouldBan {
+		return OffenseProtocolViolation
+	}
+	return OffenseNone
+}
+
 func (e ProtocolError) Error() string {
 	return e.Cause.Error()
 }
@@ -26,6 +39,7 @@ func (e ProtocolError) Unwrap() error {
 func Errorf(shouldBan bool, format string, args ...interface{}) error {
 	return ProtocolError{
 		ShouldBan: shouldBan,
+		Offense:   offenseForShouldBan(shouldBan),
 		Cause:     errors.Errorf(format, args...),
 	}
 }
@@ -35,6 +49,7 @@ func Errorf(shouldBan bool, format string, args ...interface{}) error {
 func New(shouldBan bool, message string) error {
 	return ProtocolError{
 		ShouldBan: shouldBan,
+		Offense:   offenseForShouldBan(shouldBan),
 		Cause:     errors.New(message),
 	}
 }
@@ -43,6 +58,7 @@ func New(shouldBan bool, message string) error {
 func Wrap(shouldBan bool, err error, message string) error {
 	return ProtocolError{
 		ShouldBan: shouldBan,
+		Offense:   offenseForShouldBan(shouldBan),
 		Cause:     errors.Wrap(err, message),
 	}
 }
@@ -51,6 +67,27 @@ func Wrap(shouldBan bool, err error, message string) error {
 func Wrapf(shouldBan bool, err error, format string, args ...interface{}) error {
 	return ProtocolError{
 		ShouldBan: shouldBan,
+		Offense:   offenseForShouldBan(shouldBan),
+		Cause:     errors.Wrapf(err, format, args...),
+	}
+}
+
+// Offensef formats according to a format specifier and returns the string
+// as a ProtocolError that counts as the given offense.
+func Offensef(offense Offense, format string, args ...interface{}) error {
+	return ProtocolError{
+		ShouldBan: offense != OffenseNone,
+		Offense:   offense,
+		Cause:     errors.Errorf(format, args...),
+	}
+}
+
+// WrapOffensef wraps the given error with the given format and returns it
+// as a ProtocolError that counts as the given offense.
+func WrapOffensef(offense Offense, err error, format string, args ...interface{}) error {
+	return ProtocolError{
+		ShouldBan: offense != OffenseNone,
+		Offense:   offense,
 		Cause:     errors.Wrapf(err, format, args...),
 	}
 }
@@ -63,5 +100,5 @@ func ConvertToBanningProtocolErrorIfRuleError(err error, format string, args ...
 		return err
 	}
 
-	return Wrapf(true, err, format, args...)
+	return WrapOffensef(OffenseInvalidBlock, err, format, args...)
 }
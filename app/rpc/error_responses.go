@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+// errorResponseConstructors maps every request command to a constructor of
+// an error response to it. It's used to reject requests without handling them
+var errorResponseConstructors = map[appmessage.MessageCommand]func(*appmessage.RPCError) appmessage.Message{
+	appmessage.CmdAddPeerRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.AddPeerResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdBackupDatabaseRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.BackupDatabaseResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdBanRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.BanResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBannedPeersRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBannedPeersResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockCountRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockCountResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockDAGInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockDAGInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockTemplateRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockTemplateResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlocksRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlocksResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetConnectedPeerInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetConnectedPeerInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetCurrentNetworkRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetCurrentNetworkResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetFeeEstimateRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetFeeEstimateResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetHeadersRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetHeadersResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetLogLevelsRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetLogLevelsResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetMempoolEntriesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetMempoolEntriesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetMempoolEntryRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetMempoolEntryResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetPeerAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetPeerAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetSelectedTipHashRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetSelectedTipHashResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetSubnetworkRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetSubnetworkResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetTransactionRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetTransactionResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetTransactionsByAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetTransactionsByAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetUTXOHistoryByAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetUTXOHistoryByAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetUTXOsByAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetUTXOsByAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetVirtualSelectedParentBlueScoreRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetVirtualSelectedParentBlueScoreResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetVirtualSelectedParentChainFromBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetVirtualSelectedParentChainFromBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyBlockAddedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyBlockAddedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyFinalityConflictsRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyFinalityConflictsResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyPruningPointUTXOSetOverrideRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyPruningPointUTXOSetOverrideResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyUTXOsChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyUTXOsChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualDaaScoreChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualDaaScoreChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualSelectedParentBlueScoreChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualSelectedParentBlueScoreChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualSelectedParentChainChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualSelectedParentChainChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdResolveFinalityConflictRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.ResolveFinalityConflictResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSetLogLevelRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SetLogLevelResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdShutDownRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.ShutDownResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.StopNotifyingPruningPointUTXOSetOverrideResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdStopNotifyingUTXOsChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.StopNotifyingUTXOsChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSubmitBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SubmitBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSubmitTransactionRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SubmitTransactionResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdUnbanRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.UnbanResponseMessage{Error: rpcError}
+	},
+}
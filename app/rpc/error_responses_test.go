@@ -0,0 +1,26 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestErrorResponseConstructors(t *testing.T) {
+	for command := range handlers {
+		newErrorResponse, ok := errorResponseConstructors[command]
+		if !ok {
+			t.Errorf("Missing an error response constructor for %s", command)
+			continue
+		}
+		rpcError := appmessage.RPCErrorf("error")
+		response := newErrorResponse(rpcError)
+
+		// Every response command immediately follows its request command
+		expectedResponseCommand := command + 1
+		if response.Command() != expectedResponseCommand {
+			t.Errorf("Expected the error response to %s to be %s, but got %s",
+				command, expectedResponseCommand, response.Command())
+		}
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"github.com/kaspanet/kaspad/app/protocol"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/addressindex"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/txindex"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
 	"github.com/kaspanet/kaspad/infrastructure/network/connmanager"
@@ -17,6 +20,7 @@ import (
 // Manager is an RPC manager
 type Manager struct {
 	context *rpccontext.Context
+	policy  *policy
 }
 
 // NewManager creates a new RPC Manager
@@ -28,6 +32,9 @@ func NewManager(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressIndex *addressindex.AddressIndex,
+	db database.Database,
 	shutDownChan chan<- struct{}) *Manager {
 
 	manager := Manager{
@@ -39,8 +46,12 @@ func NewManager(
 			connectionManager,
 			addressManager,
 			utxoIndex,
+			txIndex,
+			addressIndex,
+			db,
 			shutDownChan,
 		),
+		policy: newPolicy(cfg.RPCDenyRules, cfg.RPCRateLimits),
 	}
 	netAdapter.SetRPCRouterInitializer(manager.routerInitializer)
 
@@ -59,6 +70,20 @@ func (m *Manager) NotifyBlockAddedToDAG(block *externalapi.DomainBlock, blockIns
 		}
 	}
 
+	if m.context.Config.TXIndex {
+		err := m.context.TXIndex.Update(blockInsertionResult)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressIndex {
+		err := m.context.AddressIndex.Update(blockInsertionResult)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := m.notifyVirtualSelectedParentBlueScoreChanged()
 	if err != nil {
 		return err
@@ -96,6 +121,20 @@ func (m *Manager) NotifyPruningPointUTXOSetOverride() error {
 		}
 	}
 
+	if m.context.Config.TXIndex {
+		err := m.context.TXIndex.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressIndex {
+		err := m.context.AddressIndex.Sync()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,26 @@
+package rpc
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+)
+
+const (
+	rpcCallStatusHandled     = "handled"
+	rpcCallStatusDenied      = "denied"
+	rpcCallStatusRateLimited = "rate_limited"
+)
+
+var rpcCallsMetric = metrics.NewCounterVec("kaspad_rpc_calls_total",
+	"Number of RPC requests by method and by whether they were handled, denied or rate limited", "method", "status")
+
+func updateRPCCallMetrics(command appmessage.MessageCommand, rpcError *appmessage.RPCError) {
+	status := rpcCallStatusHandled
+	if rpcError != nil {
+		status = rpcCallStatusDenied
+		if rpcError.Code == appmessage.RPCErrorCodeRateLimited {
+			status = rpcCallStatusRateLimited
+		}
+	}
+	rpcCallsMetric.WithLabelValues(command.String(), status).Inc()
+}
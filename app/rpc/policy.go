@@ -0,0 +1,188 @@
+package rpc
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+)
+
+// adminOnlyCommands are the commands that change the state of the node.
+// They may only be called over RPC connections with admin permissions.
+// Every other command only reads the node's state, and may be called with
+// read-only permissions.
+var adminOnlyCommands = map[appmessage.MessageCommand]struct{}{
+	appmessage.CmdSubmitBlockRequestMessage:             {},
+	appmessage.CmdSubmitTransactionRequestMessage:       {},
+	appmessage.CmdAddPeerRequestMessage:                 {},
+	appmessage.CmdBanRequestMessage:                     {},
+	appmessage.CmdUnbanRequestMessage:                   {},
+	appmessage.CmdResolveFinalityConflictRequestMessage: {},
+	appmessage.CmdShutDownRequestMessage:                {},
+	appmessage.CmdSetLogLevelRequestMessage:             {},
+	appmessage.CmdBackupDatabaseRequestMessage:          {},
+}
+
+// rateLimiterPruneInterval is how often the rate limiter forgets the
+// clients that are no longer limited
+const rateLimiterPruneInterval = time.Minute
+
+// rpcClient describes an RPC connection to the policy
+type rpcClient struct {
+	authentication server.RPCAuthentication
+	listenAddress  string
+
+	// host identifies the client for rate limiting, so that clients
+	// can't evade the limits by opening more connections
+	host string
+}
+
+// policy decides which requests RPC clients may make. It enforces the admin
+// permissions of admin-only commands, the deny rules and the rate limits of
+// the configuration.
+type policy struct {
+	denyRules  []*config.RPCDenyRule
+	rateLimits map[appmessage.MessageCommand]int
+
+	rateLimiterLock sync.Mutex
+	buckets         map[rateLimiterKey]*tokenBucket
+	lastPruneTime   time.Time
+}
+
+type rateLimiterKey struct {
+	host    string
+	command appmessage.MessageCommand
+}
+
+func newPolicy(denyRules []*config.RPCDenyRule, rateLimits map[appmessage.MessageCommand]int) *policy {
+	return &policy{
+		denyRules:  denyRules,
+		rateLimits: rateLimits,
+		buckets:    make(map[rateLimiterKey]*tokenBucket),
+	}
+}
+
+// check returns an error if the given client may not make the given request
+// at the given time, or nil if it may
+func (p *policy) check(command appmessage.MessageCommand, client *rpcClient, now time.Time) *appmessage.RPCError {
+	if _, ok := adminOnlyCommands[command]; ok && client.authentication == server.RPCAuthenticationLimited {
+		return &appmessage.RPCError{
+			Message: command.String() + " requires admin permissions",
+			Code:    appmessage.RPCErrorCodePermissionDenied,
+		}
+	}
+
+	for _, rule := range p.denyRules {
+		if rule.Command == command && isInScope(client, rule.Scope) {
+			return &appmessage.RPCError{
+				Message: command.String() + " is denied over this RPC connection",
+				Code:    appmessage.RPCErrorCodePermissionDenied,
+			}
+		}
+	}
+
+	// Admins are trusted not to overload the node
+	if client.authentication == server.RPCAuthenticationAdmin {
+		return nil
+	}
+	limit, ok := p.rateLimits[command]
+	if !ok {
+		return nil
+	}
+	retryAfter := p.takeToken(rateLimiterKey{host: client.host, command: command}, limit, now)
+	if retryAfter > 0 {
+		return &appmessage.RPCError{
+			Message: fmt.Sprintf("exceeded the rate limit of %d %s per minute. Retry after %s",
+				limit, command, retryAfter),
+			Code:       appmessage.RPCErrorCodeRateLimited,
+			RetryAfter: retryAfter,
+		}
+	}
+	return nil
+}
+
+func isInScope(client *rpcClient, scope string) bool {
+	switch scope {
+	case "":
+		return true
+	case config.RPCDenyScopeAdmin:
+		return client.authentication == server.RPCAuthenticationAdmin
+	case config.RPCDenyScopeLimited:
+		return client.authentication == server.RPCAuthenticationLimited
+	case config.RPCDenyScopeNone:
+		return client.authentication == server.RPCAuthenticationNone
+	default:
+		return client.listenAddress == scope
+	}
+}
+
+// takeToken takes a token from the bucket of the given key, and returns zero.
+// If the bucket is empty, it returns how long it'll take for it to refill
+// with a token instead.
+func (p *policy) takeToken(key rateLimiterKey, limit int, now time.Time) time.Duration {
+	p.rateLimiterLock.Lock()
+	defer p.rateLimiterLock.Unlock()
+
+	if now.Sub(p.lastPruneTime) >= rateLimiterPruneInterval {
+		p.pruneBuckets(now)
+	}
+
+	bucket, ok := p.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(limit, now)
+		p.buckets[key] = bucket
+	}
+	return bucket.take(now)
+}
+
+// pruneBuckets removes the buckets that are full, since new buckets
+// start out full anyway
+func (p *policy) pruneBuckets(now time.Time) {
+	for key, bucket := range p.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.capacity {
+			delete(p.buckets, key)
+		}
+	}
+	p.lastPruneTime = now
+}
+
+// tokenBucket allows bursts of up to capacity requests, and refills
+// at a rate of capacity tokens per minute
+type tokenBucket struct {
+	capacity       float64
+	tokens         float64
+	lastRefillTime time.Time
+}
+
+func newTokenBucket(requestsPerMinute int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity:       float64(requestsPerMinute),
+		tokens:         float64(requestsPerMinute),
+		lastRefillTime: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefillTime)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(b.capacity, b.tokens+b.capacity*elapsed.Minutes())
+	b.lastRefillTime = now
+}
+
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	missingTokens := 1 - b.tokens
+	retryAfter := time.Duration(math.Ceil(missingTokens / b.capacity * float64(time.Minute/time.Millisecond)))
+	return retryAfter * time.Millisecond
+}
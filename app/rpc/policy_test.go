@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+)
+
+func TestPolicyPermissions(t *testing.T) {
+	const publicListener = "0.0.0.0:16110"
+	denyRules := []*config.RPCDenyRule{
+		{Command: appmessage.CmdGetPeerAddressesRequestMessage, Scope: publicListener},
+		{Command: appmessage.CmdGetConnectedPeerInfoRequestMessage, Scope: config.RPCDenyScopeLimited},
+		{Command: appmessage.CmdGetSubnetworkRequestMessage},
+	}
+	policy := newPolicy(denyRules, nil)
+
+	admin := &rpcClient{authentication: server.RPCAuthenticationAdmin, listenAddress: "127.0.0.1:16110"}
+	limited := &rpcClient{authentication: server.RPCAuthenticationLimited, listenAddress: "127.0.0.1:16110"}
+	public := &rpcClient{authentication: server.RPCAuthenticationNone, listenAddress: publicListener}
+
+	tests := []struct {
+		command       appmessage.MessageCommand
+		client        *rpcClient
+		expectAllowed bool
+	}{
+		{appmessage.CmdAddPeerRequestMessage, admin, true},
+		{appmessage.CmdAddPeerRequestMessage, limited, false},
+		{appmessage.CmdAddPeerRequestMessage, public, true},
+		{appmessage.CmdGetPeerAddressesRequestMessage, admin, true},
+		{appmessage.CmdGetPeerAddressesRequestMessage, public, false},
+		{appmessage.CmdGetConnectedPeerInfoRequestMessage, admin, true},
+		{appmessage.CmdGetConnectedPeerInfoRequestMessage, limited, false},
+		{appmessage.CmdGetConnectedPeerInfoRequestMessage, public, true},
+		{appmessage.CmdGetSubnetworkRequestMessage, admin, false},
+		{appmessage.CmdGetSubnetworkRequestMessage, public, false},
+		{appmessage.CmdGetInfoRequestMessage, limited, true},
+	}
+	for _, test := range tests {
+		rpcError := policy.check(test.command, test.client, time.Now())
+		if test.expectAllowed {
+			if rpcError != nil {
+				t.Errorf("Expected %s to be allowed for %s connections on %s, but got: %s",
+					test.command, test.client.authentication, test.client.listenAddress, rpcError.Message)
+			}
+			continue
+		}
+		if rpcError == nil {
+			t.Errorf("Expected %s to be denied for %s connections on %s, but it's allowed",
+				test.command, test.client.authentication, test.client.listenAddress)
+			continue
+		}
+		if rpcError.Code != appmessage.RPCErrorCodePermissionDenied {
+			t.Errorf("Expected a %s error, but got %s", appmessage.RPCErrorCodePermissionDenied, rpcError.Code)
+		}
+	}
+}
+
+func TestPolicyRateLimits(t *testing.T) {
+	const limit = 6
+	policy := newPolicy(nil, map[appmessage.MessageCommand]int{appmessage.CmdGetBlocksRequestMessage: limit})
+
+	client := &rpcClient{authentication: server.RPCAuthenticationNone, host: "1.2.3.4"}
+	otherClient := &rpcClient{authentication: server.RPCAuthenticationNone, host: "5.6.7.8"}
+	admin := &rpcClient{authentication: server.RPCAuthenticationAdmin, host: "1.2.3.4"}
+
+	now := time.Unix(1000, 0)
+	for i := 0; i < limit; i++ {
+		rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, client, now)
+		if rpcError != nil {
+			t.Fatalf("Request #%d unexpectedly rate limited: %s", i, rpcError.Message)
+		}
+	}
+
+	rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, client, now)
+	if rpcError == nil {
+		t.Fatalf("Expected request #%d to be rate limited", limit)
+	}
+	if rpcError.Code != appmessage.RPCErrorCodeRateLimited {
+		t.Fatalf("Expected a %s error, but got %s", appmessage.RPCErrorCodeRateLimited, rpcError.Code)
+	}
+	expectedRetryAfter := time.Minute / limit
+	if rpcError.RetryAfter != expectedRetryAfter {
+		t.Fatalf("Expected RetryAfter %s, but got %s", expectedRetryAfter, rpcError.RetryAfter)
+	}
+
+	// Other commands, other clients and admins are not limited
+	if rpcError := policy.check(appmessage.CmdGetHeadersRequestMessage, client, now); rpcError != nil {
+		t.Fatalf("Unexpected error for an unlimited command: %s", rpcError.Message)
+	}
+	if rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, otherClient, now); rpcError != nil {
+		t.Fatalf("Unexpected error for another client: %s", rpcError.Message)
+	}
+	if rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, admin, now); rpcError != nil {
+		t.Fatalf("Unexpected error for an admin: %s", rpcError.Message)
+	}
+
+	// Once RetryAfter passes the client may make one more request
+	now = now.Add(expectedRetryAfter)
+	if rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, client, now); rpcError != nil {
+		t.Fatalf("Unexpected error after RetryAfter passed: %s", rpcError.Message)
+	}
+	if rpcError := policy.check(appmessage.CmdGetBlocksRequestMessage, client, now); rpcError == nil {
+		t.Fatalf("Expected the request right after to be rate limited")
+	}
+
+	// Buckets that refilled are pruned
+	now = now.Add(time.Minute)
+	policy.check(appmessage.CmdGetBlocksRequestMessage, otherClient, now)
+	if len(policy.buckets) != 1 {
+		t.Fatalf("Expected only the bucket of the last request to remain, but got %d buckets", len(policy.buckets))
+	}
+}
@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"time"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
@@ -37,6 +39,10 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdNotifyUTXOsChangedRequestMessage:                          rpchandlers.HandleNotifyUTXOsChanged,
 	appmessage.CmdStopNotifyingUTXOsChangedRequestMessage:                   rpchandlers.HandleStopNotifyingUTXOsChanged,
 	appmessage.CmdGetUTXOsByAddressesRequestMessage:                         rpchandlers.HandleGetUTXOsByAddresses,
+	appmessage.CmdGetUTXOHistoryByAddressesRequestMessage:                   rpchandlers.HandleGetUTXOHistoryByAddresses,
+	appmessage.CmdGetTransactionRequestMessage:                              rpchandlers.HandleGetTransaction,
+	appmessage.CmdGetTransactionsByAddressesRequestMessage:                  rpchandlers.HandleGetTransactionsByAddresses,
+	appmessage.CmdGetFeeEstimateRequestMessage:                              rpchandlers.HandleGetFeeEstimate,
 	appmessage.CmdGetVirtualSelectedParentBlueScoreRequestMessage:           rpchandlers.HandleGetVirtualSelectedParentBlueScore,
 	appmessage.CmdNotifyVirtualSelectedParentBlueScoreChangedRequestMessage: rpchandlers.HandleNotifyVirtualSelectedParentBlueScoreChanged,
 	appmessage.CmdBanRequestMessage:                                         rpchandlers.HandleBan,
@@ -45,6 +51,10 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdNotifyPruningPointUTXOSetOverrideRequestMessage:           rpchandlers.HandleNotifyPruningPointUTXOSetOverrideRequest,
 	appmessage.CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage:    rpchandlers.HandleStopNotifyingPruningPointUTXOSetOverrideRequest,
 	appmessage.CmdNotifyVirtualDaaScoreChangedRequestMessage:                rpchandlers.HandleNotifyVirtualDaaScoreChanged,
+	appmessage.CmdGetLogLevelsRequestMessage:                                rpchandlers.HandleGetLogLevels,
+	appmessage.CmdSetLogLevelRequestMessage:                                 rpchandlers.HandleSetLogLevel,
+	appmessage.CmdGetBannedPeersRequestMessage:                              rpchandlers.HandleGetBannedPeers,
+	appmessage.CmdBackupDatabaseRequestMessage:                              rpchandlers.HandleBackupDatabase,
 }
 
 func (m *Manager) routerInitializer(router *router.Router, netConnection *netadapter.NetConnection) {
@@ -61,12 +71,19 @@ func (m *Manager) routerInitializer(router *router.Router, netConnection *netada
 	spawn("routerInitializer-handleIncomingMessages", func() {
 		defer m.context.NotificationManager.RemoveListener(router)
 
-		err := m.handleIncomingMessages(router, incomingRoute)
+		err := m.handleIncomingMessages(router, incomingRoute, netConnection)
 		m.handleError(err, netConnection)
 	})
 }
 
-func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *router.Route) error {
+func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *router.Route,
+	netConnection *netadapter.NetConnection) error {
+
+	client := &rpcClient{
+		authentication: netConnection.RPCAuthentication(),
+		listenAddress:  netConnection.RPCListenAddress(),
+		host:           netConnection.NetAddress().IP.String(),
+	}
 	outgoingRoute := router.OutgoingRoute()
 	for {
 		request, err := incomingRoute.Dequeue()
@@ -77,10 +94,22 @@ func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *r
 		if !ok {
 			return err
 		}
-		response, err := handler(m.context, router, request)
-		if err != nil {
-			return err
+		var response appmessage.Message
+		rpcError := m.policy.check(request.Command(), client, time.Now())
+		if rpcError != nil {
+			if rpcError.Code == appmessage.RPCErrorCodeRateLimited {
+				log.Debugf("Rate limited %s from %s", request.Command(), netConnection)
+			} else {
+				log.Warnf("Rejected %s from %s: %s", request.Command(), netConnection, rpcError.Message)
+			}
+			response = errorResponseConstructors[request.Command()](rpcError)
+		} else {
+			response, err = handler(m.context, router, request)
+			if err != nil {
+				return err
+			}
 		}
+		updateRPCCallMetrics(request.Command(), rpcError)
 		err = outgoingRoute.Enqueue(response)
 		if err != nil {
 			return err
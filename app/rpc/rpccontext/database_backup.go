@@ -0,0 +1,81 @@
+package rpccontext
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// databaseVersionFileName is the name of the file in the database directory
+// that holds the version of the database
+const databaseVersionFileName = "version"
+
+// BackupDatabase writes a consistent snapshot of the node's database under
+// targetDirectory, in the same path that the node's database has under its
+// home directory, so that running kaspad with --appdir=targetDirectory
+// restores the backup. It returns the path of the written database.
+func (ctx *Context) BackupDatabase(targetDirectory string) (string, *database.BackupResult, error) {
+	backuper, ok := ctx.Database.(database.Backuper)
+	if !ok {
+		return "", nil, errors.Errorf("the database backend doesn't support backups")
+	}
+	if !filepath.IsAbs(targetDirectory) {
+		return "", nil, errors.Errorf("the target directory must be an absolute path")
+	}
+
+	if !atomic.CompareAndSwapUint32(&ctx.isBackingUpDatabase, 0, 1) {
+		return "", nil, errors.Errorf("a database backup is already in progress")
+	}
+	defer atomic.StoreUint32(&ctx.isBackingUpDatabase, 0)
+
+	// The node's database is in <appdir>/<network>/data. Unlike
+	// targetDirectory, Config.AppDir already includes the network
+	databasePath := filepath.Join(targetDirectory, ctx.Config.NetParams().Name, "data")
+	_, err := os.Stat(databasePath)
+	if err == nil {
+		return "", nil, errors.Errorf("'%s' already exists", databasePath)
+	}
+	if !os.IsNotExist(err) {
+		return "", nil, errors.WithStack(err)
+	}
+	err = os.MkdirAll(filepath.Dir(databasePath), 0700)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	log.Infof("Backing up the database into '%s'", databasePath)
+	start := time.Now()
+	result, err := backuper.Backup(databasePath)
+	if err == nil {
+		err = copyDatabaseVersionFile(filepath.Join(ctx.Config.AppDir, "data"), databasePath)
+	}
+	if err != nil {
+		removeErr := os.RemoveAll(databasePath)
+		if removeErr != nil {
+			log.Errorf("Failed to remove the incomplete backup in '%s': %s", databasePath, removeErr)
+		}
+		return "", nil, err
+	}
+	log.Infof("Backed up %d records (%d bytes) into '%s' in %s",
+		result.RecordCount, result.Size, databasePath, time.Since(start))
+
+	return databasePath, result, nil
+}
+
+// copyDatabaseVersionFile copies the version file of the database, if it has
+// one, so that the restored database isn't assumed to be of a different version
+func copyDatabaseVersionFile(sourceDatabasePath string, targetDatabasePath string) error {
+	version, err := os.ReadFile(filepath.Join(sourceDatabasePath, databaseVersionFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	err = os.WriteFile(filepath.Join(targetDatabasePath, databaseVersionFileName), version, 0600)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,30 @@
+package rpccontext
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/addressindex"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+)
+
+// ConvertTransactionEntriesToTransactionsByAddressesEntries converts address index
+// TransactionEntries to a slice of TransactionsByAddressesEntry, with the addresses
+// of the entries' scriptPublicKeys
+func ConvertTransactionEntriesToTransactionsByAddressesEntries(
+	addressesByScriptPublicKey map[utxoindex.ScriptPublicKeyString]string,
+	transactionEntries []*addressindex.TransactionEntry) []*appmessage.TransactionsByAddressesEntry {
+
+	transactionsByAddressesEntries := make([]*appmessage.TransactionsByAddressesEntry, len(transactionEntries))
+	for i, transactionEntry := range transactionEntries {
+		scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(transactionEntry.ScriptPublicKey)
+		transactionsByAddressesEntries[i] = &appmessage.TransactionsByAddressesEntry{
+			Address:                 addressesByScriptPublicKey[scriptPublicKeyString],
+			TransactionID:           transactionEntry.TransactionID.String(),
+			IncludingBlockHash:      transactionEntry.IncludingBlockHash.String(),
+			AcceptingBlockHash:      transactionEntry.AcceptingBlockHash.String(),
+			AcceptingBlockBlueScore: transactionEntry.AcceptingBlockBlueScore,
+			Received:                transactionEntry.Received,
+			Sent:                    transactionEntry.Sent,
+		}
+	}
+	return transactionsByAddressesEntries
+}
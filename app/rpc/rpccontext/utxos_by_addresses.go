@@ -32,6 +32,42 @@ func ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(address string, pair
 	return utxosByAddressesEntries
 }
 
+// ConvertUTXOHistoryEntriesToUTXOHistoryByAddressesEntries converts
+// UTXOHistoryEntries to a slice of UTXOHistoryByAddressesEntry
+func ConvertUTXOHistoryEntriesToUTXOHistoryByAddressesEntries(address string,
+	historyEntries []*utxoindex.UTXOHistoryEntry) []*appmessage.UTXOHistoryByAddressesEntry {
+
+	utxoHistoryByAddressesEntries := make([]*appmessage.UTXOHistoryByAddressesEntry, len(historyEntries))
+	for i, historyEntry := range historyEntries {
+		utxoEntry := historyEntry.UTXOEntry
+		utxoHistoryByAddressesEntry := &appmessage.UTXOHistoryByAddressesEntry{
+			Address: address,
+			Outpoint: &appmessage.RPCOutpoint{
+				TransactionID: historyEntry.Outpoint.TransactionID.String(),
+				Index:         historyEntry.Outpoint.Index,
+			},
+			UTXOEntry: &appmessage.RPCUTXOEntry{
+				Amount:          utxoEntry.Amount(),
+				ScriptPublicKey: &appmessage.RPCScriptPublicKey{Script: hex.EncodeToString(utxoEntry.ScriptPublicKey().Script), Version: utxoEntry.ScriptPublicKey().Version},
+				BlockDAAScore:   utxoEntry.BlockDAAScore(),
+				IsCoinbase:      utxoEntry.IsCoinbase(),
+			},
+		}
+		if historyEntry.AcceptingBlockHash != nil {
+			utxoHistoryByAddressesEntry.AcceptingBlockHash = historyEntry.AcceptingBlockHash.String()
+			utxoHistoryByAddressesEntry.AcceptingBlockBlueScore = historyEntry.AcceptingBlockBlueScore
+		}
+		if historyEntry.SpendingTransactionID != nil {
+			utxoHistoryByAddressesEntry.SpendingTransactionID = historyEntry.SpendingTransactionID.String()
+			utxoHistoryByAddressesEntry.SpendingBlockHash = historyEntry.SpendingBlockHash.String()
+			utxoHistoryByAddressesEntry.SpendingBlockBlueScore = historyEntry.SpendingBlockBlueScore
+			utxoHistoryByAddressesEntry.SpendingTransactionFee = historyEntry.SpendingTransactionFee
+		}
+		utxoHistoryByAddressesEntries[i] = utxoHistoryByAddressesEntry
+	}
+	return utxoHistoryByAddressesEntries
+}
+
 // convertUTXOOutpointsToUTXOsByAddressesEntries converts
 // UTXOOutpoints to a slice of UTXOsByAddressesEntry
 func convertUTXOOutpointsToUTXOsByAddressesEntries(address string, outpoints utxoindex.UTXOOutpoints) []*appmessage.UTXOsByAddressesEntry {
@@ -0,0 +1,21 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleBackupDatabase handles the respectively named RPC command
+func HandleBackupDatabase(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	backupDatabaseRequest := request.(*appmessage.BackupDatabaseRequestMessage)
+
+	databasePath, result, err := context.BackupDatabase(backupDatabaseRequest.TargetDirectory)
+	if err != nil {
+		errorMessage := &appmessage.BackupDatabaseResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not back up the database: %s", err)
+		return errorMessage, nil
+	}
+
+	return appmessage.NewBackupDatabaseResponseMessage(databasePath, result.RecordCount, result.Size), nil
+}
@@ -1,23 +1,36 @@
 package rpchandlers
 
 import (
+	"math"
+	"time"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"net"
 )
 
+// maxBanDurationSeconds is the longest ban duration that fits in a time.Duration
+const maxBanDurationSeconds = math.MaxInt64 / uint64(time.Second)
+
 // HandleBan handles the respectively named RPC command
 func HandleBan(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
 	banRequest := request.(*appmessage.BanRequestMessage)
-	ip := net.ParseIP(banRequest.IP)
-	if ip == nil {
+	subnet, err := addressmanager.ParseSubnet(banRequest.IP)
+	if err != nil {
 		errorMessage := &appmessage.BanResponseMessage{}
-		errorMessage.Error = appmessage.RPCErrorf("Could not parse IP %s", banRequest.IP)
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse IP %s: %s", banRequest.IP, err)
 		return errorMessage, nil
 	}
 
-	err := context.ConnectionManager.BanByIP(ip)
+	if banRequest.DurationSeconds > maxBanDurationSeconds {
+		errorMessage := &appmessage.BanResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Ban duration %d seconds is above the maximum of %d seconds",
+			banRequest.DurationSeconds, maxBanDurationSeconds)
+		return errorMessage, nil
+	}
+	duration := time.Duration(banRequest.DurationSeconds) * time.Second
+	err = context.ConnectionManager.BanSubnet(subnet, duration, "Banned via RPC")
 	if err != nil {
 		errorMessage := &appmessage.BanResponseMessage{}
 		errorMessage.Error = appmessage.RPCErrorf("Could not ban IP: %s", err)
@@ -0,0 +1,37 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetBannedPeers handles the respectively named RPC command
+func HandleGetBannedPeers(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	bans, err := context.AddressManager.Bans()
+	if err != nil {
+		return nil, err
+	}
+
+	bannedPeers := make([]*appmessage.RPCBannedPeer, len(bans))
+	for i, ban := range bans {
+		bannedPeers[i] = &appmessage.RPCBannedPeer{
+			IP:        banString(ban),
+			Reason:    ban.Reason,
+			CreatedAt: ban.CreatedAt.UnixMilliseconds(),
+			ExpiresAt: ban.ExpiresAt.UnixMilliseconds(),
+		}
+	}
+	return appmessage.NewGetBannedPeersResponseMessage(bannedPeers), nil
+}
+
+// banString returns the banned IP if the ban is of a single IP, and the banned
+// subnet in CIDR notation otherwise
+func banString(ban *addressmanager.BanInfo) string {
+	ones, bits := ban.Subnet.Mask.Size()
+	if ones == bits {
+		return ban.Subnet.IP.String()
+	}
+	return ban.Subnet.String()
+}
@@ -10,8 +10,14 @@ import (
 func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
 	peers := context.ProtocolManager.Peers()
 	ibdPeer := context.ProtocolManager.IBDPeer()
+	protectedInboundConnections := context.ConnectionManager.ProtectedInboundConnections()
 	infos := make([]*appmessage.GetConnectedPeerInfoMessage, 0, len(peers))
 	for _, peer := range peers {
+		banScore, err := context.ConnectionManager.BanScore(peer.Connection().NetAddress().IP)
+		if err != nil {
+			return nil, err
+		}
+		_, isProtectedFromEviction := protectedInboundConnections[peer.Connection()]
 		info := &appmessage.GetConnectedPeerInfoMessage{
 			ID:                        peer.ID().String(),
 			Address:                   peer.Address(),
@@ -22,6 +28,9 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 			AdvertisedProtocolVersion: peer.AdvertisedProtocolVersion(),
 			TimeConnected:             peer.TimeConnected().Milliseconds(),
 			IsIBDPeer:                 peer == ibdPeer,
+			BanScore:                  banScore,
+			NetworkGroup:              context.ConnectionManager.NetworkGroup(peer.Connection()),
+			IsProtectedFromEviction:   isProtectedFromEviction,
 		}
 		infos = append(infos, info)
 	}
@@ -0,0 +1,26 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetFeeEstimate handles the respectively named RPC command
+func HandleGetFeeEstimate(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	feeEstimate := context.Domain.MiningManager().FeeEstimate()
+
+	return appmessage.NewGetFeeEstimateResponseMessage(&appmessage.RPCFeeEstimate{
+		PriorityBucket: feeRateBucketToRPC(feeEstimate.PriorityBucket),
+		NormalBucket:   feeRateBucketToRPC(feeEstimate.NormalBucket),
+		LowBucket:      feeRateBucketToRPC(feeEstimate.LowBucket),
+	}), nil
+}
+
+func feeRateBucketToRPC(bucket *miningmanagermodel.FeeRateBucket) *appmessage.RPCFeeRateBucket {
+	return &appmessage.RPCFeeRateBucket{
+		FeeRate:          bucket.FeeRate,
+		EstimatedSeconds: bucket.EstimatedSeconds,
+	}
+}
@@ -3,15 +3,49 @@ package rpchandlers
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 )
 
 // HandleGetInfo handles the respectively named RPC command
 func HandleGetInfo(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	var databaseStats *appmessage.RPCDatabaseStats
+	if levelDB, ok := context.Database.(*ldb.LevelDB); ok {
+		stats, err := levelDB.Stats()
+		if err != nil {
+			return nil, err
+		}
+		databaseStats = rpcDatabaseStats(stats)
+	}
+
 	response := appmessage.NewGetInfoResponseMessage(
 		context.NetAdapter.ID().String(),
 		uint64(context.Domain.MiningManager().TransactionCount()),
+		databaseStats,
 	)
 
 	return response, nil
 }
+
+func rpcDatabaseStats(stats *ldb.Stats) *appmessage.RPCDatabaseStats {
+	levels := make([]*appmessage.RPCDatabaseLevelStats, len(stats.Levels))
+	for i, level := range stats.Levels {
+		levels[i] = &appmessage.RPCDatabaseLevelStats{
+			Level:              uint32(level.Level),
+			TableCount:         uint32(level.TableCount),
+			Size:               level.Size,
+			ReadBytes:          level.ReadBytes,
+			WriteBytes:         level.WriteBytes,
+			CompactionDuration: level.CompactionDuration.Milliseconds(),
+		}
+	}
+	return &appmessage.RPCDatabaseStats{
+		Levels:                   levels,
+		MemoryCompactionCount:    stats.MemoryCompactionCount,
+		Level0CompactionCount:    stats.Level0CompactionCount,
+		NonLevel0CompactionCount: stats.NonLevel0CompactionCount,
+		SeekCompactionCount:      stats.SeekCompactionCount,
+		WriteDelayCount:          uint32(stats.WriteDelayCount),
+		WriteDelayDuration:       stats.WriteDelayDuration.Milliseconds(),
+	}
+}
@@ -0,0 +1,29 @@
+package rpchandlers
+
+import (
+	"sort"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetLogLevels handles the respectively named RPC command
+func HandleGetLogLevels(_ *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	levels := logger.LogLevels()
+	subsystems := make([]string, 0, len(levels))
+	for subsystem := range levels {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+
+	logLevels := make([]*appmessage.RPCSubsystemLogLevel, len(subsystems))
+	for i, subsystem := range subsystems {
+		logLevels[i] = &appmessage.RPCSubsystemLogLevel{
+			Subsystem: subsystem,
+			Level:     levels[subsystem].Name(),
+		}
+	}
+	return appmessage.NewGetLogLevelsResponseMessage(logLevels), nil
+}
@@ -18,11 +18,19 @@ func HandleGetPeerAddresses(context *rpccontext.Context, _ *router.Router, _ app
 		addressMessages[i] = &appmessage.GetPeerAddressesKnownAddressMessage{Addr: addressWithPort}
 	}
 
-	bannedAddresses := context.AddressManager.BannedAddresses()
-	bannedAddressMessages := make([]*appmessage.GetPeerAddressesKnownAddressMessage, len(bannedAddresses))
-	for i, netAddress := range bannedAddresses {
-		addressWithPort := net.JoinHostPort(netAddress.IP.String(), strconv.FormatUint(uint64(netAddress.Port), 10))
-		bannedAddressMessages[i] = &appmessage.GetPeerAddressesKnownAddressMessage{Addr: addressWithPort}
+	bans, err := context.AddressManager.Bans()
+	if err != nil {
+		return nil, err
+	}
+	bannedAddressMessages := make([]*appmessage.GetPeerAddressesKnownAddressMessage, len(bans))
+	for i, ban := range bans {
+		// Bans of single IPs are listed with a zero port, same as known addresses
+		// are, while subnet bans are listed in CIDR notation
+		bannedAddress := banString(ban)
+		if ones, bits := ban.Subnet.Mask.Size(); ones == bits {
+			bannedAddress = net.JoinHostPort(bannedAddress, "0")
+		}
+		bannedAddressMessages[i] = &appmessage.GetPeerAddressesKnownAddressMessage{Addr: bannedAddress}
 	}
 
 	response := appmessage.NewGetPeerAddressesResponseMessage(addressMessages, bannedAddressMessages)
@@ -0,0 +1,74 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// HandleGetTransaction handles the respectively named RPC command
+func HandleGetTransaction(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.TXIndex {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --txindex")
+		return errorMessage, nil
+	}
+
+	getTransactionRequest := request.(*appmessage.GetTransactionRequestMessage)
+
+	transactionID, err := transactionid.FromString(getTransactionRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	txLocation, found, err := context.TXIndex.TXLocation(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+		return errorMessage, nil
+	}
+
+	block, err := context.Domain.Consensus().GetBlock(txLocation.IncludingBlockHash)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s is in block %s, whose data is not available",
+			transactionID, txLocation.IncludingBlockHash)
+		return errorMessage, nil
+	}
+	var rpcTransaction *appmessage.RPCTransaction
+	for _, transaction := range block.Transactions {
+		if consensushashing.TransactionID(transaction).Equal(transactionID) {
+			rpcTransaction = appmessage.DomainTransactionToRPCTransaction(transaction)
+			break
+		}
+	}
+	if rpcTransaction == nil {
+		return nil, errors.Errorf("transaction %s is missing from its including block %s",
+			transactionID, txLocation.IncludingBlockHash)
+	}
+	err = context.PopulateTransactionWithVerboseData(rpcTransaction, block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptingBlockInfo, err := context.Domain.Consensus().GetBlockInfo(txLocation.AcceptingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	virtualInfo, err := context.Domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return nil, err
+	}
+	confirmations := virtualInfo.BlueScore - acceptingBlockInfo.BlueScore
+
+	return appmessage.NewGetTransactionResponseMessage(rpcTransaction, txLocation.AcceptingBlockHash.String(),
+		acceptingBlockInfo.BlueScore, confirmations), nil
+}
@@ -0,0 +1,84 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/addressindex"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util"
+)
+
+// maxGetTransactionsByAddressesLimit is the maximum amount of entries
+// returned by a single GetTransactionsByAddresses request
+const maxGetTransactionsByAddressesLimit = 1000
+
+// HandleGetTransactionsByAddresses handles the respectively named RPC command
+func HandleGetTransactionsByAddresses(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.AddressIndex {
+		errorMessage := &appmessage.GetTransactionsByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --addressindex")
+		return errorMessage, nil
+	}
+
+	getTransactionsByAddressesRequest := request.(*appmessage.GetTransactionsByAddressesRequestMessage)
+
+	limit := uint64(getTransactionsByAddressesRequest.Limit)
+	if limit == 0 {
+		limit = maxGetTransactionsByAddressesLimit
+	}
+	if limit > maxGetTransactionsByAddressesLimit {
+		errorMessage := &appmessage.GetTransactionsByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Limit %d is above the maximum of %d",
+			limit, maxGetTransactionsByAddressesLimit)
+		return errorMessage, nil
+	}
+	var startAfter *addressindex.EntryCursor
+	if getTransactionsByAddressesRequest.StartAfter != nil {
+		transactionID, err := externalapi.NewDomainTransactionIDFromString(
+			getTransactionsByAddressesRequest.StartAfter.TransactionID)
+		if err != nil {
+			errorMessage := &appmessage.GetTransactionsByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not parse the transaction ID of startAfter: %s", err)
+			return errorMessage, nil
+		}
+		startAfter = &addressindex.EntryCursor{
+			AcceptingBlockBlueScore: getTransactionsByAddressesRequest.StartAfter.AcceptingBlockBlueScore,
+			TransactionID:           transactionID,
+		}
+	}
+
+	scriptPublicKeys := make([]*externalapi.ScriptPublicKey, 0, len(getTransactionsByAddressesRequest.Addresses))
+	addressesByScriptPublicKey := make(map[utxoindex.ScriptPublicKeyString]string)
+	for _, addressString := range getTransactionsByAddressesRequest.Addresses {
+		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
+		if err != nil {
+			errorMessage := &appmessage.GetTransactionsByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not decode address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			errorMessage := &appmessage.GetTransactionsByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)
+		if _, ok := addressesByScriptPublicKey[scriptPublicKeyString]; ok {
+			continue
+		}
+		addressesByScriptPublicKey[scriptPublicKeyString] = addressString
+		scriptPublicKeys = append(scriptPublicKeys, scriptPublicKey)
+	}
+
+	transactionEntries, err := context.AddressIndex.TransactionEntries(scriptPublicKeys, startAfter, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := rpccontext.ConvertTransactionEntriesToTransactionsByAddressesEntries(addressesByScriptPublicKey, transactionEntries)
+
+	response := appmessage.NewGetTransactionsByAddressesResponseMessage(entries)
+	return response, nil
+}
@@ -0,0 +1,45 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util"
+)
+
+// HandleGetUTXOHistoryByAddresses handles the respectively named RPC command
+func HandleGetUTXOHistoryByAddresses(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.UTXOIndex {
+		errorMessage := &appmessage.GetUTXOHistoryByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --utxoindex")
+		return errorMessage, nil
+	}
+
+	getUTXOHistoryByAddressesRequest := request.(*appmessage.GetUTXOHistoryByAddressesRequestMessage)
+
+	allEntries := make([]*appmessage.UTXOHistoryByAddressesEntry, 0)
+	for _, addressString := range getUTXOHistoryByAddressesRequest.Addresses {
+		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
+		if err != nil {
+			errorMessage := &appmessage.GetUTXOHistoryByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not decode address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			errorMessage := &appmessage.GetUTXOHistoryByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		historyEntries, err := context.UTXOIndex.UTXOHistory(scriptPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		entries := rpccontext.ConvertUTXOHistoryEntriesToUTXOHistoryByAddressesEntries(addressString, historyEntries)
+		allEntries = append(allEntries, entries...)
+	}
+
+	response := appmessage.NewGetUTXOHistoryByAddressesResponseMessage(allEntries)
+	return response, nil
+}
@@ -0,0 +1,33 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleSetLogLevel handles the respectively named RPC command
+func HandleSetLogLevel(_ *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	setLogLevelRequest := request.(*appmessage.SetLogLevelRequestMessage)
+
+	var err error
+	if setLogLevelRequest.Subsystem == "" {
+		err = logger.SetLogLevelsString(setLogLevelRequest.Level)
+	} else {
+		err = logger.SetLogLevel(setLogLevelRequest.Subsystem, setLogLevelRequest.Level)
+	}
+	if err != nil {
+		errorMessage := &appmessage.SetLogLevelResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not set log level: %s", err)
+		return errorMessage, nil
+	}
+
+	if setLogLevelRequest.Subsystem == "" {
+		log.Infof("Log level of all subsystems set to %s via RPC", setLogLevelRequest.Level)
+	} else {
+		log.Infof("Log level of subsystem %s set to %s via RPC",
+			setLogLevelRequest.Subsystem, setLogLevelRequest.Level)
+	}
+	return appmessage.NewSetLogLevelResponseMessage(), nil
+}
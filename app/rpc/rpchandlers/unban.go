@@ -3,20 +3,20 @@ package rpchandlers
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"net"
 )
 
 // HandleUnban handles the respectively named RPC command
 func HandleUnban(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
 	unbanRequest := request.(*appmessage.UnbanRequestMessage)
-	ip := net.ParseIP(unbanRequest.IP)
-	if ip == nil {
+	subnet, err := addressmanager.ParseSubnet(unbanRequest.IP)
+	if err != nil {
 		errorMessage := &appmessage.UnbanResponseMessage{}
-		errorMessage.Error = appmessage.RPCErrorf("Could not parse IP %s", unbanRequest.IP)
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse IP %s: %s", unbanRequest.IP, err)
 		return errorMessage, nil
 	}
-	err := context.AddressManager.Unban(appmessage.NewNetAddressIPPort(ip, 0))
+	err = context.AddressManager.UnbanSubnet(subnet)
 	if err != nil {
 		errorMessage := &appmessage.UnbanResponseMessage{}
 		errorMessage.Error = appmessage.RPCErrorf("Could not unban IP: %s", err)
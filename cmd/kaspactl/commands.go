@@ -30,12 +30,22 @@ var commandTypes = []reflect.Type{
 
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntryRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntriesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetFeeEstimateRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetUtxosByAddressesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetUtxoHistoryByAddressesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionsByAddressesRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_BanRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_UnbanRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetBannedPeersRequest{}),
+
+	reflect.TypeOf(protowire.KaspadMessage_GetLogLevelsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_SetLogLevelRequest{}),
+
+	reflect.TypeOf(protowire.KaspadMessage_BackupDatabaseRequest{}),
 }
 
 type commandDescription struct {
@@ -18,6 +18,7 @@ type configFlags struct {
 	ListCommands         bool   `short:"l" long:"list-commands" description:"List all commands and exit"`
 	CommandAndParameters []string
 	config.NetworkFlags
+	config.RPCClientFlags
 }
 
 func parseConfig() (*configFlags, error) {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+// bucketStats holds the number of keys of a bucket and their total size
+type bucketStats struct {
+	name      string
+	keyCount  uint64
+	totalSize uint64
+}
+
+func buckets(conf *bucketsConfig) error {
+	db, err := openDatabase(conf.AppDir, conf.NetParams(), false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	statsByName, err := collectBucketStats(db)
+	if err != nil {
+		return err
+	}
+
+	allStats := make([]*bucketStats, 0, len(statsByName))
+	for _, stats := range statsByName {
+		allStats = append(allStats, stats)
+	}
+	sort.Slice(allStats, func(i, j int) bool {
+		return allStats[i].name < allStats[j].name
+	})
+
+	fmt.Printf("%-40s %12s %16s\n", "Bucket", "Keys", "Size (bytes)")
+	for _, stats := range allStats {
+		fmt.Printf("%-40s %12d %16d\n", stats.name, stats.keyCount, stats.totalSize)
+	}
+	return nil
+}
+
+// collectBucketStats goes over all the keys of the database, and sums them
+// up by the record type they belong to. Keys that don't belong to any known
+// record type are summed up by the first part of their bucket path
+func collectBucketStats(db database.DataAccessor) (map[string]*bucketStats, error) {
+	cursor, err := db.Cursor(database.MakeBucket(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	statsByName := make(map[string]*bucketStats)
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		name := recordTypeNameOfKey(key.Bytes())
+		stats, ok := statsByName[name]
+		if !ok {
+			stats = &bucketStats{name: name}
+			statsByName[name] = stats
+		}
+		stats.keyCount++
+		stats.totalSize += uint64(len(key.Bytes()) + len(value))
+	}
+	return statsByName, nil
+}
+
+// recordTypeNameOfKey returns the name of the record type the given key
+// belongs to
+func recordTypeNameOfKey(key []byte) string {
+	for _, recordType := range recordTypes {
+		if recordType.isSingleKey {
+			if bytes.Equal(key, recordType.key().Bytes()) {
+				return recordType.name
+			}
+			continue
+		}
+		if bytes.HasPrefix(key, recordType.bucket().Path()) {
+			return recordType.name
+		}
+	}
+
+	separatorIndex := bytes.IndexByte(key, '/')
+	if separatorIndex == -1 {
+		return "(unknown keys)"
+	}
+	return fmt.Sprintf("%s (unknown)", key[:separatorIndex])
+}
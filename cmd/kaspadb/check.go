@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// inconsistency is a single inconsistency that was found in the database
+type inconsistency struct {
+	description string
+
+	// repair fixes the inconsistency. It's nil if the inconsistency can't be
+	// repaired, in which case the database has to be reset
+	repair func(dbTx database.Transaction) error
+}
+
+func (i *inconsistency) isRepairable() bool {
+	return i.repair != nil
+}
+
+// blockRecordTypes are the record types that every block that isn't invalid
+// must have, whether or not its body was pruned
+var blockRecordTypes = []string{"block-headers", "block-relations", "block-ghostdag-data", "reachability-data"}
+
+// blockPointerRecordTypes are the single key record types that point to a
+// block that must be known
+var blockPointerRecordTypes = []string{"pruning-block-hash", "previous-pruning-block-hash",
+	"candidate-pruning-point-hash", "headers-selected-tip", "reachability-reindex-root"}
+
+func check(conf *checkConfig) error {
+	db, err := openDatabase(conf.AppDir, conf.NetParams(), conf.Repair)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	inconsistencies, err := findInconsistencies(db)
+	if err != nil {
+		return err
+	}
+	if len(inconsistencies) == 0 {
+		fmt.Println("No inconsistencies were found")
+		return nil
+	}
+
+	repairableCount := 0
+	for _, inconsistency := range inconsistencies {
+		repairableNote := ""
+		if inconsistency.isRepairable() {
+			repairableNote = " (repairable)"
+			repairableCount++
+		}
+		fmt.Printf("%s%s\n", inconsistency.description, repairableNote)
+	}
+	fmt.Printf("Found %d inconsistencies, of which %d are repairable\n", len(inconsistencies), repairableCount)
+
+	if conf.Repair && repairableCount > 0 {
+		err := repairInconsistencies(db, inconsistencies)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Repaired %d inconsistencies\n", repairableCount)
+	} else if repairableCount > 0 {
+		fmt.Println("Run with --repair to repair the repairable inconsistencies")
+	}
+
+	if repairableCount < len(inconsistencies) {
+		return errors.Errorf("%d inconsistencies can't be repaired. Restart kaspad with --reset-db "+
+			"to resync the node from scratch", len(inconsistencies)-repairableCount)
+	}
+	if !conf.Repair {
+		return errors.Errorf("found %d inconsistencies", len(inconsistencies))
+	}
+	return nil
+}
+
+// findInconsistencies checks the consistency of the consensus data of db
+func findInconsistencies(db database.DataAccessor) ([]*inconsistency, error) {
+	inconsistencies, err := findBlockInconsistencies(db)
+	if err != nil {
+		return nil, err
+	}
+
+	countInconsistencies, err := findCountInconsistencies(db)
+	if err != nil {
+		return nil, err
+	}
+	inconsistencies = append(inconsistencies, countInconsistencies...)
+
+	pointerInconsistencies, err := findBlockPointerInconsistencies(db)
+	if err != nil {
+		return nil, err
+	}
+	inconsistencies = append(inconsistencies, pointerInconsistencies...)
+
+	return inconsistencies, nil
+}
+
+// findBlockInconsistencies checks that every block with a status has all the
+// records that a block with that status should have
+func findBlockInconsistencies(db database.DataAccessor) ([]*inconsistency, error) {
+	blockStatuses, _ := recordTypeByName("block-statuses")
+	blocks, _ := recordTypeByName("blocks")
+
+	cursor, err := db.Cursor(blockStatuses.bucket())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var inconsistencies []*inconsistency
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		blockHash, err := externalapi.NewDomainHashFromByteSlice(key.Suffix())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the block hash of the block status with key %s", key)
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		dbBlockStatus := &serialization.DbBlockStatus{}
+		err = proto.Unmarshal(value, dbBlockStatus)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the status of block %s", blockHash)
+		}
+		blockStatus := serialization.DbBlockStatusToDomainBlockStatus(dbBlockStatus)
+
+		// Invalid blocks are stored with their status alone
+		if blockStatus == externalapi.StatusInvalid {
+			continue
+		}
+
+		for _, recordTypeName := range blockRecordTypes {
+			recordType, _ := recordTypeByName(recordTypeName)
+			exists, err := db.Has(recordType.bucket().Key(blockHash.ByteSlice()))
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				inconsistencies = append(inconsistencies, &inconsistency{
+					description: fmt.Sprintf("Block %s with status %s is missing its %s record",
+						blockHash, blockStatus, recordTypeName),
+				})
+			}
+		}
+
+		// A block whose body is missing can be regarded as a block whose body
+		// was pruned, so that it's requested again if it's needed
+		if blockStatus != externalapi.StatusHeaderOnly {
+			exists, err := db.Has(blocks.bucket().Key(blockHash.ByteSlice()))
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				statusKey := blockStatuses.bucket().Key(blockHash.ByteSlice())
+				inconsistencies = append(inconsistencies, &inconsistency{
+					description: fmt.Sprintf("Block %s with status %s is missing its body",
+						blockHash, blockStatus),
+					repair: func(dbTx database.Transaction) error {
+						headerOnlyStatusBytes, err := proto.Marshal(
+							serialization.DomainBlockStatusToDbBlockStatus(externalapi.StatusHeaderOnly))
+						if err != nil {
+							return err
+						}
+						return dbTx.Put(statusKey, headerOnlyStatusBytes)
+					},
+				})
+			}
+		}
+	}
+	return inconsistencies, nil
+}
+
+// findCountInconsistencies checks that the stored counts of blocks and
+// headers match the actual number of blocks and headers
+func findCountInconsistencies(db database.DataAccessor) ([]*inconsistency, error) {
+	var inconsistencies []*inconsistency
+	checkCount := func(countRecordTypeName string, recordTypeName string,
+		serializeCount func(count uint64) ([]byte, error)) error {
+
+		countRecordType, _ := recordTypeByName(countRecordTypeName)
+		recordType, _ := recordTypeByName(recordTypeName)
+
+		countBytes, err := db.Get(countRecordType.key())
+		if err != nil {
+			if database.IsNotFoundError(err) {
+				return nil
+			}
+			return err
+		}
+		storedCount, err := countRecordType.decodeValue(countBytes)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %s", countRecordTypeName)
+		}
+
+		actualCount, err := countKeys(db, recordType.bucket())
+		if err != nil {
+			return err
+		}
+		if storedCount.(uint64) != actualCount {
+			inconsistencies = append(inconsistencies, &inconsistency{
+				description: fmt.Sprintf("The stored %s is %d, while there are %d %s records",
+					countRecordTypeName, storedCount, actualCount, recordTypeName),
+				repair: func(dbTx database.Transaction) error {
+					actualCountBytes, err := serializeCount(actualCount)
+					if err != nil {
+						return err
+					}
+					return dbTx.Put(countRecordType.key(), actualCountBytes)
+				},
+			})
+		}
+		return nil
+	}
+
+	err := checkCount("blocks-count", "blocks", func(count uint64) ([]byte, error) {
+		return proto.Marshal(&serialization.DbBlockCount{Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = checkCount("block-headers-count", "block-headers", func(count uint64) ([]byte, error) {
+		return proto.Marshal(&serialization.DbBlockHeaderCount{Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inconsistencies, nil
+}
+
+// findBlockPointerInconsistencies checks that the blocks that the consensus
+// state points to, such as the pruning point and the tips, have statuses
+func findBlockPointerInconsistencies(db database.DataAccessor) ([]*inconsistency, error) {
+	blockStatuses, _ := recordTypeByName("block-statuses")
+
+	var inconsistencies []*inconsistency
+	checkBlockPointer := func(pointerName string, blockHashString string) error {
+		blockHash, err := externalapi.NewDomainHashFromString(blockHashString)
+		if err != nil {
+			return err
+		}
+		exists, err := db.Has(blockStatuses.bucket().Key(blockHash.ByteSlice()))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			inconsistencies = append(inconsistencies, &inconsistency{
+				description: fmt.Sprintf("The %s points to block %s, which has no status",
+					pointerName, blockHash),
+			})
+		}
+		return nil
+	}
+
+	for _, recordTypeName := range blockPointerRecordTypes {
+		recordType, _ := recordTypeByName(recordTypeName)
+		value, err := db.Get(recordType.key())
+		if err != nil {
+			if database.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		blockHashString, err := recordType.decodeValue(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s", recordTypeName)
+		}
+		err = checkBlockPointer(recordTypeName, blockHashString.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tips, _ := recordTypeByName("tips")
+	value, err := db.Get(tips.key())
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return inconsistencies, nil
+		}
+		return nil, err
+	}
+	tipHashStrings, err := tips.decodeValue(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode tips")
+	}
+	for _, tipHashString := range tipHashStrings.([]string) {
+		err := checkBlockPointer("tips", tipHashString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inconsistencies, nil
+}
+
+// repairInconsistencies repairs all the repairable inconsistencies out of
+// the given ones, in a single transaction
+func repairInconsistencies(db database.Database, inconsistencies []*inconsistency) error {
+	dbTx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTx.RollbackUnlessClosed()
+
+	for _, inconsistency := range inconsistencies {
+		if !inconsistency.isRepairable() {
+			continue
+		}
+		err := inconsistency.repair(dbTx)
+		if err != nil {
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
+func countKeys(db database.DataAccessor, bucket *database.Bucket) (uint64, error) {
+	cursor, err := db.Cursor(bucket)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close()
+
+	count := uint64(0)
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		count++
+	}
+	return count, nil
+}
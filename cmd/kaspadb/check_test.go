@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+func newTestConsensusWithBlocks(t *testing.T, testName string) (
+	tc testapi.TestConsensus, blockHashes []*externalapi.DomainHash, teardown func(keepDataDir bool)) {
+
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+	tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, testName)
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+
+	tipHash := consensusConfig.GenesisHash
+	for i := 0; i < 5; i++ {
+		tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		blockHashes = append(blockHashes, tipHash)
+	}
+	return tc, blockHashes, teardown
+}
+
+func TestFindInconsistencies(t *testing.T) {
+	tc, blockHashes, teardown := newTestConsensusWithBlocks(t, "TestFindInconsistencies")
+	defer teardown(false)
+	db := tc.Database()
+
+	inconsistencies, err := findInconsistencies(db)
+	if err != nil {
+		t.Fatalf("findInconsistencies: %+v", err)
+	}
+	if len(inconsistencies) != 0 {
+		t.Fatalf("Found inconsistencies in a consistent database: %s", inconsistencies[0].description)
+	}
+
+	// Delete the body of one block, which can be repaired, and the relations
+	// of another, which can't
+	blocks, _ := recordTypeByName("blocks")
+	err = db.Delete(blocks.bucket().Key(blockHashes[1].ByteSlice()))
+	if err != nil {
+		t.Fatalf("Delete: %+v", err)
+	}
+	blockRelations, _ := recordTypeByName("block-relations")
+	err = db.Delete(blockRelations.bucket().Key(blockHashes[2].ByteSlice()))
+	if err != nil {
+		t.Fatalf("Delete: %+v", err)
+	}
+
+	inconsistencies, err = findInconsistencies(db)
+	if err != nil {
+		t.Fatalf("findInconsistencies: %+v", err)
+	}
+	var repairable, unrepairable []*inconsistency
+	for _, inconsistency := range inconsistencies {
+		if inconsistency.isRepairable() {
+			repairable = append(repairable, inconsistency)
+		} else {
+			unrepairable = append(unrepairable, inconsistency)
+		}
+	}
+
+	// The deleted body is also reflected in the stored blocks count
+	if len(repairable) != 2 {
+		t.Fatalf("Unexpected amount of repairable inconsistencies. Want: 2, got: %d", len(repairable))
+	}
+	if len(unrepairable) != 1 || !strings.Contains(unrepairable[0].description, blockHashes[2].String()) {
+		t.Fatalf("Unexpected unrepairable inconsistencies: %v", unrepairable)
+	}
+
+	err = repairInconsistencies(db, inconsistencies)
+	if err != nil {
+		t.Fatalf("repairInconsistencies: %+v", err)
+	}
+	inconsistencies, err = findInconsistencies(db)
+	if err != nil {
+		t.Fatalf("findInconsistencies: %+v", err)
+	}
+	if len(inconsistencies) != 1 || inconsistencies[0].isRepairable() {
+		t.Fatalf("Expected only the unrepairable inconsistency to remain after the repair, got: %v",
+			inconsistencies)
+	}
+
+	blockStatuses, _ := recordTypeByName("block-statuses")
+	blockStatusBytes, err := db.Get(blockStatuses.bucket().Key(blockHashes[1].ByteSlice()))
+	if err != nil {
+		t.Fatalf("Get: %+v", err)
+	}
+	blockStatus, err := decodeBlockStatus(blockStatusBytes)
+	if err != nil {
+		t.Fatalf("decodeBlockStatus: %+v", err)
+	}
+	if blockStatus != externalapi.StatusHeaderOnly.String() {
+		t.Fatalf("The status of a block whose body is missing wasn't repaired. Want: %s, got: %s",
+			externalapi.StatusHeaderOnly, blockStatus)
+	}
+}
+
+func TestRecordTypes(t *testing.T) {
+	tc, _, teardown := newTestConsensusWithBlocks(t, "TestRecordTypes")
+	defer teardown(false)
+	db := tc.Database()
+
+	// Every key written by consensus should belong to a known record type
+	statsByName, err := collectBucketStats(db)
+	if err != nil {
+		t.Fatalf("collectBucketStats: %+v", err)
+	}
+	for name := range statsByName {
+		if _, ok := recordTypeByName(name); !ok {
+			t.Fatalf("The database contains keys of an unknown record type: %s", name)
+		}
+	}
+
+	// And every record of a known record type should decode
+	for _, recordType := range recordTypes {
+		if recordType.isSingleKey {
+			value, err := db.Get(recordType.key())
+			if err != nil {
+				if database.IsNotFoundError(err) {
+					continue
+				}
+				t.Fatalf("Get: %+v", err)
+			}
+			if recordType.decodeValue != nil {
+				_, err := recordType.decodeValue(value)
+				if err != nil {
+					t.Fatalf("Failed to decode %s: %+v", recordType.name, err)
+				}
+			}
+			continue
+		}
+
+		cursor, err := db.Cursor(recordType.bucket())
+		if err != nil {
+			t.Fatalf("Cursor: %+v", err)
+		}
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			key, err := cursor.Key()
+			if err != nil {
+				t.Fatalf("Key: %+v", err)
+			}
+			value, err := cursor.Value()
+			if err != nil {
+				t.Fatalf("Value: %+v", err)
+			}
+			if recordType.decodeKey != nil {
+				_, err := recordType.decodeKey(key.Suffix())
+				if err != nil {
+					t.Fatalf("Failed to decode the key of a %s record: %+v", recordType.name, err)
+				}
+			}
+			if recordType.decodeValue != nil {
+				_, err := recordType.decodeValue(value)
+				if err != nil {
+					t.Fatalf("Failed to decode the value of a %s record: %+v", recordType.name, err)
+				}
+			}
+		}
+		cursor.Close()
+	}
+}
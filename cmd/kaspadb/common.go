@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/logdb"
+	"github.com/pkg/errors"
+)
+
+const databaseCacheSizeMiB = 64
+
+func printErrorAndExit(err error) {
+	fmt.Fprintf(os.Stderr, "%s\n", err)
+	os.Exit(1)
+}
+
+// databasePath returns the path of the database of the given network under
+// appDir, the same way kaspad resolves it
+func databasePath(appDir string, params *dagconfig.Params) string {
+	if appDir == "" {
+		appDir = config.DefaultAppDir
+	}
+	return filepath.Join(appDir, params.Name, "data")
+}
+
+// openDatabase opens the database of the given network under appDir with
+// the backend that created it. Unless writable is set, the database is opened
+// for reading only
+func openDatabase(appDir string, params *dagconfig.Params, writable bool) (database.Database, error) {
+	path := databasePath(appDir, params)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, errors.Errorf("there's no database in '%s'", path)
+	}
+
+	isLogDB := logdb.IsLogDB(path)
+	if writable {
+		if isLogDB {
+			return logdb.NewLogDB(path)
+		}
+		return ldb.NewLevelDB(path, databaseCacheSizeMiB)
+	}
+
+	var db database.Database
+	var err error
+	if isLogDB {
+		db, err = logdb.NewLogDBReadOnly(path)
+	} else {
+		db, err = ldb.NewLevelDBReadOnly(path, databaseCacheSizeMiB)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the database in '%s'. Note that the database "+
+			"can't be opened while kaspad is running", path)
+	}
+	return db, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	bucketsSubCmd = "buckets"
+	dumpSubCmd    = "dump"
+	checkSubCmd   = "check"
+)
+
+type configFlags struct {
+	config.NetworkFlags
+}
+
+type bucketsConfig struct {
+	AppDir string `long:"appdir" short:"b" description:"The kaspad home directory (default: ~/.kaspad (*nix), %LOCALAPPDATA%\\Kaspad (Windows))"`
+	config.NetworkFlags
+}
+
+type dumpConfig struct {
+	AppDir string `long:"appdir" short:"b" description:"The kaspad home directory (default: ~/.kaspad (*nix), %LOCALAPPDATA%\\Kaspad (Windows))"`
+	Bucket string `long:"bucket" description:"The bucket to dump the records of, as listed by the buckets command" required:"true"`
+	Key    string `long:"key" short:"k" description:"Dump only the record with this key in the bucket (encoded in hex), such as a block hash"`
+	Limit  int    `long:"limit" short:"l" description:"The maximum number of records to dump. 0 dumps all of them" default:"10"`
+	config.NetworkFlags
+}
+
+type checkConfig struct {
+	AppDir string `long:"appdir" short:"b" description:"The kaspad home directory (default: ~/.kaspad (*nix), %LOCALAPPDATA%\\Kaspad (Windows))"`
+	Repair bool   `long:"repair" description:"Repair the inconsistencies that can be repaired. Back up the database before using this"`
+	config.NetworkFlags
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	cfg := &configFlags{}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+
+	bucketsConf := &bucketsConfig{}
+	parser.AddCommand(bucketsSubCmd, "Lists the buckets of the database",
+		"Lists the buckets of the database, along with the number of keys and the total size of each of them",
+		bucketsConf)
+
+	dumpConf := &dumpConfig{}
+	parser.AddCommand(dumpSubCmd, "Prints the records of a bucket",
+		"Prints the records of a bucket of the database. The records of the consensus buckets are decoded, "+
+			"and the records of the rest are printed in hex", dumpConf)
+
+	checkConf := &checkConfig{}
+	parser.AddCommand(checkSubCmd, "Checks the consistency of the database",
+		"Checks the consistency of the consensus data of the database, and repairs the inconsistencies "+
+			"that can be repaired with --repair. Unlike the other sub-commands, --repair opens the database "+
+			"for writing, so kaspad must not be running", checkConf)
+
+	_, err := parser.Parse()
+
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case bucketsSubCmd:
+		combineNetworkFlags(&bucketsConf.NetworkFlags, &cfg.NetworkFlags)
+		err := bucketsConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = bucketsConf
+	case dumpSubCmd:
+		combineNetworkFlags(&dumpConf.NetworkFlags, &cfg.NetworkFlags)
+		err := dumpConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = dumpConf
+	case checkSubCmd:
+		combineNetworkFlags(&checkConf.NetworkFlags, &cfg.NetworkFlags)
+		err := checkConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = checkConf
+	}
+
+	return parser.Command.Active.Name, config
+}
+
+func combineNetworkFlags(dst, src *config.NetworkFlags) {
+	dst.Testnet = dst.Testnet || src.Testnet
+	dst.Simnet = dst.Simnet || src.Simnet
+	dst.Devnet = dst.Devnet || src.Devnet
+	if dst.OverrideDAGParamsFile == "" {
+		dst.OverrideDAGParamsFile = src.OverrideDAGParamsFile
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// dumpedRecord is the printed form of a single record of the database. The
+// value is decoded if its record type has a decoder, and is printed in hex
+// otherwise, or if it fails to decode
+type dumpedRecord struct {
+	Key         string      `json:",omitempty"`
+	DecodedKey  interface{} `json:",omitempty"`
+	Value       interface{}
+	DecodeError string `json:",omitempty"`
+}
+
+func dump(conf *dumpConfig) error {
+	recordType, ok := recordTypeByName(conf.Bucket)
+	if !ok {
+		return errors.Errorf("unknown bucket '%s'. The buckets sub-command lists the buckets "+
+			"of the database", conf.Bucket)
+	}
+
+	db, err := openDatabase(conf.AppDir, conf.NetParams(), false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if recordType.isSingleKey || conf.Key != "" {
+		key := recordType.key()
+		if !recordType.isSingleKey {
+			suffix, err := hex.DecodeString(conf.Key)
+			if err != nil {
+				return errors.Wrapf(err, "the key must be encoded in hex")
+			}
+			key = recordType.bucket().Key(suffix)
+		}
+		value, err := db.Get(key)
+		if err != nil {
+			if database.IsNotFoundError(err) {
+				return errors.Errorf("there's no record with key %s in '%s'", conf.Key, recordType.name)
+			}
+			return err
+		}
+		return printRecord(recordType, key.Suffix(), value)
+	}
+
+	cursor, err := db.Cursor(recordType.bucket())
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	dumpedCount := 0
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		if conf.Limit != 0 && dumpedCount == conf.Limit {
+			fmt.Fprintf(os.Stderr, "Dumped the first %d records. Use --limit to dump more\n", conf.Limit)
+			break
+		}
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		err = printRecord(recordType, key.Suffix(), value)
+		if err != nil {
+			return err
+		}
+		dumpedCount++
+	}
+	return nil
+}
+
+func printRecord(recordType *recordType, keySuffix []byte, value []byte) error {
+	record := &dumpedRecord{}
+	if !recordType.isSingleKey {
+		record.Key = hex.EncodeToString(keySuffix)
+		if recordType.decodeKey != nil {
+			decodedKey, err := recordType.decodeKey(keySuffix)
+			if err != nil {
+				record.DecodeError = fmt.Sprintf("failed to decode the key: %s", err)
+			}
+			record.DecodedKey = decodedKey
+		}
+	}
+
+	record.Value = hex.EncodeToString(value)
+	if recordType.decodeValue != nil && record.DecodeError == "" {
+		decodedValue, err := recordType.decodeValue(value)
+		if err != nil {
+			record.DecodeError = fmt.Sprintf("failed to decode the value: %s", err)
+		} else {
+			record.Value = decodedValue
+		}
+	}
+
+	recordJSON, err := json.MarshalIndent(record, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(recordJSON))
+	return nil
+}
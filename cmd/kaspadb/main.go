@@ -0,0 +1,23 @@
+package main
+
+import "github.com/pkg/errors"
+
+func main() {
+	subCmd, config := parseCommandLine()
+
+	var err error
+	switch subCmd {
+	case bucketsSubCmd:
+		err = buckets(config.(*bucketsConfig))
+	case dumpSubCmd:
+		err = dump(config.(*dumpConfig))
+	case checkSubCmd:
+		err = check(config.(*checkConfig))
+	default:
+		err = errors.Errorf("Unknown sub-command '%s'\n", subCmd)
+	}
+
+	if err != nil {
+		printErrorAndExit(err)
+	}
+}
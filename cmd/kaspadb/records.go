@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/hex"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+// recordType describes the records that are kept either in a single bucket of
+// the database, or in a single key outside of any bucket
+type recordType struct {
+	name        string
+	isSingleKey bool
+
+	// decodeKey decodes the key suffix of a record in the bucket. It's nil
+	// if the key suffix should simply be printed in hex, as in the case of
+	// block hashes
+	decodeKey func(suffix []byte) (interface{}, error)
+
+	// decodeValue decodes the value of a record. It's nil if the value
+	// should simply be printed in hex
+	decodeValue func(value []byte) (interface{}, error)
+}
+
+// bucket returns the bucket of the records of this type
+func (rt *recordType) bucket() *database.Bucket {
+	return database.MakeBucket([]byte(rt.name))
+}
+
+// key returns the key of a single key record type
+func (rt *recordType) key() *database.Key {
+	return database.MakeBucket(nil).Key([]byte(rt.name))
+}
+
+// recordTypes lists the records that kaspad keeps in its database, and how to
+// decode them. The names mirror the buckets and keys that are defined by each
+// of the stores that write to the database, and must be updated along with them
+var recordTypes = []*recordType{
+	// Consensus
+	{name: "block-statuses", decodeValue: decodeBlockStatus},
+	{name: "block-headers", decodeValue: decodeBlockHeader},
+	{name: "block-headers-count", isSingleKey: true, decodeValue: decodeBlockHeaderCount},
+	{name: "blocks", decodeValue: decodeBlock},
+	{name: "blocks-count", isSingleKey: true, decodeValue: decodeBlockCount},
+	{name: "block-relations", decodeValue: decodeBlockRelations},
+	{name: "block-ghostdag-data", decodeValue: decodeBlockGHOSTDAGData},
+	{name: "reachability-data", decodeValue: decodeReachabilityData},
+	{name: "reachability-reindex-root", isSingleKey: true, decodeValue: decodeHash},
+	{name: "utxo-diffs", decodeValue: decodeUTXODiff},
+	{name: "utxo-diff-children", decodeValue: decodeHash},
+	{name: "virtual-utxo-set", decodeKey: decodeOutpoint, decodeValue: decodeUTXOEntry},
+	{name: "tips", isSingleKey: true, decodeValue: decodeTips},
+	{name: "importing-pruning-point-utxo-set", isSingleKey: true},
+	{name: "pruning-block-hash", isSingleKey: true, decodeValue: decodeHash},
+	{name: "previous-pruning-block-hash", isSingleKey: true, decodeValue: decodeHash},
+	{name: "candidate-pruning-point-hash", isSingleKey: true, decodeValue: decodeHash},
+	{name: "pruning-point-utxo-set", decodeKey: decodeOutpoint, decodeValue: decodeUTXOEntry},
+	{name: "updating-pruning-point-utxo-set", isSingleKey: true},
+	{name: "imported-pruning-point-utxos", decodeKey: decodeOutpoint, decodeValue: decodeUTXOEntry},
+	{name: "imported-pruning-point-multiset", isSingleKey: true},
+	{name: "headers-selected-tip", isSingleKey: true, decodeValue: decodeHash},
+	{name: "chain-block-hash-by-index"},
+	{name: "chain-block-index-by-hash"},
+	{name: "highest-chain-block-index", isSingleKey: true},
+	{name: "acceptance-data"},
+	{name: "multisets"},
+	{name: "finality-points"},
+	{name: "daa-score"},
+	{name: "daa-added-blocks"},
+
+	// Indexes
+	{name: "utxo-index"},
+	{name: "utxo-index-history"},
+	{name: "utxo-index-virtual-parents", isSingleKey: true},
+	{name: "tx-index"},
+	{name: "tx-index-virtual-selected-parent", isSingleKey: true},
+	{name: "address-index"},
+	{name: "address-index-chain-blocks"},
+	{name: "address-index-virtual-selected-parent", isSingleKey: true},
+
+	// Address manager
+	{name: "not-banned-addresses"},
+	{name: "bans"},
+	{name: "banned-addresses"},
+	{name: "ban-scores"},
+	{name: "address-manager-secret-key", isSingleKey: true},
+
+	// Database migrations
+	{name: "migration-checkpoint", isSingleKey: true},
+}
+
+// recordTypeByName returns the record type with the given name, or false if
+// there's no such record type
+func recordTypeByName(name string) (*recordType, bool) {
+	for _, recordType := range recordTypes {
+		if recordType.name == name {
+			return recordType, true
+		}
+	}
+	return nil, false
+}
+
+type blockRelationsRecord struct {
+	Parents  []string
+	Children []string
+}
+
+type blockGHOSTDAGDataRecord struct {
+	BlueScore          uint64
+	BlueWork           string
+	SelectedParent     string
+	MergeSetBlues      []string
+	MergeSetReds       []string
+	BluesAnticoneSizes map[string]uint8
+}
+
+type reachabilityDataRecord struct {
+	Parent            string
+	Children          []string
+	Interval          string
+	FutureCoveringSet []string
+}
+
+type utxoRecord struct {
+	Outpoint string
+	Entry    *utxoEntryRecord
+}
+
+type utxoEntryRecord struct {
+	Amount          uint64
+	ScriptPublicKey string
+	Version         uint16
+	BlockDAAScore   uint64
+	IsCoinbase      bool
+}
+
+type utxoDiffRecord struct {
+	ToAdd    []*utxoRecord
+	ToRemove []*utxoRecord
+}
+
+func decodeBlockStatus(value []byte) (interface{}, error) {
+	dbBlockStatus := &serialization.DbBlockStatus{}
+	err := proto.Unmarshal(value, dbBlockStatus)
+	if err != nil {
+		return nil, err
+	}
+	return serialization.DbBlockStatusToDomainBlockStatus(dbBlockStatus).String(), nil
+}
+
+func decodeBlockHeader(value []byte) (interface{}, error) {
+	dbBlockHeader := &serialization.DbBlockHeader{}
+	err := proto.Unmarshal(value, dbBlockHeader)
+	if err != nil {
+		return nil, err
+	}
+	header, err := serialization.DbBlockHeaderToDomainBlockHeader(dbBlockHeader)
+	if err != nil {
+		return nil, err
+	}
+	return appmessage.DomainBlockToRPCBlock(&externalapi.DomainBlock{Header: header}).Header, nil
+}
+
+func decodeBlockHeaderCount(value []byte) (interface{}, error) {
+	dbBlockHeaderCount := &serialization.DbBlockHeaderCount{}
+	err := proto.Unmarshal(value, dbBlockHeaderCount)
+	if err != nil {
+		return nil, err
+	}
+	return dbBlockHeaderCount.Count, nil
+}
+
+func decodeBlock(value []byte) (interface{}, error) {
+	dbBlock := &serialization.DbBlock{}
+	err := proto.Unmarshal(value, dbBlock)
+	if err != nil {
+		return nil, err
+	}
+	block, err := serialization.DbBlockToDomainBlock(dbBlock)
+	if err != nil {
+		return nil, err
+	}
+	return appmessage.DomainBlockToRPCBlock(block), nil
+}
+
+func decodeBlockCount(value []byte) (interface{}, error) {
+	dbBlockCount := &serialization.DbBlockCount{}
+	err := proto.Unmarshal(value, dbBlockCount)
+	if err != nil {
+		return nil, err
+	}
+	return dbBlockCount.Count, nil
+}
+
+func decodeBlockRelations(value []byte) (interface{}, error) {
+	dbBlockRelations := &serialization.DbBlockRelations{}
+	err := proto.Unmarshal(value, dbBlockRelations)
+	if err != nil {
+		return nil, err
+	}
+	blockRelations, err := serialization.DbBlockRelationsToDomainBlockRelations(dbBlockRelations)
+	if err != nil {
+		return nil, err
+	}
+	return &blockRelationsRecord{
+		Parents:  hashesToStrings(blockRelations.Parents),
+		Children: hashesToStrings(blockRelations.Children),
+	}, nil
+}
+
+func decodeBlockGHOSTDAGData(value []byte) (interface{}, error) {
+	dbBlockGHOSTDAGData := &serialization.DbBlockGhostdagData{}
+	err := proto.Unmarshal(value, dbBlockGHOSTDAGData)
+	if err != nil {
+		return nil, err
+	}
+	blockGHOSTDAGData, err := serialization.DBBlockGHOSTDAGDataToBlockGHOSTDAGData(dbBlockGHOSTDAGData)
+	if err != nil {
+		return nil, err
+	}
+
+	bluesAnticoneSizes := make(map[string]uint8, len(blockGHOSTDAGData.BluesAnticoneSizes()))
+	for blueHash, anticoneSize := range blockGHOSTDAGData.BluesAnticoneSizes() {
+		bluesAnticoneSizes[blueHash.String()] = uint8(anticoneSize)
+	}
+	selectedParent := ""
+	if blockGHOSTDAGData.SelectedParent() != nil {
+		selectedParent = blockGHOSTDAGData.SelectedParent().String()
+	}
+	return &blockGHOSTDAGDataRecord{
+		BlueScore:          blockGHOSTDAGData.BlueScore(),
+		BlueWork:           blockGHOSTDAGData.BlueWork().Text(16),
+		SelectedParent:     selectedParent,
+		MergeSetBlues:      hashesToStrings(blockGHOSTDAGData.MergeSetBlues()),
+		MergeSetReds:       hashesToStrings(blockGHOSTDAGData.MergeSetReds()),
+		BluesAnticoneSizes: bluesAnticoneSizes,
+	}, nil
+}
+
+func decodeReachabilityData(value []byte) (interface{}, error) {
+	dbReachabilityData := &serialization.DbReachabilityData{}
+	err := proto.Unmarshal(value, dbReachabilityData)
+	if err != nil {
+		return nil, err
+	}
+	reachabilityData, err := serialization.DBReachablityDataToReachablityData(dbReachabilityData)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := ""
+	if reachabilityData.Parent() != nil {
+		parent = reachabilityData.Parent().String()
+	}
+	return &reachabilityDataRecord{
+		Parent:            parent,
+		Children:          hashesToStrings(reachabilityData.Children()),
+		Interval:          reachabilityData.Interval().String(),
+		FutureCoveringSet: hashesToStrings(reachabilityData.FutureCoveringSet()),
+	}, nil
+}
+
+func decodeUTXODiff(value []byte) (interface{}, error) {
+	dbUTXODiff := &serialization.DbUtxoDiff{}
+	err := proto.Unmarshal(value, dbUTXODiff)
+	if err != nil {
+		return nil, err
+	}
+	utxoDiff, err := serialization.DBUTXODiffToUTXODiff(dbUTXODiff)
+	if err != nil {
+		return nil, err
+	}
+
+	toAdd, err := utxoCollectionToRecords(utxoDiff.ToAdd())
+	if err != nil {
+		return nil, err
+	}
+	toRemove, err := utxoCollectionToRecords(utxoDiff.ToRemove())
+	if err != nil {
+		return nil, err
+	}
+	return &utxoDiffRecord{
+		ToAdd:    toAdd,
+		ToRemove: toRemove,
+	}, nil
+}
+
+func decodeOutpoint(suffix []byte) (interface{}, error) {
+	dbOutpoint := &serialization.DbOutpoint{}
+	err := proto.Unmarshal(suffix, dbOutpoint)
+	if err != nil {
+		return nil, err
+	}
+	outpoint, err := serialization.DbOutpointToDomainOutpoint(dbOutpoint)
+	if err != nil {
+		return nil, err
+	}
+	return outpoint.String(), nil
+}
+
+func decodeUTXOEntry(value []byte) (interface{}, error) {
+	dbUTXOEntry := &serialization.DbUtxoEntry{}
+	err := proto.Unmarshal(value, dbUTXOEntry)
+	if err != nil {
+		return nil, err
+	}
+	utxoEntry, err := serialization.DBUTXOEntryToUTXOEntry(dbUTXOEntry)
+	if err != nil {
+		return nil, err
+	}
+	return utxoEntryToRecord(utxoEntry), nil
+}
+
+func decodeHash(value []byte) (interface{}, error) {
+	dbHash := &serialization.DbHash{}
+	err := proto.Unmarshal(value, dbHash)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := serialization.DbHashToDomainHash(dbHash)
+	if err != nil {
+		return nil, err
+	}
+	return hash.String(), nil
+}
+
+func decodeTips(value []byte) (interface{}, error) {
+	dbTips := &serialization.DbTips{}
+	err := proto.Unmarshal(value, dbTips)
+	if err != nil {
+		return nil, err
+	}
+	tips, err := serialization.DBTipsToTips(dbTips)
+	if err != nil {
+		return nil, err
+	}
+	return hashesToStrings(tips), nil
+}
+
+func utxoCollectionToRecords(utxoCollection externalapi.UTXOCollection) ([]*utxoRecord, error) {
+	utxoRecords := make([]*utxoRecord, 0, utxoCollection.Len())
+	iterator := utxoCollection.Iterator()
+	defer iterator.Close()
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		outpoint, utxoEntry, err := iterator.Get()
+		if err != nil {
+			return nil, err
+		}
+		utxoRecords = append(utxoRecords, &utxoRecord{
+			Outpoint: outpoint.String(),
+			Entry:    utxoEntryToRecord(utxoEntry),
+		})
+	}
+	return utxoRecords, nil
+}
+
+func utxoEntryToRecord(utxoEntry externalapi.UTXOEntry) *utxoEntryRecord {
+	return &utxoEntryRecord{
+		Amount:          utxoEntry.Amount(),
+		ScriptPublicKey: hex.EncodeToString(utxoEntry.ScriptPublicKey().Script),
+		Version:         utxoEntry.ScriptPublicKey().Version,
+		BlockDAAScore:   utxoEntry.BlockDAAScore(),
+		IsCoinbase:      utxoEntry.IsCoinbase(),
+	}
+}
+
+func hashesToStrings(hashes []*externalapi.DomainHash) []string {
+	strings := make([]string, len(hashes))
+	for i, hash := range hashes {
+		strings[i] = hash.String()
+	}
+	return strings
+}
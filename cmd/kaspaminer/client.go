@@ -22,7 +22,11 @@ func (mc *minerClient) connect() error {
 	if err != nil {
 		return err
 	}
-	rpcClient, err := rpcclient.NewRPCClient(rpcAddress)
+	connectOptions, err := mc.cfg.ConnectOptions()
+	if err != nil {
+		return err
+	}
+	rpcClient, err := rpcclient.NewRPCClientWithOptions(rpcAddress, connectOptions)
 	if err != nil {
 		return err
 	}
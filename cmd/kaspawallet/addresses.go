@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/kaspanet/kaspad/util"
+)
+
+// addressGapLimit is the number of addresses beyond the last used index of every keychain
+// that are scanned for funds, so that a wallet restored from a mnemonic finds its UTXOs.
+// See https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki#address-gap-limit
+const addressGapLimit = 20
+
+// walletAddress is an address of the wallet along with its location in the wallet
+// derivation tree.
+type walletAddress struct {
+	address        util.Address
+	derivationPath string
+	keychain       uint32
+	index          uint32
+}
+
+func walletAddressAt(params *dagconfig.Params, keysFile *keys.Data, keychain uint32, index uint32) (*walletAddress, error) {
+	derivationPath := libkaspawallet.AddressDerivationPath(keysFile.IsMultisig(), keysFile.CosignerIndex, keychain, index)
+	address, err := libkaspawallet.Address(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+		derivationPath, keysFile.ECDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletAddress{
+		address:        address,
+		derivationPath: derivationPath,
+		keychain:       keychain,
+		index:          index,
+	}, nil
+}
+
+func lastUsedIndex(keysFile *keys.Data, keychain uint32) uint32 {
+	if keychain == libkaspawallet.InternalKeychain {
+		return keysFile.LastUsedInternalIndex
+	}
+	return keysFile.LastUsedExternalIndex
+}
+
+// walletAddresses returns all the addresses of the wallet up to the last used index of
+// each keychain, plus addressGapLimit addresses beyond it.
+func walletAddresses(params *dagconfig.Params, keysFile *keys.Data) ([]*walletAddress, error) {
+	var addresses []*walletAddress
+	for _, keychain := range []uint32{libkaspawallet.ExternalKeychain, libkaspawallet.InternalKeychain} {
+		for index := uint32(0); index <= lastUsedIndex(keysFile, keychain)+addressGapLimit; index++ {
+			address, err := walletAddressAt(params, keysFile, keychain, index)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses, nil
+}
+
+// nextWalletAddress generates the next unused address of the given keychain and
+// persists the new last used index into the keys file.
+func nextWalletAddress(params *dagconfig.Params, keysFile *keys.Data, keychain uint32) (*walletAddress, error) {
+	if keychain == libkaspawallet.InternalKeychain {
+		keysFile.LastUsedInternalIndex++
+	} else {
+		keysFile.LastUsedExternalIndex++
+	}
+
+	err := keysFile.Save()
+	if err != nil {
+		return nil, err
+	}
+
+	return walletAddressAt(params, keysFile, keychain, lastUsedIndex(keysFile, keychain))
+}
+
+// fetchWalletUTXOs fetches the UTXOs of all the wallet addresses. It returns the UTXOs along
+// with a map from every wallet address string to its walletAddress. If funds are found beyond
+// the last used index of a keychain, the keys file is updated accordingly.
+func fetchWalletUTXOs(params *dagconfig.Params, client *rpcclient.RPCClient, keysFile *keys.Data) (
+	[]*appmessage.UTXOsByAddressesEntry, map[string]*walletAddress, error) {
+
+	addressStrings, addressesByString, err := walletAddressesByString(params, keysFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses(addressStrings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = updateLastUsedIndexes(keysFile, getUTXOsByAddressesResponse.Entries, addressesByString)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return getUTXOsByAddressesResponse.Entries, addressesByString, nil
+}
+
+// walletAddressesByString returns the strings of all the wallet addresses, along with a map
+// from every one of them to its walletAddress.
+func walletAddressesByString(params *dagconfig.Params, keysFile *keys.Data) (
+	[]string, map[string]*walletAddress, error) {
+
+	addresses, err := walletAddresses(params, keysFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addressStrings := make([]string, len(addresses))
+	addressesByString := make(map[string]*walletAddress, len(addresses))
+	for i, address := range addresses {
+		addressStrings[i] = address.address.String()
+		addressesByString[addressStrings[i]] = address
+	}
+
+	return addressStrings, addressesByString, nil
+}
+
+// updateLastUsedIndexes updates the last used indexes of the keys file, and saves it, if any of
+// the given UTXOs is paid to an address beyond them.
+func updateLastUsedIndexes(keysFile *keys.Data, entries []*appmessage.UTXOsByAddressesEntry,
+	addressesByString map[string]*walletAddress) error {
+
+	isKeysFileUpdated := false
+	for _, entry := range entries {
+		address := addressesByString[entry.Address]
+		if address.keychain == libkaspawallet.InternalKeychain && address.index > keysFile.LastUsedInternalIndex {
+			keysFile.LastUsedInternalIndex = address.index
+			isKeysFileUpdated = true
+		}
+		if address.keychain == libkaspawallet.ExternalKeychain && address.index > keysFile.LastUsedExternalIndex {
+			keysFile.LastUsedExternalIndex = address.index
+			isKeysFileUpdated = true
+		}
+	}
+
+	if isKeysFileUpdated {
+		return keysFile.Save()
+	}
+	return nil
+}
@@ -4,12 +4,11 @@ import (
 	"fmt"
 
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
-	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
 	"github.com/kaspanet/kaspad/util"
 )
 
 func balance(conf *balanceConfig) error {
-	client, err := connectToRPC(conf.NetParams(), conf.RPCServer)
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
 	if err != nil {
 		return err
 	}
@@ -19,12 +18,7 @@ func balance(conf *balanceConfig) error {
 		return err
 	}
 
-	addr, err := libkaspawallet.Address(conf.NetParams(), keysFile.PublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA)
-	if err != nil {
-		return err
-	}
-
-	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses([]string{addr.String()})
+	utxos, _, err := fetchWalletUTXOs(conf.NetParams(), client, keysFile)
 	if err != nil {
 		return err
 	}
@@ -34,7 +28,7 @@ func balance(conf *balanceConfig) error {
 	}
 
 	var availableBalance, pendingBalance uint64
-	for _, entry := range getUTXOsByAddressesResponse.Entries {
+	for _, entry := range utxos {
 		if isUTXOSpendable(entry, blockDAGInfo.VirtualDAAScore, conf.ActiveNetParams.BlockCoinbaseMaturity) {
 			availableBalance += entry.UTXOEntry.Amount
 		} else {
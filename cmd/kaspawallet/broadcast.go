@@ -7,7 +7,7 @@ import (
 )
 
 func broadcast(conf *broadcastConfig) error {
-	client, err := connectToRPC(conf.NetParams(), conf.RPCServer)
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+func bumpFee(conf *bumpFeeConfig) error {
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+	if keysFile.WatchOnly {
+		return errors.Wrap(keys.ErrWatchOnly, "the replacement transaction has to be signed")
+	}
+
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
+	if err != nil {
+		return err
+	}
+
+	getMempoolEntryResponse, err := client.GetMempoolEntry(conf.TransactionID)
+	if err != nil {
+		return errors.Wrapf(err, "error fetching transaction %s from the mempool", conf.TransactionID)
+	}
+	originalFee := getMempoolEntryResponse.Entry.Fee
+	originalTx, err := appmessage.RPCTransactionToDomainTransaction(getMempoolEntryResponse.Entry.Transaction)
+	if err != nil {
+		return err
+	}
+	if !relaypolicy.IsReplaceable(originalTx) {
+		return errors.Errorf("transaction %s did not opt in to be replaced. Only transactions that were "+
+			"created with --replaceable can be replaced", conf.TransactionID)
+	}
+
+	utxoEntries, addressesByString, err := fetchWalletUTXOs(conf.NetParams(), client, keysFile)
+	if err != nil {
+		return err
+	}
+	utxosByOutpoint := make(map[externalapi.DomainOutpoint]*libkaspawallet.UTXO, len(utxoEntries))
+	for _, entry := range utxoEntries {
+		utxo, err := rpcUTXOToWalletUTXO(entry, addressesByString[entry.Address].derivationPath)
+		if err != nil {
+			return err
+		}
+		utxosByOutpoint[*utxo.Outpoint] = utxo
+	}
+
+	utxos := make([]*libkaspawallet.UTXO, len(originalTx.Inputs))
+	for i, input := range originalTx.Inputs {
+		utxo, ok := utxosByOutpoint[input.PreviousOutpoint]
+		if !ok {
+			return errors.Errorf("transaction %s spends %s, which is not a UTXO of this wallet",
+				conf.TransactionID, input.PreviousOutpoint)
+		}
+		utxos[i] = utxo
+	}
+
+	changeOutputIndex := -1
+	for i, output := range originalTx.Outputs {
+		_, address, err := txscript.ExtractScriptPubKeyAddress(output.ScriptPublicKey, conf.NetParams())
+		if err != nil || address == nil {
+			continue
+		}
+		walletAddress, ok := addressesByString[address.String()]
+		if ok && walletAddress.keychain == libkaspawallet.InternalKeychain {
+			changeOutputIndex = i
+			break
+		}
+	}
+	if changeOutputIndex == -1 {
+		return errors.Errorf("transaction %s has no change output to pay the additional fee from",
+			conf.TransactionID)
+	}
+
+	unsignedTransaction, fee, err := libkaspawallet.CreateReplacementTransaction(conf.NetParams(),
+		keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA, utxos, originalTx.Outputs,
+		changeOutputIndex, originalFee, conf.FeeRate)
+	if err != nil {
+		return err
+	}
+
+	mnemonics, err := keysFile.DecryptMnemonics()
+	if err != nil {
+		return err
+	}
+	signedTransaction, err := libkaspawallet.Sign(conf.NetParams(), mnemonics, unsignedTransaction, keysFile.ECDSA)
+	if err != nil {
+		return err
+	}
+	tx, err := libkaspawallet.ExtractTransaction(signedTransaction)
+	if err != nil {
+		return err
+	}
+
+	transactionID, err := sendTransaction(client, tx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Replacement transaction was sent successfully")
+	fmt.Printf("Transaction ID: \t%s\n", transactionID)
+	fmt.Printf("Fee: \t\t\tKAS %f (was KAS %f)\n", float64(fee)/util.SompiPerKaspa,
+		float64(originalFee)/util.SompiPerKaspa)
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
 	"os"
 )
@@ -21,11 +22,17 @@ func printErrorAndExit(err error) {
 	os.Exit(1)
 }
 
-func connectToRPC(params *dagconfig.Params, rpcServer string) (*rpcclient.RPCClient, error) {
+func connectToRPC(params *dagconfig.Params, rpcServer string, rpcClientFlags *config.RPCClientFlags) (
+	*rpcclient.RPCClient, error) {
+
 	rpcAddress, err := params.NormalizeRPCServerAddress(rpcServer)
 	if err != nil {
 		return nil, err
 	}
+	connectOptions, err := rpcClientFlags.ConnectOptions()
+	if err != nil {
+		return nil, err
+	}
 
-	return rpcclient.NewRPCClient(rpcAddress)
+	return rpcclient.NewRPCClientWithOptions(rpcAddress, connectOptions)
 }
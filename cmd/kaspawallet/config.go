@@ -17,8 +17,14 @@ const (
 	broadcastSubCmd                 = "broadcast"
 	showAddressSubCmd               = "show-address"
 	dumpUnencryptedDataSubCmd       = "dump-unencrypted-data"
+	startDaemonSubCmd               = "start-daemon"
+	historySubCmd                   = "history"
+	bumpFeeSubCmd                   = "bump-fee"
+	sweepLegacySubCmd               = "sweep-legacy"
 )
 
+const defaultDaemonListen = "localhost:8082"
+
 type configFlags struct {
 	config.NetworkFlags
 }
@@ -29,7 +35,9 @@ type createConfig struct {
 	NumPrivateKeys    uint32 `long:"num-private-keys" short:"k" description:"Number of private keys" default:"1"`
 	NumPublicKeys     uint32 `long:"num-public-keys" short:"n" description:"Total number of keys" default:"1"`
 	ECDSA             bool   `long:"ecdsa" description:"Create an ECDSA wallet"`
-	Import            bool   `long:"import" short:"i" description:"Import private keys (as opposed to generating them)"`
+	Import            bool   `long:"import" short:"i" description:"Import mnemonics (as opposed to generating them)"`
+	WatchOnly         bool   `long:"watch-only" short:"w" description:"Create a watch-only wallet out of extended public keys alone. It can show the balance and addresses and create unsigned transactions, but can't sign them"`
+	CosignerIndex     uint32 `long:"cosigner-index" description:"The cosigner index of a watch-only multisig wallet. Should match the cosigner index of the wallet it watches, so that both generate the same addresses"`
 	config.NetworkFlags
 }
 
@@ -37,22 +45,29 @@ type balanceConfig struct {
 	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
 	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
 	config.NetworkFlags
+	config.RPCClientFlags
 }
 
 type sendConfig struct {
-	KeysFile   string  `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
-	RPCServer  string  `long:"rpcserver" short:"s" description:"RPC server to connect to"`
-	ToAddress  string  `long:"to-address" short:"t" description:"The public address to send Kaspa to" required:"true"`
-	SendAmount float64 `long:"send-amount" short:"v" description:"An amount to send in Kaspa (e.g. 1234.12345678)" required:"true"`
+	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	paymentsFlags
+	FeeRate       float64 `long:"fee-rate" description:"The fee rate to pay in sompi/gram" default:"1"`
+	CoinSelection string  `long:"coin-selection" description:"The coin selection strategy: branch-and-bound or largest-first" default:"branch-and-bound"`
+	Replaceable   bool    `long:"replaceable" description:"Allow replacing the transaction with one that pays a higher fee, using bump-fee"`
 	config.NetworkFlags
+	config.RPCClientFlags
 }
 
 type createUnsignedTransactionConfig struct {
-	KeysFile   string  `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
-	RPCServer  string  `long:"rpcserver" short:"s" description:"RPC server to connect to"`
-	ToAddress  string  `long:"to-address" short:"t" description:"The public address to send Kaspa to" required:"true"`
-	SendAmount float64 `long:"send-amount" short:"v" description:"An amount to send in Kaspa (e.g. 1234.12345678)" required:"true"`
+	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	paymentsFlags
+	FeeRate       float64 `long:"fee-rate" description:"The fee rate to pay in sompi/gram" default:"1"`
+	CoinSelection string  `long:"coin-selection" description:"The coin selection strategy: branch-and-bound or largest-first" default:"branch-and-bound"`
+	Replaceable   bool    `long:"replaceable" description:"Allow replacing the transaction with one that pays a higher fee, using bump-fee"`
 	config.NetworkFlags
+	config.RPCClientFlags
 }
 
 type signConfig struct {
@@ -65,10 +80,12 @@ type broadcastConfig struct {
 	RPCServer   string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
 	Transaction string `long:"transaction" short:"t" description:"The signed transaction to broadcast (encoded in hex)" required:"true"`
 	config.NetworkFlags
+	config.RPCClientFlags
 }
 
 type showAddressConfig struct {
 	KeysFile string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	New      bool   `long:"new" short:"n" description:"Generate a new receive address instead of showing the current one"`
 	config.NetworkFlags
 }
 
@@ -77,13 +94,50 @@ type dumpUnencryptedDataConfig struct {
 	config.NetworkFlags
 }
 
+type historyConfig struct {
+	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	Address   string `long:"address" short:"a" description:"Show the ledger of this address alone instead of the whole wallet"`
+	config.NetworkFlags
+	config.RPCClientFlags
+}
+
+type bumpFeeConfig struct {
+	KeysFile      string  `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer     string  `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	TransactionID string  `long:"transaction-id" short:"t" description:"The ID of the transaction to replace. It must still be in the mempool" required:"true"`
+	FeeRate       float64 `long:"fee-rate" description:"The new fee rate to pay in sompi/gram" required:"true"`
+	config.NetworkFlags
+	config.RPCClientFlags
+}
+
+type sweepLegacyConfig struct {
+	LegacyKeysFile string  `long:"legacy-keys-file" short:"f" description:"Legacy keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer      string  `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	ToAddress      string  `long:"to-address" short:"t" description:"The address to send the funds to, such as an address of a new wallet" required:"true"`
+	FeeRate        float64 `long:"fee-rate" description:"The fee rate to pay in sompi/gram" default:"1"`
+	config.NetworkFlags
+	config.RPCClientFlags
+}
+
+type startDaemonConfig struct {
+	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
+	Listen    string `long:"listen" short:"l" description:"Address to listen on for the daemon gRPC API. Addresses other than loopback ones require --auth-token or --tls-cert"`
+	AuthToken string `long:"auth-token" default-mask:"-" description:"Token that clients of the daemon gRPC API must authenticate with"`
+	TLSCert   string `long:"tls-cert" description:"File containing the TLS certificate of the daemon gRPC API. A self-signed certificate pair is generated at --tls-cert and --tls-key if neither exists"`
+	TLSKey    string `long:"tls-key" description:"File containing the TLS key of the daemon gRPC API"`
+	config.NetworkFlags
+	config.RPCClientFlags
+}
+
 func parseCommandLine() (subCommand string, config interface{}) {
 	cfg := &configFlags{}
 	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
 
 	createConf := &createConfig{}
 	parser.AddCommand(createSubCmd, "Creates a new wallet",
-		"Creates a private key and 3 public addresses, one for each of MainNet, TestNet and DevNet", createConf)
+		"Creates a new hierarchical deterministic wallet from newly generated or imported mnemonics", createConf)
 
 	balanceConf := &balanceConfig{}
 	parser.AddCommand(balanceSubCmd, "Shows the balance of a public address",
@@ -106,14 +160,38 @@ func parseCommandLine() (subCommand string, config interface{}) {
 		"Broadcast the given transaction", broadcastConf)
 
 	showAddressConf := &showAddressConfig{}
-	parser.AddCommand(showAddressSubCmd, "Shows the public address of the current wallet",
-		"Shows the public address of the current wallet", showAddressConf)
+	parser.AddCommand(showAddressSubCmd, "Shows the receive address of the current wallet",
+		"Shows the current receive address of the wallet, or generates a new one with --new", showAddressConf)
 
 	dumpUnencryptedDataConf := &dumpUnencryptedDataConfig{}
 	parser.AddCommand(dumpUnencryptedDataSubCmd, "Prints the unencrypted wallet data",
-		"Prints the unencrypted wallet data including its private keys. Anyone that sees it can access "+
+		"Prints the unencrypted wallet data including its mnemonics. Anyone that sees it can access "+
 			"the funds. Use only on safe environment.", dumpUnencryptedDataConf)
 
+	historyConf := &historyConfig{}
+	parser.AddCommand(historySubCmd, "Shows the transaction history of the wallet",
+		"Shows the incoming and outgoing transactions of the wallet, or of a single address with --address. "+
+			"Requires a node that runs with --utxoindex, and only shows transactions since the pruning point "+
+			"the node had when its UTXO index was first built", historyConf)
+
+	bumpFeeConf := &bumpFeeConfig{}
+	parser.AddCommand(bumpFeeSubCmd, "Replaces a pending transaction with one that pays a higher fee",
+		"Replaces a transaction that is still in the mempool with one that spends the same UTXOs to the same "+
+			"recipients at a higher fee rate. The additional fee is deducted from the change. Only transactions "+
+			"that were created with --replaceable can be replaced", bumpFeeConf)
+
+	sweepLegacyConf := &sweepLegacyConfig{}
+	parser.AddCommand(sweepLegacySubCmd, "Moves the funds of a legacy wallet to the given address",
+		"Sends all the funds of a keys file that was created by an older version of kaspawallet, which "+
+			"didn't derive its keys from a mnemonic, to the given address", sweepLegacyConf)
+
+	startDaemonConf := &startDaemonConfig{
+		Listen: defaultDaemonListen,
+	}
+	parser.AddCommand(startDaemonSubCmd, "Start the wallet daemon",
+		"Starts a long-running wallet daemon that keeps the keys file unlocked in memory, tracks the "+
+			"wallet UTXOs and exposes a gRPC API", startDaemonConf)
+
 	_, err := parser.Parse()
 
 	if err != nil {
@@ -183,6 +261,34 @@ func parseCommandLine() (subCommand string, config interface{}) {
 			printErrorAndExit(err)
 		}
 		config = dumpUnencryptedDataConf
+	case startDaemonSubCmd:
+		combineNetworkFlags(&startDaemonConf.NetworkFlags, &cfg.NetworkFlags)
+		err := startDaemonConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = startDaemonConf
+	case historySubCmd:
+		combineNetworkFlags(&historyConf.NetworkFlags, &cfg.NetworkFlags)
+		err := historyConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = historyConf
+	case bumpFeeSubCmd:
+		combineNetworkFlags(&bumpFeeConf.NetworkFlags, &cfg.NetworkFlags)
+		err := bumpFeeConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = bumpFeeConf
+	case sweepLegacySubCmd:
+		combineNetworkFlags(&sweepLegacyConf.NetworkFlags, &cfg.NetworkFlags)
+		err := sweepLegacyConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = sweepLegacyConf
 	}
 
 	return parser.Command.Active.Name, config
@@ -2,56 +2,128 @@ package main
 
 import (
 	"bufio"
-	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/bip32"
 	"github.com/pkg/errors"
 )
 
 func create(conf *createConfig) error {
-	var encryptedPrivateKeys []*keys.EncryptedPrivateKey
-	var publicKeys [][]byte
+	if conf.WatchOnly {
+		return createWatchOnly(conf)
+	}
+
+	if conf.CosignerIndex != 0 {
+		return errors.New("--cosigner-index can only be used along with --watch-only")
+	}
+
+	var mnemonics []string
 	var err error
 	if !conf.Import {
-		encryptedPrivateKeys, publicKeys, err = keys.CreateKeyPairs(conf.NumPrivateKeys, conf.ECDSA)
+		mnemonics, err = keys.CreateMnemonics(conf.NumPrivateKeys)
 	} else {
-		encryptedPrivateKeys, publicKeys, err = keys.ImportKeyPairs(conf.NumPrivateKeys)
+		mnemonics, err = keys.ImportMnemonics(conf.NumPrivateKeys)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !conf.Import {
+		for i, mnemonic := range mnemonics {
+			fmt.Printf("Mnemonic #%d (write it down and keep it in a safe place):\n%s\n\n", i+1, mnemonic)
+		}
+	}
+
+	isMultisig := conf.NumPublicKeys > 1
+	encryptedMnemonics, signerExtendedPublicKeys, err := keys.EncryptMnemonics(conf.NetParams(), mnemonics, isMultisig)
+	if err != nil {
+		return err
 	}
+
+	for i, extendedPublicKey := range signerExtendedPublicKeys {
+		fmt.Printf("Extended public key of mnemonic #%d:\n%s\n\n", i+1, extendedPublicKey)
+	}
+
+	extendedPublicKeys := make([]string, len(signerExtendedPublicKeys), conf.NumPublicKeys)
+	copy(extendedPublicKeys, signerExtendedPublicKeys)
+	cosignerExtendedPublicKeys, err := readExtendedPublicKeys(conf.NumPrivateKeys, conf.NumPublicKeys)
 	if err != nil {
 		return err
 	}
+	extendedPublicKeys = append(extendedPublicKeys, cosignerExtendedPublicKeys...)
 
-	for i, publicKey := range publicKeys {
-		fmt.Printf("Public key of private key #%d:\n%x\n\n", i+1, publicKey)
+	cosignerIndex, err := libkaspawallet.MinimumCosignerIndex(signerExtendedPublicKeys, extendedPublicKeys)
+	if err != nil {
+		return err
 	}
 
+	if isMultisig {
+		fmt.Printf("The cosigner index of this wallet is %d. Use it when creating a watch-only wallet "+
+			"that generates the same addresses.\n\n", cosignerIndex)
+	}
+
+	return writeKeysFileAndPrintAddress(conf, encryptedMnemonics, extendedPublicKeys, cosignerIndex)
+}
+
+// createWatchOnly creates a wallet out of extended public keys alone. It can track the wallet
+// balance and create unsigned transactions, but never asks for a password or signs.
+func createWatchOnly(conf *createConfig) error {
+	if conf.Import {
+		return errors.New("--import cannot be used along with --watch-only")
+	}
+
+	if conf.CosignerIndex >= conf.NumPublicKeys {
+		return errors.Errorf("--cosigner-index must be smaller than the number of public keys (%d)",
+			conf.NumPublicKeys)
+	}
+
+	extendedPublicKeys, err := readExtendedPublicKeys(0, conf.NumPublicKeys)
+	if err != nil {
+		return err
+	}
+
+	return writeKeysFileAndPrintAddress(conf, nil, extendedPublicKeys, conf.CosignerIndex)
+}
+
+// readExtendedPublicKeys reads the extended public keys numbered from `from` (inclusive) to
+// `to` (exclusive) from stdin.
+func readExtendedPublicKeys(from uint32, to uint32) ([]string, error) {
+	var extendedPublicKeys []string
 	reader := bufio.NewReader(os.Stdin)
-	for i := conf.NumPrivateKeys; i < conf.NumPublicKeys; i++ {
+	for i := from; i < to; i++ {
 		fmt.Printf("Enter public key #%d here:\n", i+1)
-		line, isPrefix, err := reader.ReadLine()
+		line, err := reader.ReadString('\n')
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		fmt.Println()
 
-		if isPrefix {
-			return errors.Errorf("Public key is too long")
+		extendedPublicKey := strings.TrimSpace(line)
+		extendedKey, err := bip32.DeserializeExtendedKey(extendedPublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s is not a valid extended public key", extendedPublicKey)
 		}
 
-		publicKey, err := hex.DecodeString(string(line))
-		if err != nil {
-			return err
+		if extendedKey.IsPrivate() {
+			return nil, errors.Errorf("Expected an extended public key but got an extended private key")
 		}
 
-		publicKeys = append(publicKeys, publicKey)
+		extendedPublicKeys = append(extendedPublicKeys, extendedPublicKey)
 	}
 
-	err = keys.WriteKeysFile(
-		conf.NetParams(), conf.KeysFile, encryptedPrivateKeys, publicKeys, conf.MinimumSignatures, conf.ECDSA)
+	return extendedPublicKeys, nil
+}
+
+func writeKeysFileAndPrintAddress(conf *createConfig, encryptedMnemonics []*keys.EncryptedMnemonic,
+	extendedPublicKeys []string, cosignerIndex uint32) error {
+
+	err := keys.WriteKeysFile(conf.NetParams(), conf.KeysFile, encryptedMnemonics, extendedPublicKeys,
+		conf.MinimumSignatures, cosignerIndex, conf.ECDSA)
 	if err != nil {
 		return err
 	}
@@ -61,11 +133,11 @@ func create(conf *createConfig) error {
 		return err
 	}
 
-	addr, err := libkaspawallet.Address(conf.NetParams(), keysFile.PublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA)
+	address, err := walletAddressAt(conf.NetParams(), keysFile, libkaspawallet.ExternalKeychain, 0)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("The wallet address is:\n%s\n", addr)
+	fmt.Printf("The wallet address is:\n%s\n", address.address)
 	return nil
 }
@@ -15,48 +15,41 @@ func createUnsignedTransaction(conf *createUnsignedTransactionConfig) error {
 		return err
 	}
 
-	toAddress, err := util.DecodeAddress(conf.ToAddress, conf.NetParams().Prefix)
+	payments, err := conf.payments(conf.NetParams())
 	if err != nil {
 		return err
 	}
 
-	fromAddress, err := libkaspawallet.Address(conf.NetParams(), keysFile.PublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA)
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
 	if err != nil {
 		return err
 	}
-
-	client, err := connectToRPC(conf.NetParams(), conf.RPCServer)
+	utxos, err := fetchSpendableUTXOs(conf.NetParams(), client, keysFile)
 	if err != nil {
 		return err
 	}
-	utxos, err := fetchSpendableUTXOs(conf.NetParams(), client, fromAddress.String())
+
+	strategy, err := libkaspawallet.ParseCoinSelectionStrategy(conf.CoinSelection)
 	if err != nil {
 		return err
 	}
 
-	sendAmountSompi := uint64(conf.SendAmount * util.SompiPerKaspa)
-
-	const feePerInput = 1000
-	selectedUTXOs, changeSompi, err := selectUTXOs(utxos, sendAmountSompi, feePerInput)
+	psTxs, fee, err := createWalletTransactions(conf.NetParams(), keysFile, utxos, payments, conf.FeeRate, strategy,
+		conf.Replaceable)
 	if err != nil {
 		return err
 	}
 
-	psTx, err := libkaspawallet.CreateUnsignedTransaction(keysFile.PublicKeys,
-		keysFile.MinimumSignatures,
-		keysFile.ECDSA,
-		[]*libkaspawallet.Payment{{
-			Address: toAddress,
-			Amount:  sendAmountSompi,
-		}, {
-			Address: fromAddress,
-			Amount:  changeSompi,
-		}}, selectedUTXOs)
-	if err != nil {
-		return err
+	if len(psTxs) == 1 {
+		fmt.Printf("Created unsigned transaction with a fee of KAS %f\n", float64(fee)/util.SompiPerKaspa)
+		fmt.Println(hex.EncodeToString(psTxs[0]))
+		return nil
 	}
 
-	fmt.Println("Created unsigned transaction")
-	fmt.Println(hex.EncodeToString(psTx))
+	fmt.Printf("The payments were split into %d unsigned transactions with a total fee of KAS %f\n",
+		len(psTxs), float64(fee)/util.SompiPerKaspa)
+	for i, psTx := range psTxs {
+		fmt.Printf("Unsigned transaction #%d:\n%s\n", i+1, hex.EncodeToString(psTx))
+	}
 	return nil
 }
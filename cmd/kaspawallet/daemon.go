@@ -0,0 +1,308 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+const (
+	// daemonSyncInterval is the interval in which the daemon re-fetches all the wallet UTXOs
+	// from the node, as a safety net against missed UTXOsChanged notifications.
+	daemonSyncInterval = time.Minute
+
+	// usedOutpointExpiry is the duration after which an outpoint that was spent by a transaction
+	// the daemon created is considered spendable again if the node still reports it as unspent.
+	// This happens when the transaction was never broadcast, or was rejected by the node.
+	usedOutpointExpiry = time.Minute
+)
+
+// daemonRPCClient is the part of rpcclient.RPCClient that the daemon uses to query the node
+type daemonRPCClient interface {
+	GetUTXOsByAddresses(addresses []string) (*appmessage.GetUTXOsByAddressesResponseMessage, error)
+	GetBlockDAGInfo() (*appmessage.GetBlockDAGInfoResponseMessage, error)
+	RegisterForUTXOsChangedNotifications(addresses []string,
+		onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error
+	SubmitTransaction(transaction *appmessage.RPCTransaction) (*appmessage.SubmitTransactionResponseMessage, error)
+}
+
+// walletDaemon keeps the wallet keys unlocked in memory and tracks the wallet UTXO set
+// by listening to UTXOsChanged notifications of the node.
+//
+// The daemon never holds its lock while waiting for the node, so that a slow node doesn't
+// block the gRPC API and the UTXOsChanged notifications.
+type walletDaemon struct {
+	pb.UnimplementedKaspawalletdServer
+
+	params    *dagconfig.Params
+	rpcClient daemonRPCClient
+	keysFile  *keys.Data
+	mnemonics []string
+
+	lock                sync.RWMutex
+	utxos               map[appmessage.RPCOutpoint]*appmessage.UTXOsByAddressesEntry
+	usedOutpoints       map[appmessage.RPCOutpoint]time.Time
+	addressesByString   map[string]*walletAddress
+	registeredAddresses map[string]struct{}
+
+	// notificationsDuringSync holds the UTXOsChanged notifications that were received
+	// while the UTXOs are re-fetched, so that they can be applied on top of the re-fetched
+	// UTXOs. It's nil while no sync is in progress.
+	notificationsDuringSync []*appmessage.UTXOsChangedNotificationMessage
+
+	// syncLock makes sure that only one sync runs at a time
+	syncLock sync.Mutex
+
+	// rpcLock serializes the requests to the node. The RPC client matches every response to
+	// a waiting request by its type, so concurrent requests of the same type might otherwise
+	// get each other's responses.
+	rpcLock sync.Mutex
+
+	shutdown chan struct{}
+}
+
+func newWalletDaemon(params *dagconfig.Params, rpcClient daemonRPCClient, keysFile *keys.Data,
+	mnemonics []string) *walletDaemon {
+
+	return &walletDaemon{
+		params:              params,
+		rpcClient:           rpcClient,
+		keysFile:            keysFile,
+		mnemonics:           mnemonics,
+		utxos:               make(map[appmessage.RPCOutpoint]*appmessage.UTXOsByAddressesEntry),
+		usedOutpoints:       make(map[appmessage.RPCOutpoint]time.Time),
+		addressesByString:   make(map[string]*walletAddress),
+		registeredAddresses: make(map[string]struct{}),
+		shutdown:            make(chan struct{}, 1),
+	}
+}
+
+// sync re-fetches all the wallet UTXOs from the node and makes sure that the node
+// notifies the daemon about changes in all the wallet addresses.
+func (d *walletDaemon) sync() error {
+	d.syncLock.Lock()
+	defer d.syncLock.Unlock()
+
+	d.lock.Lock()
+	addressStrings, addressesByString, err := walletAddressesByString(d.params, d.keysFile)
+	if err == nil {
+		d.notificationsDuringSync = []*appmessage.UTXOsChangedNotificationMessage{}
+	}
+	d.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	d.rpcLock.Lock()
+	getUTXOsByAddressesResponse, err := d.rpcClient.GetUTXOsByAddresses(addressStrings)
+	d.rpcLock.Unlock()
+	if err != nil {
+		d.lock.Lock()
+		d.notificationsDuringSync = nil
+		d.lock.Unlock()
+		return err
+	}
+
+	d.lock.Lock()
+	newAddresses, err := d.replaceUTXOs(getUTXOsByAddressesResponse.Entries, addressesByString)
+	d.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return d.registerAddresses(newAddresses)
+}
+
+// replaceUTXOs replaces the tracked UTXOs with the given re-fetched ones, and applies on top of
+// them the notifications that were received during the sync. It returns the wallet addresses the
+// node doesn't notify about yet. It must be called while holding the lock.
+func (d *walletDaemon) replaceUTXOs(entries []*appmessage.UTXOsByAddressesEntry,
+	addressesByString map[string]*walletAddress) ([]string, error) {
+
+	notificationsDuringSync := d.notificationsDuringSync
+	d.notificationsDuringSync = nil
+
+	err := updateLastUsedIndexes(d.keysFile, entries, addressesByString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Addresses might have been added since addressesByString was calculated, so they're
+	// recalculated from the up-to-date keys file
+	d.addressesByString = addressesByString
+	newAddresses, err := d.refreshAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	d.utxos = make(map[appmessage.RPCOutpoint]*appmessage.UTXOsByAddressesEntry, len(entries))
+	for _, entry := range entries {
+		d.utxos[*entry.Outpoint] = entry
+	}
+	for _, notification := range notificationsDuringSync {
+		d.applyUTXOsChanged(notification)
+	}
+
+	for outpoint, usedTime := range d.usedOutpoints {
+		_, ok := d.utxos[outpoint]
+		if !ok || time.Since(usedTime) > usedOutpointExpiry {
+			delete(d.usedOutpoints, outpoint)
+		}
+	}
+
+	return newAddresses, nil
+}
+
+func (d *walletDaemon) syncLoop() {
+	ticker := time.NewTicker(daemonSyncInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := d.sync()
+		if err != nil {
+			log.Warnf("Error syncing the wallet UTXOs: %s", err)
+		}
+	}
+}
+
+// handleReconnected renews the UTXOsChanged registration, which doesn't survive a reconnection,
+// and re-fetches the UTXOs that might have changed while the daemon was disconnected.
+func (d *walletDaemon) handleReconnected() {
+	d.lock.Lock()
+	d.registeredAddresses = make(map[string]struct{})
+	d.lock.Unlock()
+
+	err := d.sync()
+	if err != nil {
+		log.Warnf("Error syncing the wallet UTXOs after reconnecting: %s", err)
+	}
+}
+
+// refreshAddresses recalculates the wallet addresses after the keys file last used indexes
+// have changed. It returns the wallet addresses the node doesn't notify about yet, which
+// should be passed to registerAddresses once the lock is released. It must be called while
+// holding the lock.
+func (d *walletDaemon) refreshAddresses() ([]string, error) {
+	addresses, err := walletAddresses(d.params, d.keysFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range addresses {
+		d.addressesByString[address.address.String()] = address
+	}
+
+	return d.unregisteredAddresses(), nil
+}
+
+// unregisteredAddresses returns the wallet addresses the node doesn't notify about yet.
+// It must be called while holding the lock.
+func (d *walletDaemon) unregisteredAddresses() []string {
+	var addresses []string
+	for addressString := range d.addressesByString {
+		if _, ok := d.registeredAddresses[addressString]; !ok {
+			addresses = append(addresses, addressString)
+		}
+	}
+	return addresses
+}
+
+// registerAddresses asks the node to notify about UTXO changes in the given addresses.
+// It must be called without holding the lock.
+func (d *walletDaemon) registerAddresses(addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	d.rpcLock.Lock()
+	err := d.rpcClient.RegisterForUTXOsChangedNotifications(addresses, d.handleUTXOsChanged)
+	d.rpcLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, addressString := range addresses {
+		d.registeredAddresses[addressString] = struct{}{}
+	}
+	log.Debugf("Registered for UTXO changes of %d new addresses", len(addresses))
+	return nil
+}
+
+func (d *walletDaemon) handleUTXOsChanged(notification *appmessage.UTXOsChangedNotificationMessage) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.applyUTXOsChanged(notification)
+	if d.notificationsDuringSync != nil {
+		d.notificationsDuringSync = append(d.notificationsDuringSync, notification)
+	}
+}
+
+// applyUTXOsChanged must be called while holding the lock.
+func (d *walletDaemon) applyUTXOsChanged(notification *appmessage.UTXOsChangedNotificationMessage) {
+	for _, entry := range notification.Removed {
+		delete(d.utxos, *entry.Outpoint)
+		delete(d.usedOutpoints, *entry.Outpoint)
+	}
+
+	for _, entry := range notification.Added {
+		if _, ok := d.addressesByString[entry.Address]; ok {
+			d.utxos[*entry.Outpoint] = entry
+		}
+	}
+}
+
+// virtualDAAScore returns the DAA score of the virtual, which determines which of the
+// UTXOs are mature. It must be called without holding the lock.
+func (d *walletDaemon) virtualDAAScore() (uint64, error) {
+	d.rpcLock.Lock()
+	defer d.rpcLock.Unlock()
+
+	blockDAGInfo, err := d.rpcClient.GetBlockDAGInfo()
+	if err != nil {
+		return 0, err
+	}
+	return blockDAGInfo.VirtualDAAScore, nil
+}
+
+// spendableUTXOs returns the wallet UTXOs that are mature and that were not spent by
+// a transaction the daemon created. It must be called while holding the lock.
+func (d *walletDaemon) spendableUTXOs(virtualDAAScore uint64) ([]*libkaspawallet.UTXO, error) {
+	utxos := make([]*libkaspawallet.UTXO, 0, len(d.utxos))
+	for outpoint, entry := range d.utxos {
+		if _, ok := d.usedOutpoints[outpoint]; ok {
+			continue
+		}
+		if !isUTXOSpendable(entry, virtualDAAScore, d.params.BlockCoinbaseMaturity) {
+			continue
+		}
+
+		utxo, err := rpcUTXOToWalletUTXO(entry, d.addressesByString[entry.Address].derivationPath)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	return utxos, nil
+}
+
+// markOutpointsAsUsed makes sure that the given outpoints are not selected again until the node
+// reports them as spent, or until usedOutpointExpiry passes. It must be called while holding the lock.
+func (d *walletDaemon) markOutpointsAsUsed(outpoints []*externalapi.DomainOutpoint) {
+	now := time.Now()
+	for _, outpoint := range outpoints {
+		d.usedOutpoints[appmessage.RPCOutpoint{
+			TransactionID: outpoint.TransactionID.String(),
+			Index:         outpoint.Index,
+		}] = now
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/util"
+)
+
+func (d *walletDaemon) GetBalance(_ context.Context, _ *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	virtualDAAScore, err := d.virtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	var available, pending uint64
+	for _, entry := range d.utxos {
+		if isUTXOSpendable(entry, virtualDAAScore, d.params.BlockCoinbaseMaturity) {
+			available += entry.UTXOEntry.Amount
+		} else {
+			pending += entry.UTXOEntry.Amount
+		}
+	}
+
+	return &pb.GetBalanceResponse{
+		Available: available,
+		Pending:   pending,
+	}, nil
+}
+
+func (d *walletDaemon) ShowAddresses(_ context.Context, _ *pb.ShowAddressesRequest) (*pb.ShowAddressesResponse, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	addresses := make([]string, 0, d.keysFile.LastUsedExternalIndex+1)
+	for index := uint32(0); index <= d.keysFile.LastUsedExternalIndex; index++ {
+		address, err := walletAddressAt(d.params, d.keysFile, libkaspawallet.ExternalKeychain, index)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address.address.String())
+	}
+
+	return &pb.ShowAddressesResponse{Address: addresses}, nil
+}
+
+func (d *walletDaemon) NewAddress(_ context.Context, _ *pb.NewAddressRequest) (*pb.NewAddressResponse, error) {
+	d.lock.Lock()
+	address, err := nextWalletAddress(d.params, d.keysFile, libkaspawallet.ExternalKeychain)
+	var newAddresses []string
+	if err == nil {
+		newAddresses, err = d.refreshAddresses()
+	}
+	d.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.registerAddresses(newAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.NewAddressResponse{Address: address.address.String()}, nil
+}
+
+func (d *walletDaemon) CreateUnsignedTransaction(_ context.Context, request *pb.CreateUnsignedTransactionRequest) (
+	*pb.CreateUnsignedTransactionResponse, error) {
+
+	unsignedTransaction, fee, err := d.createUnsignedTransaction(request.Address, request.Amount, request.FeeRate,
+		request.CoinSelectionStrategy, request.Replaceable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUnsignedTransactionResponse{
+		UnsignedTransaction: unsignedTransaction,
+		Fee:                 fee,
+	}, nil
+}
+
+// createUnsignedTransaction must be called without holding the lock.
+func (d *walletDaemon) createUnsignedTransaction(address string, amount uint64, feeRate float64,
+	coinSelectionStrategy string, replaceable bool) (unsignedTransaction []byte, fee uint64, err error) {
+
+	toAddress, err := util.DecodeAddress(address, d.params.Prefix)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if feeRate == 0 {
+		feeRate = libkaspawallet.DefaultFeeRate
+	}
+
+	strategy := libkaspawallet.CoinSelectionBranchAndBound
+	if coinSelectionStrategy != "" {
+		strategy, err = libkaspawallet.ParseCoinSelectionStrategy(coinSelectionStrategy)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	virtualDAAScore, err := d.virtualDAAScore()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d.lock.Lock()
+	unsignedTransaction, fee, newAddresses, err := d.createUnsignedTransactionFromUTXOs(toAddress, amount, feeRate,
+		strategy, replaceable, virtualDAAScore)
+	d.lock.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The change address might be new
+	err = d.registerAddresses(newAddresses)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return unsignedTransaction, fee, nil
+}
+
+// createUnsignedTransactionFromUTXOs must be called while holding the lock.
+func (d *walletDaemon) createUnsignedTransactionFromUTXOs(toAddress util.Address, amount uint64, feeRate float64,
+	strategy libkaspawallet.CoinSelectionStrategy, replaceable bool, virtualDAAScore uint64) (
+	unsignedTransaction []byte, fee uint64, newAddresses []string, err error) {
+
+	utxos, err := d.spendableUTXOs(virtualDAAScore)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	unsignedTransaction, selectedUTXOs, fee, err := createWalletTransaction(d.params, d.keysFile, utxos,
+		[]*libkaspawallet.Payment{{
+			Address: toAddress,
+			Amount:  amount,
+		}}, feeRate, strategy, replaceable)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	newAddresses, err = d.refreshAddresses()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	outpoints := make([]*externalapi.DomainOutpoint, len(selectedUTXOs))
+	for i, utxo := range selectedUTXOs {
+		outpoints[i] = utxo.Outpoint
+	}
+	d.markOutpointsAsUsed(outpoints)
+
+	return unsignedTransaction, fee, newAddresses, nil
+}
+
+func (d *walletDaemon) Sign(_ context.Context, request *pb.SignRequest) (*pb.SignResponse, error) {
+	if d.keysFile.WatchOnly {
+		return nil, keys.ErrWatchOnly
+	}
+
+	signedTransaction, err := libkaspawallet.Sign(d.params, d.mnemonics, request.UnsignedTransaction, d.keysFile.ECDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SignResponse{SignedTransaction: signedTransaction}, nil
+}
+
+func (d *walletDaemon) Broadcast(_ context.Context, request *pb.BroadcastRequest) (*pb.BroadcastResponse, error) {
+	transactionID, err := d.broadcast(request.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BroadcastResponse{TxID: transactionID}, nil
+}
+
+// broadcast must be called without holding the lock.
+func (d *walletDaemon) broadcast(transaction []byte) (string, error) {
+	tx, err := libkaspawallet.ExtractTransaction(transaction)
+	if err != nil {
+		return "", err
+	}
+
+	d.rpcLock.Lock()
+	transactionID, err := sendTransaction(d.rpcClient, tx)
+	d.rpcLock.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	outpoints := make([]*externalapi.DomainOutpoint, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		outpoints[i] = &input.PreviousOutpoint
+	}
+	d.markOutpointsAsUsed(outpoints)
+
+	return transactionID, nil
+}
+
+func (d *walletDaemon) Send(_ context.Context, request *pb.SendRequest) (*pb.SendResponse, error) {
+	if d.keysFile.WatchOnly {
+		return nil, keys.ErrWatchOnly
+	}
+
+	unsignedTransaction, fee, err := d.createUnsignedTransaction(request.ToAddress, request.Amount, request.FeeRate,
+		request.CoinSelectionStrategy, request.Replaceable)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTransaction, err := libkaspawallet.Sign(d.params, d.mnemonics, unsignedTransaction, d.keysFile.ECDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionID, err := d.broadcast(signedTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SendResponse{
+		TxID: transactionID,
+		Fee:  fee,
+	}, nil
+}
+
+func (d *walletDaemon) Shutdown(_ context.Context, _ *pb.ShutdownRequest) (*pb.ShutdownResponse, error) {
+	select {
+	case d.shutdown <- struct{}{}:
+	default:
+	}
+
+	return &pb.ShutdownResponse{}, nil
+}
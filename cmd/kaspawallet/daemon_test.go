@@ -0,0 +1,364 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// fakeNode implements daemonRPCClient on top of an in-memory UTXO set, and notifies about
+// changes in it the same way the node does.
+type fakeNode struct {
+	lock                sync.Mutex
+	utxos               map[appmessage.RPCOutpoint]*appmessage.UTXOsByAddressesEntry
+	registeredAddresses map[string]struct{}
+	onUTXOsChanged      func(notification *appmessage.UTXOsChangedNotificationMessage)
+
+	// afterGetUTXOsByAddresses, if set, is called after the UTXOs are fetched and before they're returned
+	afterGetUTXOsByAddresses func()
+}
+
+func newFakeNode() *fakeNode {
+	return &fakeNode{
+		utxos:               make(map[appmessage.RPCOutpoint]*appmessage.UTXOsByAddressesEntry),
+		registeredAddresses: make(map[string]struct{}),
+	}
+}
+
+func (n *fakeNode) GetUTXOsByAddresses(addresses []string) (*appmessage.GetUTXOsByAddressesResponseMessage, error) {
+	n.lock.Lock()
+	addressSet := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		addressSet[address] = struct{}{}
+	}
+	var entries []*appmessage.UTXOsByAddressesEntry
+	for _, entry := range n.utxos {
+		if _, ok := addressSet[entry.Address]; ok {
+			entries = append(entries, entry)
+		}
+	}
+	afterGetUTXOsByAddresses := n.afterGetUTXOsByAddresses
+	n.lock.Unlock()
+
+	if afterGetUTXOsByAddresses != nil {
+		afterGetUTXOsByAddresses()
+	}
+	return appmessage.NewGetUTXOsByAddressesResponseMessage(entries), nil
+}
+
+func (n *fakeNode) GetBlockDAGInfo() (*appmessage.GetBlockDAGInfoResponseMessage, error) {
+	return &appmessage.GetBlockDAGInfoResponseMessage{VirtualDAAScore: 1000}, nil
+}
+
+func (n *fakeNode) RegisterForUTXOsChangedNotifications(addresses []string,
+	onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for _, address := range addresses {
+		n.registeredAddresses[address] = struct{}{}
+	}
+	n.onUTXOsChanged = onUTXOsChanged
+	return nil
+}
+
+func (n *fakeNode) SubmitTransaction(_ *appmessage.RPCTransaction) (*appmessage.SubmitTransactionResponseMessage, error) {
+	return nil, errors.New("the fake node doesn't accept transactions")
+}
+
+// change adds and removes the given UTXOs, and notifies about the change if notify is true
+func (n *fakeNode) change(added []*appmessage.UTXOsByAddressesEntry, removed []*appmessage.UTXOsByAddressesEntry,
+	notify bool) {
+
+	n.lock.Lock()
+	notification := &appmessage.UTXOsChangedNotificationMessage{}
+	for _, entry := range removed {
+		delete(n.utxos, *entry.Outpoint)
+		if _, ok := n.registeredAddresses[entry.Address]; ok {
+			notification.Removed = append(notification.Removed, entry)
+		}
+	}
+	for _, entry := range added {
+		n.utxos[*entry.Outpoint] = entry
+		if _, ok := n.registeredAddresses[entry.Address]; ok {
+			notification.Added = append(notification.Added, entry)
+		}
+	}
+	onUTXOsChanged := n.onUTXOsChanged
+	n.lock.Unlock()
+
+	if notify && onUTXOsChanged != nil {
+		onUTXOsChanged(notification)
+	}
+}
+
+// disconnect makes the node forget the registration for UTXOsChanged notifications,
+// like a reconnection does
+func (n *fakeNode) disconnect() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.registeredAddresses = make(map[string]struct{})
+	n.onUTXOsChanged = nil
+}
+
+func (n *fakeNode) registeredAddressCount() int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return len(n.registeredAddresses)
+}
+
+func newTestDaemon(t *testing.T) (*walletDaemon, *fakeNode) {
+	params := &dagconfig.SimnetParams
+	mnemonic, err := libkaspawallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %s", err)
+	}
+	extendedPublicKey, err := libkaspawallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+	if err != nil {
+		t.Fatalf("MasterPublicKeyFromMnemonic: %s", err)
+	}
+
+	// A watch-only keys file doesn't require a password
+	keysFilePath := filepath.Join(t.TempDir(), "keys.json")
+	err = keys.WriteKeysFile(params, keysFilePath, nil, []string{extendedPublicKey}, 1, 0, false)
+	if err != nil {
+		t.Fatalf("WriteKeysFile: %s", err)
+	}
+	keysFile, err := keys.ReadKeysFile(params, keysFilePath)
+	if err != nil {
+		t.Fatalf("ReadKeysFile: %s", err)
+	}
+
+	node := newFakeNode()
+	return newWalletDaemon(params, node, keysFile, nil), node
+}
+
+func testWalletAddress(t *testing.T, d *walletDaemon, keychain uint32, index uint32) util.Address {
+	address, err := walletAddressAt(d.params, d.keysFile, keychain, index)
+	if err != nil {
+		t.Fatalf("walletAddressAt: %s", err)
+	}
+	return address.address
+}
+
+func testUTXO(t *testing.T, address util.Address, transactionIndex int, amount uint64) *appmessage.UTXOsByAddressesEntry {
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		t.Fatalf("PayToAddrScript: %s", err)
+	}
+	return &appmessage.UTXOsByAddressesEntry{
+		Address: address.String(),
+		Outpoint: &appmessage.RPCOutpoint{
+			TransactionID: fmt.Sprintf("%064x", transactionIndex),
+			Index:         0,
+		},
+		UTXOEntry: &appmessage.RPCUTXOEntry{
+			Amount: amount,
+			ScriptPublicKey: &appmessage.RPCScriptPublicKey{
+				Version: scriptPublicKey.Version,
+				Script:  hex.EncodeToString(scriptPublicKey.Script),
+			},
+		},
+	}
+}
+
+func checkDaemonUTXOs(t *testing.T, d *walletDaemon, testName string, expected ...*appmessage.UTXOsByAddressesEntry) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if len(d.utxos) != len(expected) {
+		t.Fatalf("%s: expected %d UTXOs, got %d", testName, len(expected), len(d.utxos))
+	}
+	for _, entry := range expected {
+		if _, ok := d.utxos[*entry.Outpoint]; !ok {
+			t.Fatalf("%s: UTXO %s is missing", testName, entry.Outpoint.TransactionID)
+		}
+	}
+}
+
+func TestWalletDaemonUTXOTracking(t *testing.T) {
+	d, node := newTestDaemon(t)
+	address := testWalletAddress(t, d, libkaspawallet.ExternalKeychain, 0)
+	changeAddress := testWalletAddress(t, d, libkaspawallet.InternalKeychain, 3)
+	foreignAddress, err := util.NewAddressPublicKey(make([]byte, 32), d.params.Prefix)
+	if err != nil {
+		t.Fatalf("NewAddressPublicKey: %s", err)
+	}
+
+	utxo1 := testUTXO(t, address, 1, 10*util.SompiPerKaspa)
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo1}, nil, false)
+	err = d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	checkDaemonUTXOs(t, d, "after the first sync", utxo1)
+	expectedAddressCount := 2 * (addressGapLimit + 1)
+	if node.registeredAddressCount() != expectedAddressCount {
+		t.Fatalf("expected %d registered addresses, got %d", expectedAddressCount, node.registeredAddressCount())
+	}
+
+	// UTXOs of addresses that don't belong to the wallet are ignored
+	utxo2 := testUTXO(t, changeAddress, 2, 20*util.SompiPerKaspa)
+	foreignUTXO := testUTXO(t, foreignAddress, 3, 30*util.SompiPerKaspa)
+	err = node.RegisterForUTXOsChangedNotifications([]string{foreignUTXO.Address}, d.handleUTXOsChanged)
+	if err != nil {
+		t.Fatalf("RegisterForUTXOsChangedNotifications: %s", err)
+	}
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo2, foreignUTXO}, nil, true)
+	checkDaemonUTXOs(t, d, "after UTXOs were added", utxo1, utxo2)
+
+	node.change(nil, []*appmessage.UTXOsByAddressesEntry{utxo1}, true)
+	checkDaemonUTXOs(t, d, "after a UTXO was removed", utxo2)
+
+	// Changes that are notified while a sync is in progress, after the node has
+	// already returned the UTXOs, aren't lost
+	utxo4 := testUTXO(t, address, 4, 40*util.SompiPerKaspa)
+	node.afterGetUTXOsByAddresses = func() {
+		node.change([]*appmessage.UTXOsByAddressesEntry{utxo4}, []*appmessage.UTXOsByAddressesEntry{utxo2}, true)
+	}
+	err = d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	node.afterGetUTXOsByAddresses = nil
+	checkDaemonUTXOs(t, d, "after changes during a sync", utxo4)
+
+	d.lock.RLock()
+	isSyncing := d.notificationsDuringSync != nil
+	d.lock.RUnlock()
+	if isSyncing {
+		t.Fatalf("notifications are still recorded after the sync is over")
+	}
+}
+
+func TestWalletDaemonUsedOutpoints(t *testing.T) {
+	d, node := newTestDaemon(t)
+	address := testWalletAddress(t, d, libkaspawallet.ExternalKeychain, 0)
+	toAddress := testWalletAddress(t, d, libkaspawallet.ExternalKeychain, 5)
+
+	utxo1 := testUTXO(t, address, 1, 10*util.SompiPerKaspa)
+	utxo2 := testUTXO(t, address, 2, 10*util.SompiPerKaspa)
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo1, utxo2}, nil, false)
+	err := d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	registeredAddressCount := node.registeredAddressCount()
+
+	checkSpendableUTXOCount := func(testName string, expected int) {
+		virtualDAAScore, err := d.virtualDAAScore()
+		if err != nil {
+			t.Fatalf("%s: virtualDAAScore: %s", testName, err)
+		}
+		d.lock.RLock()
+		defer d.lock.RUnlock()
+		utxos, err := d.spendableUTXOs(virtualDAAScore)
+		if err != nil {
+			t.Fatalf("%s: spendableUTXOs: %s", testName, err)
+		}
+		if len(utxos) != expected {
+			t.Fatalf("%s: expected %d spendable UTXOs, got %d", testName, expected, len(utxos))
+		}
+	}
+	checkSpendableUTXOCount("before creating a transaction", 2)
+
+	_, _, err = d.createUnsignedTransaction(toAddress.String(), util.SompiPerKaspa, 1, "", false)
+	if err != nil {
+		t.Fatalf("createUnsignedTransaction: %s", err)
+	}
+	checkSpendableUTXOCount("after creating a transaction", 1)
+
+	// The transaction pays change into a new address, which the node should notify about
+	if node.registeredAddressCount() != registeredAddressCount+1 {
+		t.Fatalf("expected %d registered addresses after creating a transaction, got %d",
+			registeredAddressCount+1, node.registeredAddressCount())
+	}
+
+	// A used outpoint stays unspendable as long as it didn't expire
+	err = d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	checkSpendableUTXOCount("after a sync", 1)
+
+	d.lock.Lock()
+	for outpoint := range d.usedOutpoints {
+		d.usedOutpoints[outpoint] = time.Now().Add(-usedOutpointExpiry - time.Second)
+	}
+	d.lock.Unlock()
+	err = d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	checkSpendableUTXOCount("after the used outpoint expired", 2)
+	d.lock.RLock()
+	usedOutpointCount := len(d.usedOutpoints)
+	d.lock.RUnlock()
+	if usedOutpointCount != 0 {
+		t.Fatalf("expected the expired outpoint to be forgotten, but %d outpoints are used", usedOutpointCount)
+	}
+
+	// A used outpoint is forgotten once the node reports it as spent
+	_, _, err = d.createUnsignedTransaction(toAddress.String(), util.SompiPerKaspa, 1, "", false)
+	if err != nil {
+		t.Fatalf("createUnsignedTransaction: %s", err)
+	}
+	d.lock.RLock()
+	var spent []*appmessage.UTXOsByAddressesEntry
+	for outpoint := range d.usedOutpoints {
+		spent = append(spent, d.utxos[outpoint])
+	}
+	d.lock.RUnlock()
+	node.change(nil, spent, true)
+	d.lock.RLock()
+	usedOutpointCount = len(d.usedOutpoints)
+	d.lock.RUnlock()
+	if usedOutpointCount != 0 {
+		t.Fatalf("expected the spent outpoint to be forgotten, but %d outpoints are used", usedOutpointCount)
+	}
+	checkSpendableUTXOCount("after the used outpoint was spent", 1)
+}
+
+func TestWalletDaemonReconnect(t *testing.T) {
+	d, node := newTestDaemon(t)
+	address := testWalletAddress(t, d, libkaspawallet.ExternalKeychain, 0)
+
+	utxo1 := testUTXO(t, address, 1, 10*util.SompiPerKaspa)
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo1}, nil, false)
+	err := d.sync()
+	if err != nil {
+		t.Fatalf("sync: %s", err)
+	}
+	registeredAddressCount := node.registeredAddressCount()
+
+	// Changes that happen while the daemon is disconnected aren't notified
+	node.disconnect()
+	utxo2 := testUTXO(t, address, 2, 20*util.SompiPerKaspa)
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo2}, []*appmessage.UTXOsByAddressesEntry{utxo1}, true)
+	checkDaemonUTXOs(t, d, "while disconnected", utxo1)
+
+	d.handleReconnected()
+	checkDaemonUTXOs(t, d, "after reconnecting", utxo2)
+	if node.registeredAddressCount() != registeredAddressCount {
+		t.Fatalf("expected %d addresses to be registered again after reconnecting, got %d",
+			registeredAddressCount, node.registeredAddressCount())
+	}
+
+	utxo3 := testUTXO(t, address, 3, 30*util.SompiPerKaspa)
+	node.change([]*appmessage.UTXOsByAddressesEntry{utxo3}, nil, true)
+	checkDaemonUTXOs(t, d, "after a change was notified after reconnecting", utxo2, utxo3)
+}
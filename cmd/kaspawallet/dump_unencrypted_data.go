@@ -11,43 +11,53 @@ import (
 )
 
 func dumpUnencryptedData(conf *dumpUnencryptedDataConfig) error {
-	err := confirmDump()
-	if err != nil {
-		return err
-	}
-
 	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
 	if err != nil {
 		return err
 	}
 
-	privateKeys, err := keysFile.DecryptPrivateKeys()
-	if err != nil {
-		return err
+	// A watch-only wallet has no secrets to protect
+	var mnemonics []string
+	if !keysFile.WatchOnly {
+		err = confirmDump()
+		if err != nil {
+			return err
+		}
+
+		mnemonics, err = keysFile.DecryptMnemonics()
+		if err != nil {
+			return err
+		}
 	}
 
-	privateKeysPublicKeys := make(map[string]struct{})
-	for i, privateKey := range privateKeys {
-		fmt.Printf("Private key #%d:\n%x\n\n", i+1, privateKey)
-		publicKey, err := libkaspawallet.PublicKeyFromPrivateKey(privateKey)
+	mnemonicPublicKeys := make(map[string]struct{})
+	for i, mnemonic := range mnemonics {
+		fmt.Printf("Mnemonic #%d:\n%s\n\n", i+1, mnemonic)
+		publicKey, err := libkaspawallet.MasterPublicKeyFromMnemonic(conf.NetParams(), mnemonic, keysFile.IsMultisig())
 		if err != nil {
 			return err
 		}
 
-		privateKeysPublicKeys[string(publicKey)] = struct{}{}
+		mnemonicPublicKeys[publicKey] = struct{}{}
 	}
 
 	i := 1
-	for _, publicKey := range keysFile.PublicKeys {
-		if _, exists := privateKeysPublicKeys[string(publicKey)]; exists {
+	for _, extendedPublicKey := range keysFile.ExtendedPublicKeys {
+		if _, exists := mnemonicPublicKeys[extendedPublicKey]; exists {
 			continue
 		}
 
-		fmt.Printf("Public key #%d:\n%x\n\n", i, publicKey)
+		fmt.Printf("Extended public key #%d:\n%s\n\n", i, extendedPublicKey)
 		i++
 	}
 
 	fmt.Printf("Minimum number of signatures: %d\n", keysFile.MinimumSignatures)
+	if keysFile.IsMultisig() {
+		fmt.Printf("Cosigner index: %d\n", keysFile.CosignerIndex)
+	}
+	if keysFile.WatchOnly {
+		fmt.Println("This is a watch-only wallet")
+	}
 	return nil
 }
 
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// historyTransaction is a transaction that was accepted by the selected parent chain
+// and paid or spent from the addresses whose history is shown
+type historyTransaction struct {
+	transactionID           string
+	acceptingBlockHash      string
+	acceptingBlockBlueScore uint64
+	isCoinbase              bool
+
+	received uint64
+	spent    uint64
+	// fee is only known for transactions that spent from the addresses
+	fee uint64
+}
+
+func history(conf *historyConfig) error {
+	addresses, err := historyAddresses(conf)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
+	if err != nil {
+		return err
+	}
+
+	getUTXOHistoryByAddressesResponse, err := client.GetUTXOHistoryByAddresses(addresses)
+	if err != nil {
+		return err
+	}
+	virtualSelectedParentBlueScoreResponse, err := client.GetVirtualSelectedParentBlueScore()
+	if err != nil {
+		return err
+	}
+
+	transactions := historyTransactions(getUTXOHistoryByAddressesResponse.Entries)
+	if len(transactions) == 0 {
+		fmt.Println("No transactions were found")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "Transaction ID\tType\tAmount (KAS)\tFee (KAS)\tAccepting block\tConfirmations")
+	var totalReceived, totalSent, totalFees uint64
+	for _, transaction := range transactions {
+		transactionType, amount := transaction.typeAndAmount()
+		if transactionType == "sent" {
+			totalSent += amount
+		} else {
+			totalReceived += amount
+		}
+		totalFees += transaction.fee
+
+		fee := ""
+		if transaction.spent > 0 {
+			fee = formatKAS(transaction.fee)
+		}
+		confirmations := uint64(0)
+		if virtualSelectedParentBlueScoreResponse.BlueScore >= transaction.acceptingBlockBlueScore {
+			confirmations = virtualSelectedParentBlueScoreResponse.BlueScore - transaction.acceptingBlockBlueScore + 1
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%d\n", transaction.transactionID, transactionType, formatKAS(amount),
+			fee, transaction.acceptingBlockHash, confirmations)
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nTotal received:\tKAS %s\n", formatKAS(totalReceived))
+	fmt.Printf("Total sent:\tKAS %s\n", formatKAS(totalSent))
+	fmt.Printf("Total fees:\tKAS %s\n", formatKAS(totalFees))
+	return nil
+}
+
+// historyAddresses returns the address given with --address, or all the wallet addresses otherwise
+func historyAddresses(conf *historyConfig) ([]string, error) {
+	if conf.Address != "" {
+		_, err := util.DecodeAddress(conf.Address, conf.ActiveNetParams.Prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address %s", conf.Address)
+		}
+		return []string{conf.Address}, nil
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return nil, err
+	}
+	walletAddresses, err := walletAddresses(conf.NetParams(), keysFile)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, len(walletAddresses))
+	for i, address := range walletAddresses {
+		addresses[i] = address.address.String()
+	}
+	return addresses, nil
+}
+
+// historyTransactions groups the given UTXO history entries by the transactions that created
+// and spent them, ordered by the blue score of their accepting blocks
+func historyTransactions(entries []*appmessage.UTXOHistoryByAddressesEntry) []*historyTransaction {
+	transactionsByID := make(map[string]*historyTransaction)
+	getOrAddTransaction := func(transactionID string, acceptingBlockHash string, acceptingBlockBlueScore uint64) *historyTransaction {
+		if transaction, ok := transactionsByID[transactionID]; ok {
+			return transaction
+		}
+		transaction := &historyTransaction{
+			transactionID:           transactionID,
+			acceptingBlockHash:      acceptingBlockHash,
+			acceptingBlockBlueScore: acceptingBlockBlueScore,
+		}
+		transactionsByID[transactionID] = transaction
+		return transaction
+	}
+
+	for _, entry := range entries {
+		if entry.AcceptingBlockHash != "" {
+			creatingTransaction := getOrAddTransaction(entry.Outpoint.TransactionID, entry.AcceptingBlockHash,
+				entry.AcceptingBlockBlueScore)
+			creatingTransaction.received += entry.UTXOEntry.Amount
+			creatingTransaction.isCoinbase = entry.UTXOEntry.IsCoinbase
+		}
+		if entry.SpendingTransactionID != "" {
+			spendingTransaction := getOrAddTransaction(entry.SpendingTransactionID, entry.SpendingBlockHash,
+				entry.SpendingBlockBlueScore)
+			spendingTransaction.spent += entry.UTXOEntry.Amount
+			spendingTransaction.fee = entry.SpendingTransactionFee
+		}
+	}
+
+	transactions := make([]*historyTransaction, 0, len(transactionsByID))
+	for _, transaction := range transactionsByID {
+		transactions = append(transactions, transaction)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].acceptingBlockBlueScore != transactions[j].acceptingBlockBlueScore {
+			return transactions[i].acceptingBlockBlueScore < transactions[j].acceptingBlockBlueScore
+		}
+		return transactions[i].transactionID < transactions[j].transactionID
+	})
+	return transactions
+}
+
+// typeAndAmount returns whether the transaction received funds, sent funds or only moved them
+// between the addresses, along with the amount it received or sent to others excluding the fee
+func (ht *historyTransaction) typeAndAmount() (transactionType string, amount uint64) {
+	if ht.spent == 0 {
+		if ht.isCoinbase {
+			return "coinbase", ht.received
+		}
+		return "received", ht.received
+	}
+	if ht.received+ht.fee == ht.spent {
+		return "self", 0
+	}
+	if ht.received+ht.fee > ht.spent {
+		// Nothing left the addresses but the fee, so whatever they received in excess came from others
+		return "received", ht.received + ht.fee - ht.spent
+	}
+	return "sent", ht.spent - ht.received - ht.fee
+}
+
+func formatKAS(sompi uint64) string {
+	return fmt.Sprintf("%f", float64(sompi)/util.SompiPerKaspa)
+}
@@ -4,48 +4,54 @@ import (
 	"bufio"
 	"crypto/rand"
 	"crypto/subtle"
-	"encoding/hex"
 	"fmt"
+	"os"
+	"strings"
+
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/pkg/errors"
-	"os"
 )
 
-// CreateKeyPairs generates `numKeys` number of key pairs.
-func CreateKeyPairs(numKeys uint32, ecdsa bool) (encryptedPrivateKeys []*EncryptedPrivateKey, publicKeys [][]byte, err error) {
-	return createKeyPairsFromFunction(numKeys, func(_ uint32) ([]byte, []byte, error) {
-		return libkaspawallet.CreateKeyPair(ecdsa)
-	})
-}
-
-// ImportKeyPairs imports a `numKeys` of private keys and generates key pairs out of them.
-func ImportKeyPairs(numKeys uint32) (encryptedPrivateKeys []*EncryptedPrivateKey, publicKeys [][]byte, err error) {
-	return createKeyPairsFromFunction(numKeys, func(keyIndex uint32) ([]byte, []byte, error) {
-		fmt.Printf("Enter private key #%d here:\n", keyIndex+1)
-		reader := bufio.NewReader(os.Stdin)
-		line, isPrefix, err := reader.ReadLine()
+// CreateMnemonics generates `numKeys` number of mnemonics.
+func CreateMnemonics(numKeys uint32) ([]string, error) {
+	mnemonics := make([]string, numKeys)
+	for i := uint32(0); i < numKeys; i++ {
+		var err error
+		mnemonics[i], err = libkaspawallet.CreateMnemonic()
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
-		if isPrefix {
-			return nil, nil, errors.Errorf("Private key is too long")
-		}
-		privateKey, err := hex.DecodeString(string(line))
+	}
+
+	return mnemonics, nil
+}
+
+// ImportMnemonics imports a `numKeys` of mnemonics.
+func ImportMnemonics(numKeys uint32) ([]string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	mnemonics := make([]string, numKeys)
+	for i := uint32(0); i < numKeys; i++ {
+		fmt.Printf("Enter mnemonic #%d here:\n", i+1)
+		mnemonic, err := reader.ReadString('\n')
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
-		publicKey, err := libkaspawallet.PublicKeyFromPrivateKey(privateKey)
+		mnemonics[i] = strings.TrimSpace(mnemonic)
+		err = libkaspawallet.ValidateMnemonic(mnemonics[i])
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
+	}
 
-		return privateKey, publicKey, nil
-	})
+	return mnemonics, nil
 }
 
-func createKeyPairsFromFunction(numKeys uint32, keyPairFunction func(keyIndex uint32) ([]byte, []byte, error)) (
-	encryptedPrivateKeys []*EncryptedPrivateKey, publicKeys [][]byte, err error) {
+// EncryptMnemonics asks the user for a password, encrypts the given mnemonics with it, and
+// returns the encrypted mnemonics along with their matching extended public keys.
+func EncryptMnemonics(params *dagconfig.Params, mnemonics []string, isMultisig bool) (
+	encryptedMnemonics []*EncryptedMnemonic, extendedPublicKeys []string, err error) {
 
 	password := getPassword("Enter password for the key file:")
 	confirmPassword := getPassword("Confirm password:")
@@ -54,23 +60,23 @@ func createKeyPairsFromFunction(numKeys uint32, keyPairFunction func(keyIndex ui
 		return nil, nil, errors.New("Passwords are not identical")
 	}
 
-	encryptedPrivateKeys = make([]*EncryptedPrivateKey, 0, numKeys)
-	for i := uint32(0); i < numKeys; i++ {
-		privateKey, publicKey, err := keyPairFunction(i)
+	encryptedMnemonics = make([]*EncryptedMnemonic, 0, len(mnemonics))
+	for _, mnemonic := range mnemonics {
+		extendedPublicKey, err := libkaspawallet.MasterPublicKeyFromMnemonic(params, mnemonic, isMultisig)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		publicKeys = append(publicKeys, publicKey)
+		extendedPublicKeys = append(extendedPublicKeys, extendedPublicKey)
 
-		encryptedPrivateKey, err := encryptPrivateKey(privateKey, password)
+		encryptedMnemonic, err := encryptMnemonic(mnemonic, password)
 		if err != nil {
 			return nil, nil, err
 		}
-		encryptedPrivateKeys = append(encryptedPrivateKeys, encryptedPrivateKey)
+		encryptedMnemonics = append(encryptedMnemonics, encryptedMnemonic)
 	}
 
-	return encryptedPrivateKeys, publicKeys, nil
+	return encryptedMnemonics, extendedPublicKeys, nil
 }
 
 func generateSalt() ([]byte, error) {
@@ -83,7 +89,9 @@ func generateSalt() ([]byte, error) {
 	return salt, nil
 }
 
-func encryptPrivateKey(privateKey []byte, password []byte) (*EncryptedPrivateKey, error) {
+func encryptMnemonic(mnemonic string, password []byte) (*EncryptedMnemonic, error) {
+	mnemonicBytes := []byte(mnemonic)
+
 	salt, err := generateSalt()
 	if err != nil {
 		return nil, err
@@ -95,15 +103,15 @@ func encryptPrivateKey(privateKey []byte, password []byte) (*EncryptedPrivateKey
 	}
 
 	// Select a random nonce, and leave capacity for the ciphertext.
-	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(privateKey)+aead.Overhead())
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(mnemonicBytes)+aead.Overhead())
 	if _, err := rand.Read(nonce); err != nil {
 		return nil, err
 	}
 
 	// Encrypt the message and append the ciphertext to the nonce.
-	cipher := aead.Seal(nonce, nonce, privateKey, nil)
+	cipher := aead.Seal(nonce, nonce, mnemonicBytes, nil)
 
-	return &EncryptedPrivateKey{
+	return &EncryptedMnemonic{
 		cipher: cipher,
 		salt:   salt,
 	}, nil
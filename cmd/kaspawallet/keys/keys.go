@@ -25,102 +25,146 @@ func defaultKeysFile(netParams *dagconfig.Params) string {
 	return filepath.Join(defaultAppDir, netParams.Name, "keys.json")
 }
 
+// LastVersion is the most up to date file format version
+const LastVersion = 1
+
+// ErrWatchOnly is returned when an operation that requires the private keys is
+// attempted on a watch-only wallet
+var ErrWatchOnly = errors.New("this is a watch-only wallet, which has no private keys. Sign the transaction " +
+	"on a machine that has the wallet private keys instead")
+
 type encryptedPrivateKeyJSON struct {
 	Cipher string `json:"cipher"`
 	Salt   string `json:"salt"`
 }
 
 type keysFileJSON struct {
-	EncryptedPrivateKeys []*encryptedPrivateKeyJSON `json:"encryptedPrivateKeys"`
-	PublicKeys           []string                   `json:"publicKeys"`
-	MinimumSignatures    uint32                     `json:"minimumSignatures"`
-	ECDSA                bool                       `json:"ecdsa"`
+	Version               uint32                     `json:"version"`
+	EncryptedMnemonics    []*encryptedPrivateKeyJSON `json:"encryptedMnemonics"`
+	ExtendedPublicKeys    []string                   `json:"publicKeys"`
+	MinimumSignatures     uint32                     `json:"minimumSignatures"`
+	CosignerIndex         uint32                     `json:"cosignerIndex"`
+	LastUsedExternalIndex uint32                     `json:"lastUsedExternalIndex"`
+	LastUsedInternalIndex uint32                     `json:"lastUsedInternalIndex"`
+	ECDSA                 bool                       `json:"ecdsa"`
+	WatchOnly             bool                       `json:"watchOnly"`
 }
 
-// EncryptedPrivateKey represents an encrypted private key
-type EncryptedPrivateKey struct {
+// EncryptedMnemonic represents an encrypted mnemonic
+type EncryptedMnemonic struct {
 	cipher []byte
 	salt   []byte
 }
 
 // Data holds all the data related to the wallet keys
 type Data struct {
-	encryptedPrivateKeys []*EncryptedPrivateKey
-	PublicKeys           [][]byte
-	MinimumSignatures    uint32
-	ECDSA                bool
+	encryptedMnemonics    []*EncryptedMnemonic
+	ExtendedPublicKeys    []string
+	MinimumSignatures     uint32
+	CosignerIndex         uint32
+	LastUsedExternalIndex uint32
+	LastUsedInternalIndex uint32
+	ECDSA                 bool
+
+	// WatchOnly is true if the wallet was created from extended public keys alone, in which
+	// case it can create unsigned transactions but not sign them
+	WatchOnly bool
+
+	path string
 }
 
 func (d *Data) toJSON() *keysFileJSON {
-	encryptedPrivateKeysJSON := make([]*encryptedPrivateKeyJSON, len(d.encryptedPrivateKeys))
-	for i, encryptedPrivateKey := range d.encryptedPrivateKeys {
-		encryptedPrivateKeysJSON[i] = &encryptedPrivateKeyJSON{
-			Cipher: hex.EncodeToString(encryptedPrivateKey.cipher),
-			Salt:   hex.EncodeToString(encryptedPrivateKey.salt),
+	encryptedMnemonicsJSON := make([]*encryptedPrivateKeyJSON, len(d.encryptedMnemonics))
+	for i, encryptedMnemonic := range d.encryptedMnemonics {
+		encryptedMnemonicsJSON[i] = &encryptedPrivateKeyJSON{
+			Cipher: hex.EncodeToString(encryptedMnemonic.cipher),
+			Salt:   hex.EncodeToString(encryptedMnemonic.salt),
 		}
 	}
 
-	publicKeysHex := make([]string, len(d.PublicKeys))
-	for i, publicKey := range d.PublicKeys {
-		publicKeysHex[i] = hex.EncodeToString(publicKey)
-	}
-
 	return &keysFileJSON{
-		EncryptedPrivateKeys: encryptedPrivateKeysJSON,
-		PublicKeys:           publicKeysHex,
-		MinimumSignatures:    d.MinimumSignatures,
-		ECDSA:                d.ECDSA,
+		Version:               LastVersion,
+		EncryptedMnemonics:    encryptedMnemonicsJSON,
+		ExtendedPublicKeys:    d.ExtendedPublicKeys,
+		MinimumSignatures:     d.MinimumSignatures,
+		CosignerIndex:         d.CosignerIndex,
+		LastUsedExternalIndex: d.LastUsedExternalIndex,
+		LastUsedInternalIndex: d.LastUsedInternalIndex,
+		ECDSA:                 d.ECDSA,
+		WatchOnly:             d.WatchOnly,
 	}
 }
 
 func (d *Data) fromJSON(fileJSON *keysFileJSON) error {
+	if fileJSON.Version != LastVersion {
+		return errors.Errorf("keys file version %d is not supported (expected version %d)",
+			fileJSON.Version, LastVersion)
+	}
+
 	d.MinimumSignatures = fileJSON.MinimumSignatures
+	d.CosignerIndex = fileJSON.CosignerIndex
+	d.LastUsedExternalIndex = fileJSON.LastUsedExternalIndex
+	d.LastUsedInternalIndex = fileJSON.LastUsedInternalIndex
 	d.ECDSA = fileJSON.ECDSA
+	d.ExtendedPublicKeys = fileJSON.ExtendedPublicKeys
+	d.WatchOnly = fileJSON.WatchOnly
 
-	d.encryptedPrivateKeys = make([]*EncryptedPrivateKey, len(fileJSON.EncryptedPrivateKeys))
-	for i, encryptedPrivateKeyJSON := range fileJSON.EncryptedPrivateKeys {
-		cipher, err := hex.DecodeString(encryptedPrivateKeyJSON.Cipher)
+	if d.WatchOnly && len(fileJSON.EncryptedMnemonics) > 0 {
+		return errors.New("a watch-only keys file must not contain encrypted mnemonics")
+	}
+	if !d.WatchOnly && len(fileJSON.EncryptedMnemonics) == 0 {
+		return errors.New("the keys file contains no encrypted mnemonics and is not marked as watch-only")
+	}
+
+	d.encryptedMnemonics = make([]*EncryptedMnemonic, len(fileJSON.EncryptedMnemonics))
+	for i, encryptedMnemonicJSON := range fileJSON.EncryptedMnemonics {
+		cipher, err := hex.DecodeString(encryptedMnemonicJSON.Cipher)
 		if err != nil {
 			return err
 		}
 
-		salt, err := hex.DecodeString(encryptedPrivateKeyJSON.Salt)
+		salt, err := hex.DecodeString(encryptedMnemonicJSON.Salt)
 		if err != nil {
 			return err
 		}
 
-		d.encryptedPrivateKeys[i] = &EncryptedPrivateKey{
+		d.encryptedMnemonics[i] = &EncryptedMnemonic{
 			cipher: cipher,
 			salt:   salt,
 		}
 	}
 
-	d.PublicKeys = make([][]byte, len(fileJSON.PublicKeys))
-	for i, publicKey := range fileJSON.PublicKeys {
-		var err error
-		d.PublicKeys[i], err = hex.DecodeString(publicKey)
-		if err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
-// DecryptPrivateKeys asks the user to enter the password for the private keys and
-// returns the decrypted private keys.
-func (d *Data) DecryptPrivateKeys() ([][]byte, error) {
+// DecryptMnemonics asks the user to enter the password for the mnemonics and
+// returns the decrypted mnemonics. It returns ErrWatchOnly for watch-only wallets.
+func (d *Data) DecryptMnemonics() ([]string, error) {
+	if d.WatchOnly {
+		return nil, ErrWatchOnly
+	}
+
 	password := getPassword("Password:")
-	privateKeys := make([][]byte, len(d.encryptedPrivateKeys))
-	for i, encryptedPrivateKey := range d.encryptedPrivateKeys {
-		var err error
-		privateKeys[i], err = decryptPrivateKey(encryptedPrivateKey, password)
+	mnemonics := make([]string, len(d.encryptedMnemonics))
+	for i, encryptedMnemonic := range d.encryptedMnemonics {
+		mnemonic, err := decryptMnemonic(encryptedMnemonic, password)
 		if err != nil {
 			return nil, err
 		}
+		mnemonics[i] = string(mnemonic)
 	}
 
-	return privateKeys, nil
+	return mnemonics, nil
+}
+
+// IsMultisig returns whether the keys file describes a multisig wallet
+func (d *Data) IsMultisig() bool {
+	return len(d.ExtendedPublicKeys) > 1
+}
+
+// Save writes the keys file data back into the file it was read from
+func (d *Data) Save() error {
+	return writeKeysFile(d.path, d)
 }
 
 // ReadKeysFile returns the data related to the keys file
@@ -129,10 +173,19 @@ func ReadKeysFile(netParams *dagconfig.Params, path string) (*Data, error) {
 		path = defaultKeysFile(netParams)
 	}
 
+	version, err := keysFileVersion(path)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return nil, errors.Wrapf(ErrLegacyKeysFile, "cannot read %s", path)
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
 	decoder := json.NewDecoder(file)
 	decoder.DisallowUnknownFields()
@@ -142,7 +195,7 @@ func ReadKeysFile(netParams *dagconfig.Params, path string) (*Data, error) {
 		return nil, err
 	}
 
-	keysFile := &Data{}
+	keysFile := &Data{path: path}
 	err = keysFile.fromJSON(decodedFile)
 	if err != nil {
 		return nil, err
@@ -180,9 +233,10 @@ func pathExists(path string) (bool, error) {
 	return false, err
 }
 
-// WriteKeysFile writes a keys file with the given data
-func WriteKeysFile(netParams *dagconfig.Params, path string, encryptedPrivateKeys []*EncryptedPrivateKey,
-	publicKeys [][]byte, minimumSignatures uint32, ecdsa bool) error {
+// WriteKeysFile writes a keys file with the given data. If no encrypted mnemonics
+// are given, the keys file describes a watch-only wallet.
+func WriteKeysFile(netParams *dagconfig.Params, path string, encryptedMnemonics []*EncryptedMnemonic,
+	extendedPublicKeys []string, minimumSignatures uint32, cosignerIndex uint32, ecdsa bool) error {
 
 	if path == "" {
 		path = defaultKeysFile(netParams)
@@ -206,32 +260,38 @@ func WriteKeysFile(netParams *dagconfig.Params, path string, encryptedPrivateKey
 		}
 	}
 
-	err = createFileDirectoryIfDoesntExist(path)
-	if err != nil {
-		return err
+	keysFile := &Data{
+		encryptedMnemonics: encryptedMnemonics,
+		ExtendedPublicKeys: extendedPublicKeys,
+		MinimumSignatures:  minimumSignatures,
+		CosignerIndex:      cosignerIndex,
+		ECDSA:              ecdsa,
+		WatchOnly:          len(encryptedMnemonics) == 0,
 	}
 
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0600)
+	err = writeKeysFile(path, keysFile)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	keysFile := &Data{
-		encryptedPrivateKeys: encryptedPrivateKeys,
-		PublicKeys:           publicKeys,
-		MinimumSignatures:    minimumSignatures,
-		ECDSA:                ecdsa,
+	fmt.Printf("Wrote the keys into %s\n", path)
+	return nil
+}
+
+func writeKeysFile(path string, keysFile *Data) error {
+	err := createFileDirectoryIfDoesntExist(path)
+	if err != nil {
+		return err
 	}
 
-	encoder := json.NewEncoder(file)
-	err = encoder.Encode(keysFile.toJSON())
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
-	fmt.Printf("Wrote the keys into %s\n", path)
-	return nil
+	encoder := json.NewEncoder(file)
+	return encoder.Encode(keysFile.toJSON())
 }
 
 func getAEAD(password, salt []byte) (cipher.AEAD, error) {
@@ -239,18 +299,18 @@ func getAEAD(password, salt []byte) (cipher.AEAD, error) {
 	return chacha20poly1305.NewX(key)
 }
 
-func decryptPrivateKey(encryptedPrivateKey *EncryptedPrivateKey, password []byte) ([]byte, error) {
-	aead, err := getAEAD(password, encryptedPrivateKey.salt)
+func decryptMnemonic(encryptedMnemonic *EncryptedMnemonic, password []byte) ([]byte, error) {
+	aead, err := getAEAD(password, encryptedMnemonic.salt)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(encryptedPrivateKey.cipher) < aead.NonceSize() {
+	if len(encryptedMnemonic.cipher) < aead.NonceSize() {
 		return nil, errors.New("ciphertext too short")
 	}
 
 	// Split nonce and ciphertext.
-	nonce, ciphertext := encryptedPrivateKey.cipher[:aead.NonceSize()], encryptedPrivateKey.cipher[aead.NonceSize():]
+	nonce, ciphertext := encryptedMnemonic.cipher[:aead.NonceSize()], encryptedMnemonic.cipher[aead.NonceSize():]
 
 	// Decrypt the message and check it wasn't tampered with.
 	return aead.Open(nil, nonce, ciphertext, nil)
@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+// ErrLegacyKeysFile is returned when a keys file that was created before kaspawallet
+// derived its keys from mnemonics is read as a current keys file
+var ErrLegacyKeysFile = errors.New("this keys file was created by an older version of kaspawallet, which " +
+	"didn't derive its keys from a mnemonic. Create a new wallet and move the funds into it with sweep-legacy")
+
+// legacyKeysFileJSON is the format of keys files that predate versioning, which is
+// considered version 0
+type legacyKeysFileJSON struct {
+	EncryptedPrivateKeys []*encryptedPrivateKeyJSON `json:"encryptedPrivateKeys"`
+	PublicKeys           []string                   `json:"publicKeys"`
+	MinimumSignatures    uint32                     `json:"minimumSignatures"`
+	ECDSA                bool                       `json:"ecdsa"`
+}
+
+// LegacyData holds the data of a legacy keys file, which contains raw private keys
+// instead of mnemonics, and has a single address
+type LegacyData struct {
+	encryptedPrivateKeys []*EncryptedMnemonic
+	PublicKeys           [][]byte
+	MinimumSignatures    uint32
+	ECDSA                bool
+}
+
+func (d *LegacyData) fromJSON(fileJSON *legacyKeysFileJSON) error {
+	d.MinimumSignatures = fileJSON.MinimumSignatures
+	d.ECDSA = fileJSON.ECDSA
+
+	d.encryptedPrivateKeys = make([]*EncryptedMnemonic, len(fileJSON.EncryptedPrivateKeys))
+	for i, encryptedPrivateKeyJSON := range fileJSON.EncryptedPrivateKeys {
+		cipher, err := hex.DecodeString(encryptedPrivateKeyJSON.Cipher)
+		if err != nil {
+			return err
+		}
+
+		salt, err := hex.DecodeString(encryptedPrivateKeyJSON.Salt)
+		if err != nil {
+			return err
+		}
+
+		d.encryptedPrivateKeys[i] = &EncryptedMnemonic{
+			cipher: cipher,
+			salt:   salt,
+		}
+	}
+
+	d.PublicKeys = make([][]byte, len(fileJSON.PublicKeys))
+	for i, publicKey := range fileJSON.PublicKeys {
+		var err error
+		d.PublicKeys[i], err = hex.DecodeString(publicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecryptPrivateKeys asks the user to enter the password for the private keys and
+// returns the decrypted private keys.
+func (d *LegacyData) DecryptPrivateKeys() ([][]byte, error) {
+	return d.decryptPrivateKeys(getPassword("Password:"))
+}
+
+func (d *LegacyData) decryptPrivateKeys(password []byte) ([][]byte, error) {
+	// The private keys were encrypted the same way mnemonics are
+	privateKeys := make([][]byte, len(d.encryptedPrivateKeys))
+	for i, encryptedPrivateKey := range d.encryptedPrivateKeys {
+		var err error
+		privateKeys[i], err = decryptMnemonic(encryptedPrivateKey, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return privateKeys, nil
+}
+
+// keysFileVersion returns the version of the keys file in the given path. Files that predate
+// versioning have no version field, and are considered version 0.
+func keysFileVersion(path string) (uint32, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	versionJSON := &struct {
+		Version uint32 `json:"version"`
+	}{}
+	err = json.Unmarshal(content, versionJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	return versionJSON.Version, nil
+}
+
+// ReadLegacyKeysFile returns the data of a keys file that was created before kaspawallet
+// derived its keys from mnemonics
+func ReadLegacyKeysFile(netParams *dagconfig.Params, path string) (*LegacyData, error) {
+	if path == "" {
+		path = defaultKeysFile(netParams)
+	}
+
+	version, err := keysFileVersion(path)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 {
+		return nil, errors.Errorf("the keys file %s is of version %d, which isn't a legacy keys file", path, version)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	decodedFile := &legacyKeysFileJSON{}
+	err = decoder.Decode(&decodedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keysFile := &LegacyData{}
+	err = keysFile.fromJSON(decodedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysFile, nil
+}
@@ -0,0 +1,81 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+func TestReadLegacyKeysFile(t *testing.T) {
+	password := []byte("password")
+	privateKey, publicKey, err := libkaspawallet.CreateKeyPair(false)
+	if err != nil {
+		t.Fatalf("CreateKeyPair: %+v", err)
+	}
+	encryptedPrivateKey, err := encryptMnemonic(string(privateKey), password)
+	if err != nil {
+		t.Fatalf("encryptMnemonic: %+v", err)
+	}
+	_, cosignerPublicKey, err := libkaspawallet.CreateKeyPair(false)
+	if err != nil {
+		t.Fatalf("CreateKeyPair: %+v", err)
+	}
+
+	// This is the format of the keys files written before keys files were versioned
+	legacyKeysFileContent := fmt.Sprintf(`{"encryptedPrivateKeys":[{"cipher":"%s","salt":"%s"}],`+
+		`"publicKeys":["%s","%s"],"minimumSignatures":1,"ecdsa":false}`,
+		hex.EncodeToString(encryptedPrivateKey.cipher), hex.EncodeToString(encryptedPrivateKey.salt),
+		hex.EncodeToString(publicKey), hex.EncodeToString(cosignerPublicKey))
+	path := filepath.Join(t.TempDir(), "keys.json")
+	err = os.WriteFile(path, []byte(legacyKeysFileContent), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+
+	_, err = ReadKeysFile(&dagconfig.SimnetParams, path)
+	if !errors.Is(err, ErrLegacyKeysFile) {
+		t.Fatalf("ReadKeysFile: expected ErrLegacyKeysFile, got: %v", err)
+	}
+
+	legacyKeysFile, err := ReadLegacyKeysFile(&dagconfig.SimnetParams, path)
+	if err != nil {
+		t.Fatalf("ReadLegacyKeysFile: %+v", err)
+	}
+	if legacyKeysFile.MinimumSignatures != 1 || legacyKeysFile.ECDSA {
+		t.Fatalf("Unexpected legacy keys file parameters: minimum signatures %d, ECDSA %t",
+			legacyKeysFile.MinimumSignatures, legacyKeysFile.ECDSA)
+	}
+	if len(legacyKeysFile.PublicKeys) != 2 || !bytes.Equal(legacyKeysFile.PublicKeys[0], publicKey) ||
+		!bytes.Equal(legacyKeysFile.PublicKeys[1], cosignerPublicKey) {
+		t.Fatalf("Unexpected public keys %x", legacyKeysFile.PublicKeys)
+	}
+
+	privateKeys, err := legacyKeysFile.decryptPrivateKeys(password)
+	if err != nil {
+		t.Fatalf("decryptPrivateKeys: %+v", err)
+	}
+	if len(privateKeys) != 1 || !bytes.Equal(privateKeys[0], privateKey) {
+		t.Fatalf("The decrypted private keys don't match the encrypted ones")
+	}
+	_, err = legacyKeysFile.decryptPrivateKeys([]byte("wrong password"))
+	if err == nil {
+		t.Fatalf("decryptPrivateKeys unexpectedly succeeded with a wrong password")
+	}
+
+	// A current keys file isn't a legacy keys file
+	err = writeKeysFile(path, &Data{ExtendedPublicKeys: []string{"xpub"}, MinimumSignatures: 1, WatchOnly: true})
+	if err != nil {
+		t.Fatalf("writeKeysFile: %+v", err)
+	}
+	_, err = ReadLegacyKeysFile(&dagconfig.SimnetParams, path)
+	if err == nil {
+		t.Fatalf("ReadLegacyKeysFile unexpectedly succeeded reading a current keys file")
+	}
+}
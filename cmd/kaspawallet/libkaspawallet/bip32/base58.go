@@ -0,0 +1,86 @@
+package bip32
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const checksumLength = 4
+
+var bigRadix = big.NewInt(58)
+
+func base58Encode(data []byte) string {
+	number := new(big.Int).SetBytes(data)
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	modulo := new(big.Int)
+	for number.Sign() > 0 {
+		number.DivMod(number, bigRadix, modulo)
+		encoded = append(encoded, base58Alphabet[modulo.Int64()])
+	}
+
+	// Leading zero bytes are encoded as the first alphabet character.
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+
+	return string(encoded)
+}
+
+func base58Decode(encoded string) ([]byte, error) {
+	number := new(big.Int)
+	for _, char := range []byte(encoded) {
+		digit := bytes.IndexByte([]byte(base58Alphabet), char)
+		if digit == -1 {
+			return nil, errors.Errorf("invalid base58 character '%c'", char)
+		}
+		number.Mul(number, bigRadix)
+		number.Add(number, big.NewInt(int64(digit)))
+	}
+
+	numLeadingZeros := 0
+	for numLeadingZeros < len(encoded) && encoded[numLeadingZeros] == base58Alphabet[0] {
+		numLeadingZeros++
+	}
+
+	return append(make([]byte, numLeadingZeros), number.Bytes()...), nil
+}
+
+func doubleSha256Checksum(data []byte) []byte {
+	firstHash := sha256.Sum256(data)
+	secondHash := sha256.Sum256(firstHash[:])
+	return secondHash[:checksumLength]
+}
+
+func base58CheckEncode(data []byte) string {
+	return base58Encode(append(data, doubleSha256Checksum(data)...))
+}
+
+func base58CheckDecode(encoded string) ([]byte, error) {
+	decoded, err := base58Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) < checksumLength {
+		return nil, errors.Errorf("base58 string is too short")
+	}
+
+	data, checksum := decoded[:len(decoded)-checksumLength], decoded[len(decoded)-checksumLength:]
+	if !bytes.Equal(checksum, doubleSha256Checksum(data)) {
+		return nil, errors.Errorf("invalid base58 checksum")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+
+	"github.com/kaspanet/go-secp256k1"
+)
+
+// NewMasterWithPath returns a new master key based on the given seed and version, with a derivation
+// to the given path.
+func NewMasterWithPath(seed []byte, version [4]byte, pathString string) (*ExtendedKey, error) {
+	masterKey, err := NewMaster(seed, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return masterKey.DeriveFromPath(pathString)
+}
+
+// NewMaster returns a new master key based on the given seed and version.
+func NewMaster(seed []byte, version [4]byte) (*ExtendedKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	_, err := mac.Write(seed)
+	if err != nil {
+		return nil, err
+	}
+	hash := mac.Sum(nil)
+
+	privateKey, err := secp256k1.DeserializeECDSAPrivateKeyFromSlice(hash[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	extKey := &ExtendedKey{
+		privateKey:        privateKey,
+		Version:           version,
+		Depth:             0,
+		ParentFingerprint: [4]byte{},
+		ChildNumber:       0,
+	}
+	copy(extKey.ChainCode[:], hash[32:])
+
+	return extKey, nil
+}
@@ -0,0 +1,155 @@
+package bip32
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBIP32SpecVectors(t *testing.T) {
+	type testPath struct {
+		path            string
+		extendedPublic  string
+		extendedPrivate string
+	}
+
+	// Test vectors 1 and 2 taken from https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+	tests := []struct {
+		seed  string
+		paths []testPath
+	}{
+		{
+			seed: "000102030405060708090a0b0c0d0e0f",
+			paths: []testPath{
+				{
+					path:            "m",
+					extendedPublic:  "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+					extendedPrivate: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+				},
+				{
+					path:            "m/0'",
+					extendedPublic:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+					extendedPrivate: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+				},
+				{
+					path:            "m/0'/1",
+					extendedPublic:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+					extendedPrivate: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+				},
+				{
+					path:            "m/0'/1/2'/2/1000000000",
+					extendedPublic:  "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+					extendedPrivate: "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+				},
+			},
+		},
+		{
+			seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			paths: []testPath{
+				{
+					path:            "m",
+					extendedPublic:  "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+					extendedPrivate: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+				},
+				{
+					path:            "m/0/2147483647'/1/2147483646'/2",
+					extendedPublic:  "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+					extendedPrivate: "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+				},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		seed, err := hex.DecodeString(test.seed)
+		if err != nil {
+			t.Fatalf("DecodeString: %+v", err)
+		}
+
+		masterKey, err := NewMaster(seed, BitcoinMainnetPrivate)
+		if err != nil {
+			t.Fatalf("NewMaster: %+v", err)
+		}
+
+		for j, path := range test.paths {
+			extendedPrivateKey, err := masterKey.DeriveFromPath(path.path)
+			if err != nil {
+				t.Fatalf("DeriveFromPath: %+v", err)
+			}
+
+			if extendedPrivateKey.String() != path.extendedPrivate {
+				t.Fatalf("Test (%d, %d): expected extended private key %s but got %s",
+					i, j, path.extendedPrivate, extendedPrivateKey.String())
+			}
+
+			decodedExtendedPrivateKey, err := DeserializeExtendedKey(extendedPrivateKey.String())
+			if err != nil {
+				t.Fatalf("DeserializeExtendedKey: %+v", err)
+			}
+
+			if decodedExtendedPrivateKey.String() != extendedPrivateKey.String() {
+				t.Fatalf("Test (%d, %d): deserializing and serializing the extended private key "+
+					"didn't preserve the data", i, j)
+			}
+
+			extendedPublicKey, err := extendedPrivateKey.Public()
+			if err != nil {
+				t.Fatalf("Public: %+v", err)
+			}
+
+			if extendedPublicKey.String() != path.extendedPublic {
+				t.Fatalf("Test (%d, %d): expected extended public key %s but got %s",
+					i, j, path.extendedPublic, extendedPublicKey.String())
+			}
+		}
+	}
+}
+
+func TestPublicParentPublicChildDerivation(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("DecodeString: %+v", err)
+	}
+
+	for _, version := range []([4]byte){KaspaMainnetPrivate, KaspaTestnetPrivate, KaspaDevnetPrivate, KaspaSimnetPrivate} {
+		masterKey, err := NewMasterWithPath(seed, version, "m/44'/111111'/0'")
+		if err != nil {
+			t.Fatalf("NewMasterWithPath: %+v", err)
+		}
+
+		masterPublicKey, err := masterKey.Public()
+		if err != nil {
+			t.Fatalf("Public: %+v", err)
+		}
+
+		decodedMasterPublicKey, err := DeserializeExtendedKey(masterPublicKey.String())
+		if err != nil {
+			t.Fatalf("DeserializeExtendedKey: %+v", err)
+		}
+
+		const path = "m/1/5"
+		childFromPrivate, err := masterKey.DeriveFromPath(path)
+		if err != nil {
+			t.Fatalf("DeriveFromPath: %+v", err)
+		}
+
+		childFromPrivatePublic, err := childFromPrivate.Public()
+		if err != nil {
+			t.Fatalf("Public: %+v", err)
+		}
+
+		childFromPublic, err := decodedMasterPublicKey.DeriveFromPath(path)
+		if err != nil {
+			t.Fatalf("DeriveFromPath: %+v", err)
+		}
+
+		if childFromPrivatePublic.String() != childFromPublic.String() {
+			t.Fatalf("expected the public child derived from the public parent (%s) to be equal to "+
+				"the public child derived from the private parent (%s)", childFromPublic, childFromPrivatePublic)
+		}
+
+		_, err = decodedMasterPublicKey.DeriveFromPath("m/1'")
+		if err == nil {
+			t.Fatalf("expected deriving a hardened child from a public key to fail")
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package bip32
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// HardenedIndexStart is the index of the first hardened key (2^31).
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#extended-keys
+	HardenedIndexStart = 0x80000000
+
+	// MaxDepth is the maximum depth of an extended key.
+	MaxDepth = 0xFF
+
+	serializedKeyLength = 33
+
+	// versionLength + depthLength + fingerprintLength + childNumberLength + chainCodeLength + serializedKeyLength
+	serializedExtendedKeyLength = 4 + 1 + 4 + 4 + 32 + serializedKeyLength
+)
+
+// ExtendedKey is a bip32 extended key
+type ExtendedKey struct {
+	privateKey        *secp256k1.ECDSAPrivateKey
+	publicKey         *secp256k1.ECDSAPublicKey
+	Version           [4]byte
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+	ChainCode         [32]byte
+}
+
+// PrivateKey returns the ECDSA private key of the extended key.
+// It returns an error if the extended key is a public key.
+func (extKey *ExtendedKey) PrivateKey() (*secp256k1.ECDSAPrivateKey, error) {
+	if !extKey.IsPrivate() {
+		return nil, errors.Errorf("extended key is not a private key")
+	}
+
+	return extKey.privateKey, nil
+}
+
+// PublicKey returns the ECDSA public key of the extended key.
+func (extKey *ExtendedKey) PublicKey() (*secp256k1.ECDSAPublicKey, error) {
+	if extKey.publicKey != nil {
+		return extKey.publicKey, nil
+	}
+
+	publicKey, err := extKey.privateKey.ECDSAPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	extKey.publicKey = publicKey
+	return publicKey, nil
+}
+
+// IsPrivate returns whether the extended key is a private key
+func (extKey *ExtendedKey) IsPrivate() bool {
+	return isPrivateVersion(extKey.Version)
+}
+
+// Public returns the public extended key that matches
+// this extended key
+func (extKey *ExtendedKey) Public() (*ExtendedKey, error) {
+	if !extKey.IsPrivate() {
+		return extKey, nil
+	}
+
+	publicKey, err := extKey.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := toPublicVersion(extKey.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendedKey{
+		publicKey:         publicKey,
+		Version:           version,
+		Depth:             extKey.Depth,
+		ParentFingerprint: extKey.ParentFingerprint,
+		ChildNumber:       extKey.ChildNumber,
+		ChainCode:         extKey.ChainCode,
+	}, nil
+}
+
+// Child return the i'th derived child of extKey.
+func (extKey *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	if extKey.Depth == MaxDepth {
+		return nil, errors.Errorf("cannot derive a key with depth greater than %d", MaxDepth)
+	}
+
+	isHardened := i >= HardenedIndexStart
+	if isHardened && !extKey.IsPrivate() {
+		return nil, errors.Errorf("cannot derive a hardened key from a public key")
+	}
+
+	fingerprint, err := extKey.calcFingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if isHardened {
+		data = append([]byte{0x00}, extKey.privateKey.Serialize()[:]...)
+	} else {
+		publicKey, err := extKey.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+
+		serializedPublicKey, err := publicKey.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		data = append([]byte{}, serializedPublicKey[:]...)
+	}
+	data = append(data, serializeUint32(i)...)
+
+	mac := hmac.New(sha512.New, extKey.ChainCode[:])
+	_, err = mac.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	hash := mac.Sum(nil)
+
+	var tweak [32]byte
+	copy(tweak[:], hash[:32])
+
+	child := &ExtendedKey{
+		Version:           extKey.Version,
+		Depth:             extKey.Depth + 1,
+		ParentFingerprint: fingerprint,
+		ChildNumber:       i,
+	}
+	copy(child.ChainCode[:], hash[32:])
+
+	if extKey.IsPrivate() {
+		child.privateKey, err = secp256k1.DeserializeECDSAPrivateKey(extKey.privateKey.Serialize())
+		if err != nil {
+			return nil, err
+		}
+
+		err = child.privateKey.Add(tweak)
+		if err != nil {
+			return nil, err
+		}
+
+		return child, nil
+	}
+
+	serializedPublicKey, err := extKey.publicKey.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	child.publicKey, err = secp256k1.DeserializeECDSAPubKey(serializedPublicKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	err = child.publicKey.Add(tweak)
+	if err != nil {
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// DeriveFromPath derives an extended key from the given path
+// (e.g. "m/44'/111111'/0'/0/1") relative to extKey.
+func (extKey *ExtendedKey) DeriveFromPath(pathString string) (*ExtendedKey, error) {
+	indexes, err := parsePath(pathString)
+	if err != nil {
+		return nil, err
+	}
+
+	descendantExtendedKey := extKey
+	for _, index := range indexes {
+		descendantExtendedKey, err = descendantExtendedKey.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return descendantExtendedKey, nil
+}
+
+func (extKey *ExtendedKey) calcFingerprint() ([4]byte, error) {
+	publicKey, err := extKey.PublicKey()
+	if err != nil {
+		return [4]byte{}, err
+	}
+
+	serializedPublicKey, err := publicKey.Serialize()
+	if err != nil {
+		return [4]byte{}, err
+	}
+
+	var fingerprint [4]byte
+	copy(fingerprint[:], hash160(serializedPublicKey[:])[:4])
+	return fingerprint, nil
+}
+
+func (extKey *ExtendedKey) serialize() ([]byte, error) {
+	serialized := make([]byte, 0, serializedExtendedKeyLength)
+	serialized = append(serialized, extKey.Version[:]...)
+	serialized = append(serialized, extKey.Depth)
+	serialized = append(serialized, extKey.ParentFingerprint[:]...)
+	serialized = append(serialized, serializeUint32(extKey.ChildNumber)...)
+	serialized = append(serialized, extKey.ChainCode[:]...)
+	if extKey.IsPrivate() {
+		serialized = append(serialized, 0x00)
+		serialized = append(serialized, extKey.privateKey.Serialize()[:]...)
+	} else {
+		serializedPublicKey, err := extKey.publicKey.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		serialized = append(serialized, serializedPublicKey[:]...)
+	}
+
+	return serialized, nil
+}
+
+// String returns the extended key in its base58 representation
+func (extKey *ExtendedKey) String() string {
+	serialized, err := extKey.serialize()
+	if err != nil {
+		return "<Invalid ExtendedKey>"
+	}
+
+	return base58CheckEncode(serialized)
+}
+
+// DeserializeExtendedKey deserializes the given base58 string to an extended key
+func DeserializeExtendedKey(extKeyString string) (*ExtendedKey, error) {
+	serialized, err := base58CheckDecode(extKeyString)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(serialized) != serializedExtendedKeyLength {
+		return nil, errors.Errorf("extended key length is %d while it's expected to be %d",
+			len(serialized), serializedExtendedKeyLength)
+	}
+
+	extKey := &ExtendedKey{}
+	copy(extKey.Version[:], serialized[:4])
+	extKey.Depth = serialized[4]
+	copy(extKey.ParentFingerprint[:], serialized[5:9])
+	extKey.ChildNumber = binary.BigEndian.Uint32(serialized[9:13])
+	copy(extKey.ChainCode[:], serialized[13:45])
+
+	serializedKey := serialized[45:]
+	if extKey.IsPrivate() {
+		if serializedKey[0] != 0x00 {
+			return nil, errors.Errorf("private extended key has an invalid key prefix %x", serializedKey[0])
+		}
+
+		extKey.privateKey, err = secp256k1.DeserializeECDSAPrivateKeyFromSlice(serializedKey[1:])
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if !isPublicVersion(extKey.Version) {
+			return nil, errors.Errorf("unknown extended key version %x", extKey.Version)
+		}
+
+		extKey.publicKey, err = secp256k1.DeserializeECDSAPubKey(serializedKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return extKey, nil
+}
+
+func serializeUint32(v uint32) []byte {
+	serialized := make([]byte, 4)
+	binary.BigEndian.PutUint32(serialized, v)
+	return serialized
+}
+
+func hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	_, err := hasher.Write(sha[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,48 @@
+package bip32
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parsePath parses a derivation path such as "m/44'/111111'/0'/0/1"
+// into its child indexes.
+func parsePath(pathString string) ([]uint32, error) {
+	parts := strings.Split(pathString, "/")
+	if parts[0] != "m" {
+		return nil, errors.Errorf("path %s doesn't start with 'm'", pathString)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		index, err := parseIndex(part)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+func parseIndex(indexString string) (uint32, error) {
+	isHardened := strings.HasSuffix(indexString, "'")
+	trimmedIndexString := strings.TrimSuffix(indexString, "'")
+
+	index, err := strconv.ParseUint(trimmedIndexString, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid path index %s", indexString)
+	}
+
+	if index >= HardenedIndexStart {
+		return 0, errors.Errorf("path index %s is too big", indexString)
+	}
+
+	if isHardened {
+		return uint32(index) + HardenedIndexStart, nil
+	}
+
+	return uint32(index), nil
+}
@@ -0,0 +1,101 @@
+package libkaspawallet
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/bip32"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// SingleSignerPurpose is the purpose used in the derivation path of single signer wallets.
+	// See https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
+	SingleSignerPurpose = 44
+
+	// MultiSigPurpose is the purpose used in the derivation path of multisig wallets.
+	// See https://github.com/bitcoin/bips/blob/master/bip-0045.mediawiki
+	MultiSigPurpose = 45
+
+	// CoinType is the coin type used in the derivation path of kaspa wallets.
+	CoinType = 111111
+)
+
+// CreateMnemonic creates a new bip-39 compatible mnemonic
+func CreateMnemonic() (string, error) {
+	const bip39BitSize = 256
+	entropy, err := bip39.NewEntropy(bip39BitSize)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic returns an error if the given string is not a valid bip-39 mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return errors.Errorf("invalid mnemonic")
+	}
+
+	return nil
+}
+
+func defaultPath(isMultisig bool) string {
+	purpose := SingleSignerPurpose
+	if isMultisig {
+		purpose = MultiSigPurpose
+	}
+
+	return fmt.Sprintf("m/%d'/%d'/0'", purpose, CoinType)
+}
+
+// MasterPublicKeyFromMnemonic returns the master public key with the correct derivation for the given mnemonic.
+func MasterPublicKeyFromMnemonic(params *dagconfig.Params, mnemonic string, isMultisig bool) (string, error) {
+	extendedKey, err := extendedKeyFromMnemonicAndPath(mnemonic, defaultPath(isMultisig), params)
+	if err != nil {
+		return "", err
+	}
+
+	extendedPublicKey, err := extendedKey.Public()
+	if err != nil {
+		return "", err
+	}
+
+	return extendedPublicKey.String(), nil
+}
+
+func extendedKeyFromMnemonicAndPath(mnemonic string, path string, params *dagconfig.Params) (*bip32.ExtendedKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := versionFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := bip32.NewMasterWithPath(seed, version, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return master, nil
+}
+
+func versionFromParams(params *dagconfig.Params) ([4]byte, error) {
+	switch params.Name {
+	case dagconfig.MainnetParams.Name:
+		return bip32.KaspaMainnetPrivate, nil
+	case dagconfig.TestnetParams.Name:
+		return bip32.KaspaTestnetPrivate, nil
+	case dagconfig.DevnetParams.Name:
+		return bip32.KaspaDevnetPrivate, nil
+	case dagconfig.SimnetParams.Name:
+		return bip32.KaspaSimnetPrivate, nil
+	}
+
+	return [4]byte{}, errors.Errorf("unknown network %s", params.Name)
+}
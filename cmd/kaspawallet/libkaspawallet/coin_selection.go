@@ -0,0 +1,329 @@
+package libkaspawallet
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// DefaultFeeRate is the default fee rate in sompi/gram
+const DefaultFeeRate = 1.0
+
+// maxBranchAndBoundTries is the maximum number of nodes the branch-and-bound search visits
+// before giving up on finding a changeless selection
+const maxBranchAndBoundTries = 100000
+
+// ErrTransactionTooLarge is returned when the selected UTXOs and the payments don't fit
+// in a transaction that would be relayed by the network
+var ErrTransactionTooLarge = errors.New("transaction too large")
+
+// CoinSelectionStrategy is a method to select the UTXOs that fund a transaction
+type CoinSelectionStrategy uint8
+
+const (
+	// CoinSelectionBranchAndBound looks for a set of UTXOs that pays for the transaction with no
+	// change output, wasting at most the cost of creating and later spending a change output.
+	// If no such set is found, it falls back to CoinSelectionLargestFirst.
+	CoinSelectionBranchAndBound CoinSelectionStrategy = iota
+
+	// CoinSelectionLargestFirst selects the largest UTXOs until the transaction is funded.
+	CoinSelectionLargestFirst
+)
+
+var coinSelectionStrategyStrings = map[CoinSelectionStrategy]string{
+	CoinSelectionBranchAndBound: "branch-and-bound",
+	CoinSelectionLargestFirst:   "largest-first",
+}
+
+func (s CoinSelectionStrategy) String() string {
+	if str, ok := coinSelectionStrategyStrings[s]; ok {
+		return str
+	}
+	return "unknown"
+}
+
+// ParseCoinSelectionStrategy returns the coin selection strategy matching the given string
+func ParseCoinSelectionStrategy(str string) (CoinSelectionStrategy, error) {
+	for strategy, strategyString := range coinSelectionStrategyStrings {
+		if strategyString == str {
+			return strategy, nil
+		}
+	}
+	return 0, errors.Errorf("unknown coin selection strategy '%s'", str)
+}
+
+// feeEstimator calculates the fee of a transaction by the number of its inputs and
+// by whether it has a change output. Since all the inputs of a wallet spend the same
+// kind of script, every input adds the same mass and size to the transaction.
+type feeEstimator struct {
+	feeRate     float64
+	numPayments int
+
+	baseMass, baseSize     uint64
+	inputMass, inputSize   uint64
+	changeMass, changeSize uint64
+}
+
+func newFeeEstimator(params *dagconfig.Params, extendedPublicKeys []string, minimumSignatures uint32, ecdsa bool,
+	payments []*Payment, changeAddress util.Address, sampleUTXO *UTXO, feeRate float64) (*feeEstimator, error) {
+
+	sortedExtendedPublicKeys := sortPublicKeys(extendedPublicKeys)
+	massAndSize := func(payments []*Payment, utxos []*UTXO) (uint64, uint64, error) {
+		// Whether the transaction is replaceable doesn't affect its mass
+		psTx, err := createUnsignedTransaction(sortedExtendedPublicKeys, minimumSignatures, ecdsa, payments, utxos,
+			false)
+		if err != nil {
+			return 0, 0, err
+		}
+		return transactionMassAndSize(params, psTx)
+	}
+
+	baseMass, baseSize, err := massAndSize(payments, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	massWithInput, sizeWithInput, err := massAndSize(payments, []*UTXO{sampleUTXO})
+	if err != nil {
+		return nil, err
+	}
+
+	paymentsWithChange := append(payments[:len(payments):len(payments)], &Payment{Address: changeAddress})
+	massWithChange, sizeWithChange, err := massAndSize(paymentsWithChange, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &feeEstimator{
+		feeRate:     feeRate,
+		numPayments: len(payments),
+		baseMass:    baseMass,
+		baseSize:    baseSize,
+		inputMass:   massWithInput - baseMass,
+		inputSize:   sizeWithInput - baseSize,
+		changeMass:  massWithChange - baseMass,
+		changeSize:  sizeWithChange - baseSize,
+	}, nil
+}
+
+func (fe *feeEstimator) massAndSize(numInputs int, hasChange bool) (mass uint64, size uint64) {
+	mass = fe.baseMass + uint64(numInputs)*fe.inputMass
+	size = fe.baseSize + uint64(numInputs)*fe.inputSize
+	if hasChange {
+		mass += fe.changeMass
+		size += fe.changeSize
+	}
+	return mass, size
+}
+
+func (fe *feeEstimator) numOutputs(hasChange bool) int {
+	if hasChange {
+		return fe.numPayments + 1
+	}
+	return fe.numPayments
+}
+
+func (fe *feeEstimator) feeForMass(mass uint64) uint64 {
+	return uint64(math.Ceil(float64(mass) * fe.feeRate))
+}
+
+// fee returns the fee of a transaction with the given number of inputs at the estimator's fee rate,
+// but never less than the minimum fee the mempool requires in order to relay it.
+func (fe *feeEstimator) fee(numInputs int, hasChange bool) uint64 {
+	mass, size := fe.massAndSize(numInputs, hasChange)
+	fee := fe.feeForMass(mass)
+	minimumRelayFee := uint64(relaypolicy.CalcMinRequiredTxRelayFee(int64(size), relaypolicy.DefaultMinRelayTxFee))
+	if fee < minimumRelayFee {
+		return minimumRelayFee
+	}
+	return fee
+}
+
+// SelectUTXOs selects UTXOs out of the given ones to fund the given payments along with a fee at the
+// given fee rate (in sompi/gram). It returns the selected UTXOs, the amount to send to changeAddress,
+// and the transaction fee. A change that would be considered dust is added to the fee instead, in
+// which case the returned change amount is 0 and no change output should be created.
+func SelectUTXOs(params *dagconfig.Params, extendedPublicKeys []string, minimumSignatures uint32, ecdsa bool,
+	utxos []*UTXO, payments []*Payment, changeAddress util.Address, feeRate float64,
+	strategy CoinSelectionStrategy) (selectedUTXOs []*UTXO, changeSompi uint64, fee uint64, err error) {
+
+	if feeRate < 0 {
+		return nil, 0, 0, errors.Errorf("fee rate cannot be negative")
+	}
+
+	paymentsTotal := uint64(0)
+	for _, payment := range payments {
+		scriptPublicKey, err := txscript.PayToAddrScript(payment.Address)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		output := &externalapi.DomainTransactionOutput{Value: payment.Amount, ScriptPublicKey: scriptPublicKey}
+		if relaypolicy.IsTransactionOutputDust(output, relaypolicy.DefaultMinRelayTxFee) {
+			return nil, 0, 0, errors.Errorf("the payment of %d sompi to %s is too small and would be "+
+				"considered dust", payment.Amount, payment.Address)
+		}
+		paymentsTotal += payment.Amount
+	}
+
+	if len(utxos) == 0 {
+		return nil, 0, 0, errors.Errorf("Insufficient funds for send: the wallet has no spendable UTXOs")
+	}
+
+	estimator, err := newFeeEstimator(params, extendedPublicKeys, minimumSignatures, ecdsa, payments, changeAddress,
+		utxos[0], feeRate)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	sortedUTXOs := make([]*UTXO, len(utxos))
+	copy(sortedUTXOs, utxos)
+	sort.Slice(sortedUTXOs, func(i, j int) bool {
+		return sortedUTXOs[i].UTXOEntry.Amount() > sortedUTXOs[j].UTXOEntry.Amount()
+	})
+
+	if strategy == CoinSelectionBranchAndBound {
+		selectedUTXOs, fee, found := selectUTXOsBranchAndBound(sortedUTXOs, paymentsTotal, estimator)
+		if found {
+			return selectedUTXOs, 0, fee, checkTransactionSize(params, estimator, len(selectedUTXOs), false)
+		}
+	}
+
+	selectedUTXOs, changeSompi, fee, err = selectUTXOsLargestFirst(sortedUTXOs, paymentsTotal, changeAddress, estimator)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return selectedUTXOs, changeSompi, fee, checkTransactionSize(params, estimator, len(selectedUTXOs), changeSompi > 0)
+}
+
+// selectUTXOsLargestFirst expects the UTXOs to be sorted from the largest to the smallest
+func selectUTXOsLargestFirst(sortedUTXOs []*UTXO, paymentsTotal uint64, changeAddress util.Address,
+	estimator *feeEstimator) (selectedUTXOs []*UTXO, changeSompi uint64, fee uint64, err error) {
+
+	changeScriptPublicKey, err := txscript.PayToAddrScript(changeAddress)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalValue := uint64(0)
+	for i, utxo := range sortedUTXOs {
+		totalValue += utxo.UTXOEntry.Amount()
+		numInputs := i + 1
+
+		feeWithoutChange := estimator.fee(numInputs, false)
+		if totalValue < paymentsTotal+feeWithoutChange {
+			continue
+		}
+
+		selectedUTXOs = sortedUTXOs[:numInputs]
+		feeWithChange := estimator.fee(numInputs, true)
+		if totalValue > paymentsTotal+feeWithChange {
+			changeSompi = totalValue - paymentsTotal - feeWithChange
+			changeOutput := &externalapi.DomainTransactionOutput{Value: changeSompi, ScriptPublicKey: changeScriptPublicKey}
+			if !relaypolicy.IsTransactionOutputDust(changeOutput, relaypolicy.DefaultMinRelayTxFee) {
+				return selectedUTXOs, changeSompi, feeWithChange, nil
+			}
+		}
+
+		// The change would be dust, so it's added to the fee instead
+		return selectedUTXOs, 0, totalValue - paymentsTotal, nil
+	}
+
+	requiredValue := paymentsTotal + estimator.fee(len(sortedUTXOs), false)
+	return nil, 0, 0, errors.Errorf("Insufficient funds for send: %f required, while only %f available",
+		float64(requiredValue)/util.SompiPerKaspa, float64(totalValue)/util.SompiPerKaspa)
+}
+
+// selectUTXOsBranchAndBound searches for a changeless selection of UTXOs, as described in
+// https://murch.one/wp-content/uploads/2016/11/erhardt2016coinselection.pdf. It expects the
+// UTXOs to be sorted from the largest to the smallest.
+func selectUTXOsBranchAndBound(sortedUTXOs []*UTXO, paymentsTotal uint64, estimator *feeEstimator) (
+	selectedUTXOs []*UTXO, fee uint64, found bool) {
+
+	// The effective value of a UTXO is its amount minus the fee required to spend it
+	inputFee := estimator.feeForMass(estimator.inputMass)
+	candidates := make([]*UTXO, 0, len(sortedUTXOs))
+	effectiveValues := make([]uint64, 0, len(sortedUTXOs))
+	for _, utxo := range sortedUTXOs {
+		if utxo.UTXOEntry.Amount() <= inputFee {
+			continue
+		}
+		candidates = append(candidates, utxo)
+		effectiveValues = append(effectiveValues, utxo.UTXOEntry.Amount()-inputFee)
+	}
+
+	// remainingValues[i] is the sum of the effective values of candidates[i:]
+	remainingValues := make([]uint64, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remainingValues[i] = remainingValues[i+1] + effectiveValues[i]
+	}
+
+	target := paymentsTotal + estimator.feeForMass(estimator.baseMass)
+
+	// Any excess above the target goes to the fee, so it must not exceed the cost
+	// of creating a change output and spending it later
+	costOfChange := estimator.feeForMass(estimator.changeMass) + inputFee
+	upperBound := target + costOfChange
+
+	selection := make([]int, 0, len(candidates))
+	tries := 0
+	var search func(index int, selectedValue uint64) bool
+	search = func(index int, selectedValue uint64) bool {
+		tries++
+		if tries > maxBranchAndBoundTries || selectedValue > upperBound {
+			return false
+		}
+		if selectedValue >= target {
+			return true
+		}
+		if index == len(candidates) || selectedValue+remainingValues[index] < target {
+			return false
+		}
+
+		selection = append(selection, index)
+		if search(index+1, selectedValue+effectiveValues[index]) {
+			return true
+		}
+		selection = selection[:len(selection)-1]
+
+		// Excluding a candidate and then including another of the same value leads
+		// to an already explored selection
+		nextIndex := index + 1
+		for nextIndex < len(candidates) && effectiveValues[nextIndex] == effectiveValues[index] {
+			nextIndex++
+		}
+		return search(nextIndex, selectedValue)
+	}
+	if !search(0, 0) {
+		return nil, 0, false
+	}
+
+	selectedUTXOs = make([]*UTXO, len(selection))
+	totalValue := uint64(0)
+	for i, candidateIndex := range selection {
+		selectedUTXOs[i] = candidates[candidateIndex]
+		totalValue += candidates[candidateIndex].UTXOEntry.Amount()
+	}
+
+	// The effective values don't take the minimum relay fee into account, so make
+	// sure the selection still pays for it
+	if totalValue < paymentsTotal+estimator.fee(len(selectedUTXOs), false) {
+		return nil, 0, false
+	}
+
+	return selectedUTXOs, totalValue - paymentsTotal, true
+}
+
+func checkTransactionSize(params *dagconfig.Params, estimator *feeEstimator, numInputs int, hasChange bool) error {
+	mass, size := estimator.massAndSize(numInputs, hasChange)
+	if size > relaypolicy.MaxStandardTxSize || mass > params.MaxMassAcceptedByBlock {
+		return errors.Wrapf(ErrTransactionTooLarge, "a transaction with %d inputs and %d outputs would "+
+			"be too large to be relayed", numInputs, estimator.numOutputs(hasChange))
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package libkaspawallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+type testWallet struct {
+	mnemonics          []string
+	extendedPublicKeys []string
+	minimumSignatures  uint32
+	ecdsa              bool
+}
+
+func newTestWallet(t *testing.T, params *dagconfig.Params, numKeys int, minimumSignatures uint32, ecdsa bool) *testWallet {
+	wallet := &testWallet{
+		mnemonics:          make([]string, numKeys),
+		extendedPublicKeys: make([]string, numKeys),
+		minimumSignatures:  minimumSignatures,
+		ecdsa:              ecdsa,
+	}
+	for i := 0; i < numKeys; i++ {
+		var err error
+		wallet.mnemonics[i], err = CreateMnemonic()
+		if err != nil {
+			t.Fatalf("CreateMnemonic: %+v", err)
+		}
+
+		wallet.extendedPublicKeys[i], err = MasterPublicKeyFromMnemonic(params, wallet.mnemonics[i], numKeys > 1)
+		if err != nil {
+			t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+		}
+	}
+	return wallet
+}
+
+func (w *testWallet) path(index uint32) string {
+	return AddressDerivationPath(len(w.extendedPublicKeys) > 1, 0, ExternalKeychain, index)
+}
+
+func (w *testWallet) address(t *testing.T, params *dagconfig.Params, index uint32) util.Address {
+	address, err := Address(params, w.extendedPublicKeys, w.minimumSignatures, w.path(index), w.ecdsa)
+	if err != nil {
+		t.Fatalf("Address: %+v", err)
+	}
+	return address
+}
+
+func (w *testWallet) utxos(t *testing.T, params *dagconfig.Params, amounts ...uint64) []*UTXO {
+	utxos := make([]*UTXO, len(amounts))
+	for i, amount := range amounts {
+		scriptPublicKey, err := txscript.PayToAddrScript(w.address(t, params, uint32(i)))
+		if err != nil {
+			t.Fatalf("PayToAddrScript: %+v", err)
+		}
+		transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{byte(i)})
+		utxos[i] = &UTXO{
+			Outpoint: &externalapi.DomainOutpoint{
+				TransactionID: *transactionID,
+				Index:         uint32(i),
+			},
+			UTXOEntry:      utxo.NewUTXOEntry(amount, scriptPublicKey, false, 0),
+			DerivationPath: w.path(uint32(i)),
+		}
+	}
+	return utxos
+}
+
+func forTestWallets(t *testing.T, params *dagconfig.Params, testFunc func(t *testing.T, wallet *testWallet)) {
+	tests := []struct {
+		name              string
+		numKeys           int
+		minimumSignatures uint32
+		ecdsa             bool
+	}{
+		{name: "schnorr", numKeys: 1, minimumSignatures: 1},
+		{name: "ecdsa", numKeys: 1, minimumSignatures: 1, ecdsa: true},
+		{name: "schnorr multisig", numKeys: 3, minimumSignatures: 2},
+		{name: "ecdsa multisig", numKeys: 3, minimumSignatures: 2, ecdsa: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testFunc(t, newTestWallet(t, params, test.numKeys, test.minimumSignatures, test.ecdsa))
+		})
+	}
+}
+
+func TestFeeEstimator(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	forTestWallets(t, params, func(t *testing.T, wallet *testWallet) {
+		utxos := wallet.utxos(t, params, 1000, 2000, 3000)
+		payments := []*Payment{{Address: wallet.address(t, params, 100), Amount: 100}}
+		changeAddress := wallet.address(t, params, 101)
+
+		estimator, err := newFeeEstimator(params, wallet.extendedPublicKeys, wallet.minimumSignatures, wallet.ecdsa,
+			payments, changeAddress, utxos[0], DefaultFeeRate)
+		if err != nil {
+			t.Fatalf("newFeeEstimator: %+v", err)
+		}
+
+		paymentsWithChange := append(payments, &Payment{Address: changeAddress, Amount: 200})
+		unsignedTransaction, err := CreateUnsignedTransaction(wallet.extendedPublicKeys, wallet.minimumSignatures,
+			wallet.ecdsa, paymentsWithChange, utxos, false)
+		if err != nil {
+			t.Fatalf("CreateUnsignedTransaction: %+v", err)
+		}
+
+		signedTransaction, err := Sign(params, wallet.mnemonics[:wallet.minimumSignatures], unsignedTransaction, wallet.ecdsa)
+		if err != nil {
+			t.Fatalf("Sign: %+v", err)
+		}
+
+		psTx, err := serialization.DeserializePartiallySignedTransaction(signedTransaction)
+		if err != nil {
+			t.Fatalf("DeserializePartiallySignedTransaction: %+v", err)
+		}
+
+		expectedMass, expectedSize, err := transactionMassAndSize(params, psTx)
+		if err != nil {
+			t.Fatalf("transactionMassAndSize: %+v", err)
+		}
+
+		tx, err := extractTransaction(psTx)
+		if err != nil {
+			t.Fatalf("extractTransaction: %+v", err)
+		}
+		if estimatedsize.TransactionEstimatedSerializedSize(tx) != expectedSize {
+			t.Fatalf("expected the size of the signed transaction to be %d but got %d",
+				expectedSize, estimatedsize.TransactionEstimatedSerializedSize(tx))
+		}
+
+		mass, size := estimator.massAndSize(len(utxos), true)
+		if mass != expectedMass || size != expectedSize {
+			t.Fatalf("expected an estimated mass of %d and size of %d but got %d and %d",
+				expectedMass, expectedSize, mass, size)
+		}
+
+		if estimator.fee(len(utxos), true) != expectedMass {
+			t.Fatalf("expected a fee of %d sompi at 1 sompi/gram but got %d", expectedMass,
+				estimator.fee(len(utxos), true))
+		}
+	})
+}
+
+func TestSelectUTXOs(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	forTestWallets(t, params, func(t *testing.T, wallet *testWallet) {
+		toAddress := wallet.address(t, params, 100)
+		changeAddress := wallet.address(t, params, 101)
+		payments := func(amount uint64) []*Payment {
+			return []*Payment{{Address: toAddress, Amount: amount}}
+		}
+
+		estimator, err := newFeeEstimator(params, wallet.extendedPublicKeys, wallet.minimumSignatures, wallet.ecdsa,
+			payments(1), changeAddress, wallet.utxos(t, params, 1)[0], DefaultFeeRate)
+		if err != nil {
+			t.Fatalf("newFeeEstimator: %+v", err)
+		}
+		oneInputFee := estimator.fee(1, false)
+		twoInputsFee := estimator.fee(2, false)
+		twoInputsWithChangeFee := estimator.fee(2, true)
+
+		selectUTXOs := func(utxos []*UTXO, amount uint64, strategy CoinSelectionStrategy) (
+			[]*UTXO, uint64, uint64, error) {
+
+			return SelectUTXOs(params, wallet.extendedPublicKeys, wallet.minimumSignatures, wallet.ecdsa,
+				utxos, payments(amount), changeAddress, DefaultFeeRate, strategy)
+		}
+
+		// Largest-first selects the largest UTXOs and sends the rest to change
+		utxos := wallet.utxos(t, params, 100_000, 10_000_000, 20_000_000, 50_000)
+		selectedUTXOs, change, fee, err := selectUTXOs(utxos, 25_000_000, CoinSelectionLargestFirst)
+		if err != nil {
+			t.Fatalf("SelectUTXOs: %+v", err)
+		}
+		if len(selectedUTXOs) != 2 || selectedUTXOs[0] != utxos[2] || selectedUTXOs[1] != utxos[1] {
+			t.Fatalf("expected the two largest UTXOs to be selected")
+		}
+		if fee != twoInputsWithChangeFee || change != 30_000_000-25_000_000-twoInputsWithChangeFee {
+			t.Fatalf("unexpected fee %d and change %d", fee, change)
+		}
+
+		// A change that would be dust is added to the fee
+		amount := 30_000_000 - twoInputsFee - 10
+		selectedUTXOs, change, fee, err = selectUTXOs(utxos, amount, CoinSelectionLargestFirst)
+		if err != nil {
+			t.Fatalf("SelectUTXOs: %+v", err)
+		}
+		if len(selectedUTXOs) != 2 || change != 0 || fee != twoInputsFee+10 {
+			t.Fatalf("expected the dust change to be added to the fee, but got %d inputs, "+
+				"a change of %d and a fee of %d", len(selectedUTXOs), change, fee)
+		}
+
+		// Branch-and-bound finds the UTXO that pays the payment with no change
+		amount = 100_000 - oneInputFee
+		selectedUTXOs, change, fee, err = selectUTXOs(utxos, amount, CoinSelectionBranchAndBound)
+		if err != nil {
+			t.Fatalf("SelectUTXOs: %+v", err)
+		}
+		if len(selectedUTXOs) != 1 || selectedUTXOs[0] != utxos[0] || change != 0 || fee != oneInputFee {
+			t.Fatalf("expected branch-and-bound to select a single changeless UTXO")
+		}
+
+		// Branch-and-bound falls back to largest-first when there's no changeless solution
+		selectedUTXOs, change, _, err = selectUTXOs(utxos, 25_000_000, CoinSelectionBranchAndBound)
+		if err != nil {
+			t.Fatalf("SelectUTXOs: %+v", err)
+		}
+		if len(selectedUTXOs) != 2 || change == 0 {
+			t.Fatalf("expected branch-and-bound to fall back to largest-first")
+		}
+
+		_, _, _, err = selectUTXOs(utxos, 30_150_000, CoinSelectionLargestFirst)
+		if err == nil || !strings.Contains(err.Error(), "Insufficient funds") {
+			t.Fatalf("expected an insufficient funds error but got %v", err)
+		}
+
+		_, _, _, err = selectUTXOs(utxos, 1, CoinSelectionLargestFirst)
+		if err == nil || !strings.Contains(err.Error(), "dust") {
+			t.Fatalf("expected a dust payment error but got %v", err)
+		}
+
+		manyPayments := make([]*Payment, 3000)
+		for i := range manyPayments {
+			manyPayments[i] = &Payment{Address: toAddress, Amount: 1000}
+		}
+		_, _, _, err = SelectUTXOs(params, wallet.extendedPublicKeys, wallet.minimumSignatures, wallet.ecdsa,
+			utxos, manyPayments, changeAddress, DefaultFeeRate, CoinSelectionLargestFirst)
+		if !errors.Is(err, ErrTransactionTooLarge) {
+			t.Fatalf("expected ErrTransactionTooLarge but got %v", err)
+		}
+	})
+}
@@ -1,7 +1,10 @@
 package libkaspawallet
 
 import (
+	"fmt"
+
 	"github.com/kaspanet/go-secp256k1"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/bip32"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/pkg/errors"
@@ -70,24 +73,115 @@ func PublicKeyFromPrivateKey(privateKeyBytes []byte) ([]byte, error) {
 	return publicKeySerialized[:], nil
 }
 
-// Address returns the address associated with the given public keys and minimum signatures parameters.
-func Address(params *dagconfig.Params, pubKeys [][]byte, minimumSignatures uint32, ecdsa bool) (util.Address, error) {
-	sortPublicKeys(pubKeys)
-	if uint32(len(pubKeys)) < minimumSignatures {
+func extendedPublicKeyToPublicKey(extendedPublicKey string, path string, ecdsa bool) ([]byte, error) {
+	extendedKey, err := bip32.DeserializeExtendedKey(extendedPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := extendedKey.DeriveFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := derivedKey.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	serializedECDSAPublicKey, err := publicKey.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	if ecdsa {
+		return serializedECDSAPublicKey[:], nil
+	}
+
+	// A schnorr public key is the x coordinate of the ECDSA public key, which
+	// is its compressed serialization without the leading parity byte.
+	schnorrPublicKey, err := secp256k1.DeserializeSchnorrPubKey(serializedECDSAPublicKey[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	serializedSchnorrPublicKey, err := schnorrPublicKey.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return serializedSchnorrPublicKey[:], nil
+}
+
+// Address returns the address associated with the given extended public keys, minimum signatures
+// parameters and derivation path.
+func Address(params *dagconfig.Params, extendedPublicKeys []string, minimumSignatures uint32, path string,
+	ecdsa bool) (util.Address, error) {
+
+	if uint32(len(extendedPublicKeys)) < minimumSignatures {
 		return nil, errors.Errorf("The minimum amount of signatures (%d) is greater than the amount of "+
-			"provided public keys (%d)", minimumSignatures, len(pubKeys))
+			"provided public keys (%d)", minimumSignatures, len(extendedPublicKeys))
 	}
-	if len(pubKeys) == 1 {
+
+	if len(extendedPublicKeys) == 1 {
+		publicKey, err := extendedPublicKeyToPublicKey(extendedPublicKeys[0], path, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+
 		if ecdsa {
-			return util.NewAddressPublicKeyECDSA(pubKeys[0][:], params.Prefix)
+			return util.NewAddressPublicKeyECDSA(publicKey, params.Prefix)
 		}
-		return util.NewAddressPublicKey(pubKeys[0][:], params.Prefix)
+		return util.NewAddressPublicKey(publicKey, params.Prefix)
 	}
 
-	redeemScript, err := multiSigRedeemScript(pubKeys, minimumSignatures, ecdsa)
+	redeemScript, err := multiSigRedeemScript(extendedPublicKeys, minimumSignatures, path, ecdsa)
 	if err != nil {
 		return nil, err
 	}
 
 	return util.NewAddressScriptHash(redeemScript, params.Prefix)
 }
+
+// The keychains of a wallet, as defined in https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki#change
+const (
+	ExternalKeychain = 0
+	InternalKeychain = 1
+)
+
+// AddressDerivationPath returns the derivation path, relative to the extended public keys of the
+// wallet, of the index'th address in the given keychain.
+func AddressDerivationPath(isMultisig bool, cosignerIndex uint32, keychain uint32, index uint32) string {
+	if isMultisig {
+		return fmt.Sprintf("m/%d/%d/%d", cosignerIndex, keychain, index)
+	}
+
+	return fmt.Sprintf("m/%d/%d", keychain, index)
+}
+
+// MinimumCosignerIndex returns the minimum index of the given signer extended public keys among
+// all of the extended public keys of the wallet, when they're sorted.
+func MinimumCosignerIndex(signerExtendedPublicKeys, allExtendedPublicKeys []string) (uint32, error) {
+	sortedExtendedPublicKeys := sortPublicKeys(allExtendedPublicKeys)
+
+	minimumCosignerIndex := uint32(len(sortedExtendedPublicKeys))
+	for _, signerExtendedPublicKey := range signerExtendedPublicKeys {
+		cosignerIndex := -1
+		for i, extendedPublicKey := range sortedExtendedPublicKeys {
+			if extendedPublicKey == signerExtendedPublicKey {
+				cosignerIndex = i
+				break
+			}
+		}
+
+		if cosignerIndex == -1 {
+			return 0, errors.Errorf("couldn't find extended public key %s", signerExtendedPublicKey)
+		}
+
+		if uint32(cosignerIndex) < minimumCosignerIndex {
+			minimumCosignerIndex = uint32(cosignerIndex)
+		}
+	}
+
+	return minimumCosignerIndex, nil
+}
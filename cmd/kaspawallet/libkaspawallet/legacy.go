@@ -0,0 +1,321 @@
+package libkaspawallet
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// Legacy wallets were created before kaspawallet derived its keys from mnemonics. They hold
+// raw private keys, and have a single address made of their raw public keys.
+
+func sortLegacyPublicKeys(publicKeys [][]byte) [][]byte {
+	sortedPublicKeys := make([][]byte, len(publicKeys))
+	copy(sortedPublicKeys, publicKeys)
+	sort.Slice(sortedPublicKeys, func(i, j int) bool {
+		return bytes.Compare(sortedPublicKeys[i], sortedPublicKeys[j]) < 0
+	})
+	return sortedPublicKeys
+}
+
+func legacyMultiSigRedeemScript(sortedPublicKeys [][]byte, minimumSignatures uint32, ecdsa bool) ([]byte, error) {
+	scriptBuilder := txscript.NewScriptBuilder()
+	scriptBuilder.AddInt64(int64(minimumSignatures))
+	for _, publicKey := range sortedPublicKeys {
+		scriptBuilder.AddData(publicKey)
+	}
+	scriptBuilder.AddInt64(int64(len(sortedPublicKeys)))
+
+	if ecdsa {
+		scriptBuilder.AddOp(txscript.OpCheckMultiSigECDSA)
+	} else {
+		scriptBuilder.AddOp(txscript.OpCheckMultiSig)
+	}
+
+	return scriptBuilder.Script()
+}
+
+// LegacyAddress returns the address of a legacy wallet with the given raw public keys and minimum
+// signatures parameters.
+func LegacyAddress(params *dagconfig.Params, publicKeys [][]byte, minimumSignatures uint32, ecdsa bool) (
+	util.Address, error) {
+
+	if uint32(len(publicKeys)) < minimumSignatures {
+		return nil, errors.Errorf("The minimum amount of signatures (%d) is greater than the amount of "+
+			"provided public keys (%d)", minimumSignatures, len(publicKeys))
+	}
+
+	if len(publicKeys) == 1 {
+		if ecdsa {
+			return util.NewAddressPublicKeyECDSA(publicKeys[0], params.Prefix)
+		}
+		return util.NewAddressPublicKey(publicKeys[0], params.Prefix)
+	}
+
+	redeemScript, err := legacyMultiSigRedeemScript(sortLegacyPublicKeys(publicKeys), minimumSignatures, ecdsa)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.NewAddressScriptHash(redeemScript, params.Prefix)
+}
+
+func legacyPublicKey(privateKey []byte, ecdsa bool) ([]byte, error) {
+	if ecdsa {
+		keyPair, err := secp256k1.DeserializeECDSAPrivateKeyFromSlice(privateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error deserializing private key")
+		}
+		publicKey, err := keyPair.ECDSAPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		serializedPublicKey, err := publicKey.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		return serializedPublicKey[:], nil
+	}
+
+	return PublicKeyFromPrivateKey(privateKey)
+}
+
+// CreateLegacySweepTransactions creates signed transactions that send all the given UTXOs of a legacy
+// wallet, minus the fee at the given fee rate (in sompi/gram), to the given address. The UTXOs are split
+// between as many transactions as needed for every transaction to be small enough to be relayed.
+func CreateLegacySweepTransactions(params *dagconfig.Params, privateKeys [][]byte, publicKeys [][]byte,
+	minimumSignatures uint32, ecdsa bool, utxos []*UTXO, address util.Address, feeRate float64) (
+	[]*externalapi.DomainTransaction, error) {
+
+	if feeRate < 0 {
+		return nil, errors.Errorf("fee rate cannot be negative")
+	}
+	if len(utxos) == 0 {
+		return nil, errors.Errorf("there are no UTXOs to sweep")
+	}
+
+	sortedPublicKeys := sortLegacyPublicKeys(publicKeys)
+	signers := make(map[int]signer)
+	for _, privateKey := range privateKeys {
+		publicKey, err := legacyPublicKey(privateKey, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+		publicKeyIndex := -1
+		for i, sortedPublicKey := range sortedPublicKeys {
+			if bytes.Equal(sortedPublicKey, publicKey) {
+				publicKeyIndex = i
+				break
+			}
+		}
+		if publicKeyIndex == -1 {
+			return nil, errors.Errorf("Public key doesn't match any of the wallet public keys")
+		}
+		signers[publicKeyIndex], err = deserializeECDSAPrivateKey(privateKey, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if uint32(len(signers)) < minimumSignatures {
+		return nil, errors.Errorf("the wallet holds %d of the %d private keys required to sign",
+			len(signers), minimumSignatures)
+	}
+
+	var redeemScript []byte
+	if len(sortedPublicKeys) > 1 {
+		var err error
+		redeemScript, err = legacyMultiSigRedeemScript(sortedPublicKeys, minimumSignatures, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+	}
+	createSweepTransaction := func(utxos []*UTXO, amount uint64) (*serialization.PartiallySignedTransaction, error) {
+		return createLegacySweepTransaction(len(sortedPublicKeys), minimumSignatures, redeemScript, utxos,
+			address, amount)
+	}
+
+	estimator, err := newLegacyFeeEstimator(params, createSweepTransaction, utxos[0], feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []*externalapi.DomainTransaction
+	for len(utxos) > 0 {
+		numInputs := 0
+		for numInputs < len(utxos) && checkTransactionSize(params, estimator, numInputs+1, false) == nil {
+			numInputs++
+		}
+		if numInputs == 0 {
+			return nil, checkTransactionSize(params, estimator, 1, false)
+		}
+
+		transactionUTXOs := utxos[:numInputs]
+		utxos = utxos[numInputs:]
+
+		totalValue := uint64(0)
+		for _, utxo := range transactionUTXOs {
+			totalValue += utxo.UTXOEntry.Amount()
+		}
+		fee := estimator.fee(numInputs, false)
+		if totalValue <= fee {
+			return nil, errors.Errorf("the %d UTXOs of the transaction hold %d sompi, which doesn't pay for "+
+				"the fee of %d sompi", numInputs, totalValue, fee)
+		}
+
+		psTx, err := createSweepTransaction(transactionUTXOs, totalValue-fee)
+		if err != nil {
+			return nil, err
+		}
+		if relaypolicy.IsTransactionOutputDust(psTx.Tx.Outputs[0], relaypolicy.DefaultMinRelayTxFee) {
+			return nil, errors.Errorf("the %d UTXOs of the transaction hold %d sompi, which leaves only dust "+
+				"after paying the fee of %d sompi", numInputs, totalValue, fee)
+		}
+
+		err = signLegacySweepTransaction(psTx, signers, minimumSignatures)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := extractTransaction(psTx)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+func newLegacyFeeEstimator(params *dagconfig.Params,
+	createSweepTransaction func(utxos []*UTXO, amount uint64) (*serialization.PartiallySignedTransaction, error),
+	sampleUTXO *UTXO, feeRate float64) (*feeEstimator, error) {
+
+	massAndSize := func(utxos []*UTXO) (uint64, uint64, error) {
+		psTx, err := createSweepTransaction(utxos, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		return transactionMassAndSize(params, psTx)
+	}
+
+	baseMass, baseSize, err := massAndSize(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	massWithInput, sizeWithInput, err := massAndSize([]*UTXO{sampleUTXO})
+	if err != nil {
+		return nil, err
+	}
+
+	return &feeEstimator{
+		feeRate:     feeRate,
+		numPayments: 1,
+		baseMass:    baseMass,
+		baseSize:    baseSize,
+		inputMass:   massWithInput - baseMass,
+		inputSize:   sizeWithInput - baseSize,
+	}, nil
+}
+
+func createLegacySweepTransaction(numPublicKeys int, minimumSignatures uint32, redeemScript []byte, utxos []*UTXO,
+	address util.Address, amount uint64) (*serialization.PartiallySignedTransaction, error) {
+
+	inputs := make([]*externalapi.DomainTransactionInput, len(utxos))
+	partiallySignedInputs := make([]*serialization.PartiallySignedInput, len(utxos))
+	for i, utxo := range utxos {
+		// The pairs are in the order of the sorted public keys, which is the order in which
+		// the multisig script expects the signatures
+		emptyPubKeySignaturePairs := make([]*serialization.PubKeySignaturePair, numPublicKeys)
+		for j := range emptyPubKeySignaturePairs {
+			emptyPubKeySignaturePairs[j] = &serialization.PubKeySignaturePair{}
+		}
+
+		inputs[i] = &externalapi.DomainTransactionInput{
+			PreviousOutpoint: *utxo.Outpoint,
+			Sequence:         constants.MaxTxInSequenceNum,
+		}
+		partiallySignedInputs[i] = &serialization.PartiallySignedInput{
+			RedeeemScript: redeemScript,
+			PrevOutput: &externalapi.DomainTransactionOutput{
+				Value:           utxo.UTXOEntry.Amount(),
+				ScriptPublicKey: utxo.UTXOEntry.ScriptPublicKey(),
+			},
+			MinimumSignatures:    minimumSignatures,
+			PubKeySignaturePairs: emptyPubKeySignaturePairs,
+		}
+	}
+
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+
+	domainTransaction := &externalapi.DomainTransaction{
+		Version: constants.MaxTransactionVersion,
+		Inputs:  inputs,
+		Outputs: []*externalapi.DomainTransactionOutput{{
+			Value:           amount,
+			ScriptPublicKey: scriptPublicKey,
+		}},
+		LockTime:     0,
+		SubnetworkID: subnetworks.SubnetworkIDNative,
+		Gas:          0,
+		Payload:      nil,
+	}
+
+	return &serialization.PartiallySignedTransaction{
+		Tx:                    domainTransaction,
+		PartiallySignedInputs: partiallySignedInputs,
+	}, nil
+}
+
+// signLegacySweepTransaction signs every input of the transaction with the first minimumSignatures
+// signers, in the order of their public keys. signers maps the index of every public key to its signer.
+func signLegacySweepTransaction(psTx *serialization.PartiallySignedTransaction, signers map[int]signer,
+	minimumSignatures uint32) error {
+
+	for i, partiallySignedInput := range psTx.PartiallySignedInputs {
+		prevOut := partiallySignedInput.PrevOutput
+		psTx.Tx.Inputs[i].UTXOEntry = utxo.NewUTXOEntry(
+			prevOut.Value,
+			prevOut.ScriptPublicKey,
+			false, // This is a fake value, because it's irrelevant for the signature
+			0,     // This is a fake value, because it's irrelevant for the signature
+		)
+	}
+
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	for i, partiallySignedInput := range psTx.PartiallySignedInputs {
+		numSignatures := uint32(0)
+		for publicKeyIndex, pair := range partiallySignedInput.PubKeySignaturePairs {
+			if numSignatures == minimumSignatures {
+				break
+			}
+			keyPair, ok := signers[publicKeyIndex]
+			if !ok {
+				continue
+			}
+
+			var err error
+			pair.Signature, err = keyPair.rawTxInSignature(psTx.Tx, i, consensushashing.SigHashAll, sighashReusedValues)
+			if err != nil {
+				return err
+			}
+			numSignatures++
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package libkaspawallet_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/util"
+)
+
+func TestLegacySweep(t *testing.T) {
+	tests := []struct {
+		name              string
+		numKeys           int
+		numPrivateKeys    int
+		minimumSignatures uint32
+	}{
+		{name: "P2PK", numKeys: 1, numPrivateKeys: 1, minimumSignatures: 1},
+		{name: "multisig", numKeys: 3, numPrivateKeys: 2, minimumSignatures: 2},
+	}
+
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+			for _, test := range tests {
+				testLegacySweep(t, consensusConfig, ecdsa, test.name, test.numKeys, test.numPrivateKeys,
+					test.minimumSignatures)
+			}
+		})
+	})
+}
+
+func testLegacySweep(t *testing.T, consensusConfig *consensus.Config, ecdsa bool, name string,
+	numKeys int, numPrivateKeys int, minimumSignatures uint32) {
+
+	consensusConfig.BlockCoinbaseMaturity = 0
+	tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, "TestLegacySweep")
+	if err != nil {
+		t.Fatalf("%s: Error setting up tc: %+v", name, err)
+	}
+	defer teardown(false)
+
+	privateKeys := make([][]byte, numKeys)
+	publicKeys := make([][]byte, numKeys)
+	for i := 0; i < numKeys; i++ {
+		privateKeys[i], publicKeys[i], err = libkaspawallet.CreateKeyPair(ecdsa)
+		if err != nil {
+			t.Fatalf("%s: CreateKeyPair: %+v", name, err)
+		}
+	}
+
+	legacyAddress, err := libkaspawallet.LegacyAddress(&consensusConfig.Params, publicKeys, minimumSignatures, ecdsa)
+	if err != nil {
+		t.Fatalf("%s: LegacyAddress: %+v", name, err)
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(legacyAddress)
+	if err != nil {
+		t.Fatalf("%s: PayToAddrScript: %+v", name, err)
+	}
+
+	// The coinbase of every block pays the miner of its selected parent, so the
+	// coinbase of the first block doesn't pay the legacy address. Collect the
+	// coinbase UTXOs of the last two blocks
+	coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey}
+	tipHash := consensusConfig.GenesisHash
+	var utxos []*libkaspawallet.UTXO
+	for i := 0; i < 4; i++ {
+		tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("%s: AddBlock: %+v", name, err)
+		}
+		if i < 2 {
+			continue
+		}
+
+		block, err := tc.GetBlock(tipHash)
+		if err != nil {
+			t.Fatalf("%s: GetBlock: %+v", name, err)
+		}
+		coinbaseOutput := block.Transactions[0].Outputs[0]
+		utxos = append(utxos, &libkaspawallet.UTXO{
+			Outpoint: &externalapi.DomainOutpoint{
+				TransactionID: *consensushashing.TransactionID(block.Transactions[0]),
+				Index:         0,
+			},
+			UTXOEntry: utxo.NewUTXOEntry(coinbaseOutput.Value, coinbaseOutput.ScriptPublicKey, true, 0),
+		})
+	}
+
+	_, destinationPublicKey, err := libkaspawallet.CreateKeyPair(false)
+	if err != nil {
+		t.Fatalf("%s: CreateKeyPair: %+v", name, err)
+	}
+	destination, err := util.NewAddressPublicKey(destinationPublicKey, consensusConfig.Prefix)
+	if err != nil {
+		t.Fatalf("%s: NewAddressPublicKey: %+v", name, err)
+	}
+
+	_, err = libkaspawallet.CreateLegacySweepTransactions(&consensusConfig.Params,
+		privateKeys[:minimumSignatures-1], publicKeys, minimumSignatures, ecdsa, utxos, destination, 1)
+	if err == nil {
+		t.Fatalf("%s: CreateLegacySweepTransactions unexpectedly succeeded without enough private keys", name)
+	}
+
+	txs, err := libkaspawallet.CreateLegacySweepTransactions(&consensusConfig.Params,
+		privateKeys[:numPrivateKeys], publicKeys, minimumSignatures, ecdsa, utxos, destination, 1)
+	if err != nil {
+		t.Fatalf("%s: CreateLegacySweepTransactions: %+v", name, err)
+	}
+	if len(txs) != 1 || len(txs[0].Inputs) != len(utxos) || len(txs[0].Outputs) != 1 {
+		t.Fatalf("%s: expected a single transaction spending all the UTXOs into a single output", name)
+	}
+
+	_, insertionResult, err := tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, txs)
+	if err != nil {
+		t.Fatalf("%s: AddBlock: %+v", name, err)
+	}
+	sweptUTXO := &externalapi.DomainOutpoint{
+		TransactionID: *consensushashing.TransactionID(txs[0]),
+		Index:         0,
+	}
+	if !insertionResult.VirtualUTXODiff.ToAdd().Contains(sweptUTXO) {
+		t.Fatalf("%s: the sweep transaction wasn't accepted in the DAG", name)
+	}
+}
@@ -0,0 +1,68 @@
+package libkaspawallet
+
+import (
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txmass"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+// signatureWithSigHashTypeLength is the length of a Schnorr or an ECDSA signature
+// followed by its sighash type
+const signatureWithSigHashTypeLength = 65
+
+// transactionMassAndSize returns the mass and the estimated serialized size the given partially signed
+// transaction would have once all of its inputs are signed. The mass is calculated by the same
+// calculator consensus uses.
+func transactionMassAndSize(params *dagconfig.Params, psTx *serialization.PartiallySignedTransaction) (
+	mass uint64, size uint64, err error) {
+
+	tx, err := transactionWithPlaceholderSignatures(psTx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, input := range tx.Inputs {
+		prevOutput := psTx.PartiallySignedInputs[i].PrevOutput
+		input.UTXOEntry = utxo.NewUTXOEntry(prevOutput.Value, prevOutput.ScriptPublicKey, false, 0)
+	}
+
+	txMassCalculator := txmass.NewCalculator(params.MassPerTxByte, params.MassPerScriptPubKeyByte, params.MassPerSigOp)
+	mass, err = txMassCalculator.CalculateTransactionMass(tx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return mass, estimatedsize.TransactionEstimatedSerializedSize(tx), nil
+}
+
+// transactionWithPlaceholderSignatures returns a copy of the transaction in which every input has a signature
+// script of the same length it would have once signed.
+func transactionWithPlaceholderSignatures(psTx *serialization.PartiallySignedTransaction) (
+	*externalapi.DomainTransaction, error) {
+
+	tx := psTx.Tx.Clone()
+	placeholderSignature := make([]byte, signatureWithSigHashTypeLength)
+	for i, input := range psTx.PartiallySignedInputs {
+		isMultisig := input.RedeeemScript != nil
+		scriptBuilder := txscript.NewScriptBuilder()
+		if isMultisig {
+			for j := uint32(0); j < input.MinimumSignatures; j++ {
+				scriptBuilder.AddData(placeholderSignature)
+			}
+			scriptBuilder.AddData(input.RedeeemScript)
+		} else {
+			scriptBuilder.AddData(placeholderSignature)
+		}
+
+		sigScript, err := scriptBuilder.Script()
+		if err != nil {
+			return nil, err
+		}
+		tx.Inputs[i].SignatureScript = sigScript
+	}
+
+	return tx, nil
+}
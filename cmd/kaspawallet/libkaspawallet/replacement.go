@@ -0,0 +1,68 @@
+package libkaspawallet
+
+import (
+	"math"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
+	"github.com/pkg/errors"
+)
+
+// CreateReplacementTransaction creates an unsigned transaction that replaces a transaction which spends
+// the given UTXOs to the given outputs and pays originalFee, by spending the same UTXOs to the same outputs
+// at the given fee rate (in sompi/gram). The additional fee is deducted from the output at changeOutputIndex.
+// A change that would be considered dust is added to the fee instead, in which case the change output
+// is dropped. It returns the unsigned transaction along with its fee.
+func CreateReplacementTransaction(params *dagconfig.Params, extendedPublicKeys []string, minimumSignatures uint32,
+	ecdsa bool, utxos []*UTXO, outputs []*externalapi.DomainTransactionOutput, changeOutputIndex int,
+	originalFee uint64, feeRate float64) (unsignedTransaction []byte, fee uint64, err error) {
+
+	if changeOutputIndex < 0 || changeOutputIndex >= len(outputs) {
+		return nil, 0, errors.Errorf("change output index %d is out of range", changeOutputIndex)
+	}
+
+	// The replacement stays replaceable, so that its fee can be bumped again
+	psTx, err := createUnsignedTransaction(sortPublicKeys(extendedPublicKeys), minimumSignatures, ecdsa, nil, utxos,
+		true)
+	if err != nil {
+		return nil, 0, err
+	}
+	psTx.Tx.Outputs = make([]*externalapi.DomainTransactionOutput, len(outputs))
+	for i, output := range outputs {
+		psTx.Tx.Outputs[i] = output.Clone()
+	}
+
+	mass, size, err := transactionMassAndSize(params, psTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	fee = uint64(math.Ceil(float64(mass) * feeRate))
+	minimumRelayFee := uint64(relaypolicy.CalcMinRequiredTxRelayFee(int64(size), relaypolicy.DefaultMinRelayTxFee))
+	if fee < minimumRelayFee {
+		fee = minimumRelayFee
+	}
+	if fee <= originalFee {
+		return nil, 0, errors.Errorf("a fee rate of %f sompi/gram results in a fee of %d sompi, which is not "+
+			"higher than the original fee of %d sompi", feeRate, fee, originalFee)
+	}
+
+	change := psTx.Tx.Outputs[changeOutputIndex]
+	additionalFee := fee - originalFee
+	if change.Value < additionalFee {
+		return nil, 0, errors.Errorf("the change of %d sompi is not enough to pay the additional fee of "+
+			"%d sompi", change.Value, additionalFee)
+	}
+	change.Value -= additionalFee
+	if relaypolicy.IsTransactionOutputDust(change, relaypolicy.DefaultMinRelayTxFee) {
+		fee += change.Value
+		psTx.Tx.Outputs = append(psTx.Tx.Outputs[:changeOutputIndex], psTx.Tx.Outputs[changeOutputIndex+1:]...)
+	}
+
+	unsignedTransaction, err = serialization.SerializePartiallySignedTransaction(psTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return unsignedTransaction, fee, nil
+}
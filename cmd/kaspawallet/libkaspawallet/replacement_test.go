@@ -0,0 +1,131 @@
+package libkaspawallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
+)
+
+func TestCreateReplacementTransaction(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	forTestWallets(t, params, func(t *testing.T, wallet *testWallet) {
+		utxos := wallet.utxos(t, params, 10_000_000, 20_000_000)
+		payments := []*Payment{
+			{Address: wallet.address(t, params, 100), Amount: 25_000_000},
+			{Address: wallet.address(t, params, 101), Amount: 4_000_000},
+		}
+		const changeOutputIndex = 1
+
+		unsignedTransaction, err := CreateUnsignedTransaction(wallet.extendedPublicKeys, wallet.minimumSignatures,
+			wallet.ecdsa, payments, utxos, true)
+		if err != nil {
+			t.Fatalf("CreateUnsignedTransaction: %+v", err)
+		}
+		originalPSTx, err := serialization.DeserializePartiallySignedTransaction(unsignedTransaction)
+		if err != nil {
+			t.Fatalf("DeserializePartiallySignedTransaction: %+v", err)
+		}
+		const originalFee = 1_000_000
+		outputs := originalPSTx.Tx.Outputs
+
+		createReplacement := func(feeRate float64) (*serialization.PartiallySignedTransaction, uint64, error) {
+			unsignedReplacement, fee, err := CreateReplacementTransaction(params, wallet.extendedPublicKeys,
+				wallet.minimumSignatures, wallet.ecdsa, utxos, outputs, changeOutputIndex, originalFee, feeRate)
+			if err != nil {
+				return nil, 0, err
+			}
+			psTx, err := serialization.DeserializePartiallySignedTransaction(unsignedReplacement)
+			if err != nil {
+				t.Fatalf("DeserializePartiallySignedTransaction: %+v", err)
+			}
+			return psTx, fee, nil
+		}
+
+		mass, _, err := transactionMassAndSize(params, originalPSTx)
+		if err != nil {
+			t.Fatalf("transactionMassAndSize: %+v", err)
+		}
+
+		// The replacement has to pay more than the original transaction
+		_, _, err = createReplacement(float64(originalFee-1) / float64(mass))
+		if err == nil || !strings.Contains(err.Error(), "not higher than the original fee") {
+			t.Fatalf("expected an error about the fee not being higher, but got: %+v", err)
+		}
+
+		// The additional fee is paid by the change output
+		replacementPSTx, fee, err := createReplacement(float64(2*originalFee) / float64(mass))
+		if err != nil {
+			t.Fatalf("createReplacement: %+v", err)
+		}
+		if fee < 2*originalFee || fee > 2*originalFee+1 {
+			t.Fatalf("expected a fee of %d but got %d", 2*originalFee, fee)
+		}
+		if len(replacementPSTx.Tx.Outputs) != len(outputs) {
+			t.Fatalf("expected %d outputs but got %d", len(outputs), len(replacementPSTx.Tx.Outputs))
+		}
+		if !replacementPSTx.Tx.Outputs[0].Equal(outputs[0]) {
+			t.Fatalf("expected the payment output to remain unchanged")
+		}
+		if replacementPSTx.Tx.Outputs[changeOutputIndex].Value != outputs[changeOutputIndex].Value-(fee-originalFee) {
+			t.Fatalf("expected the change to be reduced by %d, but got %d", fee-originalFee,
+				replacementPSTx.Tx.Outputs[changeOutputIndex].Value)
+		}
+		if outputs[changeOutputIndex].Value != 4_000_000 {
+			t.Fatalf("expected the original outputs to remain unchanged")
+		}
+		for i, input := range replacementPSTx.Tx.Inputs {
+			if input.PreviousOutpoint != *utxos[i].Outpoint {
+				t.Fatalf("expected the replacement to spend the same UTXOs")
+			}
+		}
+		replacementTx := &externalapi.DomainTransaction{Inputs: replacementPSTx.Tx.Inputs}
+		if !relaypolicy.IsReplaceable(replacementTx) {
+			t.Fatalf("expected the replacement to be replaceable as well")
+		}
+
+		// A change that would be dust is added to the fee
+		replacementPSTx, fee, err = createReplacement(float64(originalFee+4_000_000-10) / float64(mass))
+		if err != nil {
+			t.Fatalf("createReplacement: %+v", err)
+		}
+		if len(replacementPSTx.Tx.Outputs) != len(outputs)-1 || fee != originalFee+4_000_000 {
+			t.Fatalf("expected the dust change to be added to the fee, but got %d outputs and a fee of %d",
+				len(replacementPSTx.Tx.Outputs), fee)
+		}
+
+		// The change has to be enough to pay the additional fee
+		_, _, err = createReplacement(float64(originalFee+5_000_000) / float64(mass))
+		if err == nil || !strings.Contains(err.Error(), "is not enough to pay the additional fee") {
+			t.Fatalf("expected an error about insufficient change, but got: %+v", err)
+		}
+	})
+}
+
+func TestCreateUnsignedTransactionReplaceable(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	forTestWallets(t, params, func(t *testing.T, wallet *testWallet) {
+		utxos := wallet.utxos(t, params, 10_000_000, 20_000_000)
+		payments := []*Payment{{Address: wallet.address(t, params, 100), Amount: 25_000_000}}
+
+		for _, replaceable := range []bool{false, true} {
+			unsignedTransaction, err := CreateUnsignedTransaction(wallet.extendedPublicKeys,
+				wallet.minimumSignatures, wallet.ecdsa, payments, utxos, replaceable)
+			if err != nil {
+				t.Fatalf("CreateUnsignedTransaction: %+v", err)
+			}
+			psTx, err := serialization.DeserializePartiallySignedTransaction(unsignedTransaction)
+			if err != nil {
+				t.Fatalf("DeserializePartiallySignedTransaction: %+v", err)
+			}
+
+			// Transactions are only replaceable if they explicitly opt in
+			if relaypolicy.IsReplaceable(psTx.Tx) != replaceable {
+				t.Fatalf("expected the transaction to be replaceable: %t, but it's the opposite", replaceable)
+			}
+		}
+	})
+}
@@ -25,12 +25,13 @@ type PartiallySignedInput struct {
 	PrevOutput           *externalapi.DomainTransactionOutput
 	MinimumSignatures    uint32
 	PubKeySignaturePairs []*PubKeySignaturePair
+	DerivationPath       string
 }
 
-// PubKeySignaturePair is a pair of public key and (potentially) its associated signature
+// PubKeySignaturePair is a pair of extended public key and (potentially) its associated signature
 type PubKeySignaturePair struct {
-	PubKey    []byte
-	Signature []byte
+	ExtendedPublicKey string
+	Signature         []byte
 }
 
 // DeserializePartiallySignedTransaction deserializes a byte slice into PartiallySignedTransaction.
@@ -97,6 +98,7 @@ func partiallySignedInputFromProto(protoPartiallySignedInput *protoserialization
 		PrevOutput:           output,
 		MinimumSignatures:    protoPartiallySignedInput.MinimumSignatures,
 		PubKeySignaturePairs: pubKeySignaturePairs,
+		DerivationPath:       protoPartiallySignedInput.DerivationPath,
 	}, nil
 }
 
@@ -111,20 +113,21 @@ func partiallySignedInputToProto(partiallySignedInput *PartiallySignedInput) *pr
 		PrevOutput:           transactionOutputToProto(partiallySignedInput.PrevOutput),
 		MinimumSignatures:    partiallySignedInput.MinimumSignatures,
 		PubKeySignaturePairs: protoPairs,
+		DerivationPath:       partiallySignedInput.DerivationPath,
 	}
 }
 
 func pubKeySignaturePairFromProto(protoPubKeySignaturePair *protoserialization.PubKeySignaturePair) *PubKeySignaturePair {
 	return &PubKeySignaturePair{
-		PubKey:    protoPubKeySignaturePair.PubKey,
-		Signature: protoPubKeySignaturePair.Signature,
+		ExtendedPublicKey: protoPubKeySignaturePair.ExtendedPubKey,
+		Signature:         protoPubKeySignaturePair.Signature,
 	}
 }
 
 func pubKeySignaturePairToProto(pubKeySignaturePair *PubKeySignaturePair) *protoserialization.PubKeySignaturePair {
 	return &protoserialization.PubKeySignaturePair{
-		PubKey:    pubKeySignaturePair.PubKey,
-		Signature: pubKeySignaturePair.Signature,
+		ExtendedPubKey: pubKeySignaturePair.ExtendedPublicKey,
+		Signature:      pubKeySignaturePair.Signature,
 	}
 }
 
@@ -1,20 +1,19 @@
 package libkaspawallet
 
 import (
-	"bytes"
 	"github.com/kaspanet/go-secp256k1"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/pkg/errors"
 )
 
 type signer interface {
 	rawTxInSignature(tx *externalapi.DomainTransaction, idx int, hashType consensushashing.SigHashType,
 		sighashReusedValues *consensushashing.SighashReusedValues) ([]byte, error)
-	serializedPublicKey() ([]byte, error)
 }
 
 type schnorrSigner secp256k1.SchnorrKeyPair
@@ -24,20 +23,6 @@ func (s *schnorrSigner) rawTxInSignature(tx *externalapi.DomainTransaction, idx
 	return txscript.RawTxInSignature(tx, idx, hashType, (*secp256k1.SchnorrKeyPair)(s), sighashReusedValues)
 }
 
-func (s *schnorrSigner) serializedPublicKey() ([]byte, error) {
-	publicKey, err := (*secp256k1.SchnorrKeyPair)(s).SchnorrPublicKey()
-	if err != nil {
-		return nil, err
-	}
-
-	serializedPublicKey, err := publicKey.Serialize()
-	if err != nil {
-		return nil, err
-	}
-
-	return serializedPublicKey[:], nil
-}
-
 type ecdsaSigner secp256k1.ECDSAPrivateKey
 
 func (e *ecdsaSigner) rawTxInSignature(tx *externalapi.DomainTransaction, idx int, hashType consensushashing.SigHashType,
@@ -45,20 +30,6 @@ func (e *ecdsaSigner) rawTxInSignature(tx *externalapi.DomainTransaction, idx in
 	return txscript.RawTxInSignatureECDSA(tx, idx, hashType, (*secp256k1.ECDSAPrivateKey)(e), sighashReusedValues)
 }
 
-func (e *ecdsaSigner) serializedPublicKey() ([]byte, error) {
-	publicKey, err := (*secp256k1.ECDSAPrivateKey)(e).ECDSAPublicKey()
-	if err != nil {
-		return nil, err
-	}
-
-	serializedPublicKey, err := publicKey.Serialize()
-	if err != nil {
-		return nil, err
-	}
-
-	return serializedPublicKey[:], nil
-}
-
 func deserializeECDSAPrivateKey(privateKey []byte, ecdsa bool) (signer, error) {
 	if ecdsa {
 		keyPair, err := secp256k1.DeserializeECDSAPrivateKeyFromSlice(privateKey)
@@ -77,24 +48,15 @@ func deserializeECDSAPrivateKey(privateKey []byte, ecdsa bool) (signer, error) {
 	return (*schnorrSigner)(keyPair), nil
 }
 
-// Sign signs the transaction with the given private keys
-func Sign(privateKeys [][]byte, serializedPSTx []byte, ecdsa bool) ([]byte, error) {
-	keyPairs := make([]signer, len(privateKeys))
-	for i, privateKey := range privateKeys {
-		var err error
-		keyPairs[i], err = deserializeECDSAPrivateKey(privateKey, ecdsa)
-		if err != nil {
-			return nil, errors.Wrap(err, "Error deserializing private key")
-		}
-	}
-
+// Sign signs the transaction with the given mnemonics
+func Sign(params *dagconfig.Params, mnemonics []string, serializedPSTx []byte, ecdsa bool) ([]byte, error) {
 	partiallySignedTransaction, err := serialization.DeserializePartiallySignedTransaction(serializedPSTx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, keyPair := range keyPairs {
-		err = sign(keyPair, partiallySignedTransaction)
+	for _, mnemonic := range mnemonics {
+		err = sign(params, mnemonic, partiallySignedTransaction, ecdsa)
 		if err != nil {
 			return nil, err
 		}
@@ -103,16 +65,11 @@ func Sign(privateKeys [][]byte, serializedPSTx []byte, ecdsa bool) ([]byte, erro
 	return serialization.SerializePartiallySignedTransaction(partiallySignedTransaction)
 }
 
-func sign(keyPair signer, psTx *serialization.PartiallySignedTransaction) error {
+func sign(params *dagconfig.Params, mnemonic string, psTx *serialization.PartiallySignedTransaction, ecdsa bool) error {
 	if isTransactionFullySigned(psTx) {
 		return nil
 	}
 
-	serializedPublicKey, err := keyPair.serializedPublicKey()
-	if err != nil {
-		return err
-	}
-
 	sighashReusedValues := &consensushashing.SighashReusedValues{}
 	for i, partiallySignedInput := range psTx.PartiallySignedInputs {
 		prevOut := partiallySignedInput.PrevOutput
@@ -124,17 +81,48 @@ func sign(keyPair signer, psTx *serialization.PartiallySignedTransaction) error
 		)
 	}
 
+	// All the inputs of a wallet transaction share the same set of cosigners, so the
+	// first input is enough to determine the derivation path of the signing key.
+	isMultisig := len(psTx.PartiallySignedInputs) > 0 && len(psTx.PartiallySignedInputs[0].PubKeySignaturePairs) > 1
+	extendedKey, err := extendedKeyFromMnemonicAndPath(mnemonic, defaultPath(isMultisig), params)
+	if err != nil {
+		return err
+	}
+
+	extendedPublicKey, err := extendedKey.Public()
+	if err != nil {
+		return err
+	}
+	extendedPublicKeyString := extendedPublicKey.String()
+
 	signed := false
 	for i, partiallySignedInput := range psTx.PartiallySignedInputs {
 		for _, pair := range partiallySignedInput.PubKeySignaturePairs {
-			if bytes.Equal(pair.PubKey, serializedPublicKey[:]) {
-				pair.Signature, err = keyPair.rawTxInSignature(psTx.Tx, i, consensushashing.SigHashAll, sighashReusedValues)
-				if err != nil {
-					return err
-				}
+			if pair.ExtendedPublicKey != extendedPublicKeyString {
+				continue
+			}
+
+			derivedKey, err := extendedKey.DeriveFromPath(partiallySignedInput.DerivationPath)
+			if err != nil {
+				return err
+			}
 
-				signed = true
+			privateKey, err := derivedKey.PrivateKey()
+			if err != nil {
+				return err
 			}
+
+			keyPair, err := deserializeECDSAPrivateKey(privateKey.Serialize()[:], ecdsa)
+			if err != nil {
+				return err
+			}
+
+			pair.Signature, err = keyPair.rawTxInSignature(psTx.Tx, i, consensushashing.SigHashAll, sighashReusedValues)
+			if err != nil {
+				return err
+			}
+
+			signed = true
 		}
 	}
 
@@ -1,12 +1,12 @@
 package libkaspawallet
 
 import (
-	"bytes"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/pkg/errors"
 	"sort"
@@ -18,22 +18,35 @@ type Payment struct {
 	Amount  uint64
 }
 
-func sortPublicKeys(publicKeys [][]byte) {
-	sort.Slice(publicKeys, func(i, j int) bool {
-		return bytes.Compare(publicKeys[i], publicKeys[j]) < 0
-	})
+// UTXO is a type that stores a UTXO and meta data
+// that is needed in order to sign it and create
+// transactions with it.
+type UTXO struct {
+	Outpoint       *externalapi.DomainOutpoint
+	UTXOEntry      externalapi.UTXOEntry
+	DerivationPath string
 }
 
-// CreateUnsignedTransaction creates an unsigned transaction
+func sortPublicKeys(extendedPublicKeys []string) []string {
+	sortedExtendedPublicKeys := make([]string, len(extendedPublicKeys))
+	copy(sortedExtendedPublicKeys, extendedPublicKeys)
+	sort.Strings(sortedExtendedPublicKeys)
+	return sortedExtendedPublicKeys
+}
+
+// CreateUnsignedTransaction creates an unsigned transaction. Unless replaceable is set, the transaction
+// doesn't opt in to be replaced by a transaction that pays a higher fee.
 func CreateUnsignedTransaction(
-	pubKeys [][]byte,
+	extendedPublicKeys []string,
 	minimumSignatures uint32,
 	ecdsa bool,
 	payments []*Payment,
-	selectedUTXOs []*externalapi.OutpointAndUTXOEntryPair) ([]byte, error) {
+	selectedUTXOs []*UTXO,
+	replaceable bool) ([]byte, error) {
 
-	sortPublicKeys(pubKeys)
-	unsignedTransaction, err := createUnsignedTransaction(pubKeys, minimumSignatures, ecdsa, payments, selectedUTXOs)
+	sortedExtendedPublicKeys := sortPublicKeys(extendedPublicKeys)
+	unsignedTransaction, err := createUnsignedTransaction(sortedExtendedPublicKeys, minimumSignatures, ecdsa,
+		payments, selectedUTXOs, replaceable)
 	if err != nil {
 		return nil, err
 	}
@@ -41,13 +54,18 @@ func CreateUnsignedTransaction(
 	return serialization.SerializePartiallySignedTransaction(unsignedTransaction)
 }
 
-func multiSigRedeemScript(pubKeys [][]byte, minimumSignatures uint32, ecdsa bool) ([]byte, error) {
+func multiSigRedeemScript(extendedPublicKeys []string, minimumSignatures uint32, path string, ecdsa bool) ([]byte, error) {
 	scriptBuilder := txscript.NewScriptBuilder()
 	scriptBuilder.AddInt64(int64(minimumSignatures))
-	for _, key := range pubKeys {
-		scriptBuilder.AddData(key)
+	for _, key := range sortPublicKeys(extendedPublicKeys) {
+		publicKey, err := extendedPublicKeyToPublicKey(key, path, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+
+		scriptBuilder.AddData(publicKey)
 	}
-	scriptBuilder.AddInt64(int64(len(pubKeys)))
+	scriptBuilder.AddInt64(int64(len(extendedPublicKeys)))
 
 	if ecdsa {
 		scriptBuilder.AddOp(txscript.OpCheckMultiSigECDSA)
@@ -59,32 +77,38 @@ func multiSigRedeemScript(pubKeys [][]byte, minimumSignatures uint32, ecdsa bool
 }
 
 func createUnsignedTransaction(
-	pubKeys [][]byte,
+	extendedPublicKeys []string,
 	minimumSignatures uint32,
 	ecdsa bool,
 	payments []*Payment,
-	selectedUTXOs []*externalapi.OutpointAndUTXOEntryPair) (*serialization.PartiallySignedTransaction, error) {
+	selectedUTXOs []*UTXO,
+	replaceable bool) (*serialization.PartiallySignedTransaction, error) {
 
-	var redeemScript []byte
-	if len(pubKeys) > 1 {
-		var err error
-		redeemScript, err = multiSigRedeemScript(pubKeys, minimumSignatures, ecdsa)
-		if err != nil {
-			return nil, err
-		}
+	sequence := constants.MaxTxInSequenceNum
+	if replaceable {
+		sequence = relaypolicy.ReplaceableSequence
 	}
 
 	inputs := make([]*externalapi.DomainTransactionInput, len(selectedUTXOs))
 	partiallySignedInputs := make([]*serialization.PartiallySignedInput, len(selectedUTXOs))
 	for i, utxo := range selectedUTXOs {
-		emptyPubKeySignaturePairs := make([]*serialization.PubKeySignaturePair, len(pubKeys))
-		for i, pubKey := range pubKeys {
+		var redeemScript []byte
+		if len(extendedPublicKeys) > 1 {
+			var err error
+			redeemScript, err = multiSigRedeemScript(extendedPublicKeys, minimumSignatures, utxo.DerivationPath, ecdsa)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		emptyPubKeySignaturePairs := make([]*serialization.PubKeySignaturePair, len(extendedPublicKeys))
+		for i, extendedPublicKey := range extendedPublicKeys {
 			emptyPubKeySignaturePairs[i] = &serialization.PubKeySignaturePair{
-				PubKey: pubKey,
+				ExtendedPublicKey: extendedPublicKey,
 			}
 		}
 
-		inputs[i] = &externalapi.DomainTransactionInput{PreviousOutpoint: *utxo.Outpoint}
+		inputs[i] = &externalapi.DomainTransactionInput{PreviousOutpoint: *utxo.Outpoint, Sequence: sequence}
 		partiallySignedInputs[i] = &serialization.PartiallySignedInput{
 			RedeeemScript: redeemScript,
 			PrevOutput: &externalapi.DomainTransactionOutput{
@@ -93,6 +117,7 @@ func createUnsignedTransaction(
 			},
 			MinimumSignatures:    minimumSignatures,
 			PubKeySignaturePairs: emptyPubKeySignaturePairs,
+			DerivationPath:       utxo.DerivationPath,
 		}
 	}
 
@@ -35,17 +35,24 @@ func TestMultisig(t *testing.T) {
 			defer teardown(false)
 
 			const numKeys = 3
-			privateKeys := make([][]byte, numKeys)
-			publicKeys := make([][]byte, numKeys)
+			mnemonics := make([]string, numKeys)
+			publicKeys := make([]string, numKeys)
 			for i := 0; i < numKeys; i++ {
-				privateKeys[i], publicKeys[i], err = libkaspawallet.CreateKeyPair(ecdsa)
+				var err error
+				mnemonics[i], err = libkaspawallet.CreateMnemonic()
 				if err != nil {
-					t.Fatalf("CreateKeyPair: %+v", err)
+					t.Fatalf("CreateMnemonic: %+v", err)
+				}
+
+				publicKeys[i], err = libkaspawallet.MasterPublicKeyFromMnemonic(&consensusConfig.Params, mnemonics[i], true)
+				if err != nil {
+					t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
 				}
 			}
 
 			const minimumSignatures = 2
-			address, err := libkaspawallet.Address(&consensusConfig.Params, publicKeys, minimumSignatures, ecdsa)
+			path := "m/1/2/3"
+			address, err := libkaspawallet.Address(&consensusConfig.Params, publicKeys, minimumSignatures, path, ecdsa)
 			if err != nil {
 				t.Fatalf("Address: %+v", err)
 			}
@@ -81,19 +88,22 @@ func TestMultisig(t *testing.T) {
 
 			block1Tx := block1.Transactions[0]
 			block1TxOut := block1Tx.Outputs[0]
-			selectedUTXOs := []*externalapi.OutpointAndUTXOEntryPair{{
-				Outpoint: &externalapi.DomainOutpoint{
-					TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
-					Index:         0,
+			selectedUTXOs := []*libkaspawallet.UTXO{
+				{
+					Outpoint: &externalapi.DomainOutpoint{
+						TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
+						Index:         0,
+					},
+					UTXOEntry:      utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
+					DerivationPath: path,
 				},
-				UTXOEntry: utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
-			}}
+			}
 
 			unsignedTransaction, err := libkaspawallet.CreateUnsignedTransaction(publicKeys, minimumSignatures, ecdsa,
 				[]*libkaspawallet.Payment{{
 					Address: address,
 					Amount:  10,
-				}}, selectedUTXOs)
+				}}, selectedUTXOs, false)
 			if err != nil {
 				t.Fatalf("CreateUnsignedTransaction: %+v", err)
 			}
@@ -112,7 +122,7 @@ func TestMultisig(t *testing.T) {
 				t.Fatal("Unexpectedly succeed to extract a valid transaction out of unsigned transaction")
 			}
 
-			signedTxStep1, err := libkaspawallet.Sign(privateKeys[:1], unsignedTransaction, ecdsa)
+			signedTxStep1, err := libkaspawallet.Sign(&consensusConfig.Params, mnemonics[:1], unsignedTransaction, ecdsa)
 			if err != nil {
 				t.Fatalf("IsTransactionFullySigned: %+v", err)
 			}
@@ -126,7 +136,7 @@ func TestMultisig(t *testing.T) {
 				t.Fatalf("Transaction is not expected to be fully signed")
 			}
 
-			signedTxStep2, err := libkaspawallet.Sign(privateKeys[1:2], signedTxStep1, ecdsa)
+			signedTxStep2, err := libkaspawallet.Sign(&consensusConfig.Params, mnemonics[1:2], signedTxStep1, ecdsa)
 			if err != nil {
 				t.Fatalf("Sign: %+v", err)
 			}
@@ -136,7 +146,7 @@ func TestMultisig(t *testing.T) {
 				t.Fatalf("ExtractTransaction: %+v", err)
 			}
 
-			signedTxOneStep, err := libkaspawallet.Sign(privateKeys[:2], unsignedTransaction, ecdsa)
+			signedTxOneStep, err := libkaspawallet.Sign(&consensusConfig.Params, mnemonics[:2], unsignedTransaction, ecdsa)
 			if err != nil {
 				t.Fatalf("Sign: %+v", err)
 			}
@@ -179,17 +189,24 @@ func TestP2PK(t *testing.T) {
 			defer teardown(false)
 
 			const numKeys = 1
-			privateKeys := make([][]byte, numKeys)
-			publicKeys := make([][]byte, numKeys)
+			mnemonics := make([]string, numKeys)
+			publicKeys := make([]string, numKeys)
 			for i := 0; i < numKeys; i++ {
-				privateKeys[i], publicKeys[i], err = libkaspawallet.CreateKeyPair(ecdsa)
+				var err error
+				mnemonics[i], err = libkaspawallet.CreateMnemonic()
 				if err != nil {
-					t.Fatalf("CreateKeyPair: %+v", err)
+					t.Fatalf("CreateMnemonic: %+v", err)
+				}
+
+				publicKeys[i], err = libkaspawallet.MasterPublicKeyFromMnemonic(&consensusConfig.Params, mnemonics[i], false)
+				if err != nil {
+					t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
 				}
 			}
 
 			const minimumSignatures = 1
-			address, err := libkaspawallet.Address(&consensusConfig.Params, publicKeys, minimumSignatures, ecdsa)
+			path := "m/1/2"
+			address, err := libkaspawallet.Address(&consensusConfig.Params, publicKeys, minimumSignatures, path, ecdsa)
 			if err != nil {
 				t.Fatalf("Address: %+v", err)
 			}
@@ -231,20 +248,23 @@ func TestP2PK(t *testing.T) {
 
 			block1Tx := block1.Transactions[0]
 			block1TxOut := block1Tx.Outputs[0]
-			selectedUTXOs := []*externalapi.OutpointAndUTXOEntryPair{{
-				Outpoint: &externalapi.DomainOutpoint{
-					TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
-					Index:         0,
+			selectedUTXOs := []*libkaspawallet.UTXO{
+				{
+					Outpoint: &externalapi.DomainOutpoint{
+						TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
+						Index:         0,
+					},
+					UTXOEntry:      utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
+					DerivationPath: path,
 				},
-				UTXOEntry: utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
-			}}
+			}
 
 			unsignedTransaction, err := libkaspawallet.CreateUnsignedTransaction(publicKeys, minimumSignatures,
 				ecdsa,
 				[]*libkaspawallet.Payment{{
 					Address: address,
 					Amount:  10,
-				}}, selectedUTXOs)
+				}}, selectedUTXOs, false)
 			if err != nil {
 				t.Fatalf("CreateUnsignedTransaction: %+v", err)
 			}
@@ -263,7 +283,7 @@ func TestP2PK(t *testing.T) {
 				t.Fatal("Unexpectedly succeed to extract a valid transaction out of unsigned transaction")
 			}
 
-			signedTx, err := libkaspawallet.Sign(privateKeys, unsignedTransaction, ecdsa)
+			signedTx, err := libkaspawallet.Sign(&consensusConfig.Params, mnemonics, unsignedTransaction, ecdsa)
 			if err != nil {
 				t.Fatalf("IsTransactionFullySigned: %+v", err)
 			}
@@ -23,6 +23,14 @@ func main() {
 		err = showAddress(config.(*showAddressConfig))
 	case dumpUnencryptedDataSubCmd:
 		err = dumpUnencryptedData(config.(*dumpUnencryptedDataConfig))
+	case startDaemonSubCmd:
+		err = startDaemon(config.(*startDaemonConfig))
+	case historySubCmd:
+		err = history(config.(*historyConfig))
+	case bumpFeeSubCmd:
+		err = bumpFee(config.(*bumpFeeConfig))
+	case sweepLegacySubCmd:
+		err = sweepLegacy(config.(*sweepLegacyConfig))
 	default:
 		err = errors.Errorf("Unknown sub-command '%s'\n", subCmd)
 	}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// paymentsFlags are the flags of the commands that pay to one or more recipients
+type paymentsFlags struct {
+	ToAddresses  []string `long:"to-address" short:"t" description:"The public address to send Kaspa to. May be repeated along with --send-amount to pay multiple recipients"`
+	SendAmounts  []string `long:"send-amount" short:"v" description:"An amount to send in Kaspa (e.g. 1234.12345678). May be repeated along with --to-address to pay multiple recipients"`
+	PaymentsFile string   `long:"payments-file" short:"p" description:"A CSV file of address,amount lines, or a JSON file (with a .json extension) of [{\"address\": ..., \"amount\": ...}] objects, with amounts in Kaspa as numbers or strings"`
+}
+
+// paymentJSON is a single payment of a JSON payments file. The amount is kept as
+// it's written in the file, either as a number or as a string, so that it's converted
+// to sompi exactly.
+type paymentJSON struct {
+	Address string      `json:"address"`
+	Amount  json.Number `json:"amount"`
+}
+
+func (flags *paymentsFlags) payments(params *dagconfig.Params) ([]*libkaspawallet.Payment, error) {
+	isFlagsUsed := len(flags.ToAddresses) > 0 || len(flags.SendAmounts) > 0
+	if isFlagsUsed == (flags.PaymentsFile != "") {
+		return nil, errors.New("Exactly one of --to-address along with --send-amount, or --payments-file is required")
+	}
+
+	var paymentsJSON []*paymentJSON
+	if isFlagsUsed {
+		if len(flags.ToAddresses) != len(flags.SendAmounts) {
+			return nil, errors.Errorf("Got %d addresses but %d amounts. Every --to-address must have a "+
+				"matching --send-amount", len(flags.ToAddresses), len(flags.SendAmounts))
+		}
+		paymentsJSON = make([]*paymentJSON, len(flags.ToAddresses))
+		for i := range flags.ToAddresses {
+			paymentsJSON[i] = &paymentJSON{Address: flags.ToAddresses[i], Amount: json.Number(flags.SendAmounts[i])}
+		}
+	} else {
+		var err error
+		paymentsJSON, err = readPaymentsFile(flags.PaymentsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(paymentsJSON) == 0 {
+		return nil, errors.New("No payments were given")
+	}
+
+	payments := make([]*libkaspawallet.Payment, len(paymentsJSON))
+	for i, payment := range paymentsJSON {
+		address, err := util.DecodeAddress(payment.Address, params.Prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address of payment #%d", i+1)
+		}
+		amount, err := parseKaspaAmount(string(payment.Amount))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid amount of payment #%d", i+1)
+		}
+		if amount == 0 {
+			return nil, errors.Errorf("the amount of payment #%d must be positive", i+1)
+		}
+
+		payments[i] = &libkaspawallet.Payment{
+			Address: address,
+			Amount:  amount,
+		}
+	}
+
+	return payments, nil
+}
+
+func readPaymentsFile(path string) ([]*paymentJSON, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		var payments []*paymentJSON
+		err := json.NewDecoder(file).Decode(&payments)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing the payments file %s", path)
+		}
+		return payments, nil
+	}
+
+	return readPaymentsCSV(file)
+}
+
+// readPaymentsCSV reads address,amount records. Empty lines and lines starting with # are ignored.
+func readPaymentsCSV(reader io.Reader) ([]*paymentJSON, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = 2
+	csvReader.TrimLeadingSpace = true
+
+	var payments []*paymentJSON
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing the payments file")
+		}
+
+		payments = append(payments, &paymentJSON{
+			Address: strings.TrimSpace(record[0]),
+			Amount:  json.Number(strings.TrimSpace(record[1])),
+		})
+	}
+
+	return payments, nil
+}
+
+// parseKaspaAmount converts a decimal amount in Kaspa, such as 1234.12345678, to sompi.
+// The amount is parsed as a decimal string rather than as a float, so that it's converted
+// exactly, and amounts with more than 8 fractional digits are rejected.
+func parseKaspaAmount(amount string) (uint64, error) {
+	const maxFractionalDigits = 8
+
+	integerPart, fractionalPart := amount, ""
+	if dotIndex := strings.IndexByte(amount, '.'); dotIndex != -1 {
+		integerPart, fractionalPart = amount[:dotIndex], amount[dotIndex+1:]
+		if fractionalPart == "" {
+			return 0, errors.Errorf("'%s' has no digits after the decimal point", amount)
+		}
+	}
+	if !isDecimalDigits(integerPart) || (fractionalPart != "" && !isDecimalDigits(fractionalPart)) {
+		return 0, errors.Errorf("'%s' is not a decimal amount of Kaspa", amount)
+	}
+	if len(fractionalPart) > maxFractionalDigits {
+		return 0, errors.Errorf("'%s' has more than %d digits after the decimal point", amount, maxFractionalDigits)
+	}
+
+	kaspa, err := strconv.ParseUint(integerPart, 10, 64)
+	if err != nil || kaspa > constants.MaxSompi/util.SompiPerKaspa {
+		return 0, errors.Errorf("'%s' is more than the maximum amount of KAS %d",
+			amount, constants.MaxSompi/util.SompiPerKaspa)
+	}
+
+	fractionalSompi := uint64(0)
+	if fractionalPart != "" {
+		fractionalPart += strings.Repeat("0", maxFractionalDigits-len(fractionalPart))
+		fractionalSompi, err = strconv.ParseUint(fractionalPart, 10, 64)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	sompi := kaspa*util.SompiPerKaspa + fractionalSompi
+	if sompi > constants.MaxSompi {
+		return 0, errors.Errorf("'%s' is more than the maximum amount of KAS %d",
+			amount, constants.MaxSompi/util.SompiPerKaspa)
+	}
+	return sompi, nil
+}
+
+func isDecimalDigits(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, char := range str {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util"
+)
+
+func TestParseKaspaAmount(t *testing.T) {
+	tests := []struct {
+		amount      string
+		sompi       uint64
+		expectError bool
+	}{
+		{amount: "0.29", sompi: 29_000_000},
+		{amount: "1.1", sompi: 110_000_000},
+		{amount: "1234.12345678", sompi: 123_412_345_678},
+		{amount: "0.00000001", sompi: 1},
+		{amount: "5", sompi: 500_000_000},
+		{amount: "21000000", sompi: 21_000_000 * util.SompiPerKaspa},
+		{amount: "21000000.00000001", expectError: true},
+		{amount: "18446744073709551616", expectError: true},
+		{amount: "0.000000001", expectError: true},
+		{amount: "-1", expectError: true},
+		{amount: "1.", expectError: true},
+		{amount: ".5", expectError: true},
+		{amount: "1e3", expectError: true},
+		{amount: "1,5", expectError: true},
+		{amount: "", expectError: true},
+	}
+
+	for _, test := range tests {
+		sompi, err := parseKaspaAmount(test.amount)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("parseKaspaAmount(%q): expected an error, got %d", test.amount, sompi)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseKaspaAmount(%q): %s", test.amount, err)
+			continue
+		}
+		if sompi != test.sompi {
+			t.Errorf("parseKaspaAmount(%q): expected %d sompi, got %d", test.amount, test.sompi, sompi)
+		}
+	}
+}
+
+func TestPaymentsFile(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	address, err := util.NewAddressPublicKey(make([]byte, 32), params.Prefix)
+	if err != nil {
+		t.Fatalf("NewAddressPublicKey: %s", err)
+	}
+
+	directory := t.TempDir()
+	files := map[string]string{
+		"payments.csv": address.String() + ",0.29\n" + address.String() + ", 1.1\n",
+		"payments.json": `[{"address": "` + address.String() + `", "amount": 0.29}, ` +
+			`{"address": "` + address.String() + `", "amount": "1.1"}]`,
+	}
+	for name, content := range files {
+		path := filepath.Join(directory, name)
+		err := os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("WriteFile: %s", err)
+		}
+
+		flags := &paymentsFlags{PaymentsFile: path}
+		payments, err := flags.payments(params)
+		if err != nil {
+			t.Fatalf("%s: payments: %s", name, err)
+		}
+		if len(payments) != 2 {
+			t.Fatalf("%s: expected 2 payments, got %d", name, len(payments))
+		}
+		if payments[0].Amount != 29_000_000 || payments[1].Amount != 110_000_000 {
+			t.Fatalf("%s: unexpected amounts %d and %d", name, payments[0].Amount, payments[1].Amount)
+		}
+	}
+
+	flags := &paymentsFlags{ToAddresses: []string{address.String()}, SendAmounts: []string{"0.29"}}
+	payments, err := flags.payments(params)
+	if err != nil {
+		t.Fatalf("payments: %s", err)
+	}
+	if payments[0].Amount != 29_000_000 {
+		t.Fatalf("Unexpected amount %d", payments[0].Amount)
+	}
+}
@@ -22,77 +22,76 @@ func send(conf *sendConfig) error {
 	if err != nil {
 		return err
 	}
-
-	toAddress, err := util.DecodeAddress(conf.ToAddress, conf.ActiveNetParams.Prefix)
-	if err != nil {
-		return err
+	if keysFile.WatchOnly {
+		return errors.Wrap(keys.ErrWatchOnly, "use create-unsigned-transaction instead of send")
 	}
 
-	fromAddress, err := libkaspawallet.Address(conf.NetParams(), keysFile.PublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA)
+	payments, err := conf.payments(conf.NetParams())
 	if err != nil {
 		return err
 	}
 
-	client, err := connectToRPC(conf.NetParams(), conf.RPCServer)
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
 	if err != nil {
 		return err
 	}
-	utxos, err := fetchSpendableUTXOs(conf.NetParams(), client, fromAddress.String())
+	utxos, err := fetchSpendableUTXOs(conf.NetParams(), client, keysFile)
 	if err != nil {
 		return err
 	}
 
-	sendAmountSompi := uint64(conf.SendAmount * util.SompiPerKaspa)
-
-	const feePerInput = 1000
-	selectedUTXOs, changeSompi, err := selectUTXOs(utxos, sendAmountSompi, feePerInput)
+	strategy, err := libkaspawallet.ParseCoinSelectionStrategy(conf.CoinSelection)
 	if err != nil {
 		return err
 	}
 
-	psTx, err := libkaspawallet.CreateUnsignedTransaction(keysFile.PublicKeys,
-		keysFile.MinimumSignatures,
-		keysFile.ECDSA,
-		[]*libkaspawallet.Payment{{
-			Address: toAddress,
-			Amount:  sendAmountSompi,
-		}, {
-			Address: fromAddress,
-			Amount:  changeSompi,
-		}},
-		selectedUTXOs)
+	psTxs, fee, err := createWalletTransactions(conf.NetParams(), keysFile, utxos, payments, conf.FeeRate, strategy,
+		conf.Replaceable)
 	if err != nil {
 		return err
 	}
 
-	privateKeys, err := keysFile.DecryptPrivateKeys()
+	mnemonics, err := keysFile.DecryptMnemonics()
 	if err != nil {
 		return err
 	}
 
-	updatedPSTx, err := libkaspawallet.Sign(privateKeys, psTx, keysFile.ECDSA)
-	if err != nil {
-		return err
-	}
+	// Sign all the transactions before broadcasting any of them, so that a failure
+	// doesn't leave the payments partially sent
+	txs := make([]*externalapi.DomainTransaction, len(psTxs))
+	for i, psTx := range psTxs {
+		updatedPSTx, err := libkaspawallet.Sign(conf.NetParams(), mnemonics, psTx, keysFile.ECDSA)
+		if err != nil {
+			return err
+		}
 
-	tx, err := libkaspawallet.ExtractTransaction(updatedPSTx)
-	if err != nil {
-		return err
+		txs[i], err = libkaspawallet.ExtractTransaction(updatedPSTx)
+		if err != nil {
+			return err
+		}
 	}
 
-	transactionID, err := sendTransaction(client, tx)
-	if err != nil {
-		return err
+	if len(txs) > 1 {
+		fmt.Printf("The payments were split into %d transactions\n", len(txs))
 	}
+	for _, tx := range txs {
+		transactionID, err := sendTransaction(client, tx)
+		if err != nil {
+			return err
+		}
 
-	fmt.Println("Transaction was sent successfully")
-	fmt.Printf("Transaction ID: \t%s\n", transactionID)
+		fmt.Println("Transaction was sent successfully")
+		fmt.Printf("Transaction ID: \t%s\n", transactionID)
+	}
+	fmt.Printf("Fee: \t\t\tKAS %f\n", float64(fee)/util.SompiPerKaspa)
 
 	return nil
 }
 
-func fetchSpendableUTXOs(params *dagconfig.Params, client *rpcclient.RPCClient, address string) ([]*appmessage.UTXOsByAddressesEntry, error) {
-	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses([]string{address})
+func fetchSpendableUTXOs(params *dagconfig.Params, client *rpcclient.RPCClient, keysFile *keys.Data) (
+	[]*libkaspawallet.UTXO, error) {
+
+	utxos, addressesByString, err := fetchWalletUTXOs(params, client, keysFile)
 	if err != nil {
 		return nil, err
 	}
@@ -102,67 +101,50 @@ func fetchSpendableUTXOs(params *dagconfig.Params, client *rpcclient.RPCClient,
 		return nil, err
 	}
 
-	spendableUTXOs := make([]*appmessage.UTXOsByAddressesEntry, 0)
-	for _, entry := range getUTXOsByAddressesResponse.Entries {
+	spendableUTXOs := make([]*libkaspawallet.UTXO, 0)
+	for _, entry := range utxos {
 		if !isUTXOSpendable(entry, blockDAGInfo.VirtualDAAScore, params.BlockCoinbaseMaturity) {
 			continue
 		}
-		spendableUTXOs = append(spendableUTXOs, entry)
-	}
-	return spendableUTXOs, nil
-}
-
-func selectUTXOs(utxos []*appmessage.UTXOsByAddressesEntry, spendAmount uint64, feePerInput uint64) (
-	selectedUTXOs []*externalapi.OutpointAndUTXOEntryPair, changeSompi uint64, err error) {
-
-	selectedUTXOs = []*externalapi.OutpointAndUTXOEntryPair{}
-	totalValue := uint64(0)
 
-	for _, utxo := range utxos {
-		txID, err := transactionid.FromString(utxo.Outpoint.TransactionID)
+		utxo, err := rpcUTXOToWalletUTXO(entry, addressesByString[entry.Address].derivationPath)
 		if err != nil {
-			return nil, 0, err
-		}
-
-		rpcUTXOEntry := utxo.UTXOEntry
-		scriptPublicKeyScript, err := hex.DecodeString(rpcUTXOEntry.ScriptPublicKey.Script)
-		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
+		spendableUTXOs = append(spendableUTXOs, utxo)
+	}
+	return spendableUTXOs, nil
+}
 
-		scriptPublicKey := &externalapi.ScriptPublicKey{
-			Script:  scriptPublicKeyScript,
-			Version: rpcUTXOEntry.ScriptPublicKey.Version,
-		}
+func rpcUTXOToWalletUTXO(entry *appmessage.UTXOsByAddressesEntry, derivationPath string) (*libkaspawallet.UTXO, error) {
+	txID, err := transactionid.FromString(entry.Outpoint.TransactionID)
+	if err != nil {
+		return nil, err
+	}
 
-		utxoEntry := utxopkg.NewUTXOEntry(rpcUTXOEntry.Amount, scriptPublicKey, rpcUTXOEntry.IsCoinbase, rpcUTXOEntry.BlockDAAScore)
-		selectedUTXOs = append(selectedUTXOs, &externalapi.OutpointAndUTXOEntryPair{
-			Outpoint: &externalapi.DomainOutpoint{
-				TransactionID: *txID,
-				Index:         utxo.Outpoint.Index,
-			},
-			UTXOEntry: utxoEntry,
-		})
-		totalValue += utxo.UTXOEntry.Amount
-
-		fee := feePerInput * uint64(len(selectedUTXOs))
-		totalSpend := spendAmount + fee
-		if totalValue >= totalSpend {
-			break
-		}
+	rpcUTXOEntry := entry.UTXOEntry
+	scriptPublicKeyScript, err := hex.DecodeString(rpcUTXOEntry.ScriptPublicKey.Script)
+	if err != nil {
+		return nil, err
 	}
 
-	fee := feePerInput * uint64(len(selectedUTXOs))
-	totalSpend := spendAmount + fee
-	if totalValue < totalSpend {
-		return nil, 0, errors.Errorf("Insufficient funds for send: %f required, while only %f available",
-			float64(totalSpend)/util.SompiPerKaspa, float64(totalValue)/util.SompiPerKaspa)
+	scriptPublicKey := &externalapi.ScriptPublicKey{
+		Script:  scriptPublicKeyScript,
+		Version: rpcUTXOEntry.ScriptPublicKey.Version,
 	}
 
-	return selectedUTXOs, totalValue - totalSpend, nil
+	return &libkaspawallet.UTXO{
+		Outpoint: &externalapi.DomainOutpoint{
+			TransactionID: *txID,
+			Index:         entry.Outpoint.Index,
+		},
+		UTXOEntry: utxopkg.NewUTXOEntry(rpcUTXOEntry.Amount, scriptPublicKey, rpcUTXOEntry.IsCoinbase,
+			rpcUTXOEntry.BlockDAAScore),
+		DerivationPath: derivationPath,
+	}, nil
 }
 
-func sendTransaction(client *rpcclient.RPCClient, tx *externalapi.DomainTransaction) (string, error) {
+func sendTransaction(client daemonRPCClient, tx *externalapi.DomainTransaction) (string, error) {
 	submitTransactionResponse, err := client.SubmitTransaction(appmessage.DomainTransactionToRPCTransaction(tx))
 	if err != nil {
 		return "", errors.Wrapf(err, "error submitting transaction")
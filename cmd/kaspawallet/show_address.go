@@ -13,11 +13,17 @@ func showAddress(conf *showAddressConfig) error {
 		return err
 	}
 
-	address, err := libkaspawallet.Address(conf.NetParams(), keysFile.PublicKeys, keysFile.MinimumSignatures, keysFile.ECDSA)
+	var address *walletAddress
+	if conf.New {
+		address, err = nextWalletAddress(conf.NetParams(), keysFile, libkaspawallet.ExternalKeychain)
+	} else {
+		address, err = walletAddressAt(conf.NetParams(), keysFile, libkaspawallet.ExternalKeychain,
+			keysFile.LastUsedExternalIndex)
+	}
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("The wallet address is:\n%s\n", address)
+	fmt.Printf("The wallet address is:\n%s\n", address.address)
 	return nil
 }
@@ -19,12 +19,12 @@ func sign(conf *signConfig) error {
 		return err
 	}
 
-	privateKeys, err := keysFile.DecryptPrivateKeys()
+	mnemonics, err := keysFile.DecryptMnemonics()
 	if err != nil {
 		return err
 	}
 
-	updatedPSTxBytes, err := libkaspawallet.Sign(privateKeys, psTxBytes, keysFile.ECDSA)
+	updatedPSTxBytes, err := libkaspawallet.Sign(conf.NetParams(), mnemonics, psTxBytes, keysFile.ECDSA)
 	if err != nil {
 		return err
 	}
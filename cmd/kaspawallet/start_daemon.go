@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/os/signal"
+	"github.com/kaspanet/kaspad/util/panics"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func startDaemon(conf *startDaemonConfig) error {
+	serverOptions, err := daemonServerOptions(conf)
+	if err != nil {
+		return err
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	// A watch-only daemon can't sign, so it doesn't need the password
+	var mnemonics []string
+	if !keysFile.WatchOnly {
+		mnemonics, err = keysFile.DecryptMnemonics()
+		if err != nil {
+			return err
+		}
+	}
+
+	initDaemonLog()
+	defer backendLog.Close()
+	defer panics.HandlePanic(log, "MAIN", nil)
+	interrupt := signal.InterruptListener()
+
+	listener, err := net.Listen("tcp", conf.Listen)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", conf.Listen)
+	}
+
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
+	if err != nil {
+		return err
+	}
+	client.SetLogger(backendLog, logger.LevelInfo)
+	defer client.Close()
+
+	daemon := newWalletDaemon(conf.NetParams(), client, keysFile, mnemonics)
+	client.SetOnReconnectedHandler(daemon.handleReconnected)
+	err = daemon.sync()
+	if err != nil {
+		return err
+	}
+	spawn("walletDaemon.syncLoop", daemon.syncLoop)
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	pb.RegisterKaspawalletdServer(grpcServer, daemon)
+	spawn("grpcServer.Serve", func() {
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			panic(errors.Wrap(err, "error serving the wallet daemon gRPC server"))
+		}
+	})
+	log.Infof("Wallet daemon is listening on %s", listener.Addr())
+
+	select {
+	case <-interrupt:
+	case <-daemon.shutdown:
+	}
+
+	log.Infof("Shutting down the wallet daemon")
+	grpcServer.GracefulStop()
+	return nil
+}
+
+// daemonServerOptions returns the options of the daemon gRPC server according to its TLS and
+// authentication flags. Since the daemon holds the decrypted keys, it refuses to listen on
+// addresses other than loopback ones if neither is configured.
+func daemonServerOptions(conf *startDaemonConfig) ([]grpc.ServerOption, error) {
+	if (conf.TLSCert == "") != (conf.TLSKey == "") {
+		return nil, errors.New("--tls-cert and --tls-key must be specified together")
+	}
+
+	isLoopback, err := isLoopbackListenAddress(conf.Listen)
+	if err != nil {
+		return nil, err
+	}
+	if !isLoopback && conf.AuthToken == "" && conf.TLSCert == "" {
+		return nil, errors.Errorf("refusing to listen on %s, which isn't a loopback address, without "+
+			"--auth-token or --tls-cert", conf.Listen)
+	}
+
+	var serverOptions []grpc.ServerOption
+	if conf.TLSCert != "" {
+		tlsConfig, err := grpcserver.LoadRPCTLSConfig(conf.TLSCert, conf.TLSKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if conf.AuthToken != "" {
+		authenticator := grpcserver.NewRPCAuthenticator(&grpcserver.RPCCredentials{Token: conf.AuthToken}, nil)
+		serverOptions = append(serverOptions, grpc.UnaryInterceptor(authenticationInterceptor(authenticator)))
+	}
+	return serverOptions, nil
+}
+
+// isLoopbackListenAddress returns whether the given listen address only accepts connections
+// from the local machine
+func isLoopbackListenAddress(listen string) (bool, error) {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid listen address %s", listen)
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
+
+// authenticationInterceptor rejects the requests that don't carry the credentials
+// of the given authenticator
+func authenticationInterceptor(authenticator *grpcserver.RPCAuthenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		var authorizations []string
+		md, ok := metadata.FromIncomingContext(ctx)
+		if ok {
+			authorizations = md.Get(grpcserver.RPCAuthorizationMetadataKey)
+		}
+		_, err := authenticator.Authenticate(authorizations)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDaemonServerOptions(t *testing.T) {
+	tlsDirectory := t.TempDir()
+	tlsCert := filepath.Join(tlsDirectory, "daemon.cert")
+	tlsKey := filepath.Join(tlsDirectory, "daemon.key")
+
+	tests := []struct {
+		name        string
+		conf        *startDaemonConfig
+		expectError bool
+	}{
+		{name: "default", conf: &startDaemonConfig{Listen: defaultDaemonListen}},
+		{name: "IPv4 loopback", conf: &startDaemonConfig{Listen: "127.0.0.1:8082"}},
+		{name: "IPv6 loopback", conf: &startDaemonConfig{Listen: "[::1]:8082"}},
+		{name: "all interfaces", conf: &startDaemonConfig{Listen: ":8082"}, expectError: true},
+		{name: "unspecified address", conf: &startDaemonConfig{Listen: "0.0.0.0:8082"}, expectError: true},
+		{name: "remote host", conf: &startDaemonConfig{Listen: "example.com:8082"}, expectError: true},
+		{name: "authenticated", conf: &startDaemonConfig{Listen: "0.0.0.0:8082", AuthToken: "token"}},
+		{name: "TLS", conf: &startDaemonConfig{Listen: "0.0.0.0:8082", TLSCert: tlsCert, TLSKey: tlsKey}},
+		{name: "TLS without key", conf: &startDaemonConfig{Listen: "0.0.0.0:8082", TLSCert: tlsCert}, expectError: true},
+		{name: "no port", conf: &startDaemonConfig{Listen: "localhost"}, expectError: true},
+	}
+
+	for _, test := range tests {
+		_, err := daemonServerOptions(test.conf)
+		if test.expectError && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+		if !test.expectError && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+func sweepLegacy(conf *sweepLegacyConfig) error {
+	legacyKeysFile, err := keys.ReadLegacyKeysFile(conf.NetParams(), conf.LegacyKeysFile)
+	if err != nil {
+		return err
+	}
+
+	toAddress, err := util.DecodeAddress(conf.ToAddress, conf.NetParams().Prefix)
+	if err != nil {
+		return err
+	}
+
+	legacyAddress, err := libkaspawallet.LegacyAddress(conf.NetParams(), legacyKeysFile.PublicKeys,
+		legacyKeysFile.MinimumSignatures, legacyKeysFile.ECDSA)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectToRPC(conf.NetParams(), conf.RPCServer, &conf.RPCClientFlags)
+	if err != nil {
+		return err
+	}
+	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses([]string{legacyAddress.String()})
+	if err != nil {
+		return err
+	}
+	blockDAGInfo, err := client.GetBlockDAGInfo()
+	if err != nil {
+		return err
+	}
+
+	var utxos []*libkaspawallet.UTXO
+	for _, entry := range getUTXOsByAddressesResponse.Entries {
+		if !isUTXOSpendable(entry, blockDAGInfo.VirtualDAAScore, conf.NetParams().BlockCoinbaseMaturity) {
+			continue
+		}
+
+		utxo, err := rpcUTXOToWalletUTXO(entry, "")
+		if err != nil {
+			return err
+		}
+		utxos = append(utxos, utxo)
+	}
+	if len(utxos) == 0 {
+		return errors.Errorf("the legacy address %s has no spendable funds", legacyAddress)
+	}
+
+	privateKeys, err := legacyKeysFile.DecryptPrivateKeys()
+	if err != nil {
+		return err
+	}
+
+	txs, err := libkaspawallet.CreateLegacySweepTransactions(conf.NetParams(), privateKeys,
+		legacyKeysFile.PublicKeys, legacyKeysFile.MinimumSignatures, legacyKeysFile.ECDSA, utxos, toAddress,
+		conf.FeeRate)
+	if err != nil {
+		return err
+	}
+
+	sweptSompi := uint64(0)
+	for _, tx := range txs {
+		transactionID, err := sendTransaction(client, tx)
+		if err != nil {
+			return err
+		}
+		sweptSompi += tx.Outputs[0].Value
+
+		fmt.Println("Transaction was sent successfully")
+		fmt.Printf("Transaction ID: \t%s\n", transactionID)
+	}
+	fmt.Printf("Swept KAS %f from %s to %s\n", float64(sweptSompi)/util.SompiPerKaspa, legacyAddress, toAddress)
+
+	return nil
+}
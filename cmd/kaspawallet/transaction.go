@@ -0,0 +1,141 @@
+package main
+
+import (
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+// selectWalletUTXOs selects UTXOs out of the given ones to fund the given payments at the given fee rate.
+// It has no side effects, and returns the change address that should be used if there's change.
+func selectWalletUTXOs(params *dagconfig.Params, keysFile *keys.Data, utxos []*libkaspawallet.UTXO,
+	payments []*libkaspawallet.Payment, feeRate float64, strategy libkaspawallet.CoinSelectionStrategy) (
+	selectedUTXOs []*libkaspawallet.UTXO, changeSompi uint64, fee uint64, err error) {
+
+	changeAddress, err := walletAddressAt(params, keysFile, libkaspawallet.InternalKeychain,
+		keysFile.LastUsedInternalIndex+1)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return libkaspawallet.SelectUTXOs(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+		keysFile.ECDSA, utxos, payments, changeAddress.address, feeRate, strategy)
+}
+
+// createWalletTransaction selects UTXOs out of the given ones to fund the given payments at the given
+// fee rate, and creates an unsigned transaction that spends them. The change, if there's any, is sent
+// to a new address of the internal keychain. Unless replaceable is set, the transaction can't be
+// replaced with bump-fee.
+func createWalletTransaction(params *dagconfig.Params, keysFile *keys.Data, utxos []*libkaspawallet.UTXO,
+	payments []*libkaspawallet.Payment, feeRate float64, strategy libkaspawallet.CoinSelectionStrategy,
+	replaceable bool) (
+	psTx []byte, selectedUTXOs []*libkaspawallet.UTXO, fee uint64, err error) {
+
+	selectedUTXOs, changeSompi, fee, err := selectWalletUTXOs(params, keysFile, utxos, payments, feeRate, strategy)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	psTx, err = buildWalletTransaction(params, keysFile, selectedUTXOs, payments, changeSompi, replaceable)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return psTx, selectedUTXOs, fee, nil
+}
+
+func buildWalletTransaction(params *dagconfig.Params, keysFile *keys.Data, selectedUTXOs []*libkaspawallet.UTXO,
+	payments []*libkaspawallet.Payment, changeSompi uint64, replaceable bool) ([]byte, error) {
+
+	if changeSompi > 0 {
+		// Mark the change address as used only once we know it's needed
+		changeAddress, err := nextWalletAddress(params, keysFile, libkaspawallet.InternalKeychain)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments[:len(payments):len(payments)], &libkaspawallet.Payment{
+			Address: changeAddress.address,
+			Amount:  changeSompi,
+		})
+	}
+
+	return libkaspawallet.CreateUnsignedTransaction(keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+		keysFile.ECDSA, payments, selectedUTXOs, replaceable)
+}
+
+// createWalletTransactions creates unsigned transactions that pay all the given payments. If the payments
+// don't fit in a single transaction that would be relayed by the network, they're split across as few
+// transactions as possible, in their original order.
+func createWalletTransactions(params *dagconfig.Params, keysFile *keys.Data, utxos []*libkaspawallet.UTXO,
+	payments []*libkaspawallet.Payment, feeRate float64, strategy libkaspawallet.CoinSelectionStrategy,
+	replaceable bool) (
+	psTxs [][]byte, totalFee uint64, err error) {
+
+	type selection struct {
+		numPayments   int
+		selectedUTXOs []*libkaspawallet.UTXO
+		changeSompi   uint64
+		fee           uint64
+	}
+
+	remainingUTXOs := utxos
+	remainingPayments := payments
+	for len(remainingPayments) > 0 {
+		// Binary search for the largest number of payments that fit in a single transaction
+		var best *selection
+		low, high := 1, len(remainingPayments)
+		for low <= high {
+			numPayments := (low + high) / 2
+			selectedUTXOs, changeSompi, fee, err := selectWalletUTXOs(params, keysFile, remainingUTXOs,
+				remainingPayments[:numPayments], feeRate, strategy)
+			if err != nil {
+				if errors.Is(err, libkaspawallet.ErrTransactionTooLarge) {
+					if numPayments == 1 {
+						return nil, 0, err
+					}
+					high = numPayments - 1
+					continue
+				}
+				return nil, 0, err
+			}
+
+			best = &selection{
+				numPayments:   numPayments,
+				selectedUTXOs: selectedUTXOs,
+				changeSompi:   changeSompi,
+				fee:           fee,
+			}
+			low = numPayments + 1
+		}
+
+		psTx, err := buildWalletTransaction(params, keysFile, best.selectedUTXOs,
+			remainingPayments[:best.numPayments], best.changeSompi, replaceable)
+		if err != nil {
+			return nil, 0, err
+		}
+		psTxs = append(psTxs, psTx)
+		totalFee += best.fee
+
+		remainingPayments = remainingPayments[best.numPayments:]
+		remainingUTXOs = excludeUTXOs(remainingUTXOs, best.selectedUTXOs)
+	}
+
+	return psTxs, totalFee, nil
+}
+
+func excludeUTXOs(utxos []*libkaspawallet.UTXO, excludedUTXOs []*libkaspawallet.UTXO) []*libkaspawallet.UTXO {
+	excludedOutpoints := make(map[externalapi.DomainOutpoint]struct{}, len(excludedUTXOs))
+	for _, utxo := range excludedUTXOs {
+		excludedOutpoints[*utxo.Outpoint] = struct{}{}
+	}
+
+	remainingUTXOs := make([]*libkaspawallet.UTXO, 0, len(utxos)-len(excludedUTXOs))
+	for _, utxo := range utxos {
+		if _, ok := excludedOutpoints[*utxo.Outpoint]; !ok {
+			remainingUTXOs = append(remainingUTXOs, utxo)
+		}
+	}
+	return remainingUTXOs
+}
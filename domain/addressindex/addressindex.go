@@ -0,0 +1,231 @@
+package addressindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/chainindex"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"sync"
+)
+
+// AddressIndex maintains an index between scriptPublicKeys and the
+// transactions accepted by the selected parent chain that paid or
+// spent from them
+type AddressIndex struct {
+	consensus  externalapi.Consensus
+	store      *addressIndexStore
+	chainIndex *chainindex.Index
+
+	// prunedPruningPoint is the pruning point below which the indexed chain
+	// blocks were last deleted
+	prunedPruningPoint *externalapi.DomainHash
+
+	mutex sync.Mutex
+}
+
+// New creates a new address index.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(consensus externalapi.Consensus, database database.Database) (*AddressIndex, error) {
+	addressIndex := &AddressIndex{
+		consensus: consensus,
+		store:     newAddressIndexStore(database),
+	}
+	addressIndex.chainIndex = &chainindex.Index{
+		Name:                        "address index",
+		Log:                         log,
+		AddChainBlock:               addressIndex.addChainBlock,
+		RemoveChainBlock:            addressIndex.removeChainBlock,
+		UpdateVirtualSelectedParent: addressIndex.store.updateVirtualSelectedParent,
+		Commit:                      addressIndex.commit,
+		Discard:                     addressIndex.store.discard,
+	}
+
+	err := addressIndex.Sync()
+	if err != nil {
+		return nil, err
+	}
+	return addressIndex, nil
+}
+
+// Sync brings the address index up to date with the current virtual selected parent.
+// Unlike the UTXO index, the address index is never rebuilt from scratch, so its
+// history survives pruning point UTXO set overrides.
+func (ai *AddressIndex) Sync() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressIndex.Sync")
+	defer onEnd()
+
+	ai.mutex.Lock()
+	defer ai.mutex.Unlock()
+
+	syncPoint, err := ai.syncPoint()
+	if err != nil {
+		return err
+	}
+	return chainindex.SyncFrom(ai.consensus, ai.chainIndex, syncPoint)
+}
+
+// syncPoint returns the selected parent chain block to sync the address index from.
+// This is the virtual selected parent that the index was last synced to, unless it's
+// missing, in which case the index is synced from the pruning point. Chain blocks below
+// the pruning point can't be removed from the selected parent chain anymore, so
+// anything that was indexed for them remains correct.
+func (ai *AddressIndex) syncPoint() (*externalapi.DomainHash, error) {
+	indexedVirtualSelectedParent, err := ai.store.getVirtualSelectedParent()
+	if err != nil && !database.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	if indexedVirtualSelectedParent != nil {
+		blockInfo, err := ai.consensus.GetBlockInfo(indexedVirtualSelectedParent)
+		if err != nil {
+			return nil, err
+		}
+		if blockInfo.Exists {
+			return indexedVirtualSelectedParent, nil
+		}
+		log.Infof("The block %s the address index was synced to is missing, "+
+			"syncing it from the pruning point", indexedVirtualSelectedParent)
+	}
+
+	return ai.consensus.PruningPoint()
+}
+
+// Update updates the address index with the given DAG selected parent chain changes
+func (ai *AddressIndex) Update(blockInsertionResult *externalapi.BlockInsertionResult) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressIndex.Update")
+	defer onEnd()
+
+	ai.mutex.Lock()
+	defer ai.mutex.Unlock()
+
+	return chainindex.Update(ai.chainIndex, blockInsertionResult.VirtualSelectedParentChainChanges)
+}
+
+func (ai *AddressIndex) addChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	acceptanceData, err := ai.consensus.GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			// This is the case for the chain blocks below a pruning point whose
+			// UTXO set was just imported, so their transactions can't be indexed
+			log.Debugf("Skipping chain block %s, whose acceptance data is missing", chainBlockHash)
+			return nil
+		}
+		return err
+	}
+	blockInfo, err := ai.consensus.GetBlockInfo(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	chainBlock := &indexedChainBlock{blueScore: blockInfo.BlueScore}
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			transaction := transactionAcceptanceData.Transaction
+			transactionID := consensushashing.TransactionID(transaction)
+
+			entries := make(map[utxoindex.ScriptPublicKeyString]*TransactionEntry)
+			entryOf := func(scriptPublicKey *externalapi.ScriptPublicKey) *TransactionEntry {
+				scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)
+				entry, ok := entries[scriptPublicKeyString]
+				if !ok {
+					entry = &TransactionEntry{
+						ScriptPublicKey:         scriptPublicKey,
+						TransactionID:           transactionID,
+						IncludingBlockHash:      blockAcceptanceData.BlockHash,
+						AcceptingBlockHash:      chainBlockHash,
+						AcceptingBlockBlueScore: blockInfo.BlueScore,
+					}
+					entries[scriptPublicKeyString] = entry
+				}
+				return entry
+			}
+
+			for _, spentUTXOEntry := range transactionAcceptanceData.TransactionInputUTXOEntries {
+				entryOf(spentUTXOEntry.ScriptPublicKey()).Sent += spentUTXOEntry.Amount()
+			}
+			for _, output := range transaction.Outputs {
+				entryOf(output.ScriptPublicKey).Received += output.Value
+			}
+
+			for _, entry := range entries {
+				ai.store.putEntry(entry.ScriptPublicKey, entry)
+				chainBlock.entries = append(chainBlock.entries, &entryLocation{
+					scriptPublicKey: entry.ScriptPublicKey,
+					transactionID:   transactionID,
+				})
+			}
+		}
+	}
+
+	ai.store.putChainBlock(chainBlockHash, chainBlock)
+	return nil
+}
+
+func (ai *AddressIndex) removeChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	blockInfo, err := ai.consensus.GetBlockInfo(chainBlockHash)
+	if err != nil {
+		return err
+	}
+	chainBlock, found, err := ai.store.getChainBlock(blockInfo.BlueScore, chainBlockHash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Debugf("Chain block %s was not indexed, so there's nothing to remove", chainBlockHash)
+		return nil
+	}
+
+	for _, entry := range chainBlock.entries {
+		ai.store.deleteEntry(entry.scriptPublicKey, chainBlock.blueScore, entry.transactionID)
+	}
+	ai.store.deleteChainBlock(chainBlock.blueScore, chainBlockHash)
+	return nil
+}
+
+// commit commits the staged changes to the address index. If the pruning point moved
+// since the last commit, it also deletes the indexed chain blocks below it, since they
+// can't be removed from the selected parent chain anymore.
+func (ai *AddressIndex) commit() error {
+	pruningPoint, err := ai.consensus.PruningPoint()
+	if err != nil {
+		return err
+	}
+	if !pruningPoint.Equal(ai.prunedPruningPoint) {
+		pruningPointInfo, err := ai.consensus.GetBlockInfo(pruningPoint)
+		if err != nil {
+			return err
+		}
+		ai.store.pruneChainBlocks(pruningPointInfo.BlueScore)
+	}
+
+	err = ai.store.commit()
+	if err != nil {
+		return err
+	}
+	ai.prunedPruningPoint = pruningPoint
+	return nil
+}
+
+// TransactionEntries returns up to limit transaction entries of the given
+// scriptPublicKeys that come after the given cursor, ordered by their accepting
+// block blue score and then by their transaction ID. A nil startAfter starts
+// from the first entry. The entries of a single transaction are never split,
+// so more than limit entries are returned if the last transaction has entries
+// of several of the scriptPublicKeys.
+func (ai *AddressIndex) TransactionEntries(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	startAfter *EntryCursor, limit uint64) ([]*TransactionEntry, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressIndex.TransactionEntries")
+	defer onEnd()
+
+	ai.mutex.Lock()
+	defer ai.mutex.Unlock()
+
+	return ai.store.getTransactionEntries(scriptPublicKeys, startAfter, limit)
+}
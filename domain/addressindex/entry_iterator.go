@@ -0,0 +1,191 @@
+package addressindex
+
+import (
+	"container/heap"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// entryIterator iterates over the transaction entries of a single scriptPublicKey
+// in the order of their database keys, which is by their accepting block blue score
+// and then by their transaction ID
+type entryIterator struct {
+	scriptPublicKey *externalapi.ScriptPublicKey
+	cursor          database.Cursor
+
+	// current is nil once the iterator is exhausted
+	current *TransactionEntry
+
+	// index is the position of the scriptPublicKey in the queried scriptPublicKeys,
+	// which orders the entries of the same transaction
+	index int
+}
+
+// newEntryIterator returns an iterator over the entries of the given scriptPublicKey
+// that come after the given cursor, or over all of them if startAfter is nil
+func (ais *addressIndexStore) newEntryIterator(scriptPublicKey *externalapi.ScriptPublicKey,
+	startAfter *EntryCursor, index int) (*entryIterator, error) {
+
+	bucket := ais.bucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := ais.database.Cursor(bucket)
+	if err != nil {
+		return nil, err
+	}
+	iterator := &entryIterator{
+		scriptPublicKey: scriptPublicKey,
+		cursor:          cursor,
+		index:           index,
+	}
+
+	if startAfter == nil {
+		cursor.First()
+	} else {
+		// Seek moves the cursor to the first key that's greater than or equal
+		// to the given one, and fails with ErrNotFound unless they're equal
+		startAfterKey := bucket.Key(serializeEntryKeySuffix(startAfter.AcceptingBlockBlueScore, startAfter.TransactionID))
+		err := cursor.Seek(startAfterKey)
+		if err == nil {
+			cursor.Next()
+		} else if !database.IsNotFoundError(err) {
+			cursor.Close()
+			return nil, err
+		}
+	}
+
+	err = iterator.readCurrent()
+	if err != nil {
+		cursor.Close()
+		return nil, err
+	}
+	return iterator, nil
+}
+
+func (it *entryIterator) readCurrent() error {
+	key, err := it.cursor.Key()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			it.current = nil
+			return nil
+		}
+		return err
+	}
+	acceptingBlockBlueScore, transactionID, err := deserializeEntryKeySuffix(key.Suffix())
+	if err != nil {
+		return err
+	}
+	serializedEntry, err := it.cursor.Value()
+	if err != nil {
+		return err
+	}
+	entry, err := deserializeTransactionEntry(acceptingBlockBlueScore, transactionID, serializedEntry)
+	if err != nil {
+		return err
+	}
+	entry.ScriptPublicKey = it.scriptPublicKey
+	it.current = entry
+	return nil
+}
+
+func (it *entryIterator) next() error {
+	it.cursor.Next()
+	return it.readCurrent()
+}
+
+func (it *entryIterator) less(other *entryIterator) bool {
+	if it.current.AcceptingBlockBlueScore != other.current.AcceptingBlockBlueScore {
+		return it.current.AcceptingBlockBlueScore < other.current.AcceptingBlockBlueScore
+	}
+	if !it.current.TransactionID.Equal(other.current.TransactionID) {
+		return it.current.TransactionID.Less(other.current.TransactionID)
+	}
+	return it.index < other.index
+}
+
+// entryIteratorHeap is an implementation of heap.Interface that orders
+// non-exhausted entry iterators by their current entries
+type entryIteratorHeap []*entryIterator
+
+func (h entryIteratorHeap) Len() int           { return len(h) }
+func (h entryIteratorHeap) Less(i, j int) bool { return h[i].less(h[j]) }
+func (h entryIteratorHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *entryIteratorHeap) Push(x interface{}) {
+	*h = append(*h, x.(*entryIterator))
+}
+
+func (h *entryIteratorHeap) Pop() interface{} {
+	oldHeap := *h
+	popped := oldHeap[len(oldHeap)-1]
+	*h = oldHeap[:len(oldHeap)-1]
+	return popped
+}
+
+// getTransactionEntries merges the entries of the given scriptPublicKeys that come
+// after the given cursor, and returns up to limit of them, along with any remaining
+// entries of the last returned transaction.
+// Every scriptPublicKey is read through its own database cursor, so only the
+// returned entries are read, no matter how far into the entries startAfter is.
+func (ais *addressIndexStore) getTransactionEntries(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	startAfter *EntryCursor, limit uint64) (entries []*TransactionEntry, err error) {
+
+	if ais.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get transaction entries while staging isn't empty")
+	}
+
+	iterators := make([]*entryIterator, 0, len(scriptPublicKeys))
+	defer func() {
+		for _, iterator := range iterators {
+			closeErr := iterator.cursor.Close()
+			if err == nil {
+				err = closeErr
+			}
+		}
+	}()
+	for i, scriptPublicKey := range scriptPublicKeys {
+		iterator, err := ais.newEntryIterator(scriptPublicKey, startAfter, i)
+		if err != nil {
+			return nil, err
+		}
+		iterators = append(iterators, iterator)
+	}
+
+	// Exhausted iterators stay in the slice so that their cursors are closed,
+	// but are never added to the heap
+	iteratorHeap := make(entryIteratorHeap, 0, len(iterators))
+	for _, iterator := range iterators {
+		if iterator.current != nil {
+			iteratorHeap = append(iteratorHeap, iterator)
+		}
+	}
+	heap.Init(&iteratorHeap)
+
+	entries = make([]*TransactionEntry, 0)
+	for iteratorHeap.Len() > 0 {
+		iterator := iteratorHeap[0]
+		entry := iterator.current
+		if uint64(len(entries)) >= limit {
+			if len(entries) == 0 || !isSameTransaction(entries[len(entries)-1], entry) {
+				break
+			}
+		}
+		entries = append(entries, entry)
+
+		err := iterator.next()
+		if err != nil {
+			return nil, err
+		}
+		if iterator.current == nil {
+			heap.Pop(&iteratorHeap)
+		} else {
+			heap.Fix(&iteratorHeap, 0)
+		}
+	}
+	return entries, nil
+}
+
+func isSameTransaction(entry *TransactionEntry, other *TransactionEntry) bool {
+	return entry.AcceptingBlockBlueScore == other.AcceptingBlockBlueScore &&
+		entry.TransactionID.Equal(other.TransactionID)
+}
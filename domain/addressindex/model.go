@@ -0,0 +1,47 @@
+package addressindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+// TransactionEntry describes a transaction accepted by the selected parent
+// chain that either paid or spent from some scriptPublicKey
+type TransactionEntry struct {
+	// ScriptPublicKey is the scriptPublicKey the entry belongs to
+	ScriptPublicKey *externalapi.ScriptPublicKey
+
+	TransactionID           *externalapi.DomainTransactionID
+	IncludingBlockHash      *externalapi.DomainHash
+	AcceptingBlockHash      *externalapi.DomainHash
+	AcceptingBlockBlueScore uint64
+
+	// Received is the total amount of the transaction's outputs
+	// that pay the scriptPublicKey
+	Received uint64
+
+	// Sent is the total amount of the transaction's inputs that
+	// spend outputs of the scriptPublicKey
+	Sent uint64
+}
+
+// EntryCursor identifies the position of a TransactionEntry among the entries of
+// the address index, which are ordered by their accepting block blue score, and
+// then by their transaction ID
+type EntryCursor struct {
+	AcceptingBlockBlueScore uint64
+	TransactionID           *externalapi.DomainTransactionID
+}
+
+// indexedChainBlock lists the entries that were added to the index when
+// a chain block was added to the selected parent chain, so that they could
+// be removed without its acceptance data if the chain block is removed from it
+type indexedChainBlock struct {
+	blueScore uint64
+	entries   []*entryLocation
+}
+
+// entryLocation identifies a single transaction entry of some scriptPublicKey
+type entryLocation struct {
+	scriptPublicKey *externalapi.ScriptPublicKey
+	transactionID   *externalapi.DomainTransactionID
+}
@@ -0,0 +1,203 @@
+package addressindex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+)
+
+const (
+	blueScoreSize            = 8
+	entryKeySuffixSize       = blueScoreSize + externalapi.DomainHashSize
+	serializedEntryValueSize = 2*externalapi.DomainHashSize + 2*8
+)
+
+// serializeEntryKeySuffix serializes the blue score in big-endian, so
+// that the entries of every scriptPublicKey are ordered by it in the database
+func serializeEntryKeySuffix(acceptingBlockBlueScore uint64, transactionID *externalapi.DomainTransactionID) []byte {
+	serializedKeySuffix := make([]byte, entryKeySuffixSize)
+	binary.BigEndian.PutUint64(serializedKeySuffix[:blueScoreSize], acceptingBlockBlueScore)
+	copy(serializedKeySuffix[blueScoreSize:], transactionID.ByteSlice())
+	return serializedKeySuffix
+}
+
+// serializeChainBlockKeySuffix serializes the blue score in big-endian, so
+// that the indexed chain blocks are ordered by it in the database
+func serializeChainBlockKeySuffix(blueScore uint64, chainBlockHash *externalapi.DomainHash) []byte {
+	serializedKeySuffix := make([]byte, blueScoreSize+externalapi.DomainHashSize)
+	binary.BigEndian.PutUint64(serializedKeySuffix[:blueScoreSize], blueScore)
+	copy(serializedKeySuffix[blueScoreSize:], chainBlockHash.ByteSlice())
+	return serializedKeySuffix
+}
+
+func deserializeChainBlockKeySuffix(serializedKeySuffix []byte) (
+	blueScore uint64, chainBlockHash *externalapi.DomainHash, err error) {
+
+	if len(serializedKeySuffix) != blueScoreSize+externalapi.DomainHashSize {
+		return 0, nil, errors.Wrapf(io.ErrUnexpectedEOF, "expected a serialized chain block key of %d bytes "+
+			"but got %d bytes", blueScoreSize+externalapi.DomainHashSize, len(serializedKeySuffix))
+	}
+
+	blueScore = binary.BigEndian.Uint64(serializedKeySuffix[:blueScoreSize])
+	chainBlockHash, err = externalapi.NewDomainHashFromByteSlice(serializedKeySuffix[blueScoreSize:])
+	if err != nil {
+		return 0, nil, err
+	}
+	return blueScore, chainBlockHash, nil
+}
+
+func deserializeEntryKeySuffix(serializedKeySuffix []byte) (
+	acceptingBlockBlueScore uint64, transactionID *externalapi.DomainTransactionID, err error) {
+
+	if len(serializedKeySuffix) != entryKeySuffixSize {
+		return 0, nil, errors.Wrapf(io.ErrUnexpectedEOF, "expected a serialized entry key of %d bytes "+
+			"but got %d bytes", entryKeySuffixSize, len(serializedKeySuffix))
+	}
+
+	acceptingBlockBlueScore = binary.BigEndian.Uint64(serializedKeySuffix[:blueScoreSize])
+	transactionID, err = externalapi.NewDomainTransactionIDFromByteSlice(serializedKeySuffix[blueScoreSize:])
+	if err != nil {
+		return 0, nil, err
+	}
+	return acceptingBlockBlueScore, transactionID, nil
+}
+
+// serializeTransactionEntry serializes everything in the given entry but
+// its accepting block blue score and transaction ID, which are stored in
+// the database key
+func serializeTransactionEntry(entry *TransactionEntry) []byte {
+	buffer := bytes.NewBuffer(make([]byte, 0, serializedEntryValueSize))
+	buffer.Write(entry.IncludingBlockHash.ByteSlice())
+	buffer.Write(entry.AcceptingBlockHash.ByteSlice())
+	writeUint64(buffer, entry.Received)
+	writeUint64(buffer, entry.Sent)
+	return buffer.Bytes()
+}
+
+func deserializeTransactionEntry(acceptingBlockBlueScore uint64, transactionID *externalapi.DomainTransactionID,
+	serializedEntry []byte) (*TransactionEntry, error) {
+
+	reader := bytes.NewReader(serializedEntry)
+	includingBlockHash, err := readHash(reader)
+	if err != nil {
+		return nil, err
+	}
+	acceptingBlockHash, err := readHash(reader)
+	if err != nil {
+		return nil, err
+	}
+	received, err := readUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := readUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionEntry{
+		TransactionID:           transactionID,
+		IncludingBlockHash:      includingBlockHash,
+		AcceptingBlockHash:      acceptingBlockHash,
+		AcceptingBlockBlueScore: acceptingBlockBlueScore,
+		Received:                received,
+		Sent:                    sent,
+	}, nil
+}
+
+func serializeIndexedChainBlock(chainBlock *indexedChainBlock) []byte {
+	buffer := &bytes.Buffer{}
+	writeUint64(buffer, chainBlock.blueScore)
+	writeUint64(buffer, uint64(len(chainBlock.entries)))
+	for _, entry := range chainBlock.entries {
+		var serializedVersion [2]byte
+		binary.LittleEndian.PutUint16(serializedVersion[:], entry.scriptPublicKey.Version)
+		buffer.Write(serializedVersion[:])
+		writeUint64(buffer, uint64(len(entry.scriptPublicKey.Script)))
+		buffer.Write(entry.scriptPublicKey.Script)
+		buffer.Write(entry.transactionID.ByteSlice())
+	}
+	return buffer.Bytes()
+}
+
+func deserializeIndexedChainBlock(serializedChainBlock []byte) (*indexedChainBlock, error) {
+	reader := bytes.NewReader(serializedChainBlock)
+	blueScore, err := readUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+	entryCount, err := readUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every entry takes at least the size of its script version and length, and of its transaction ID
+	const minSerializedEntrySize = 2 + 8 + externalapi.DomainHashSize
+	if entryCount > uint64(reader.Len())/minSerializedEntrySize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an indexed chain block")
+	}
+
+	entries := make([]*entryLocation, entryCount)
+	for i := range entries {
+		var serializedVersion [2]byte
+		_, err := io.ReadFull(reader, serializedVersion[:])
+		if err != nil {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an indexed chain block")
+		}
+		scriptLength, err := readUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+		if scriptLength > uint64(reader.Len()) {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an indexed chain block")
+		}
+		script := make([]byte, scriptLength)
+		_, err = io.ReadFull(reader, script)
+		if err != nil {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an indexed chain block")
+		}
+		transactionID, err := readHash(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = &entryLocation{
+			scriptPublicKey: &externalapi.ScriptPublicKey{
+				Script:  script,
+				Version: binary.LittleEndian.Uint16(serializedVersion[:]),
+			},
+			transactionID: externalapi.NewDomainTransactionIDFromByteArray(transactionID.ByteArray()),
+		}
+	}
+
+	return &indexedChainBlock{
+		blueScore: blueScore,
+		entries:   entries,
+	}, nil
+}
+
+func writeUint64(buffer *bytes.Buffer, value uint64) {
+	var serializedValue [8]byte
+	binary.LittleEndian.PutUint64(serializedValue[:], value)
+	buffer.Write(serializedValue[:])
+}
+
+func readUint64(reader *bytes.Reader) (uint64, error) {
+	var serializedValue [8]byte
+	_, err := io.ReadFull(reader, serializedValue[:])
+	if err != nil {
+		return 0, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an address index value")
+	}
+	return binary.LittleEndian.Uint64(serializedValue[:]), nil
+}
+
+func readHash(reader *bytes.Reader) (*externalapi.DomainHash, error) {
+	var serializedHash [externalapi.DomainHashSize]byte
+	_, err := io.ReadFull(reader, serializedHash[:])
+	if err != nil {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing an address index value")
+	}
+	return externalapi.NewDomainHashFromByteArray(&serializedHash), nil
+}
@@ -0,0 +1,100 @@
+package addressindex
+
+import (
+	"bytes"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_serializeEntryKeySuffix(t *testing.T) {
+	transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	serialized := serializeEntryKeySuffix(2, transactionID)
+	blueScore, resultTransactionID, err := deserializeEntryKeySuffix(serialized)
+	if err != nil {
+		t.Fatalf("Failed deserializing entry key suffix: %v", err)
+	}
+	if blueScore != 2 || !resultTransactionID.Equal(transactionID) {
+		t.Fatalf("Expected \n %d:%s \n==\n %d:%s\n", 2, transactionID, blueScore, resultTransactionID)
+	}
+
+	// Entries have to be ordered by blue score regardless of their transaction IDs
+	lowerBlueScoreKeySuffix := serializeEntryKeySuffix(1,
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{255}))
+	higherBlueScoreKeySuffix := serializeEntryKeySuffix(256,
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{0}))
+	if bytes.Compare(lowerBlueScoreKeySuffix, higherBlueScoreKeySuffix) >= 0 {
+		t.Fatalf("Expected entry key suffixes to be ordered by blue score")
+	}
+
+	_, _, err = deserializeEntryKeySuffix(serialized[:len(serialized)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
+
+func Test_serializeTransactionEntry(t *testing.T) {
+	entry := &TransactionEntry{
+		TransactionID:           externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		IncludingBlockHash:      externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+		AcceptingBlockHash:      externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{3}),
+		AcceptingBlockBlueScore: 4,
+		Received:                5,
+		Sent:                    6,
+	}
+
+	serialized := serializeTransactionEntry(entry)
+	result, err := deserializeTransactionEntry(entry.AcceptingBlockBlueScore, entry.TransactionID, serialized)
+	if err != nil {
+		t.Fatalf("Failed deserializing transaction entry: %v", err)
+	}
+	if !reflect.DeepEqual(entry, result) {
+		t.Fatalf("Expected \n %+v \n==\n %+v\n", entry, result)
+	}
+
+	_, err = deserializeTransactionEntry(entry.AcceptingBlockBlueScore, entry.TransactionID,
+		serialized[:len(serialized)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
+
+func Test_serializeIndexedChainBlock(t *testing.T) {
+	tests := []*indexedChainBlock{
+		{
+			blueScore: 1,
+			entries:   []*entryLocation{},
+		},
+		{
+			blueScore: 2,
+			entries: []*entryLocation{
+				{
+					scriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{3, 4}, Version: 0},
+					transactionID:   externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{5}),
+				},
+				{
+					scriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{6}, Version: 7},
+					transactionID:   externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{8}),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		serialized := serializeIndexedChainBlock(test)
+		result, err := deserializeIndexedChainBlock(serialized)
+		if err != nil {
+			t.Fatalf("Failed deserializing indexed chain block: %v", err)
+		}
+		if !reflect.DeepEqual(test, result) {
+			t.Fatalf("Expected \n %+v \n==\n %+v\n", test, result)
+		}
+
+		_, err = deserializeIndexedChainBlock(serialized[:len(serialized)-1])
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("Expected error to be EOF, instead got: %v", err)
+		}
+	}
+}
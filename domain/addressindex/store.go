@@ -0,0 +1,259 @@
+package addressindex
+
+import (
+	"encoding/binary"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+var entriesBucket = database.MakeBucket([]byte("address-index"))
+var chainBlocksBucket = database.MakeBucket([]byte("address-index-chain-blocks"))
+var virtualSelectedParentKey = database.MakeBucket([]byte("")).Key([]byte("address-index-virtual-selected-parent"))
+
+type stagedEntry struct {
+	key *database.Key
+
+	// entry is nil if the entry is to be deleted
+	entry *TransactionEntry
+}
+
+type stagedChainBlock struct {
+	key *database.Key
+
+	// chainBlock is nil if the chain block is to be deleted
+	chainBlock *indexedChainBlock
+}
+
+type addressIndexStore struct {
+	database database.Database
+
+	// entriesToPut is keyed by the database keys of the entries
+	entriesToPut map[string]*stagedEntry
+
+	// chainBlocksToPut is keyed by the database keys of the chain blocks
+	chainBlocksToPut map[string]*stagedChainBlock
+
+	// pruneChainBlocksBelowBlueScore is the blue score below which all the
+	// indexed chain blocks are to be deleted, or 0 if none are
+	pruneChainBlocksBelowBlueScore uint64
+
+	virtualSelectedParent *externalapi.DomainHash
+}
+
+func newAddressIndexStore(database database.Database) *addressIndexStore {
+	return &addressIndexStore{
+		database:         database,
+		entriesToPut:     make(map[string]*stagedEntry),
+		chainBlocksToPut: make(map[string]*stagedChainBlock),
+	}
+}
+
+func (ais *addressIndexStore) putEntry(scriptPublicKey *externalapi.ScriptPublicKey, entry *TransactionEntry) {
+	log.Tracef("Staging entry of transaction %s accepted by %s", entry.TransactionID, entry.AcceptingBlockHash)
+	key := ais.convertEntryToKey(scriptPublicKey, entry.AcceptingBlockBlueScore, entry.TransactionID)
+	ais.entriesToPut[string(key.Bytes())] = &stagedEntry{key: key, entry: entry}
+}
+
+func (ais *addressIndexStore) deleteEntry(scriptPublicKey *externalapi.ScriptPublicKey,
+	acceptingBlockBlueScore uint64, transactionID *externalapi.DomainTransactionID) {
+
+	log.Tracef("Staging the deletion of the entry of transaction %s", transactionID)
+	key := ais.convertEntryToKey(scriptPublicKey, acceptingBlockBlueScore, transactionID)
+	ais.entriesToPut[string(key.Bytes())] = &stagedEntry{key: key, entry: nil}
+}
+
+// chainBlockKey returns the database key of the given chain block. Chain blocks are
+// keyed by their blue score first, so that the ones below the pruning point can be
+// found without going over the rest
+func chainBlockKey(blueScore uint64, chainBlockHash *externalapi.DomainHash) *database.Key {
+	return chainBlocksBucket.Key(serializeChainBlockKeySuffix(blueScore, chainBlockHash))
+}
+
+func (ais *addressIndexStore) putChainBlock(chainBlockHash *externalapi.DomainHash, chainBlock *indexedChainBlock) {
+	key := chainBlockKey(chainBlock.blueScore, chainBlockHash)
+	ais.chainBlocksToPut[string(key.Bytes())] = &stagedChainBlock{key: key, chainBlock: chainBlock}
+}
+
+func (ais *addressIndexStore) deleteChainBlock(blueScore uint64, chainBlockHash *externalapi.DomainHash) {
+	key := chainBlockKey(blueScore, chainBlockHash)
+	ais.chainBlocksToPut[string(key.Bytes())] = &stagedChainBlock{key: key, chainBlock: nil}
+}
+
+// pruneChainBlocks stages the deletion of all the indexed chain blocks below the
+// given blue score. They are only needed to remove chain blocks from the index when
+// they're removed from the selected parent chain, which can't happen below the
+// pruning point.
+func (ais *addressIndexStore) pruneChainBlocks(belowBlueScore uint64) {
+	ais.pruneChainBlocksBelowBlueScore = belowBlueScore
+}
+
+// getChainBlock returns what was indexed for the given chain block, taking
+// anything that was staged but not yet committed into account
+func (ais *addressIndexStore) getChainBlock(blueScore uint64, chainBlockHash *externalapi.DomainHash) (
+	chainBlock *indexedChainBlock, found bool, err error) {
+
+	key := chainBlockKey(blueScore, chainBlockHash)
+	if stagedChainBlock, ok := ais.chainBlocksToPut[string(key.Bytes())]; ok {
+		return stagedChainBlock.chainBlock, stagedChainBlock.chainBlock != nil, nil
+	}
+
+	serializedChainBlock, err := ais.database.Get(key)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	chainBlock, err = deserializeIndexedChainBlock(serializedChainBlock)
+	if err != nil {
+		return nil, false, err
+	}
+	return chainBlock, true, nil
+}
+
+func (ais *addressIndexStore) updateVirtualSelectedParent(virtualSelectedParent *externalapi.DomainHash) {
+	ais.virtualSelectedParent = virtualSelectedParent
+}
+
+func (ais *addressIndexStore) discard() {
+	ais.entriesToPut = make(map[string]*stagedEntry)
+	ais.chainBlocksToPut = make(map[string]*stagedChainBlock)
+	ais.pruneChainBlocksBelowBlueScore = 0
+	ais.virtualSelectedParent = nil
+}
+
+func (ais *addressIndexStore) commit() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "addressIndexStore.commit")
+	defer onEnd()
+
+	if ais.virtualSelectedParent == nil {
+		return errors.Errorf("cannot commit the address index without a virtual selected parent")
+	}
+
+	prunedChainBlockKeys, err := ais.chainBlockKeysBelow(ais.pruneChainBlocksBelowBlueScore)
+	if err != nil {
+		return err
+	}
+
+	dbTransaction, err := ais.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	for _, stagedEntry := range ais.entriesToPut {
+		if stagedEntry.entry == nil {
+			err = dbTransaction.Delete(stagedEntry.key)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = dbTransaction.Put(stagedEntry.key, serializeTransactionEntry(stagedEntry.entry))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, stagedChainBlock := range ais.chainBlocksToPut {
+		if stagedChainBlock.chainBlock == nil {
+			err = dbTransaction.Delete(stagedChainBlock.key)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if stagedChainBlock.chainBlock.blueScore < ais.pruneChainBlocksBelowBlueScore {
+			continue
+		}
+		err = dbTransaction.Put(stagedChainBlock.key, serializeIndexedChainBlock(stagedChainBlock.chainBlock))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range prunedChainBlockKeys {
+		err = dbTransaction.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dbTransaction.Put(virtualSelectedParentKey, ais.virtualSelectedParent.ByteSlice())
+	if err != nil {
+		return err
+	}
+
+	err = dbTransaction.Commit()
+	if err != nil {
+		return err
+	}
+
+	ais.discard()
+	return nil
+}
+
+// chainBlockKeysBelow returns the database keys of all the indexed chain blocks
+// whose blue score is below the given one
+func (ais *addressIndexStore) chainBlockKeysBelow(blueScore uint64) ([]*database.Key, error) {
+	if blueScore == 0 {
+		return nil, nil
+	}
+
+	cursor, err := ais.database.Cursor(chainBlocksBucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var keys []*database.Key
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		chainBlockBlueScore, chainBlockHash, err := deserializeChainBlockKeySuffix(key.Suffix())
+		if err != nil {
+			return nil, err
+		}
+		if chainBlockBlueScore >= blueScore {
+			break
+		}
+		keys = append(keys, chainBlockKey(chainBlockBlueScore, chainBlockHash))
+	}
+	return keys, nil
+}
+
+func (ais *addressIndexStore) bucketForScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Bucket {
+	var scriptPublicKeyBytes = make([]byte, 2+len(scriptPublicKey.Script)) // uint16
+	binary.LittleEndian.PutUint16(scriptPublicKeyBytes[:2], scriptPublicKey.Version)
+	copy(scriptPublicKeyBytes[2:], scriptPublicKey.Script)
+	return entriesBucket.Bucket(scriptPublicKeyBytes)
+}
+
+func (ais *addressIndexStore) convertEntryToKey(scriptPublicKey *externalapi.ScriptPublicKey,
+	acceptingBlockBlueScore uint64, transactionID *externalapi.DomainTransactionID) *database.Key {
+
+	bucket := ais.bucketForScriptPublicKey(scriptPublicKey)
+	return bucket.Key(serializeEntryKeySuffix(acceptingBlockBlueScore, transactionID))
+}
+
+func (ais *addressIndexStore) isAnythingStaged() bool {
+	return len(ais.entriesToPut) > 0 || len(ais.chainBlocksToPut) > 0 || ais.pruneChainBlocksBelowBlueScore != 0 ||
+		ais.virtualSelectedParent != nil
+}
+
+func (ais *addressIndexStore) getVirtualSelectedParent() (*externalapi.DomainHash, error) {
+	if ais.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the virtual selected parent while staging isn't empty")
+	}
+
+	serializedHash, err := ais.database.Get(virtualSelectedParentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return externalapi.NewDomainHashFromByteSlice(serializedHash)
+}
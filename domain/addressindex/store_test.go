@@ -0,0 +1,174 @@
+package addressindex
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+)
+
+func newAddressIndexStoreForTest(t *testing.T) *addressIndexStore {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return newAddressIndexStore(db)
+}
+
+func TestGetTransactionEntries(t *testing.T) {
+	store := newAddressIndexStoreForTest(t)
+
+	scriptPublicKeyA := &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0}
+	scriptPublicKeyB := &externalapi.ScriptPublicKey{Script: []byte{2}, Version: 0}
+	scriptPublicKeyC := &externalapi.ScriptPublicKey{Script: []byte{3}, Version: 0}
+	transactionID := func(i byte) *externalapi.DomainTransactionID {
+		return externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{i})
+	}
+	blockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})
+
+	type testEntry struct {
+		scriptPublicKey *externalapi.ScriptPublicKey
+		blueScore       uint64
+		transactionID   byte
+	}
+	// The entries in the order they're expected to be returned in
+	allEntries := []testEntry{
+		{scriptPublicKeyA, 1, 1},
+		{scriptPublicKeyB, 1, 2},
+		{scriptPublicKeyA, 2, 1},
+		{scriptPublicKeyB, 2, 1},
+		{scriptPublicKeyA, 2, 3},
+		{scriptPublicKeyB, 3, 1},
+		{scriptPublicKeyA, 4, 1},
+	}
+	for _, entry := range allEntries {
+		store.putEntry(entry.scriptPublicKey, &TransactionEntry{
+			TransactionID:           transactionID(entry.transactionID),
+			IncludingBlockHash:      blockHash,
+			AcceptingBlockHash:      blockHash,
+			AcceptingBlockBlueScore: entry.blueScore,
+		})
+	}
+	// Entries of other scriptPublicKeys aren't returned
+	store.putEntry(scriptPublicKeyC, &TransactionEntry{
+		TransactionID:           transactionID(1),
+		IncludingBlockHash:      blockHash,
+		AcceptingBlockHash:      blockHash,
+		AcceptingBlockBlueScore: 2,
+	})
+	store.updateVirtualSelectedParent(blockHash)
+	err := store.commit()
+	if err != nil {
+		t.Fatalf("commit: %+v", err)
+	}
+
+	tests := []struct {
+		name       string
+		startAfter *EntryCursor
+		limit      uint64
+		expected   []testEntry
+	}{
+		{
+			name:     "all entries",
+			limit:    100,
+			expected: allEntries,
+		},
+		{
+			name:     "up to the limit",
+			limit:    2,
+			expected: allEntries[:2],
+		},
+		{
+			name:     "the entries of the last transaction aren't split",
+			limit:    3,
+			expected: allEntries[:4],
+		},
+		{
+			name:       "after an existing entry",
+			startAfter: &EntryCursor{AcceptingBlockBlueScore: 2, TransactionID: transactionID(1)},
+			limit:      100,
+			expected:   allEntries[4:],
+		},
+		{
+			name:       "after a missing entry",
+			startAfter: &EntryCursor{AcceptingBlockBlueScore: 2, TransactionID: transactionID(2)},
+			limit:      2,
+			expected:   allEntries[4:6],
+		},
+		{
+			name:       "after the last entry",
+			startAfter: &EntryCursor{AcceptingBlockBlueScore: 4, TransactionID: transactionID(1)},
+			limit:      100,
+			expected:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		entries, err := store.getTransactionEntries(
+			[]*externalapi.ScriptPublicKey{scriptPublicKeyA, scriptPublicKeyB}, test.startAfter, test.limit)
+		if err != nil {
+			t.Fatalf("%s: getTransactionEntries: %+v", test.name, err)
+		}
+		if len(entries) != len(test.expected) {
+			t.Fatalf("%s: expected %d entries, got %d", test.name, len(test.expected), len(entries))
+		}
+		for i, entry := range entries {
+			expected := test.expected[i]
+			if entry.ScriptPublicKey != expected.scriptPublicKey ||
+				entry.AcceptingBlockBlueScore != expected.blueScore ||
+				!entry.TransactionID.Equal(transactionID(expected.transactionID)) {
+
+				t.Fatalf("%s: unexpected entry %d: script %x, blue score %d, transaction %s", test.name, i,
+					entry.ScriptPublicKey.Script, entry.AcceptingBlockBlueScore, entry.TransactionID)
+			}
+		}
+	}
+}
+
+func TestPruneChainBlocks(t *testing.T) {
+	store := newAddressIndexStoreForTest(t)
+
+	chainBlockHash := func(i byte) *externalapi.DomainHash {
+		return externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{i})
+	}
+	const chainBlockCount = 5
+	for i := byte(1); i <= chainBlockCount; i++ {
+		store.putChainBlock(chainBlockHash(i), &indexedChainBlock{blueScore: uint64(i)})
+	}
+	store.updateVirtualSelectedParent(chainBlockHash(chainBlockCount))
+	err := store.commit()
+	if err != nil {
+		t.Fatalf("commit: %+v", err)
+	}
+
+	// A chain block that's added below the blue score that's pruned
+	// along with it is never written
+	store.putChainBlock(chainBlockHash(0), &indexedChainBlock{blueScore: 0})
+	store.pruneChainBlocks(3)
+	store.updateVirtualSelectedParent(chainBlockHash(chainBlockCount))
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %+v", err)
+	}
+
+	for i := byte(0); i <= chainBlockCount; i++ {
+		_, found, err := store.getChainBlock(uint64(i), chainBlockHash(i))
+		if err != nil {
+			t.Fatalf("getChainBlock: %+v", err)
+		}
+		expectedFound := i >= 3
+		if found != expectedFound {
+			t.Fatalf("Expected the chain block of blue score %d to be found: %t, but got: %t",
+				i, expectedFound, found)
+		}
+	}
+
+	remainingKeys, err := store.chainBlockKeysBelow(chainBlockCount + 1)
+	if err != nil {
+		t.Fatalf("chainBlockKeysBelow: %+v", err)
+	}
+	if len(remainingKeys) != chainBlockCount-2 {
+		t.Fatalf("Expected %d chain blocks to remain, but got %d", chainBlockCount-2, len(remainingKeys))
+	}
+}
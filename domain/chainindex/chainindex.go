@@ -0,0 +1,106 @@
+// Package chainindex implements the walk over the selected parent chain that's
+// shared by the indexes of the transactions accepted by it
+package chainindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+// syncBatchSize is the amount of selected parent chain blocks
+// that are committed together while an index is being synced
+const syncBatchSize = 100
+
+// Index is an index of the selected parent chain, given as the operations
+// with which SyncFrom and Update apply selected parent chain changes to it
+type Index struct {
+	// Name is the name of the index in log messages
+	Name string
+	Log  *logger.Logger
+
+	AddChainBlock    func(chainBlockHash *externalapi.DomainHash) error
+	RemoveChainBlock func(chainBlockHash *externalapi.DomainHash) error
+
+	// UpdateVirtualSelectedParent stages the virtual selected parent
+	// that the index is synced to
+	UpdateVirtualSelectedParent func(virtualSelectedParent *externalapi.DomainHash)
+
+	Commit  func() error
+	Discard func()
+}
+
+// SyncFrom brings the given index from the given selected parent chain block
+// to the current virtual selected parent, committing every syncBatchSize
+// chain blocks so that an interrupted sync can be resumed
+func SyncFrom(consensus externalapi.Consensus, index *Index, blockHash *externalapi.DomainHash) error {
+	chainChanges, err := consensus.GetVirtualSelectedParentChainFromBlock(blockHash)
+	if err != nil {
+		return err
+	}
+	index.Log.Infof("Syncing the %s from %s: removing %d and adding %d selected parent chain blocks",
+		index.Name, blockHash, len(chainChanges.Removed), len(chainChanges.Added))
+
+	for _, removedChainBlockHash := range chainChanges.Removed {
+		err := index.RemoveChainBlock(removedChainBlockHash)
+		if err != nil {
+			index.Discard()
+			return err
+		}
+	}
+
+	for i, addedChainBlockHash := range chainChanges.Added {
+		err := index.AddChainBlock(addedChainBlockHash)
+		if err != nil {
+			index.Discard()
+			return err
+		}
+
+		if (i+1)%syncBatchSize == 0 || i == len(chainChanges.Added)-1 {
+			index.UpdateVirtualSelectedParent(addedChainBlockHash)
+			err = index.Commit()
+			if err != nil {
+				return err
+			}
+			index.Log.Debugf("Synced the %s with %d out of %d selected parent chain blocks",
+				index.Name, i+1, len(chainChanges.Added))
+		}
+	}
+
+	if len(chainChanges.Added) == 0 {
+		// Nothing was added, so the given block is the virtual selected parent
+		// or an ancestor of it
+		virtualSelectedParent, err := consensus.GetVirtualSelectedParent()
+		if err != nil {
+			return err
+		}
+		index.UpdateVirtualSelectedParent(virtualSelectedParent)
+		return index.Commit()
+	}
+
+	return nil
+}
+
+// Update applies the given DAG selected parent chain changes to the given index
+func Update(index *Index, chainChanges *externalapi.SelectedChainPath) error {
+	if chainChanges == nil || len(chainChanges.Added) == 0 {
+		return nil
+	}
+
+	for _, removedChainBlockHash := range chainChanges.Removed {
+		err := index.RemoveChainBlock(removedChainBlockHash)
+		if err != nil {
+			index.Discard()
+			return err
+		}
+	}
+	for _, addedChainBlockHash := range chainChanges.Added {
+		err := index.AddChainBlock(addedChainBlockHash)
+		if err != nil {
+			index.Discard()
+			return err
+		}
+	}
+
+	index.UpdateVirtualSelectedParent(chainChanges.Added[len(chainChanges.Added)-1])
+	return index.Commit()
+}
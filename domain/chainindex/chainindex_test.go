@@ -0,0 +1,159 @@
+package chainindex
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// fakeConsensus implements the parts of externalapi.Consensus that SyncFrom uses
+type fakeConsensus struct {
+	externalapi.Consensus
+	chainChanges          *externalapi.SelectedChainPath
+	virtualSelectedParent *externalapi.DomainHash
+}
+
+func (fc *fakeConsensus) GetVirtualSelectedParentChainFromBlock(*externalapi.DomainHash) (
+	*externalapi.SelectedChainPath, error) {
+
+	return fc.chainChanges, nil
+}
+
+func (fc *fakeConsensus) GetVirtualSelectedParent() (*externalapi.DomainHash, error) {
+	return fc.virtualSelectedParent, nil
+}
+
+// fakeIndex records the chain blocks that were committed into it
+type fakeIndex struct {
+	staged                []*externalapi.DomainHash
+	committed             []*externalapi.DomainHash
+	virtualSelectedParent *externalapi.DomainHash
+	commitCount           int
+	failingBlock          *externalapi.DomainHash
+}
+
+func (fi *fakeIndex) index() *Index {
+	return &Index{
+		Name: "test index",
+		Log:  logger.RegisterSubSystem("CHIX"),
+		AddChainBlock: func(chainBlockHash *externalapi.DomainHash) error {
+			if chainBlockHash.Equal(fi.failingBlock) {
+				return errors.New("failing block")
+			}
+			fi.staged = append(fi.staged, chainBlockHash)
+			return nil
+		},
+		RemoveChainBlock: func(chainBlockHash *externalapi.DomainHash) error {
+			for i, committed := range fi.committed {
+				if committed.Equal(chainBlockHash) {
+					fi.committed = append(fi.committed[:i], fi.committed[i+1:]...)
+					break
+				}
+			}
+			return nil
+		},
+		UpdateVirtualSelectedParent: func(virtualSelectedParent *externalapi.DomainHash) {
+			fi.virtualSelectedParent = virtualSelectedParent
+		},
+		Commit: func() error {
+			fi.committed = append(fi.committed, fi.staged...)
+			fi.staged = nil
+			fi.commitCount++
+			return nil
+		},
+		Discard: func() {
+			fi.staged = nil
+		},
+	}
+}
+
+func chainBlockHashes(count int) []*externalapi.DomainHash {
+	hashes := make([]*externalapi.DomainHash, count)
+	for i := range hashes {
+		var hash [externalapi.DomainHashSize]byte
+		hash[0], hash[1] = byte(i>>8), byte(i)
+		hashes[i] = externalapi.NewDomainHashFromByteArray(&hash)
+	}
+	return hashes
+}
+
+func TestSyncFrom(t *testing.T) {
+	const chainLength = 2*syncBatchSize + 1
+	hashes := chainBlockHashes(chainLength + 1)
+	syncPoint, added := hashes[0], hashes[1:]
+
+	consensus := &fakeConsensus{chainChanges: &externalapi.SelectedChainPath{Added: added}}
+	index := &fakeIndex{}
+	err := SyncFrom(consensus, index.index(), syncPoint)
+	if err != nil {
+		t.Fatalf("SyncFrom: %s", err)
+	}
+	if len(index.committed) != chainLength {
+		t.Fatalf("Expected %d chain blocks to be committed, got %d", chainLength, len(index.committed))
+	}
+	if index.commitCount != 3 {
+		t.Fatalf("Expected the sync to be committed in 3 batches, got %d", index.commitCount)
+	}
+	if !index.virtualSelectedParent.Equal(added[len(added)-1]) {
+		t.Fatalf("Expected the index to be synced to %s, got %s", added[len(added)-1], index.virtualSelectedParent)
+	}
+
+	// A failure discards the current batch but keeps the committed ones
+	index = &fakeIndex{failingBlock: added[syncBatchSize+1]}
+	err = SyncFrom(consensus, index.index(), syncPoint)
+	if err == nil {
+		t.Fatalf("Expected SyncFrom to fail")
+	}
+	if len(index.committed) != syncBatchSize || len(index.staged) != 0 {
+		t.Fatalf("Expected %d chain blocks to be committed and none to be staged, got %d and %d",
+			syncBatchSize, len(index.committed), len(index.staged))
+	}
+	if !index.virtualSelectedParent.Equal(added[syncBatchSize-1]) {
+		t.Fatalf("Expected the index to be synced to %s, got %s", added[syncBatchSize-1], index.virtualSelectedParent)
+	}
+
+	// If nothing is added, the index is synced to the virtual selected parent
+	consensus = &fakeConsensus{
+		chainChanges:          &externalapi.SelectedChainPath{},
+		virtualSelectedParent: syncPoint,
+	}
+	index = &fakeIndex{}
+	err = SyncFrom(consensus, index.index(), syncPoint)
+	if err != nil {
+		t.Fatalf("SyncFrom: %s", err)
+	}
+	if index.commitCount != 1 || !index.virtualSelectedParent.Equal(syncPoint) {
+		t.Fatalf("Expected the index to be synced to %s in a single commit, got %s in %d commits",
+			syncPoint, index.virtualSelectedParent, index.commitCount)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	hashes := chainBlockHashes(3)
+	index := &fakeIndex{committed: []*externalapi.DomainHash{hashes[0]}}
+
+	err := Update(index.index(), nil)
+	if err != nil {
+		t.Fatalf("Update: %s", err)
+	}
+	if index.commitCount != 0 {
+		t.Fatalf("Expected Update without chain changes not to commit")
+	}
+
+	err = Update(index.index(), &externalapi.SelectedChainPath{
+		Removed: []*externalapi.DomainHash{hashes[0]},
+		Added:   []*externalapi.DomainHash{hashes[1], hashes[2]},
+	})
+	if err != nil {
+		t.Fatalf("Update: %s", err)
+	}
+	if len(index.committed) != 2 || !index.committed[0].Equal(hashes[1]) || !index.committed[1].Equal(hashes[2]) {
+		t.Fatalf("Unexpected committed chain blocks after Update: %v", index.committed)
+	}
+	if index.commitCount != 1 || !index.virtualSelectedParent.Equal(hashes[2]) {
+		t.Fatalf("Expected the index to be synced to %s in a single commit, got %s in %d commits",
+			hashes[2], index.virtualSelectedParent, index.commitCount)
+	}
+}
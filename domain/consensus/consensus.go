@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"sync"
+	"time"
 
 	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/model"
@@ -47,6 +48,9 @@ type consensus struct {
 	finalityStore             model.FinalityStore
 	headersSelectedChainStore model.HeadersSelectedChainStore
 	daaBlocksStore            model.DAABlocksStore
+
+	isTrackingVirtualUTXOSetSize bool
+	virtualUTXOSetSize           int64
 }
 
 // BuildBlock builds a block over the current state, with the transactions
@@ -66,7 +70,10 @@ func (s *consensus) ValidateAndInsertBlock(block *externalapi.DomainBlock) (*ext
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	return s.blockProcessor.ValidateAndInsertBlock(block)
+	start := time.Now()
+	blockInsertionResult, err := s.blockProcessor.ValidateAndInsertBlock(block)
+	s.updateBlockProcessingMetrics(time.Since(start), blockInsertionResult, err)
+	return blockInsertionResult, err
 }
 
 // ValidateTransactionAndPopulateWithConsensusData validates the given transaction
@@ -187,6 +194,12 @@ func (s *consensus) GetBlockInfo(blockHash *externalapi.DomainHash) (*externalap
 
 	blockInfo.BlueScore = ghostdagData.BlueScore()
 
+	daaScore, err := s.daaBlocksStore.DAAScore(s.databaseContext, stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	blockInfo.DAAScore = daaScore
+
 	return blockInfo, nil
 }
 
@@ -339,7 +352,16 @@ func (s *consensus) ValidateAndInsertImportedPruningPoint(newPruningPoint *exter
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	return s.blockProcessor.ValidateAndInsertImportedPruningPoint(newPruningPoint)
+	err := s.blockProcessor.ValidateAndInsertImportedPruningPoint(newPruningPoint)
+	if err != nil {
+		return err
+	}
+
+	// The virtual UTXO set was replaced by the pruning point UTXO set
+	if s.isTrackingVirtualUTXOSetSize {
+		return s.countVirtualUTXOSet()
+	}
+	return nil
 }
 
 func (s *consensus) GetVirtualSelectedParent() (*externalapi.DomainHash, error) {
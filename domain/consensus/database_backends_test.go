@@ -0,0 +1,92 @@
+package consensus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/logdb"
+)
+
+// BenchmarkDatabaseBackends compares the throughput of inserting a chain of
+// blocks into a fresh consensus, as happens during IBD, between the database
+// backends that kaspad supports
+func BenchmarkDatabaseBackends(b *testing.B) {
+	const blockCount = 500
+
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	factory := consensus.NewFactory()
+	factory.SetTestPreAllocateCache(false)
+	tc, teardown, err := factory.NewTestConsensus(consensusConfig, "BenchmarkDatabaseBackends")
+	if err != nil {
+		b.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardown(false)
+
+	blocks := make([]*externalapi.DomainBlock, 0, blockCount)
+	tipHash := consensusConfig.GenesisHash
+	for i := 0; i < blockCount; i++ {
+		tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+		if err != nil {
+			b.Fatalf("AddBlock: %+v", err)
+		}
+		block, err := tc.GetBlock(tipHash)
+		if err != nil {
+			b.Fatalf("GetBlock: %+v", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	backends := []struct {
+		name string
+		open func(path string) (database.Database, error)
+	}{
+		{
+			name: "leveldb",
+			open: func(path string) (database.Database, error) { return ldb.NewLevelDB(path, 8) },
+		},
+		{
+			name: "logdb",
+			open: func(path string) (database.Database, error) { return logdb.NewLogDB(path) },
+		},
+	}
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			insertDuration := time.Duration(0)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				db, err := backend.open(b.TempDir())
+				if err != nil {
+					b.Fatalf("Error opening the database: %+v", err)
+				}
+				syncee, err := factory.NewConsensus(consensusConfig, db)
+				if err != nil {
+					b.Fatalf("Error setting up consensus: %+v", err)
+				}
+				b.StartTimer()
+
+				start := time.Now()
+				for _, block := range blocks {
+					_, err := syncee.ValidateAndInsertBlock(block)
+					if err != nil {
+						b.Fatalf("ValidateAndInsertBlock: %+v", err)
+					}
+				}
+				insertDuration += time.Since(start)
+
+				b.StopTimer()
+				err = db.Close()
+				if err != nil {
+					b.Fatalf("Close: %+v", err)
+				}
+			}
+			b.ReportMetric(float64(b.N*blockCount)/insertDuration.Seconds(), "blocks/s")
+		})
+	}
+}
@@ -58,6 +58,9 @@ type Config struct {
 	IsArchival bool
 	// EnableSanityCheckPruningUTXOSet checks the full pruning point utxo set against the commitment at every pruning movement
 	EnableSanityCheckPruningUTXOSet bool
+	// TrackVirtualUTXOSetSize counts the virtual UTXO set on startup and keeps
+	// its size up to date for the kaspad_utxo_set_size metric
+	TrackVirtualUTXOSetSize bool
 }
 
 // Factory instantiates new Consensuses
@@ -437,6 +440,13 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		return nil, err
 	}
 
+	if config.TrackVirtualUTXOSetSize {
+		err = c.startTrackingVirtualUTXOSetSize()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
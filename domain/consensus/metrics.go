@@ -0,0 +1,80 @@
+package consensus
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/pkg/errors"
+)
+
+var (
+	blocksProcessedMetric = metrics.NewCounter("kaspad_blocks_processed_total",
+		"Number of blocks and block headers that were validated and inserted into the DAG")
+	blocksRejectedMetric = metrics.NewCounterVec("kaspad_blocks_rejected_total",
+		"Number of blocks and block headers that were rejected, by the violated consensus rule", "reason")
+	validateAndInsertBlockDurationMetric = metrics.NewHistogram("kaspad_validate_and_insert_block_duration_seconds",
+		"Time it took to validate and insert a block or a block header", metrics.DurationBuckets)
+	virtualUTXOSetSizeMetric = metrics.NewGaugeFunc("kaspad_utxo_set_size",
+		"Number of UTXOs in the virtual UTXO set")
+)
+
+func (s *consensus) updateBlockProcessingMetrics(duration time.Duration,
+	blockInsertionResult *externalapi.BlockInsertionResult, err error) {
+
+	validateAndInsertBlockDurationMetric.ObserveDuration(duration)
+
+	if err != nil {
+		ruleError := ruleerrors.RuleError{}
+		if errors.As(err, &ruleError) {
+			blocksRejectedMetric.WithLabelValues(ruleError.Reason()).Inc()
+		}
+		return
+	}
+	blocksProcessedMetric.Inc()
+
+	if s.isTrackingVirtualUTXOSetSize && blockInsertionResult.VirtualUTXODiff != nil {
+		virtualUTXODiff := blockInsertionResult.VirtualUTXODiff
+		atomic.AddInt64(&s.virtualUTXOSetSize, int64(virtualUTXODiff.ToAdd().Len()-virtualUTXODiff.ToRemove().Len()))
+	}
+}
+
+// startTrackingVirtualUTXOSetSize counts the virtual UTXO set and reports its
+// size through virtualUTXOSetSizeMetric from now on
+func (s *consensus) startTrackingVirtualUTXOSetSize() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.countVirtualUTXOSet()
+	if err != nil {
+		return err
+	}
+	s.isTrackingVirtualUTXOSetSize = true
+
+	virtualUTXOSetSizeMetric.SetFunction(func() float64 {
+		return float64(atomic.LoadInt64(&s.virtualUTXOSetSize))
+	})
+	return nil
+}
+
+func (s *consensus) countVirtualUTXOSet() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "countVirtualUTXOSet")
+	defer onEnd()
+
+	virtualUTXOSetIterator, err := s.consensusStateStore.VirtualUTXOSetIterator(s.databaseContext, model.NewStagingArea())
+	if err != nil {
+		return err
+	}
+	defer virtualUTXOSetIterator.Close()
+
+	virtualUTXOSetSize := int64(0)
+	for ok := virtualUTXOSetIterator.First(); ok; ok = virtualUTXOSetIterator.Next() {
+		virtualUTXOSetSize++
+	}
+	atomic.StoreInt64(&s.virtualUTXOSetSize, virtualUTXOSetSize)
+	return nil
+}
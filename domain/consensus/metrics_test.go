@@ -0,0 +1,76 @@
+package consensus
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+func TestBlockProcessingMetrics(t *testing.T) {
+	consensusConfig := &Config{Params: dagconfig.SimnetParams, TrackVirtualUTXOSetSize: true}
+	consensusConfig.SkipProofOfWork = true
+
+	factory := NewFactory()
+	tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestBlockProcessingMetrics")
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardown(false)
+	consensus := tc.(*testConsensus).consensus
+
+	blocksProcessedBefore := blocksProcessedMetric.Value()
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0},
+	}
+	const blockCount = 5
+	for i := 0; i < blockCount; i++ {
+		block, err := consensus.BuildBlock(coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+		_, err = consensus.ValidateAndInsertBlock(block)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+	if blocksProcessedMetric.Value()-blocksProcessedBefore != blockCount {
+		t.Fatalf("Expected kaspad_blocks_processed_total to grow by %d, but it grew by %d",
+			blockCount, blocksProcessedMetric.Value()-blocksProcessedBefore)
+	}
+
+	trackedVirtualUTXOSetSize := atomic.LoadInt64(&consensus.virtualUTXOSetSize)
+	err = consensus.countVirtualUTXOSet()
+	if err != nil {
+		t.Fatalf("countVirtualUTXOSet: %+v", err)
+	}
+	countedVirtualUTXOSetSize := atomic.LoadInt64(&consensus.virtualUTXOSetSize)
+	if countedVirtualUTXOSetSize == 0 {
+		t.Fatalf("Expected the virtual UTXO set to contain the coinbase outputs")
+	}
+	if trackedVirtualUTXOSetSize != countedVirtualUTXOSetSize {
+		t.Fatalf("Expected the tracked virtual UTXO set size to be %d, but got %d",
+			countedVirtualUTXOSetSize, trackedVirtualUTXOSetSize)
+	}
+
+	invalidBlock, err := consensus.BuildBlock(coinbaseData, nil)
+	if err != nil {
+		t.Fatalf("BuildBlock: %+v", err)
+	}
+	invalidHeader := invalidBlock.Header.ToMutable()
+	invalidHeader.SetTimeInMilliseconds(0)
+	invalidBlock.Header = invalidHeader.ToImmutable()
+
+	blocksRejectedCounter := blocksRejectedMetric.WithLabelValues(ruleerrors.ErrTimeTooOld.Reason())
+	blocksRejectedBefore := blocksRejectedCounter.Value()
+	_, err = consensus.ValidateAndInsertBlock(invalidBlock)
+	if !errors.Is(err, ruleerrors.ErrTimeTooOld) {
+		t.Fatalf("Expected ErrTimeTooOld, but got %+v", err)
+	}
+	if blocksRejectedCounter.Value() == blocksRejectedBefore {
+		t.Fatalf("Expected the rejection to be counted under the ErrTimeTooOld reason")
+	}
+}
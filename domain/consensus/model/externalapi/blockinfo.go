@@ -5,6 +5,7 @@ type BlockInfo struct {
 	Exists      bool
 	BlockStatus BlockStatus
 	BlueScore   uint64
+	DAAScore    uint64
 }
 
 // Clone returns a clone of BlockInfo
@@ -13,5 +14,6 @@ func (bi *BlockInfo) Clone() *BlockInfo {
 		Exists:      bi.Exists,
 		BlockStatus: bi.BlockStatus.Clone(),
 		BlueScore:   bi.BlueScore,
+		DAAScore:    bi.DAAScore,
 	}
 }
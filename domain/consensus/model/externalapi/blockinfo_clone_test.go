@@ -12,22 +12,27 @@ func initTestBlockInfoStructsForClone() []*BlockInfo {
 			true,
 			BlockStatus(0x01),
 			0,
+			0,
 		}, {
 			true,
 			BlockStatus(0x02),
 			0,
+			1,
 		}, {
 			true,
 			1,
 			1,
+			2,
 		}, {
 			true,
 			255,
 			2,
+			3,
 		}, {
 			true,
 			0,
 			3,
+			4,
 		},
 	}
 	return tests
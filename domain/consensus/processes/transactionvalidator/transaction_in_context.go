@@ -45,7 +45,7 @@ func (v *transactionValidator) ValidateTransactionInContextAndPopulateMassAndFee
 		return err
 	}
 
-	tx.Mass, err = v.transactionMass(tx)
+	tx.Mass, err = v.txMassCalculator.CalculateTransactionMass(tx)
 	if err != nil {
 		return err
 	}
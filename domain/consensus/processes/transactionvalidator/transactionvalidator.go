@@ -2,6 +2,7 @@ package transactionvalidator
 
 import (
 	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txmass"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 )
 
@@ -16,9 +17,7 @@ type transactionValidator struct {
 	ghostdagDataStore          model.GHOSTDAGDataStore
 	daaBlocksStore             model.DAABlocksStore
 	enableNonNativeSubnetworks bool
-	massPerTxByte              uint64
-	massPerScriptPubKeyByte    uint64
-	massPerSigOp               uint64
+	txMassCalculator           *txmass.Calculator
 	maxCoinbasePayloadLength   uint64
 	sigCache                   *txscript.SigCache
 	sigCacheECDSA              *txscript.SigCacheECDSA
@@ -39,9 +38,7 @@ func New(blockCoinbaseMaturity uint64,
 	return &transactionValidator{
 		blockCoinbaseMaturity:      blockCoinbaseMaturity,
 		enableNonNativeSubnetworks: enableNonNativeSubnetworks,
-		massPerTxByte:              massPerTxByte,
-		massPerScriptPubKeyByte:    massPerScriptPubKeyByte,
-		massPerSigOp:               massPerSigOp,
+		txMassCalculator:           txmass.NewCalculator(massPerTxByte, massPerScriptPubKeyByte, massPerSigOp),
 		maxCoinbasePayloadLength:   maxCoinbasePayloadLength,
 		databaseContext:            databaseContext,
 		pastMedianTimeManager:      pastMedianTimeManager,
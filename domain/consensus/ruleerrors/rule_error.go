@@ -210,6 +210,11 @@ func (e RuleError) Error() string {
 	return e.message
 }
 
+// Reason returns the identifier of the violated rule, e.g. "ErrInvalidPoW"
+func (e RuleError) Reason() string {
+	return e.message
+}
+
 // Unwrap satisfies the errors.Unwrap interface
 func (e RuleError) Unwrap() error {
 	return e.inner
@@ -1,4 +1,4 @@
-package transactionvalidator
+package txmass
 
 import (
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
@@ -8,7 +8,25 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 )
 
-func (v *transactionValidator) transactionMassStandalonePart(tx *externalapi.DomainTransaction) uint64 {
+// Calculator calculates the mass of transactions the way consensus does
+type Calculator struct {
+	massPerTxByte           uint64
+	massPerScriptPubKeyByte uint64
+	massPerSigOp            uint64
+}
+
+// NewCalculator creates a new Calculator with the given mass parameters
+func NewCalculator(massPerTxByte, massPerScriptPubKeyByte, massPerSigOp uint64) *Calculator {
+	return &Calculator{
+		massPerTxByte:           massPerTxByte,
+		massPerScriptPubKeyByte: massPerScriptPubKeyByte,
+		massPerSigOp:            massPerSigOp,
+	}
+}
+
+// CalculateTransactionMassStandalonePart returns the part of the mass of the given
+// transaction that doesn't depend on the UTXO entries it spends
+func (c *Calculator) CalculateTransactionMassStandalonePart(tx *externalapi.DomainTransaction) uint64 {
 	size := estimatedsize.TransactionEstimatedSerializedSize(tx)
 
 	totalScriptPubKeySize := uint64(0)
@@ -17,15 +35,18 @@ func (v *transactionValidator) transactionMassStandalonePart(tx *externalapi.Dom
 		totalScriptPubKeySize += uint64(len(output.ScriptPublicKey.Script))
 	}
 
-	return size*v.massPerTxByte + totalScriptPubKeySize*v.massPerScriptPubKeyByte
+	return size*c.massPerTxByte + totalScriptPubKeySize*c.massPerScriptPubKeyByte
 }
 
-func (v *transactionValidator) transactionMass(tx *externalapi.DomainTransaction) (uint64, error) {
+// CalculateTransactionMass returns the mass of the given transaction. The UTXO entries
+// of all of its inputs must be populated, otherwise an ErrMissingTxOut is returned.
+// The mass of coinbase transactions is 0.
+func (c *Calculator) CalculateTransactionMass(tx *externalapi.DomainTransaction) (uint64, error) {
 	if transactionhelper.IsCoinBase(tx) {
 		return 0, nil
 	}
 
-	standaloneMass := v.transactionMassStandalonePart(tx)
+	standaloneMass := c.CalculateTransactionMassStandalonePart(tx)
 	sigOpsCount := uint64(0)
 	var missingOutpoints []*externalapi.DomainOutpoint
 	for _, input := range tx.Inputs {
@@ -44,5 +65,5 @@ func (v *transactionValidator) transactionMass(tx *externalapi.DomainTransaction
 		return 0, ruleerrors.NewErrMissingTxOut(missingOutpoints)
 	}
 
-	return standaloneMass + sigOpsCount*v.massPerSigOp, nil
+	return standaloneMass + sigOpsCount*c.massPerSigOp, nil
 }
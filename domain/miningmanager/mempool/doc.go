@@ -36,6 +36,8 @@ be an exhaustive list.
    - Reject non-fully-spent duplicate transactions
    - Reject coinbase transactions
    - Reject double spends (both from the DAG and other transactions in pool)
+   - Replace transactions that opted in with conflicting transactions that pay
+     a higher fee (replace-by-fee)
    - Reject invalid transactions according to the network consensus rules
    - Full script execution and validation with signature cache support
    - Individual transaction query support
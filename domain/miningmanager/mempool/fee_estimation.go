@@ -0,0 +1,138 @@
+package mempool
+
+import (
+	"math"
+	"time"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionhelper"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+const (
+	// priorityTargetBlocks, normalTargetBlocks and lowTargetBlocks are the
+	// amounts of blocks within which a transaction paying the fee rate of
+	// the respective bucket is expected to be included
+	priorityTargetBlocks = 1
+	normalTargetBlocks   = 10
+	lowTargetBlocks      = 60
+
+	// recentBlocksToTrack is the amount of recently added blocks whose
+	// clearing fee rates are taken into account when estimating fees
+	recentBlocksToTrack = lowTargetBlocks
+
+	// fullBlockMassRatio is the part of the maximum block mass that the
+	// known transactions of a block have to take for it to be considered full
+	fullBlockMassRatio = 0.9
+)
+
+// FeeEstimate returns the fee rates suggested for each priority bucket
+// according to the current mempool contents and recently added blocks
+//
+// This function is safe for concurrent access.
+func (mp *mempool) FeeEstimate() *miningmanagermodel.FeeEstimate {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return estimateFees(mp.orderedTransactionsByFeeRate, mp.recentClearingFeeRates,
+		mp.dagParams.MaxMassAcceptedByBlock, mp.minimumFeeRate(), mp.dagParams.TargetTimePerBlock)
+}
+
+// minimumFeeRate returns the lowest fee rate, in sompi/gram, that the mempool accepts.
+// MinRelayTxFee is set per kB, and the mass of a transaction is never lower than its size.
+func (mp *mempool) minimumFeeRate() float64 {
+	return float64(mp.policy.MinRelayTxFee) / 1000
+}
+
+// recordBlockClearingFeeRate records the clearing fee rate of a block whose
+// transactions are still in the mempool. If the block is full, that is the lowest
+// fee rate of the transactions it included, because anything paying less had
+// to wait for a later block. Otherwise, it's the minimum fee rate.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *mempool) recordBlockClearingFeeRate(txs []*consensusexternalapi.DomainTransaction) {
+	includedMass := uint64(0)
+	lowestIncludedFeeRate := math.Inf(1)
+	for _, tx := range txs[transactionhelper.CoinbaseTransactionIndex+1:] {
+		// We use the mempool transaction, because it has populated fee and mass
+		mempoolTx, exists := mp.fetchTxDesc(consensushashing.TransactionID(tx))
+		if !exists {
+			continue
+		}
+		includedMass += mempoolTx.Mass
+		feeRate := float64(mempoolTx.Fee) / float64(mempoolTx.Mass)
+		if feeRate < lowestIncludedFeeRate {
+			lowestIncludedFeeRate = feeRate
+		}
+	}
+
+	clearingFeeRate := mp.minimumFeeRate()
+	if float64(includedMass) >= fullBlockMassRatio*float64(mp.dagParams.MaxMassAcceptedByBlock) {
+		clearingFeeRate = lowestIncludedFeeRate
+	}
+
+	mp.recentClearingFeeRates = append(mp.recentClearingFeeRates, clearingFeeRate)
+	if len(mp.recentClearingFeeRates) > recentBlocksToTrack {
+		mp.recentClearingFeeRates = mp.recentClearingFeeRates[1:]
+	}
+}
+
+func estimateFees(orderedTransactionsByFeeRate []*consensusexternalapi.DomainTransaction,
+	recentClearingFeeRates []float64, blockMaxMass uint64, minimumFeeRate float64,
+	targetTimePerBlock time.Duration) *miningmanagermodel.FeeEstimate {
+
+	bucket := func(targetBlocks int) *miningmanagermodel.FeeRateBucket {
+		return &miningmanagermodel.FeeRateBucket{
+			FeeRate: estimateFeeRate(orderedTransactionsByFeeRate, recentClearingFeeRates,
+				blockMaxMass, minimumFeeRate, targetBlocks),
+			EstimatedSeconds: float64(targetBlocks) * targetTimePerBlock.Seconds(),
+		}
+	}
+
+	return &miningmanagermodel.FeeEstimate{
+		PriorityBucket: bucket(priorityTargetBlocks),
+		NormalBucket:   bucket(normalTargetBlocks),
+		LowBucket:      bucket(lowTargetBlocks),
+	}
+}
+
+// estimateFeeRate returns the fee rate a transaction has to pay in order to be
+// included within targetBlocks blocks. That is the highest out of the minimum
+// fee rate, the fee rate of the best paying mempool transaction that wouldn't fit
+// in targetBlocks blocks if they were filled in order of fee rate, and the lowest
+// clearing fee rate of the last targetBlocks blocks.
+func estimateFeeRate(orderedTransactionsByFeeRate []*consensusexternalapi.DomainTransaction,
+	recentClearingFeeRates []float64, blockMaxMass uint64, minimumFeeRate float64, targetBlocks int) float64 {
+
+	feeRate := minimumFeeRate
+
+	capacity := blockMaxMass * uint64(targetBlocks)
+	accumulatedMass := uint64(0)
+	for i := len(orderedTransactionsByFeeRate) - 1; i >= 0; i-- {
+		tx := orderedTransactionsByFeeRate[i]
+		accumulatedMass += tx.Mass
+		if accumulatedMass > capacity {
+			mempoolFeeRate := float64(tx.Fee) / float64(tx.Mass)
+			if mempoolFeeRate > feeRate {
+				feeRate = mempoolFeeRate
+			}
+			break
+		}
+	}
+
+	// Don't draw conclusions from fewer blocks than the target
+	if len(recentClearingFeeRates) >= targetBlocks {
+		lowestClearingFeeRate := math.Inf(1)
+		for _, clearingFeeRate := range recentClearingFeeRates[len(recentClearingFeeRates)-targetBlocks:] {
+			if clearingFeeRate < lowestClearingFeeRate {
+				lowestClearingFeeRate = clearingFeeRate
+			}
+		}
+		if lowestClearingFeeRate > feeRate {
+			feeRate = lowestClearingFeeRate
+		}
+	}
+
+	return feeRate
+}
@@ -0,0 +1,167 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+func syntheticTransaction(lockTime uint64, fee uint64, mass uint64) *consensusexternalapi.DomainTransaction {
+	return &consensusexternalapi.DomainTransaction{
+		Inputs:   []*consensusexternalapi.DomainTransactionInput{},
+		Outputs:  []*consensusexternalapi.DomainTransactionOutput{},
+		LockTime: lockTime,
+		Fee:      fee,
+		Mass:     mass,
+	}
+}
+
+// syntheticOrderedTransactions builds a list of transactions ordered by fee rate
+// (from lowest to highest), the same way orderedTransactionsByFeeRate is
+func syntheticOrderedTransactions(feeRates []uint64, mass uint64) []*consensusexternalapi.DomainTransaction {
+	transactions := make([]*consensusexternalapi.DomainTransaction, len(feeRates))
+	for i, feeRate := range feeRates {
+		transactions[i] = syntheticTransaction(uint64(i), feeRate*mass, mass)
+	}
+	return transactions
+}
+
+func TestEstimateFeeRate(t *testing.T) {
+	const blockMaxMass = 10_000
+	const minimumFeeRate = 1.0
+
+	tests := []struct {
+		name                   string
+		transactionFeeRates    []uint64
+		recentClearingFeeRates []float64
+		targetBlocks           int
+		expectedFeeRate        float64
+	}{
+		{
+			name:            "empty mempool",
+			targetBlocks:    1,
+			expectedFeeRate: minimumFeeRate,
+		},
+		{
+			name:                "mempool fits in a single block",
+			transactionFeeRates: []uint64{5, 6, 7, 8},
+			targetBlocks:        1,
+			expectedFeeRate:     minimumFeeRate,
+		},
+		{
+			// 10 transactions of mass 2,000 fill two blocks. The best paying
+			// transaction that doesn't fit in the next block is the sixth from the top
+			name:                "mempool overflows a single block",
+			transactionFeeRates: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			targetBlocks:        1,
+			expectedFeeRate:     5,
+		},
+		{
+			name:                "mempool fits in the target blocks",
+			transactionFeeRates: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			targetBlocks:        2,
+			expectedFeeRate:     minimumFeeRate,
+		},
+		{
+			name:                   "recent blocks cleared above the mempool estimation",
+			transactionFeeRates:    []uint64{5, 6},
+			recentClearingFeeRates: []float64{3, 4, 7},
+			targetBlocks:           2,
+			expectedFeeRate:        4,
+		},
+		{
+			name:                   "fewer recent blocks than the target are ignored",
+			recentClearingFeeRates: []float64{3, 4, 7},
+			targetBlocks:           10,
+			expectedFeeRate:        minimumFeeRate,
+		},
+	}
+
+	for _, test := range tests {
+		transactions := syntheticOrderedTransactions(test.transactionFeeRates, 2_000)
+		feeRate := estimateFeeRate(transactions, test.recentClearingFeeRates, blockMaxMass,
+			minimumFeeRate, test.targetBlocks)
+		if feeRate != test.expectedFeeRate {
+			t.Errorf("%s: expected fee rate %f but got %f", test.name, test.expectedFeeRate, feeRate)
+		}
+	}
+}
+
+func TestEstimateFees(t *testing.T) {
+	transactionFeeRates := make([]uint64, 100)
+	for i := range transactionFeeRates {
+		transactionFeeRates[i] = uint64(i + 1)
+	}
+	transactions := syntheticOrderedTransactions(transactionFeeRates, 1_000)
+
+	estimate := estimateFees(transactions, nil, 10_000, 1, time.Second)
+
+	if estimate.PriorityBucket.FeeRate != 90 {
+		t.Errorf("unexpected priority fee rate %f", estimate.PriorityBucket.FeeRate)
+	}
+	if estimate.NormalBucket.FeeRate != 1 {
+		t.Errorf("unexpected normal fee rate %f", estimate.NormalBucket.FeeRate)
+	}
+	if estimate.LowBucket.FeeRate != 1 {
+		t.Errorf("unexpected low fee rate %f", estimate.LowBucket.FeeRate)
+	}
+	if estimate.PriorityBucket.EstimatedSeconds != priorityTargetBlocks ||
+		estimate.NormalBucket.EstimatedSeconds != normalTargetBlocks ||
+		estimate.LowBucket.EstimatedSeconds != lowTargetBlocks {
+		t.Errorf("unexpected estimated seconds %f, %f, %f", estimate.PriorityBucket.EstimatedSeconds,
+			estimate.NormalBucket.EstimatedSeconds, estimate.LowBucket.EstimatedSeconds)
+	}
+	if estimate.PriorityBucket.FeeRate < estimate.NormalBucket.FeeRate ||
+		estimate.NormalBucket.FeeRate < estimate.LowBucket.FeeRate {
+		t.Errorf("fee rates are expected to be non-increasing with the bucket's target time")
+	}
+}
+
+func TestRecordBlockClearingFeeRate(t *testing.T) {
+	mp := &mempool{
+		pool:                make(map[consensusexternalapi.DomainTransactionID]*txDescriptor),
+		chainedTransactions: make(map[consensusexternalapi.DomainTransactionID]*txDescriptor),
+		dagParams:           &dagconfig.Params{MaxMassAcceptedByBlock: 10_000},
+		policy:              policy{MinRelayTxFee: 1000},
+	}
+
+	addToPool := func(transactions ...*consensusexternalapi.DomainTransaction) {
+		for _, transaction := range transactions {
+			mp.pool[*consensushashing.TransactionID(transaction)] = &txDescriptor{DomainTransaction: transaction}
+		}
+	}
+	coinbase := syntheticTransaction(1000, 0, 0)
+
+	// A block that isn't full clears at the minimum fee rate
+	lowFee := syntheticTransaction(0, 2_000, 1_000)
+	addToPool(lowFee)
+	mp.recordBlockClearingFeeRate([]*consensusexternalapi.DomainTransaction{coinbase, lowFee})
+
+	// A full block clears at the lowest fee rate it included
+	highFee := syntheticTransaction(1, 40_000, 5_000)
+	mediumFee := syntheticTransaction(2, 12_000, 4_000)
+	addToPool(highFee, mediumFee)
+	mp.recordBlockClearingFeeRate([]*consensusexternalapi.DomainTransaction{coinbase, highFee, mediumFee})
+
+	expected := []float64{1, 3}
+	if len(mp.recentClearingFeeRates) != len(expected) {
+		t.Fatalf("expected %d recorded clearing fee rates but got %d",
+			len(expected), len(mp.recentClearingFeeRates))
+	}
+	for i, clearingFeeRate := range mp.recentClearingFeeRates {
+		if clearingFeeRate != expected[i] {
+			t.Errorf("expected clearing fee rate %f at index %d but got %f", expected[i], i, clearingFeeRate)
+		}
+	}
+
+	for i := 0; i < recentBlocksToTrack*2; i++ {
+		mp.recordBlockClearingFeeRate([]*consensusexternalapi.DomainTransaction{coinbase})
+	}
+	if len(mp.recentClearingFeeRates) != recentBlocksToTrack {
+		t.Errorf("expected %d recorded clearing fee rates but got %d",
+			recentBlocksToTrack, len(mp.recentClearingFeeRates))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
 	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/kaspanet/kaspad/util/mstime"
 	"github.com/pkg/errors"
@@ -83,6 +84,11 @@ type mempool struct {
 
 	orderedTransactionsByFeeRate []*consensusexternalapi.DomainTransaction
 
+	// recentClearingFeeRates holds the clearing fee rates of the
+	// recently added blocks, ordered from oldest to newest.
+	// See recordBlockClearingFeeRate for further details.
+	recentClearingFeeRates []float64
+
 	// nextExpireScan is the time after which the orphan pool will be
 	// scanned in order to evict orphans. This is NOT a hard deadline as
 	// the scan will only run when an orphan is added to the pool as opposed
@@ -155,6 +161,13 @@ func (mp *mempool) TransactionCount() int {
 	return len(mp.pool) + len(mp.chainedTransactions)
 }
 
+func (mp *mempool) OrphanCount() int {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return len(mp.orphans)
+}
+
 // txDescriptor is a descriptor containing a transaction in the mempool along with
 // additional metadata.
 type txDescriptor struct {
@@ -614,21 +627,29 @@ func (mp *mempool) enforceTransactionLimit() error {
 
 // checkPoolDoubleSpend checks whether or not the passed transaction is
 // attempting to spend coins already spent by other transactions in the pool.
+// Double spending is allowed only if all the transactions that spent these
+// coins are replaceable, in which case they are returned so that the caller
+// can check whether the passed transaction may replace them.
 // Note it does not check for double spends against transactions already in the
 // DAG.
 //
 // This function MUST be called with the mempool lock held (for reads).
-func (mp *mempool) checkPoolDoubleSpend(tx *consensusexternalapi.DomainTransaction) error {
+func (mp *mempool) checkPoolDoubleSpend(tx *consensusexternalapi.DomainTransaction) (
+	conflictingTxs []*consensusexternalapi.DomainTransaction, err error) {
+
 	for _, txIn := range tx.Inputs {
 		if txR, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(txIn.PreviousOutpoint); exists {
+			if relaypolicy.IsReplaceable(txR) {
+				continue
+			}
 			str := fmt.Sprintf("output %s already spent by "+
 				"transaction %s in the memory pool",
 				txIn.PreviousOutpoint, consensushashing.TransactionID(txR))
-			return txRuleError(RejectDuplicate, str)
+			return nil, txRuleError(RejectDuplicate, str)
 		}
 	}
 
-	return nil
+	return mp.conflictingTransactions(tx), nil
 }
 
 // This function MUST be called with the mempool lock held (for reads).
@@ -696,7 +717,10 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 	// at this point. There is a more in-depth check that happens later
 	// after fetching the referenced transaction inputs from the DAG
 	// which examines the actual spend data and prevents double spends.
-	err := mp.checkPoolDoubleSpend(tx)
+	//
+	// The only exception are replacements of transactions that opted in to
+	// be replaced. See checkReplacement for further details.
+	conflictingTxs, err := mp.checkPoolDoubleSpend(tx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -754,7 +778,7 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 
 	// Don't allow transactions with fees too low to get into a mined block
 	serializedSize := int64(estimatedsize.TransactionEstimatedSerializedSize(tx))
-	minFee := uint64(calcMinRequiredTxRelayFee(serializedSize,
+	minFee := uint64(relaypolicy.CalcMinRequiredTxRelayFee(serializedSize,
 		mp.policy.MinRelayTxFee))
 	if tx.Fee < minFee {
 		str := fmt.Sprintf("transaction %s has %d fees which is under "+
@@ -762,6 +786,20 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 			minFee)
 		return nil, nil, txRuleError(RejectInsufficientFee, str)
 	}
+
+	// Evict the transactions that this one replaces, if any.
+	if len(conflictingTxs) > 0 {
+		err := mp.checkReplacement(tx, conflictingTxs, parentsInPool)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = mp.evictReplacedTransactions(conflictingTxs)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Debugf("Transaction %s replaced %d conflicting transactions", txID, len(conflictingTxs))
+	}
+
 	// Add to transaction pool.
 	txDesc, err := mp.addTransaction(tx, parentsInPool)
 	if err != nil {
@@ -996,6 +1034,7 @@ func (mp *mempool) HandleNewBlockTransactions(txs []*consensusexternalapi.Domain
 	// no longer an orphan. Transactions which depend on a confirmed
 	// transaction are NOT removed recursively because they are still
 	// valid.
+	mp.recordBlockClearingFeeRate(txs)
 	err := mp.removeTransactionsFromPool(txs)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed removing txs from pool")
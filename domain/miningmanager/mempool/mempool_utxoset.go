@@ -35,15 +35,10 @@ func (mpus *mempoolUTXOSet) populateUTXOEntries(tx *consensusexternalapi.DomainT
 	return parentsInPool
 }
 
+// checkExists checks whether the given transaction creates an already existing UTXO.
+// Transactions that spend outpoints already spent in the mempool are checked by
+// checkPoolDoubleSpend, since they might replace the transactions that spent them.
 func (mpus *mempoolUTXOSet) checkExists(tx *consensusexternalapi.DomainTransaction) bool {
-	// Check if it was already spent.
-	for _, txIn := range tx.Inputs {
-		if _, exists := mpus.transactionByPreviousOutpoint[txIn.PreviousOutpoint]; exists {
-			return true
-		}
-	}
-
-	// Check if it creates an already existing UTXO
 	outpoint := consensusexternalapi.DomainOutpoint{TransactionID: *consensushashing.TransactionID(tx)}
 	for i := range tx.Outputs {
 		outpoint.Index = uint32(i)
@@ -11,7 +11,7 @@ import (
 	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
-	"github.com/kaspanet/kaspad/util"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
 )
 
 const (
@@ -37,43 +37,8 @@ const (
 	// adds a few extra bytes to provide a little buffer.
 	// (1 + 15*74 + 3) + (15*34 + 3) + 23 = 1650
 	maxStandardSigScriptSize = 1650
-
-	// MaxStandardTxSize is the maximum size allowed for transactions that
-	// are considered standard and will therefore be relayed and considered
-	// for mining.
-	MaxStandardTxSize = 100000
-
-	// DefaultMinRelayTxFee is the minimum fee in sompi that is required
-	// for a transaction to be treated as free for relay and mining
-	// purposes. It is also used to help determine if a transaction is
-	// considered dust and as a base for calculating minimum required fees
-	// for larger transactions. This value is in sompi/1000 bytes.
-	DefaultMinRelayTxFee = util.Amount(1000)
 )
 
-// calcMinRequiredTxRelayFee returns the minimum transaction fee required for a
-// transaction with the passed serialized size to be accepted into the memory
-// pool and relayed.
-func calcMinRequiredTxRelayFee(serializedSize int64, minRelayTxFee util.Amount) int64 {
-	// Calculate the minimum fee for a transaction to be allowed into the
-	// mempool and relayed by scaling the base fee. minTxRelayFee is in
-	// sompi/kB so multiply by serializedSize (which is in bytes) and
-	// divide by 1000 to get minimum sompis.
-	minFee := (serializedSize * int64(minRelayTxFee)) / 1000
-
-	if minFee == 0 && minRelayTxFee > 0 {
-		minFee = int64(minRelayTxFee)
-	}
-
-	// Set the minimum fee to the maximum possible value if the calculated
-	// fee is not in the valid range for monetary amounts.
-	if minFee < 0 || minFee > util.MaxSompi {
-		minFee = util.MaxSompi
-	}
-
-	return minFee
-}
-
 // checkInputsStandard performs a series of checks on a transaction's inputs
 // to ensure they are "standard". A standard transaction input within the
 // context of this function is one whose referenced public key script is of a
@@ -112,51 +77,6 @@ func checkInputsStandard(tx *consensusexternalapi.DomainTransaction) error {
 	return nil
 }
 
-// isDust returns whether or not the passed transaction output amount is
-// considered dust or not based on the passed minimum transaction relay fee.
-// Dust is defined in terms of the minimum transaction relay fee. In
-// particular, if the cost to the network to spend coins is more than 1/3 of the
-// minimum transaction relay fee, it is considered dust.
-func isDust(txOut *consensusexternalapi.DomainTransactionOutput, minRelayTxFee util.Amount) bool {
-	// Unspendable outputs are considered dust.
-	if txscript.IsUnspendable(txOut.ScriptPublicKey.Script) {
-		return true
-	}
-
-	// The total serialized size consists of the output and the associated
-	// input script to redeem it. Since there is no input script
-	// to redeem it yet, use the minimum size of a typical input script.
-	//
-	// Pay-to-pubkey bytes breakdown:
-	//
-	//  Output to pubkey (43 bytes):
-	//   8 value, 1 script len, 34 script [1 OP_DATA_32,
-	//   32 pubkey, 1 OP_CHECKSIG]
-	//
-	//  Input (105 bytes):
-	//   36 prev outpoint, 1 script len, 64 script [1 OP_DATA_64,
-	//   64 sig], 4 sequence
-	//
-	// The most common scripts are pay-to-pubkey, and as per the above
-	// breakdown, the minimum size of a p2pk input script is 148 bytes. So
-	// that figure is used.
-	totalSize := estimatedsize.TransactionOutputEstimatedSerializedSize(txOut) + 148
-
-	// The output is considered dust if the cost to the network to spend the
-	// coins is more than 1/3 of the minimum free transaction relay fee.
-	// minFreeTxRelayFee is in sompi/KB, so multiply by 1000 to
-	// convert to bytes.
-	//
-	// Using the typical values for a pay-to-pubkey transaction from
-	// the breakdown above and the default minimum free transaction relay
-	// fee of 1000, this equates to values less than 546 sompi being
-	// considered dust.
-	//
-	// The following is equivalent to (value/totalSize) * (1/3) * 1000
-	// without needing to do floating point math.
-	return txOut.Value*1000/(3*totalSize) < uint64(minRelayTxFee)
-}
-
 // checkTransactionStandard performs a series of checks on a transaction to
 // ensure it is a "standard" transaction. A standard transaction is one that
 // conforms to several additional limiting cases over what is considered a
@@ -179,9 +99,9 @@ func checkTransactionStandard(tx *consensusexternalapi.DomainTransaction, policy
 	// size of a transaction. This also helps mitigate CPU exhaustion
 	// attacks.
 	serializedLen := estimatedsize.TransactionEstimatedSerializedSize(tx)
-	if serializedLen > MaxStandardTxSize {
+	if serializedLen > relaypolicy.MaxStandardTxSize {
 		str := fmt.Sprintf("transaction size of %d is larger than max "+
-			"allowed size of %d", serializedLen, MaxStandardTxSize)
+			"allowed size of %d", serializedLen, relaypolicy.MaxStandardTxSize)
 		return txRuleError(RejectNonstandard, str)
 	}
 
@@ -211,7 +131,7 @@ func checkTransactionStandard(tx *consensusexternalapi.DomainTransaction, policy
 			return txRuleError(RejectNonstandard, str)
 		}
 
-		if isDust(txOut, policy.MinRelayTxFee) {
+		if relaypolicy.IsTransactionOutputDust(txOut, policy.MinRelayTxFee) {
 			str := fmt.Sprintf("transaction output %d: payment "+
 				"of %d is dust", i, txOut.Value)
 			return txRuleError(RejectDust, str)
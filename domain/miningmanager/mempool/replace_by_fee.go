@@ -0,0 +1,139 @@
+package mempool
+
+import (
+	"fmt"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+// maxReplacementEvictions is the maximum number of transactions,
+// including chained transactions, that a single replacement is
+// allowed to evict from the mempool.
+const maxReplacementEvictions = 100
+
+// conflictingTransactions returns the transactions in the pool that spend any
+// of the outpoints the given transaction spends, without duplicates.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *mempool) conflictingTransactions(tx *consensusexternalapi.DomainTransaction) []*consensusexternalapi.DomainTransaction {
+	var conflictingTxs []*consensusexternalapi.DomainTransaction
+	conflictingTxIDs := make(map[consensusexternalapi.DomainTransactionID]struct{})
+	for _, txIn := range tx.Inputs {
+		conflictingTx, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(txIn.PreviousOutpoint)
+		if !exists {
+			continue
+		}
+		conflictingTxID := *consensushashing.TransactionID(conflictingTx)
+		if _, ok := conflictingTxIDs[conflictingTxID]; ok {
+			continue
+		}
+		conflictingTxIDs[conflictingTxID] = struct{}{}
+		conflictingTxs = append(conflictingTxs, conflictingTx)
+	}
+	return conflictingTxs
+}
+
+// checkReplacement checks whether the given transaction may replace the given
+// conflicting transactions along with all of their chained transactions. A
+// replacement must:
+// 1. Pay a strictly higher fee than all the evicted transactions combined
+// 2. Pay a strictly higher fee rate than every conflicting transaction
+// 3. Not spend the outputs of any of the evicted transactions
+// 4. Not evict more than maxReplacementEvictions transactions
+//
+// The conflicting transactions are expected to be replaceable, and the given
+// transaction is expected to have populated fee and mass.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *mempool) checkReplacement(tx *consensusexternalapi.DomainTransaction,
+	conflictingTxs []*consensusexternalapi.DomainTransaction,
+	parentsInPool []consensusexternalapi.DomainOutpoint) error {
+
+	txID := consensushashing.TransactionID(tx)
+	txFeeRate := float64(tx.Fee) / float64(tx.Mass)
+	for _, conflictingTx := range conflictingTxs {
+		conflictingTxFeeRate := float64(conflictingTx.Fee) / float64(conflictingTx.Mass)
+		if txFeeRate <= conflictingTxFeeRate {
+			str := fmt.Sprintf("replacement transaction %s has a fee rate of %f, which is not higher than "+
+				"the fee rate of %f of the transaction %s it replaces", txID, txFeeRate, conflictingTxFeeRate,
+				consensushashing.TransactionID(conflictingTx))
+			return txRuleError(RejectInsufficientFee, str)
+		}
+	}
+
+	evictedTxs := make(map[consensusexternalapi.DomainTransactionID]*consensusexternalapi.DomainTransaction)
+	for _, conflictingTx := range conflictingTxs {
+		mp.collectChainedTransactions(conflictingTx, evictedTxs)
+	}
+	if len(evictedTxs) > maxReplacementEvictions {
+		str := fmt.Sprintf("replacement transaction %s would evict %d transactions, which is more than "+
+			"the maximum of %d", txID, len(evictedTxs), maxReplacementEvictions)
+		return txRuleError(RejectNonstandard, str)
+	}
+
+	for _, parentOutpoint := range parentsInPool {
+		if _, ok := evictedTxs[parentOutpoint.TransactionID]; ok {
+			str := fmt.Sprintf("replacement transaction %s spends the output %s of a transaction it "+
+				"replaces", txID, parentOutpoint)
+			return txRuleError(RejectDuplicate, str)
+		}
+	}
+
+	evictedFees := uint64(0)
+	for _, evictedTx := range evictedTxs {
+		evictedFees += evictedTx.Fee
+	}
+	if tx.Fee <= evictedFees {
+		str := fmt.Sprintf("replacement transaction %s has %d fees, which is not higher than the %d "+
+			"fees of the %d transactions it replaces", txID, tx.Fee, evictedFees, len(evictedTxs))
+		return txRuleError(RejectInsufficientFee, str)
+	}
+
+	return nil
+}
+
+// collectChainedTransactions adds the given transaction and all the transactions
+// in the pool that depend on it, recursively, to the given map.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *mempool) collectChainedTransactions(tx *consensusexternalapi.DomainTransaction,
+	collected map[consensusexternalapi.DomainTransactionID]*consensusexternalapi.DomainTransaction) {
+
+	txID := consensushashing.TransactionID(tx)
+	if _, ok := collected[*txID]; ok {
+		return
+	}
+	collected[*txID] = tx
+
+	// Stop early. The replacement is going to be rejected anyway
+	if len(collected) > maxReplacementEvictions {
+		return
+	}
+
+	for i := range tx.Outputs {
+		outpoint := consensusexternalapi.DomainOutpoint{TransactionID: *txID, Index: uint32(i)}
+		if txRedeemer, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(outpoint); exists {
+			mp.collectChainedTransactions(txRedeemer, collected)
+		}
+	}
+}
+
+// evictReplacedTransactions removes the given conflicting transactions, along with
+// all of their chained transactions, from the mempool.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *mempool) evictReplacedTransactions(conflictingTxs []*consensusexternalapi.DomainTransaction) error {
+	for _, conflictingTx := range conflictingTxs {
+		// A conflicting transaction might have been already removed if it's
+		// chained to another conflicting transaction
+		if _, exists := mp.fetchTxDesc(consensushashing.TransactionID(conflictingTx)); !exists {
+			continue
+		}
+		err := mp.removeTransactionAndItsChainedTransactions(conflictingTx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
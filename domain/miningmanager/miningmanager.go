@@ -12,6 +12,8 @@ type MiningManager interface {
 	GetTransaction(transactionID *consensusexternalapi.DomainTransactionID) (*consensusexternalapi.DomainTransaction, bool)
 	AllTransactions() []*consensusexternalapi.DomainTransaction
 	TransactionCount() int
+	OrphanCount() int
+	FeeEstimate() *miningmanagermodel.FeeEstimate
 	HandleNewBlockTransactions(txs []*consensusexternalapi.DomainTransaction) ([]*consensusexternalapi.DomainTransaction, error)
 	ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) error
 }
@@ -51,3 +53,14 @@ func (mm *miningManager) AllTransactions() []*consensusexternalapi.DomainTransac
 func (mm *miningManager) TransactionCount() int {
 	return mm.mempool.TransactionCount()
 }
+
+// OrphanCount returns the number of orphan transactions in the mempool
+func (mm *miningManager) OrphanCount() int {
+	return mm.mempool.OrphanCount()
+}
+
+// FeeEstimate returns the fee rates suggested for each priority bucket
+// according to the current mempool contents and recently added blocks
+func (mm *miningManager) FeeEstimate() *miningmanagermodel.FeeEstimate {
+	return mm.mempool.FeeEstimate()
+}
@@ -2,6 +2,7 @@ package miningmanager_test
 
 import (
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
 	"strings"
 	"testing"
 
@@ -219,6 +220,81 @@ func TestDoubleSpends(t *testing.T) {
 	})
 }
 
+// TestReplaceByFee verifies that a transaction that opted in to be replaced can be replaced, along with
+// its chained transactions, by a conflicting transaction that pays a higher fee.
+func TestReplaceByFee(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestReplaceByFee")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		miningManager := miningFactory.NewMiningManager(tc, &consensusConfig.Params)
+
+		// createReplacement creates a transaction that spends the same outpoint as the transaction created
+		// by createTransactionWithUTXOEntry(t, i), but with a different output value
+		createReplacement := func(i int, outputValue uint64) *externalapi.DomainTransaction {
+			replacement := createTransactionWithUTXOEntry(t, i)
+			replacement.Outputs[0].Value = outputValue
+			return replacement
+		}
+
+		// A transaction that didn't opt in can't be replaced
+		nonReplaceableTransaction := createTransactionWithUTXOEntry(t, 0)
+		err = miningManager.ValidateAndInsertTransaction(nonReplaceableTransaction, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		err = miningManager.ValidateAndInsertTransaction(createReplacement(0, 5000), false)
+		if err == nil || !strings.Contains(err.Error(), "already spent by transaction") {
+			t.Fatalf("Unexpected error replacing a non-replaceable transaction: %v", err)
+		}
+
+		originalTransaction := createTransactionWithUTXOEntry(t, 1)
+		originalTransaction.Inputs[0].Sequence = relaypolicy.ReplaceableSequence
+		err = miningManager.ValidateAndInsertTransaction(originalTransaction, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		chainedTransaction := createChainedTransaction(t, originalTransaction, 5000)
+		err = miningManager.ValidateAndInsertTransaction(chainedTransaction, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+
+		// The replacement has to pay more than the original transaction and the transaction chained to it together
+		insufficientFeeReplacement := createReplacement(1, originalTransaction.Outputs[0].Value-1000)
+		err = miningManager.ValidateAndInsertTransaction(insufficientFeeReplacement, false)
+		if err == nil || !strings.Contains(err.Error(), "which is not higher than the") {
+			t.Fatalf("Unexpected error replacing with an insufficient fee: %v", err)
+		}
+
+		replacement := createReplacement(1, originalTransaction.Outputs[0].Value-6000)
+		err = miningManager.ValidateAndInsertTransaction(replacement, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+
+		transactionsFromMempool := miningManager.AllTransactions()
+		if !contains(replacement, transactionsFromMempool) {
+			t.Fatalf("Missing the replacement transaction %s in the mempool", consensushashing.TransactionID(replacement))
+		}
+		for _, replacedTransaction := range []*externalapi.DomainTransaction{originalTransaction, chainedTransaction} {
+			if contains(replacedTransaction, transactionsFromMempool) {
+				t.Fatalf("The transaction %s, shouldn't be in the mempool, since it was replaced",
+					consensushashing.TransactionID(replacedTransaction))
+			}
+		}
+		if len(transactionsFromMempool) != 2 {
+			t.Fatalf("Wrong number of transactions in mempool: expected: %d, got: %d", 2, len(transactionsFromMempool))
+		}
+	})
+}
+
 // TestOrphanTransactions verifies that a transaction could be a part of a new block template, only if it's not an orphan.
 func TestOrphanTransactions(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
@@ -353,6 +429,29 @@ func createTransactionWithUTXOEntry(t *testing.T, i int) *externalapi.DomainTran
 	return &tx
 }
 
+func createChainedTransaction(t *testing.T, parent *externalapi.DomainTransaction, outputValue uint64) *externalapi.DomainTransaction {
+	scriptPublicKey, redeemScript := testutils.OpTrueScript()
+	signatureScript, err := txscript.PayToScriptHashSignatureScript(redeemScript, nil)
+	if err != nil {
+		t.Fatalf("PayToScriptHashSignatureScript: %v", err)
+	}
+	input := externalapi.DomainTransactionInput{
+		PreviousOutpoint: externalapi.DomainOutpoint{TransactionID: *consensushashing.TransactionID(parent), Index: 0},
+		SignatureScript:  signatureScript,
+		Sequence:         constants.SequenceLockTimeIsSeconds,
+	}
+	output := externalapi.DomainTransactionOutput{
+		Value:           outputValue,
+		ScriptPublicKey: scriptPublicKey,
+	}
+	return &externalapi.DomainTransaction{
+		Version:      constants.MaxTransactionVersion,
+		Inputs:       []*externalapi.DomainTransactionInput{&input},
+		Outputs:      []*externalapi.DomainTransactionOutput{&output},
+		SubnetworkID: subnetworks.SubnetworkIDNative,
+	}
+}
+
 func createArraysOfParentAndChildrenTransactions(tc testapi.TestConsensus) ([]*externalapi.DomainTransaction,
 	[]*externalapi.DomainTransaction, error) {
 
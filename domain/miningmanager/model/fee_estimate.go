@@ -0,0 +1,17 @@
+package model
+
+// FeeEstimate holds the fee rates suggested for having a transaction
+// included in a block within the time of each priority bucket
+type FeeEstimate struct {
+	PriorityBucket *FeeRateBucket
+	NormalBucket   *FeeRateBucket
+	LowBucket      *FeeRateBucket
+}
+
+// FeeRateBucket is a suggested fee rate, in sompi/gram, along with the
+// time within which a transaction paying it is expected to be included
+// in a block
+type FeeRateBucket struct {
+	FeeRate          float64
+	EstimatedSeconds float64
+}
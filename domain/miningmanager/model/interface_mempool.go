@@ -14,4 +14,6 @@ type Mempool interface {
 	GetTransaction(transactionID *consensusexternalapi.DomainTransactionID) (*consensusexternalapi.DomainTransaction, bool)
 	AllTransactions() []*consensusexternalapi.DomainTransaction
 	TransactionCount() int
+	OrphanCount() int
+	FeeEstimate() *FeeEstimate
 }
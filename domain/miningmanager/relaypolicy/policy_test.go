@@ -0,0 +1,163 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package relaypolicy
+
+import (
+	"testing"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/util"
+)
+
+// TestCalcMinRequiredTxRelayFee tests the CalcMinRequiredTxRelayFee API.
+func TestCalcMinRequiredTxRelayFee(t *testing.T) {
+	tests := []struct {
+		name     string      // test description.
+		size     int64       // Transaction size in bytes.
+		relayFee util.Amount // minimum relay transaction fee.
+		want     int64       // Expected fee.
+	}{
+		{
+			// Ensure combination of size and fee that are less than 1000
+			// produce a non-zero fee.
+			"250 bytes with relay fee of 3",
+			250,
+			3,
+			3,
+		},
+		{
+			"100 bytes with default minimum relay fee",
+			100,
+			DefaultMinRelayTxFee,
+			100,
+		},
+		{
+			"max standard tx size with default minimum relay fee",
+			MaxStandardTxSize,
+			DefaultMinRelayTxFee,
+			100000,
+		},
+		{
+			"max standard tx size with max sompi relay fee",
+			MaxStandardTxSize,
+			util.MaxSompi,
+			util.MaxSompi,
+		},
+		{
+			"1500 bytes with 5000 relay fee",
+			1500,
+			5000,
+			7500,
+		},
+		{
+			"1500 bytes with 3000 relay fee",
+			1500,
+			3000,
+			4500,
+		},
+		{
+			"782 bytes with 5000 relay fee",
+			782,
+			5000,
+			3910,
+		},
+		{
+			"782 bytes with 3000 relay fee",
+			782,
+			3000,
+			2346,
+		},
+		{
+			"782 bytes with 2550 relay fee",
+			782,
+			2550,
+			1994,
+		},
+	}
+
+	for _, test := range tests {
+		got := CalcMinRequiredTxRelayFee(test.size, test.relayFee)
+		if got != test.want {
+			t.Errorf("TestCalcMinRequiredTxRelayFee test '%s' "+
+				"failed: got %v want %v", test.name, got,
+				test.want)
+			continue
+		}
+	}
+}
+
+// TestDust tests the IsTransactionOutputDust API.
+func TestDust(t *testing.T) {
+	scriptPublicKey := &consensusexternalapi.ScriptPublicKey{
+		[]byte{0x76, 0xa9, 0x21, 0x03, 0x2f, 0x7e, 0x43,
+			0x0a, 0xa4, 0xc9, 0xd1, 0x59, 0x43, 0x7e, 0x84, 0xb9,
+			0x75, 0xdc, 0x76, 0xd9, 0x00, 0x3b, 0xf0, 0x92, 0x2c,
+			0xf3, 0xaa, 0x45, 0x28, 0x46, 0x4b, 0xab, 0x78, 0x0d,
+			0xba, 0x5e}, 0}
+
+	tests := []struct {
+		name     string // test description
+		txOut    consensusexternalapi.DomainTransactionOutput
+		relayFee util.Amount // minimum relay transaction fee.
+		isDust   bool
+	}{
+		{
+			// Any value is allowed with a zero relay fee.
+			"zero value with zero relay fee",
+			consensusexternalapi.DomainTransactionOutput{Value: 0, ScriptPublicKey: scriptPublicKey},
+			0,
+			false,
+		},
+		{
+			// Zero value is dust with any relay fee"
+			"zero value with very small tx fee",
+			consensusexternalapi.DomainTransactionOutput{Value: 0, ScriptPublicKey: scriptPublicKey},
+			1,
+			true,
+		},
+		{
+			"36 byte public key script with value 605",
+			consensusexternalapi.DomainTransactionOutput{Value: 605, ScriptPublicKey: scriptPublicKey},
+			1000,
+			true,
+		},
+		{
+			"36 byte public key script with value 606",
+			consensusexternalapi.DomainTransactionOutput{Value: 606, ScriptPublicKey: scriptPublicKey},
+			1000,
+			false,
+		},
+		{
+			// Maximum allowed value is never dust.
+			"max sompi amount is never dust",
+			consensusexternalapi.DomainTransactionOutput{Value: util.MaxSompi, ScriptPublicKey: scriptPublicKey},
+			util.MaxSompi,
+			false,
+		},
+		{
+			// Maximum int64 value causes overflow.
+			"maximum int64 value",
+			consensusexternalapi.DomainTransactionOutput{Value: 1<<63 - 1, ScriptPublicKey: scriptPublicKey},
+			1<<63 - 1,
+			true,
+		},
+		{
+			// Unspendable ScriptPublicKey due to an invalid public key
+			// script.
+			"unspendable ScriptPublicKey",
+			consensusexternalapi.DomainTransactionOutput{Value: 5000, ScriptPublicKey: &consensusexternalapi.ScriptPublicKey{[]byte{0x01}, 0}},
+			0, // no relay fee
+			true,
+		},
+	}
+	for _, test := range tests {
+		res := IsTransactionOutputDust(&test.txOut, test.relayFee)
+		if res != test.isDust {
+			t.Fatalf("Dust test '%s' failed: want %v got %v",
+				test.name, test.isDust, res)
+			continue
+		}
+	}
+}
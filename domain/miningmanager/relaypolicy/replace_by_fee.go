@@ -0,0 +1,26 @@
+package relaypolicy
+
+import (
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+)
+
+// ReplaceableSequence is the input sequence number that signals that
+// the spending transaction may be replaced.
+// See IsReplaceable for further details.
+const ReplaceableSequence = constants.MaxTxInSequenceNum - 1
+
+// IsReplaceable returns whether the given transaction opted in to be replaced
+// by a conflicting transaction that pays a higher fee. A transaction opts in
+// by having at least one input with a sequence number of exactly
+// ReplaceableSequence. Transactions that predate replace-by-fee usually have
+// a sequence number of 0 or MaxTxInSequenceNum, so they can't opt in by
+// accident.
+func IsReplaceable(tx *consensusexternalapi.DomainTransaction) bool {
+	for _, input := range tx.Inputs {
+		if input.Sequence == ReplaceableSequence {
+			return true
+		}
+	}
+	return false
+}
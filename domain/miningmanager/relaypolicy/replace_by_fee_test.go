@@ -0,0 +1,60 @@
+package relaypolicy
+
+import (
+	"testing"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+)
+
+func TestIsReplaceable(t *testing.T) {
+	tests := []struct {
+		name      string
+		sequences []uint64
+		want      bool
+	}{
+		{
+			// This is the sequence transactions are created with by default
+			name:      "max sequence",
+			sequences: []uint64{constants.MaxTxInSequenceNum},
+			want:      false,
+		},
+		{
+			name:      "replaceable sequence",
+			sequences: []uint64{ReplaceableSequence},
+			want:      true,
+		},
+		{
+			// This is the sequence of most transactions that predate replace-by-fee
+			name:      "zero sequence",
+			sequences: []uint64{0},
+			want:      false,
+		},
+		{
+			name:      "max sequence minus two",
+			sequences: []uint64{constants.MaxTxInSequenceNum - 2},
+			want:      false,
+		},
+		{
+			name:      "only one input signals",
+			sequences: []uint64{constants.MaxTxInSequenceNum, ReplaceableSequence},
+			want:      true,
+		},
+		{
+			name:      "no input signals",
+			sequences: []uint64{constants.MaxTxInSequenceNum, 0},
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		tx := &consensusexternalapi.DomainTransaction{}
+		for _, sequence := range test.sequences {
+			tx.Inputs = append(tx.Inputs, &consensusexternalapi.DomainTransactionInput{Sequence: sequence})
+		}
+		got := IsReplaceable(tx)
+		if got != test.want {
+			t.Errorf("%s: IsReplaceable: want %t, got %t", test.name, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+// TXLocation describes where a transaction accepted by the
+// selected parent chain can be found
+type TXLocation struct {
+	// IncludingBlockHash is the hash of the block that contains the transaction
+	IncludingBlockHash *externalapi.DomainHash
+
+	// AcceptingBlockHash is the hash of the selected parent chain
+	// block that accepted the transaction
+	AcceptingBlockHash *externalapi.DomainHash
+}
@@ -0,0 +1,37 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+)
+
+const serializedTXLocationSize = 2 * externalapi.DomainHashSize
+
+func serializeTXLocation(txLocation *TXLocation) []byte {
+	serializedTXLocation := make([]byte, serializedTXLocationSize)
+	copy(serializedTXLocation[:externalapi.DomainHashSize], txLocation.IncludingBlockHash.ByteSlice())
+	copy(serializedTXLocation[externalapi.DomainHashSize:], txLocation.AcceptingBlockHash.ByteSlice())
+	return serializedTXLocation
+}
+
+func deserializeTXLocation(serializedTXLocation []byte) (*TXLocation, error) {
+	if len(serializedTXLocation) != serializedTXLocationSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "expected a serialized TX location of %d bytes "+
+			"but got %d bytes", serializedTXLocationSize, len(serializedTXLocation))
+	}
+
+	includingBlockHash, err := externalapi.NewDomainHashFromByteSlice(serializedTXLocation[:externalapi.DomainHashSize])
+	if err != nil {
+		return nil, err
+	}
+	acceptingBlockHash, err := externalapi.NewDomainHashFromByteSlice(serializedTXLocation[externalapi.DomainHashSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &TXLocation{
+		IncludingBlockHash: includingBlockHash,
+		AcceptingBlockHash: acceptingBlockHash,
+	}, nil
+}
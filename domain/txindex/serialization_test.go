@@ -0,0 +1,30 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_serializeTXLocation(t *testing.T) {
+	txLocation := &TXLocation{
+		IncludingBlockHash: externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		AcceptingBlockHash: externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+	}
+
+	serialized := serializeTXLocation(txLocation)
+	result, err := deserializeTXLocation(serialized)
+	if err != nil {
+		t.Fatalf("Failed deserializing TX location: %v", err)
+	}
+	if !reflect.DeepEqual(txLocation, result) {
+		t.Fatalf("Expected \n %+v \n==\n %+v\n", txLocation, result)
+	}
+
+	_, err = deserializeTXLocation(serialized[:len(serialized)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+var txIndexBucket = database.MakeBucket([]byte("tx-index"))
+var virtualSelectedParentKey = database.MakeBucket([]byte("")).Key([]byte("tx-index-virtual-selected-parent"))
+
+type txIndexStore struct {
+	database database.Database
+
+	// toPut maps to nil the transactions that are to be removed from the index
+	toPut                 map[externalapi.DomainTransactionID]*TXLocation
+	virtualSelectedParent *externalapi.DomainHash
+}
+
+func newTXIndexStore(database database.Database) *txIndexStore {
+	return &txIndexStore{
+		database: database,
+		toPut:    make(map[externalapi.DomainTransactionID]*TXLocation),
+	}
+}
+
+func (tis *txIndexStore) add(transactionID *externalapi.DomainTransactionID, txLocation *TXLocation) {
+	log.Tracef("Staging the addition of transaction %s accepted by %s", transactionID, txLocation.AcceptingBlockHash)
+	tis.toPut[*transactionID] = txLocation
+}
+
+func (tis *txIndexStore) remove(transactionID *externalapi.DomainTransactionID) {
+	log.Tracef("Staging the removal of transaction %s", transactionID)
+	tis.toPut[*transactionID] = nil
+}
+
+func (tis *txIndexStore) updateVirtualSelectedParent(virtualSelectedParent *externalapi.DomainHash) {
+	tis.virtualSelectedParent = virtualSelectedParent
+}
+
+func (tis *txIndexStore) discard() {
+	tis.toPut = make(map[externalapi.DomainTransactionID]*TXLocation)
+	tis.virtualSelectedParent = nil
+}
+
+func (tis *txIndexStore) commit() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "txIndexStore.commit")
+	defer onEnd()
+
+	if tis.virtualSelectedParent == nil {
+		return errors.Errorf("cannot commit the TX index without a virtual selected parent")
+	}
+
+	dbTransaction, err := tis.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	for transactionID, txLocation := range tis.toPut {
+		key := tis.convertTransactionIDToKey(&transactionID)
+		if txLocation == nil {
+			err = dbTransaction.Delete(key)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = dbTransaction.Put(key, serializeTXLocation(txLocation))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dbTransaction.Put(virtualSelectedParentKey, tis.virtualSelectedParent.ByteSlice())
+	if err != nil {
+		return err
+	}
+
+	err = dbTransaction.Commit()
+	if err != nil {
+		return err
+	}
+
+	tis.discard()
+	return nil
+}
+
+func (tis *txIndexStore) convertTransactionIDToKey(transactionID *externalapi.DomainTransactionID) *database.Key {
+	return txIndexBucket.Key(transactionID.ByteSlice())
+}
+
+func (tis *txIndexStore) isAnythingStaged() bool {
+	return len(tis.toPut) > 0 || tis.virtualSelectedParent != nil
+}
+
+func (tis *txIndexStore) getTXLocation(transactionID *externalapi.DomainTransactionID) (
+	txLocation *TXLocation, found bool, err error) {
+
+	if tis.isAnythingStaged() {
+		return nil, false, errors.Errorf("cannot get a TX location while staging isn't empty")
+	}
+
+	serializedTXLocation, err := tis.database.Get(tis.convertTransactionIDToKey(transactionID))
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	txLocation, err = deserializeTXLocation(serializedTXLocation)
+	if err != nil {
+		return nil, false, err
+	}
+	return txLocation, true, nil
+}
+
+func (tis *txIndexStore) getVirtualSelectedParent() (*externalapi.DomainHash, error) {
+	if tis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the virtual selected parent while staging isn't empty")
+	}
+
+	serializedHash, err := tis.database.Get(virtualSelectedParentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return externalapi.NewDomainHashFromByteSlice(serializedHash)
+}
+
+func (tis *txIndexStore) deleteAll() error {
+	// First we delete the virtual selected parent, so if anything goes wrong, the TX index
+	// will be marked as "not synced" and will be reset.
+	err := tis.database.Delete(virtualSelectedParentKey)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := tis.database.Cursor(txIndexBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+
+		err = tis.database.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/chainindex"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"sync"
+)
+
+// TXIndex maintains an index between the IDs of the transactions
+// accepted by the selected parent chain and their locations in the DAG
+type TXIndex struct {
+	consensus  externalapi.Consensus
+	store      *txIndexStore
+	chainIndex *chainindex.Index
+
+	mutex sync.Mutex
+}
+
+// New creates a new TX index.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(consensus externalapi.Consensus, database database.Database) (*TXIndex, error) {
+	txIndex := &TXIndex{
+		consensus: consensus,
+		store:     newTXIndexStore(database),
+	}
+	txIndex.chainIndex = &chainindex.Index{
+		Name:                        "TX index",
+		Log:                         log,
+		AddChainBlock:               txIndex.addChainBlock,
+		RemoveChainBlock:            txIndex.removeChainBlock,
+		UpdateVirtualSelectedParent: txIndex.store.updateVirtualSelectedParent,
+		Commit:                      txIndex.store.commit,
+		Discard:                     txIndex.store.discard,
+	}
+
+	indexedVirtualSelectedParent, isResumable, err := txIndex.resumePoint()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isResumable {
+		err = txIndex.Reset()
+		if err != nil {
+			return nil, err
+		}
+		return txIndex, nil
+	}
+
+	err = txIndex.syncFrom(indexedVirtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	return txIndex, nil
+}
+
+// Reset deletes the whole TX index and resyncs it from consensus,
+// starting from the current pruning point.
+func (ti *TXIndex) Reset() error {
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	err := ti.store.deleteAll()
+	if err != nil {
+		return err
+	}
+
+	pruningPoint, err := ti.consensus.PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	return ti.syncFrom(pruningPoint)
+}
+
+// resumePoint returns the virtual selected parent that the TX index was last synced to,
+// and whether the index can be caught up from it. This isn't the case if it's missing,
+// or if it's below the pruning point, in which case the acceptance data of the
+// chain blocks between them might already be gone.
+func (ti *TXIndex) resumePoint() (indexedVirtualSelectedParent *externalapi.DomainHash, isResumable bool, err error) {
+	indexedVirtualSelectedParent, err = ti.store.getVirtualSelectedParent()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	blockInfo, err := ti.consensus.GetBlockInfo(indexedVirtualSelectedParent)
+	if err != nil {
+		return nil, false, err
+	}
+	if !blockInfo.Exists {
+		return nil, false, nil
+	}
+
+	pruningPoint, err := ti.consensus.PruningPoint()
+	if err != nil {
+		return nil, false, err
+	}
+	isAbovePruningPoint, err := ti.consensus.IsInSelectedParentChainOf(pruningPoint, indexedVirtualSelectedParent)
+	if err != nil {
+		return nil, false, err
+	}
+	return indexedVirtualSelectedParent, isAbovePruningPoint, nil
+}
+
+// syncFrom brings the TX index from the given selected parent chain block
+// to the current virtual selected parent
+func (ti *TXIndex) syncFrom(blockHash *externalapi.DomainHash) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.syncFrom")
+	defer onEnd()
+
+	return chainindex.SyncFrom(ti.consensus, ti.chainIndex, blockHash)
+}
+
+// Update updates the TX index with the given DAG selected parent chain changes
+func (ti *TXIndex) Update(blockInsertionResult *externalapi.BlockInsertionResult) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.Update")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	return chainindex.Update(ti.chainIndex, blockInsertionResult.VirtualSelectedParentChainChanges)
+}
+
+func (ti *TXIndex) addChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	acceptanceData, err := ti.consensus.GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			transactionID := consensushashing.TransactionID(transactionAcceptanceData.Transaction)
+			ti.store.add(transactionID, &TXLocation{
+				IncludingBlockHash: blockAcceptanceData.BlockHash,
+				AcceptingBlockHash: chainBlockHash,
+			})
+		}
+	}
+	return nil
+}
+
+func (ti *TXIndex) removeChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	acceptanceData, err := ti.consensus.GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			ti.store.remove(consensushashing.TransactionID(transactionAcceptanceData.Transaction))
+		}
+	}
+	return nil
+}
+
+// TXLocation returns the location of the given transaction if it
+// was accepted by the selected parent chain
+func (ti *TXIndex) TXLocation(transactionID *externalapi.DomainTransactionID) (
+	txLocation *TXLocation, found bool, err error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.TXLocation")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	return ti.store.getTXLocation(transactionID)
+}
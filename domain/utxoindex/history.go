@@ -0,0 +1,202 @@
+package utxoindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/chainindex"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionhelper"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+// syncHistory brings the UTXO history from the selected parent chain block it was
+// last synced to up to the current virtual selected parent.
+//
+// This function MUST be called with the UTXO index mutex held.
+func (ui *UTXOIndex) syncHistory() error {
+	syncPoint, err := ui.historySyncPoint()
+	if err != nil {
+		return err
+	}
+	return chainindex.SyncFrom(ui.consensus, ui.historyChainIndex, syncPoint)
+}
+
+// historySyncPoint returns the selected parent chain block to sync the UTXO history from.
+// This is the virtual selected parent that the history was last synced to, unless it's
+// missing, in which case the history is synced from the pruning point.
+func (ui *UTXOIndex) historySyncPoint() (*externalapi.DomainHash, error) {
+	historyVirtualSelectedParent, err := ui.store.getHistoryVirtualSelectedParent()
+	if err != nil && !database.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	if historyVirtualSelectedParent != nil {
+		blockInfo, err := ui.consensus.GetBlockInfo(historyVirtualSelectedParent)
+		if err != nil {
+			return nil, err
+		}
+		if blockInfo.Exists {
+			return historyVirtualSelectedParent, nil
+		}
+		log.Infof("The block %s the UTXO history was synced to is missing, "+
+			"syncing it from the pruning point", historyVirtualSelectedParent)
+	}
+
+	return ui.consensus.PruningPoint()
+}
+
+// updateHistory records the transactions accepted by the blocks that were added
+// to the selected parent chain, and forgets the ones accepted by the blocks that
+// were removed from it
+func (ui *UTXOIndex) updateHistory(selectedParentChainChanges *externalapi.SelectedChainPath) error {
+	if selectedParentChainChanges == nil {
+		return nil
+	}
+
+	for _, removedChainBlockHash := range selectedParentChainChanges.Removed {
+		acceptanceData, err := ui.consensus.GetBlockAcceptanceData(removedChainBlockHash)
+		if err != nil {
+			if database.IsNotFoundError(err) {
+				log.Debugf("Skipping removed chain block %s, whose acceptance data is missing", removedChainBlockHash)
+				continue
+			}
+			return err
+		}
+		err = ui.removeAcceptanceDataFromHistory(acceptanceData)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, addedChainBlockHash := range selectedParentChainChanges.Added {
+		acceptanceData, err := ui.consensus.GetBlockAcceptanceData(addedChainBlockHash)
+		if err != nil {
+			if database.IsNotFoundError(err) {
+				// This is the case for the chain blocks below a pruning point whose
+				// UTXO set was just imported, so their transactions can't be recorded
+				log.Debugf("Skipping chain block %s, whose acceptance data is missing", addedChainBlockHash)
+				continue
+			}
+			return err
+		}
+		blockInfo, err := ui.consensus.GetBlockInfo(addedChainBlockHash)
+		if err != nil {
+			return err
+		}
+		err = ui.addAcceptanceDataToHistory(addedChainBlockHash, blockInfo.BlueScore, blockInfo.DAAScore, acceptanceData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ui *UTXOIndex) addAcceptanceDataToHistory(chainBlockHash *externalapi.DomainHash, chainBlockBlueScore uint64,
+	chainBlockDAAScore uint64, acceptanceData externalapi.AcceptanceData) error {
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			transaction := transactionAcceptanceData.Transaction
+			transactionID := consensushashing.TransactionID(transaction)
+
+			for i, input := range transaction.Inputs {
+				spentUTXOEntry := transactionAcceptanceData.TransactionInputUTXOEntries[i]
+				historyEntry, found, err := ui.store.getHistoryEntry(spentUTXOEntry.ScriptPublicKey(), &input.PreviousOutpoint)
+				if err != nil {
+					return err
+				}
+				if !found {
+					historyEntry = &UTXOHistoryEntry{
+						Outpoint:  input.PreviousOutpoint,
+						UTXOEntry: spentUTXOEntry,
+					}
+				}
+				historyEntry.SpendingTransactionID = transactionID
+				historyEntry.SpendingBlockHash = chainBlockHash
+				historyEntry.SpendingBlockBlueScore = chainBlockBlueScore
+				historyEntry.SpendingTransactionFee = transactionAcceptanceData.Fee
+				ui.store.putHistoryEntry(historyEntry)
+			}
+
+			isCoinbase := transactionhelper.IsCoinBase(transaction)
+			for i, output := range transaction.Outputs {
+				outpoint := externalapi.NewDomainOutpoint(transactionID, uint32(i))
+				historyEntry, found, err := ui.store.getHistoryEntry(output.ScriptPublicKey, outpoint)
+				if err != nil {
+					return err
+				}
+				// The output might already have an entry if the transaction that spends
+				// it was accepted by the same chain block
+				if !found {
+					historyEntry = &UTXOHistoryEntry{
+						Outpoint:  *outpoint,
+						UTXOEntry: utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, isCoinbase, chainBlockDAAScore),
+					}
+				}
+				historyEntry.AcceptingBlockHash = chainBlockHash
+				historyEntry.AcceptingBlockBlueScore = chainBlockBlueScore
+				ui.store.putHistoryEntry(historyEntry)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ui *UTXOIndex) removeAcceptanceDataFromHistory(acceptanceData externalapi.AcceptanceData) error {
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			transaction := transactionAcceptanceData.Transaction
+			transactionID := consensushashing.TransactionID(transaction)
+
+			for i, input := range transaction.Inputs {
+				spentUTXOEntry := transactionAcceptanceData.TransactionInputUTXOEntries[i]
+				historyEntry, found, err := ui.store.getHistoryEntry(spentUTXOEntry.ScriptPublicKey(), &input.PreviousOutpoint)
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+				historyEntry.SpendingTransactionID = nil
+				historyEntry.SpendingBlockHash = nil
+				historyEntry.SpendingBlockBlueScore = 0
+				historyEntry.SpendingTransactionFee = 0
+				ui.stageHistoryEntry(historyEntry)
+			}
+
+			for i, output := range transaction.Outputs {
+				outpoint := externalapi.NewDomainOutpoint(transactionID, uint32(i))
+				historyEntry, found, err := ui.store.getHistoryEntry(output.ScriptPublicKey, outpoint)
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+				historyEntry.AcceptingBlockHash = nil
+				historyEntry.AcceptingBlockBlueScore = 0
+				ui.stageHistoryEntry(historyEntry)
+			}
+		}
+	}
+
+	return nil
+}
+
+// stageHistoryEntry stages the given history entry, or its deletion if nothing
+// is known about it anymore
+func (ui *UTXOIndex) stageHistoryEntry(historyEntry *UTXOHistoryEntry) {
+	if historyEntry.isEmpty() {
+		ui.store.deleteHistoryEntry(historyEntry.UTXOEntry.ScriptPublicKey(), &historyEntry.Outpoint)
+		return
+	}
+	ui.store.putHistoryEntry(historyEntry)
+}
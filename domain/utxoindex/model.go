@@ -41,3 +41,27 @@ func ConvertStringToScriptPublicKey(string ScriptPublicKeyString) *externalapi.S
 	return &externalapi.ScriptPublicKey{Script: script, Version: version}
 
 }
+
+// UTXOHistoryEntry describes an output that paid some scriptPublicKey together with
+// the chain blocks that accepted the transaction that created it and, if the output
+// was spent, the transaction that spent it
+type UTXOHistoryEntry struct {
+	Outpoint  externalapi.DomainOutpoint
+	UTXOEntry externalapi.UTXOEntry
+
+	// AcceptingBlockHash is nil if the output was created before the index
+	// started recording history
+	AcceptingBlockHash      *externalapi.DomainHash
+	AcceptingBlockBlueScore uint64
+
+	// SpendingTransactionID is nil if the output wasn't spent by any transaction
+	// accepted by the selected parent chain
+	SpendingTransactionID  *externalapi.DomainTransactionID
+	SpendingBlockHash      *externalapi.DomainHash
+	SpendingBlockBlueScore uint64
+	SpendingTransactionFee uint64
+}
+
+func (uhe *UTXOHistoryEntry) isEmpty() bool {
+	return uhe.AcceptingBlockHash == nil && uhe.SpendingTransactionID == nil
+}
@@ -1,6 +1,7 @@
 package utxoindex
 
 import (
+	"bytes"
 	"encoding/binary"
 	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
@@ -70,3 +71,128 @@ func deserializeHashes(serializedHashes []byte) ([]*externalapi.DomainHash, erro
 
 	return hashes, nil
 }
+
+// serializeUTXOHistoryEntry serializes everything in the given history entry but its
+// outpoint, which is stored in the database key
+func serializeUTXOHistoryEntry(historyEntry *UTXOHistoryEntry) ([]byte, error) {
+	serializedUTXOEntry, err := serializeUTXOEntry(historyEntry.UTXOEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	writeUint64(buffer, uint64(len(serializedUTXOEntry)))
+	buffer.Write(serializedUTXOEntry)
+
+	if historyEntry.AcceptingBlockHash != nil {
+		buffer.WriteByte(1)
+		buffer.Write(historyEntry.AcceptingBlockHash.ByteSlice())
+		writeUint64(buffer, historyEntry.AcceptingBlockBlueScore)
+	} else {
+		buffer.WriteByte(0)
+	}
+
+	if historyEntry.SpendingTransactionID != nil {
+		buffer.WriteByte(1)
+		buffer.Write(historyEntry.SpendingTransactionID.ByteSlice())
+		buffer.Write(historyEntry.SpendingBlockHash.ByteSlice())
+		writeUint64(buffer, historyEntry.SpendingBlockBlueScore)
+		writeUint64(buffer, historyEntry.SpendingTransactionFee)
+	} else {
+		buffer.WriteByte(0)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func deserializeUTXOHistoryEntry(outpoint *externalapi.DomainOutpoint, serializedHistoryEntry []byte) (
+	*UTXOHistoryEntry, error) {
+
+	reader := bytes.NewReader(serializedHistoryEntry)
+	serializedUTXOEntryLength, err := readUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+	if serializedUTXOEntryLength > uint64(reader.Len()) {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	serializedUTXOEntry := make([]byte, serializedUTXOEntryLength)
+	_, err = io.ReadFull(reader, serializedUTXOEntry)
+	if err != nil {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	utxoEntry, err := deserializeUTXOEntry(serializedUTXOEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	historyEntry := &UTXOHistoryEntry{
+		Outpoint:  *outpoint,
+		UTXOEntry: utxoEntry,
+	}
+
+	hasAcceptingBlock, err := reader.ReadByte()
+	if err != nil {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	if hasAcceptingBlock != 0 {
+		historyEntry.AcceptingBlockHash, err = readHash(reader)
+		if err != nil {
+			return nil, err
+		}
+		historyEntry.AcceptingBlockBlueScore, err = readUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	isSpent, err := reader.ReadByte()
+	if err != nil {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	if isSpent != 0 {
+		spendingTransactionID, err := readHash(reader)
+		if err != nil {
+			return nil, err
+		}
+		historyEntry.SpendingTransactionID = externalapi.NewDomainTransactionIDFromByteArray(spendingTransactionID.ByteArray())
+		historyEntry.SpendingBlockHash, err = readHash(reader)
+		if err != nil {
+			return nil, err
+		}
+		historyEntry.SpendingBlockBlueScore, err = readUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+		historyEntry.SpendingTransactionFee, err = readUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return historyEntry, nil
+}
+
+func writeUint64(buffer *bytes.Buffer, value uint64) {
+	var serializedValue [8]byte
+	binary.LittleEndian.PutUint64(serializedValue[:], value)
+	buffer.Write(serializedValue[:])
+}
+
+func readUint64(reader *bytes.Reader) (uint64, error) {
+	var serializedValue [8]byte
+	_, err := io.ReadFull(reader, serializedValue[:])
+	if err != nil {
+		return 0, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	return binary.LittleEndian.Uint64(serializedValue[:]), nil
+}
+
+func readHash(reader *bytes.Reader) (*externalapi.DomainHash, error) {
+	var serializedHash [externalapi.DomainHashSize]byte
+	_, err := io.ReadFull(reader, serializedHash[:])
+	if err != nil {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing a UTXO history entry")
+	}
+	return externalapi.NewDomainHashFromByteArray(&serializedHash), nil
+}
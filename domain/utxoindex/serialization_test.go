@@ -3,9 +3,11 @@ package utxoindex
 import (
 	"encoding/binary"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
 	"github.com/pkg/errors"
 	"io"
 	"math/rand"
+	"reflect"
 	"testing"
 )
 
@@ -42,3 +44,58 @@ func Test_deserializeHashesFailure(t *testing.T) {
 		t.Fatalf("Expected error to be EOF, instead got: %v", err)
 	}
 }
+
+func Test_serializeUTXOHistoryEntry(t *testing.T) {
+	outpoint := externalapi.NewDomainOutpoint(
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1}), 2)
+	utxoEntry := utxo.NewUTXOEntry(100, &externalapi.ScriptPublicKey{Script: []byte{3, 4}, Version: 0}, false, 5)
+	acceptingBlockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{6})
+	spendingTransactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{7})
+	spendingBlockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{8})
+
+	tests := []*UTXOHistoryEntry{
+		{
+			Outpoint:                *outpoint,
+			UTXOEntry:               utxoEntry,
+			AcceptingBlockHash:      acceptingBlockHash,
+			AcceptingBlockBlueScore: 9,
+		},
+		{
+			Outpoint:               *outpoint,
+			UTXOEntry:              utxoEntry,
+			SpendingTransactionID:  spendingTransactionID,
+			SpendingBlockHash:      spendingBlockHash,
+			SpendingBlockBlueScore: 10,
+			SpendingTransactionFee: 11,
+		},
+		{
+			Outpoint:                *outpoint,
+			UTXOEntry:               utxoEntry,
+			AcceptingBlockHash:      acceptingBlockHash,
+			AcceptingBlockBlueScore: 9,
+			SpendingTransactionID:   spendingTransactionID,
+			SpendingBlockHash:       spendingBlockHash,
+			SpendingBlockBlueScore:  10,
+			SpendingTransactionFee:  11,
+		},
+	}
+
+	for i, historyEntry := range tests {
+		serialized, err := serializeUTXOHistoryEntry(historyEntry)
+		if err != nil {
+			t.Fatalf("Test #%d: Failed serializing history entry: %v", i, err)
+		}
+		result, err := deserializeUTXOHistoryEntry(outpoint, serialized)
+		if err != nil {
+			t.Fatalf("Test #%d: Failed deserializing history entry: %v", i, err)
+		}
+		if !reflect.DeepEqual(historyEntry, result) {
+			t.Fatalf("Test #%d: Expected \n %+v \n==\n %+v\n", i, historyEntry, result)
+		}
+
+		_, err = deserializeUTXOHistoryEntry(outpoint, serialized[:len(serialized)-1])
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("Test #%d: Expected error to be EOF, instead got: %v", i, err)
+		}
+	}
+}
@@ -9,13 +9,19 @@ import (
 )
 
 var utxoIndexBucket = database.MakeBucket([]byte("utxo-index"))
+var utxoHistoryBucket = database.MakeBucket([]byte("utxo-index-history"))
 var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-virtual-parents"))
+var historyVirtualSelectedParentKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-history-virtual-selected-parent"))
 
 type utxoIndexStore struct {
 	database       database.Database
 	toAdd          map[ScriptPublicKeyString]UTXOOutpointEntryPairs
 	toRemove       map[ScriptPublicKeyString]UTXOOutpoints
 	virtualParents []*externalapi.DomainHash
+
+	// historyToPut maps to nil the history entries that are to be deleted
+	historyToPut                 map[ScriptPublicKeyString]map[externalapi.DomainOutpoint]*UTXOHistoryEntry
+	historyVirtualSelectedParent *externalapi.DomainHash
 }
 
 func newUTXOIndexStore(database database.Database) *utxoIndexStore {
@@ -23,6 +29,8 @@ func newUTXOIndexStore(database database.Database) *utxoIndexStore {
 		database: database,
 		toAdd:    make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs),
 		toRemove: make(map[ScriptPublicKeyString]UTXOOutpoints),
+
+		historyToPut: make(map[ScriptPublicKeyString]map[externalapi.DomainOutpoint]*UTXOHistoryEntry),
 	}
 }
 
@@ -95,14 +103,73 @@ func (uis *utxoIndexStore) remove(scriptPublicKey *externalapi.ScriptPublicKey,
 	return nil
 }
 
+func (uis *utxoIndexStore) putHistoryEntry(historyEntry *UTXOHistoryEntry) {
+	key := ConvertScriptPublicKeyToString(historyEntry.UTXOEntry.ScriptPublicKey())
+	log.Tracef("Staging history entry for outpoint %s:%d of scriptPublicKey %s",
+		historyEntry.Outpoint.TransactionID, historyEntry.Outpoint.Index, key)
+
+	if _, ok := uis.historyToPut[key]; !ok {
+		uis.historyToPut[key] = make(map[externalapi.DomainOutpoint]*UTXOHistoryEntry)
+	}
+	uis.historyToPut[key][historyEntry.Outpoint] = historyEntry
+}
+
+func (uis *utxoIndexStore) deleteHistoryEntry(scriptPublicKey *externalapi.ScriptPublicKey, outpoint *externalapi.DomainOutpoint) {
+	key := ConvertScriptPublicKeyToString(scriptPublicKey)
+	log.Tracef("Staging the deletion of the history entry for outpoint %s:%d of scriptPublicKey %s",
+		outpoint.TransactionID, outpoint.Index, key)
+
+	if _, ok := uis.historyToPut[key]; !ok {
+		uis.historyToPut[key] = make(map[externalapi.DomainOutpoint]*UTXOHistoryEntry)
+	}
+	uis.historyToPut[key][*outpoint] = nil
+}
+
+// getHistoryEntry returns the history entry of the given outpoint, taking
+// anything that was staged but not yet committed into account
+func (uis *utxoIndexStore) getHistoryEntry(scriptPublicKey *externalapi.ScriptPublicKey,
+	outpoint *externalapi.DomainOutpoint) (historyEntry *UTXOHistoryEntry, found bool, err error) {
+
+	if stagedHistoryEntries, ok := uis.historyToPut[ConvertScriptPublicKeyToString(scriptPublicKey)]; ok {
+		if stagedHistoryEntry, ok := stagedHistoryEntries[*outpoint]; ok {
+			return stagedHistoryEntry, stagedHistoryEntry != nil, nil
+		}
+	}
+
+	key, err := uis.convertOutpointToKey(uis.historyBucketForScriptPublicKey(scriptPublicKey), outpoint)
+	if err != nil {
+		return nil, false, err
+	}
+	serializedHistoryEntry, err := uis.database.Get(key)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	historyEntry, err = deserializeUTXOHistoryEntry(outpoint, serializedHistoryEntry)
+	if err != nil {
+		return nil, false, err
+	}
+	return historyEntry, true, nil
+}
+
 func (uis *utxoIndexStore) updateVirtualParents(virtualParents []*externalapi.DomainHash) {
 	uis.virtualParents = virtualParents
 }
 
+// updateHistoryVirtualSelectedParent stages the selected parent chain block that
+// the UTXO history is synced to
+func (uis *utxoIndexStore) updateHistoryVirtualSelectedParent(virtualSelectedParent *externalapi.DomainHash) {
+	uis.historyVirtualSelectedParent = virtualSelectedParent
+}
+
 func (uis *utxoIndexStore) discard() {
 	uis.toAdd = make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs)
 	uis.toRemove = make(map[ScriptPublicKeyString]UTXOOutpoints)
 	uis.virtualParents = nil
+	uis.historyToPut = make(map[ScriptPublicKeyString]map[externalapi.DomainOutpoint]*UTXOHistoryEntry)
+	uis.historyVirtualSelectedParent = nil
 }
 
 func (uis *utxoIndexStore) commit() error {
@@ -149,10 +216,47 @@ func (uis *utxoIndexStore) commit() error {
 		}
 	}
 
-	serializeParentHashes := serializeHashes(uis.virtualParents)
-	err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
-	if err != nil {
-		return err
+	for scriptPublicKeyString, historyEntriesOfKey := range uis.historyToPut {
+		scriptPublicKey := ConvertStringToScriptPublicKey(scriptPublicKeyString)
+		bucket := uis.historyBucketForScriptPublicKey(scriptPublicKey)
+		for outpoint, historyEntry := range historyEntriesOfKey {
+			key, err := uis.convertOutpointToKey(bucket, &outpoint)
+			if err != nil {
+				return err
+			}
+			if historyEntry == nil {
+				err = dbTransaction.Delete(key)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			serializedHistoryEntry, err := serializeUTXOHistoryEntry(historyEntry)
+			if err != nil {
+				return err
+			}
+			err = dbTransaction.Put(key, serializedHistoryEntry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The UTXO history is synced on its own when the index is reset, in which case
+	// the virtual parents aren't staged
+	if uis.virtualParents != nil {
+		serializeParentHashes := serializeHashes(uis.virtualParents)
+		err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if uis.historyVirtualSelectedParent != nil {
+		err = dbTransaction.Put(historyVirtualSelectedParentKey, uis.historyVirtualSelectedParent.ByteSlice())
+		if err != nil {
+			return err
+		}
 	}
 
 	err = dbTransaction.Commit()
@@ -196,6 +300,13 @@ func (uis *utxoIndexStore) bucketForScriptPublicKey(scriptPublicKey *externalapi
 	return utxoIndexBucket.Bucket(scriptPublicKeyBytes)
 }
 
+func (uis *utxoIndexStore) historyBucketForScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Bucket {
+	var scriptPublicKeyBytes = make([]byte, 2+len(scriptPublicKey.Script)) // uint16
+	binary.LittleEndian.PutUint16(scriptPublicKeyBytes[:2], scriptPublicKey.Version)
+	copy(scriptPublicKeyBytes[2:], scriptPublicKey.Script)
+	return utxoHistoryBucket.Bucket(scriptPublicKeyBytes)
+}
+
 func (uis *utxoIndexStore) convertOutpointToKey(bucket *database.Bucket, outpoint *externalapi.DomainOutpoint) (*database.Key, error) {
 	serializedOutpoint, err := serializeOutpoint(outpoint)
 	if err != nil {
@@ -236,7 +347,7 @@ func (uis *utxoIndexStore) stagedData() (
 }
 
 func (uis *utxoIndexStore) isAnythingStaged() bool {
-	return len(uis.toAdd) > 0 || len(uis.toRemove) > 0
+	return len(uis.toAdd) > 0 || len(uis.toRemove) > 0 || len(uis.historyToPut) > 0
 }
 
 func (uis *utxoIndexStore) getUTXOOutpointEntryPairs(scriptPublicKey *externalapi.ScriptPublicKey) (UTXOOutpointEntryPairs, error) {
@@ -273,6 +384,40 @@ func (uis *utxoIndexStore) getUTXOOutpointEntryPairs(scriptPublicKey *externalap
 	return utxoOutpointEntryPairs, nil
 }
 
+func (uis *utxoIndexStore) getUTXOHistoryEntries(scriptPublicKey *externalapi.ScriptPublicKey) ([]*UTXOHistoryEntry, error) {
+	if uis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get utxo history entries while staging isn't empty")
+	}
+
+	bucket := uis.historyBucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := uis.database.Cursor(bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	var historyEntries []*UTXOHistoryEntry
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		outpoint, err := uis.convertKeyToOutpoint(key)
+		if err != nil {
+			return nil, err
+		}
+		serializedHistoryEntry, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		historyEntry, err := deserializeUTXOHistoryEntry(outpoint, serializedHistoryEntry)
+		if err != nil {
+			return nil, err
+		}
+		historyEntries = append(historyEntries, historyEntry)
+	}
+	return historyEntries, nil
+}
+
 func (uis *utxoIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
 	if uis.isAnythingStaged() {
 		return nil, errors.Errorf("cannot get the virtual parents while staging isn't empty")
@@ -286,6 +431,21 @@ func (uis *utxoIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error
 	return deserializeHashes(serializedHashes)
 }
 
+func (uis *utxoIndexStore) getHistoryVirtualSelectedParent() (*externalapi.DomainHash, error) {
+	if uis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the history virtual selected parent while staging isn't empty")
+	}
+
+	serializedHash, err := uis.database.Get(historyVirtualSelectedParentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return externalapi.NewDomainHashFromByteSlice(serializedHash)
+}
+
+// deleteAll deletes the UTXOs of the index. The UTXO history is kept, since unlike
+// the UTXOs, it can't be rebuilt from consensus.
 func (uis *utxoIndexStore) deleteAll() error {
 	// First we delete the virtual parents, so if anything goes wrong, the UTXO index will be marked as "not synced"
 	// and will be reset.
@@ -1,6 +1,7 @@
 package utxoindex
 
 import (
+	"github.com/kaspanet/kaspad/domain/chainindex"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
@@ -8,10 +9,12 @@ import (
 )
 
 // UTXOIndex maintains an index between transaction scriptPublicKeys
-// and UTXOs
+// and UTXOs. It also keeps the history of every output that was created
+// or spent by a transaction accepted by the selected parent chain
 type UTXOIndex struct {
-	consensus externalapi.Consensus
-	store     *utxoIndexStore
+	consensus         externalapi.Consensus
+	store             *utxoIndexStore
+	historyChainIndex *chainindex.Index
 
 	mutex sync.Mutex
 }
@@ -24,6 +27,19 @@ func New(consensus externalapi.Consensus, database database.Database) (*UTXOInde
 		consensus: consensus,
 		store:     newUTXOIndexStore(database),
 	}
+	utxoIndex.historyChainIndex = &chainindex.Index{
+		Name: "UTXO history",
+		Log:  log,
+		AddChainBlock: func(chainBlockHash *externalapi.DomainHash) error {
+			return utxoIndex.updateHistory(&externalapi.SelectedChainPath{Added: []*externalapi.DomainHash{chainBlockHash}})
+		},
+		RemoveChainBlock: func(chainBlockHash *externalapi.DomainHash) error {
+			return utxoIndex.updateHistory(&externalapi.SelectedChainPath{Removed: []*externalapi.DomainHash{chainBlockHash}})
+		},
+		UpdateVirtualSelectedParent: utxoIndex.store.updateHistoryVirtualSelectedParent,
+		Commit:                      utxoIndex.store.commit,
+		Discard:                     utxoIndex.store.discard,
+	}
 
 	isSynced, err := utxoIndex.isSynced()
 	if err != nil {
@@ -35,13 +51,34 @@ func New(consensus externalapi.Consensus, database database.Database) (*UTXOInde
 		if err != nil {
 			return nil, err
 		}
+		return utxoIndex, nil
+	}
+
+	utxoIndex.mutex.Lock()
+	defer utxoIndex.mutex.Unlock()
+	err = utxoIndex.syncHistory()
+	if err != nil {
+		return nil, err
 	}
 
 	return utxoIndex, nil
 }
 
-// Reset deletes the whole UTXO index and resyncs it from consensus.
+// Reset deletes the UTXOs of the index and resyncs them from consensus.
+// The UTXO history can't be rebuilt from consensus, so it's kept, and is
+// synced from the selected parent chain block it was last synced to.
 func (ui *UTXOIndex) Reset() error {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	err := ui.resetUTXOs()
+	if err != nil {
+		return err
+	}
+	return ui.syncHistory()
+}
+
+func (ui *UTXOIndex) resetUTXOs() error {
 	err := ui.store.deleteAll()
 	if err != nil {
 		return err
@@ -112,6 +149,15 @@ func (ui *UTXOIndex) Update(blockInsertionResult *externalapi.BlockInsertionResu
 		return nil, err
 	}
 
+	chainChanges := blockInsertionResult.VirtualSelectedParentChainChanges
+	err = ui.updateHistory(chainChanges)
+	if err != nil {
+		return nil, err
+	}
+	if chainChanges != nil && len(chainChanges.Added) > 0 {
+		ui.store.updateHistoryVirtualSelectedParent(chainChanges.Added[len(chainChanges.Added)-1])
+	}
+
 	ui.store.updateVirtualParents(blockInsertionResult.VirtualParents)
 
 	added, removed, _ := ui.store.stagedData()
@@ -175,3 +221,15 @@ func (ui *UTXOIndex) UTXOs(scriptPublicKey *externalapi.ScriptPublicKey) (UTXOOu
 
 	return ui.store.getUTXOOutpointEntryPairs(scriptPublicKey)
 }
+
+// UTXOHistory returns the history entries of all the outputs that paid the given scriptPublicKey
+// since the index started recording history, whether they were spent or not
+func (ui *UTXOIndex) UTXOHistory(scriptPublicKey *externalapi.ScriptPublicKey) ([]*UTXOHistoryEntry, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "UTXOIndex.UTXOHistory")
+	defer onEnd()
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	return ui.store.getUTXOHistoryEntries(scriptPublicKey)
+}
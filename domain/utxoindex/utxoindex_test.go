@@ -0,0 +1,101 @@
+package utxoindex_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+)
+
+func TestUTXOHistorySurvivesReset(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, "TestUTXOHistorySurvivesReset")
+		if err != nil {
+			t.Fatalf("Error setting up tc: %+v", err)
+		}
+		defer teardown(false)
+
+		newDatabase := func() database.Database {
+			db, err := ldb.NewLevelDB(t.TempDir(), 8)
+			if err != nil {
+				t.Fatalf("NewLevelDB: %+v", err)
+			}
+			t.Cleanup(func() { db.Close() })
+			return db
+		}
+		db := newDatabase()
+		utxoIndex, err := utxoindex.New(tc, db)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+
+		scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+		coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey}
+		tipHash := consensusConfig.GenesisHash
+		addBlocks := func(count int, updateIndex bool) {
+			for i := 0; i < count; i++ {
+				var blockInsertionResult *externalapi.BlockInsertionResult
+				tipHash, blockInsertionResult, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, coinbaseData, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				if !updateIndex {
+					continue
+				}
+				_, err = utxoIndex.Update(blockInsertionResult)
+				if err != nil {
+					t.Fatalf("Update: %+v", err)
+				}
+			}
+		}
+		historyLength := func(utxoIndex *utxoindex.UTXOIndex) int {
+			history, err := utxoIndex.UTXOHistory(scriptPublicKey)
+			if err != nil {
+				t.Fatalf("UTXOHistory: %+v", err)
+			}
+			return len(history)
+		}
+
+		addBlocks(5, true)
+		lengthBeforeReset := historyLength(utxoIndex)
+		if lengthBeforeReset == 0 {
+			t.Fatalf("No UTXO history was recorded")
+		}
+
+		err = utxoIndex.Reset()
+		if err != nil {
+			t.Fatalf("Reset: %+v", err)
+		}
+		if historyLength(utxoIndex) != lengthBeforeReset {
+			t.Fatalf("The UTXO history changed by Reset. Want %d entries, got %d",
+				lengthBeforeReset, historyLength(utxoIndex))
+		}
+
+		// Blocks that are added while the index isn't updated are added to the
+		// history once it's synced again
+		addBlocks(3, false)
+		utxoIndex, err = utxoindex.New(tc, db)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+		lengthAfterSync := historyLength(utxoIndex)
+		if lengthAfterSync <= lengthBeforeReset {
+			t.Fatalf("The UTXO history wasn't synced with the added blocks")
+		}
+
+		// A new index syncs the history from the pruning point, which is the genesis
+		// here, so it should have the same history
+		newUTXOIndex, err := utxoindex.New(tc, newDatabase())
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+		if historyLength(newUTXOIndex) != lengthAfterSync {
+			t.Fatalf("Unexpected UTXO history of a new index. Want %d entries, got %d",
+				lengthAfterSync, historyLength(newUTXOIndex))
+		}
+	})
+}
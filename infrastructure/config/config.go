@@ -18,6 +18,7 @@ import (
 
 	"github.com/btcsuite/go-socks/socks"
 	"github.com/jessevdk/go-flags"
+	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
@@ -31,11 +32,14 @@ const (
 	defaultConfigFilename      = "kaspad.conf"
 	defaultDataDirname         = "data"
 	defaultLogLevel            = "info"
+	defaultLogFormat           = "text"
 	defaultLogDirname          = "logs"
 	defaultLogFilename         = "kaspad.log"
 	defaultErrLogFilename      = "kaspad_err.log"
 	defaultTargetOutboundPeers = 8
 	defaultMaxInboundPeers     = 117
+	defaultMaxInPeersPerGroup  = 8
+	defaultMaxOutPeersPerGroup = 2
 	defaultBanDuration         = time.Hour * 24
 	defaultBanThreshold        = 100
 	//DefaultConnectTimeout is the default connection timeout when dialing
@@ -53,8 +57,16 @@ const (
 	defaultSigCacheMaxSize  = 100000
 	sampleConfigFilename    = "sample-kaspad.conf"
 	defaultMaxUTXOCacheSize = 5000000000
+	defaultDbType           = "leveldb"
+
+	defaultLevelDBCacheSizeMiB  = 256
+	minLevelDBCacheSizeMiB      = 8
+	defaultLevelDBStatsInterval = time.Minute * 10
 )
 
+// SupportedDbTypes are the database backends that can be selected with --dbtype
+var SupportedDbTypes = []string{"leveldb", "logdb"}
+
 var (
 	// DefaultAppDir is the default home directory for kaspad.
 	DefaultAppDir = util.AppDir("kaspad", false)
@@ -86,6 +98,8 @@ type Flags struct {
 	Listeners                       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 16111, testnet: 16211)"`
 	TargetOutboundPeers             int           `long:"outpeers" description:"Target number of outbound peers"`
 	MaxInboundPeers                 int           `long:"maxinpeers" description:"Max number of inbound peers"`
+	MaxInboundPeersPerGroup         int           `long:"maxinpeerspergroup" description:"Max number of inbound peers from a single network group (IPv4 /16 or IPv6 /32). 0 means no limit"`
+	MaxOutboundPeersPerGroup        int           `long:"maxoutpeerspergroup" description:"Max number of outbound peers to a single network group (IPv4 /16 or IPv6 /32). 0 means no limit"`
 	DisableBanning                  bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
 	BanDuration                     time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Valid time units are {s, m, h}. Minimum 1 second"`
 	BanThreshold                    uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
@@ -93,8 +107,20 @@ type Flags struct {
 	RPCListeners                    []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 16110, testnet: 16210)"`
 	RPCCert                         string        `long:"rpccert" description:"File containing the certificate file"`
 	RPCKey                          string        `long:"rpckey" description:"File containing the certificate key"`
+	RPCTLS                          bool          `long:"rpctls" description:"Enable TLS for the RPC server -- NOTE: A self-signed certificate pair is generated at --rpccert and --rpckey if neither exists"`
+	RPCTLSExtraHosts                []string      `long:"rpctlsextrahost" description:"Add a hostname or IP the generated RPC certificate is valid for, in addition to the local interfaces"`
+	RPCUser                         string        `long:"rpcuser" description:"Username for RPC connections with admin permissions"`
+	RPCPass                         string        `long:"rpcpass" default-mask:"-" description:"Password for RPC connections with admin permissions"`
+	RPCAuthToken                    string        `long:"rpcauthtoken" default-mask:"-" description:"Token for RPC connections with admin permissions"`
+	RPCLimitUser                    string        `long:"rpclimituser" description:"Username for read-only RPC connections"`
+	RPCLimitPass                    string        `long:"rpclimitpass" default-mask:"-" description:"Password for read-only RPC connections"`
+	RPCLimitAuthToken               string        `long:"rpclimitauthtoken" default-mask:"-" description:"Token for read-only RPC connections"`
+	RPCDeny                         []string      `long:"rpcdeny" description:"Deny an RPC command, either to all RPC clients or only within a scope: <command>[@<scope>], where <scope> is admin, limited (read-only credentials), none (no authentication configured) or an RPC listener address (eg. GetPeerAddresses@0.0.0.0:16110)"`
+	RPCRateLimit                    []string      `long:"rpcratelimit" description:"Limit how many requests per minute every RPC client without admin permissions may make of an RPC command: <command>:<requests per minute>. 0 removes the limit (default GetUTXOsByAddresses:120, GetBlocks:120, GetHeaders:120)"`
 	RPCMaxClients                   int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
-	RPCMaxWebsockets                int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCMaxWebsockets                int           `long:"rpcmaxwebsockets" description:"Max number of JSON-RPC WebSocket connections"`
+	JSONRPCListeners                []string      `long:"jsonrpclisten" description:"Add an interface/port to listen for JSON-RPC 2.0 connections over HTTP and WebSocket (eg. 127.0.0.1:16120). The JSON-RPC server is disabled unless any is specified"`
+	JSONRPCAllowedOrigins           []string      `long:"jsonrpcallowedorigin" description:"Add an origin of web pages that may call the JSON-RPC server (eg. https://dashboard.example.com), or * to allow any"`
 	RPCMaxConcurrentReqs            int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
 	DisableRPC                      bool          `long:"norpc" description:"Disable built-in RPC server"`
 	DisableDNSSeed                  bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
@@ -104,9 +130,17 @@ type Flags struct {
 	Proxy                           string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser                       string        `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass                       string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
-	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
+	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG {leveldb, logdb}. An existing database can only be opened with the backend that created it"`
+	LevelDBCacheSizeMiB             int           `long:"leveldb-cache-size" description:"Size of the leveldb block cache in MiB (--dbtype=leveldb only)"`
+	LevelDBWriteBufferSizeMiB       int           `long:"leveldb-write-buffer-size" description:"Size of the leveldb write buffer in MiB. Larger buffers speed up IBD at the cost of memory and of a slower startup after a crash (--dbtype=leveldb only) (default: half of --leveldb-cache-size)"`
+	LevelDBCompression              bool          `long:"leveldb-compression" description:"Compress leveldb tables with Snappy. Tables that were already written are compressed as they're compacted (--dbtype=leveldb only)"`
+	LevelDBSyncWrites               bool          `long:"leveldb-sync" description:"Sync every leveldb write to disk before it completes, so that no committed data is lost if the machine crashes, at a significant cost in write throughput (--dbtype=leveldb only)"`
+	LevelDBStatsInterval            time.Duration `long:"leveldb-stats-interval" description:"Interval between reports of leveldb statistics in the log. 0 disables them (--dbtype=leveldb only)"`
 	Profile                         string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	Metrics                         string        `long:"metrics" description:"Enable the Prometheus metrics HTTP server on the given interface/port (eg. 127.0.0.1:16130). The metrics are served under /metrics"`
 	LogLevel                        string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	LogFormat                       string        `long:"logformat" description:"Format of the log files {text, json}"`
+	StdoutLogFormat                 string        `long:"stdoutlogformat" description:"Format of the logs written to stdout {text, json}"`
 	Upnp                            bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
 	MinRelayTxFee                   float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
 	MaxOrphanTxs                    int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
@@ -118,8 +152,11 @@ type Flags struct {
 	RelayNonStd                     bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
 	RejectNonStd                    bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	ResetDatabase                   bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
+	CheckDatabaseMigrations         bool          `long:"check-db-migrations" description:"Print the database migrations that are needed before the node can start, without running them, and exit"`
 	MaxUTXOCacheSize                uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
 	UTXOIndex                       bool          `long:"utxoindex" description:"Enable the UTXO index"`
+	TXIndex                         bool          `long:"txindex" description:"Enable the transaction index"`
+	AddressIndex                    bool          `long:"addressindex" description:"Enable the address index"`
 	IsArchivalNode                  bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
 	EnableSanityCheckPruningUTXOSet bool          `long:"enable-sanity-check-pruning-utxo" hidden:"true" description:"When moving the pruning point - check that the utxo set matches the utxo commitment"`
 	NetworkFlags
@@ -137,6 +174,8 @@ type Config struct {
 	MinRelayTxFee util.Amount
 	Whitelists    []*net.IPNet
 	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+	RPCDenyRules  []*RPCDenyRule
+	RPCRateLimits map[appmessage.MessageCommand]int
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -170,24 +209,31 @@ func newConfigParser(cfgFlags *Flags, options flags.Options) *flags.Parser {
 
 func defaultFlags() *Flags {
 	return &Flags{
-		ConfigFile:           defaultConfigFile,
-		LogLevel:             defaultLogLevel,
-		TargetOutboundPeers:  defaultTargetOutboundPeers,
-		MaxInboundPeers:      defaultMaxInboundPeers,
-		BanDuration:          defaultBanDuration,
-		BanThreshold:         defaultBanThreshold,
-		RPCMaxClients:        defaultMaxRPCClients,
-		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
-		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
-		AppDir:               defaultDataDir,
-		RPCKey:               defaultRPCKeyFile,
-		RPCCert:              defaultRPCCertFile,
-		BlockMaxMass:         defaultBlockMaxMass,
-		MaxOrphanTxs:         defaultMaxOrphanTransactions,
-		SigCacheMaxSize:      defaultSigCacheMaxSize,
-		MinRelayTxFee:        defaultMinRelayTxFee,
-		MaxUTXOCacheSize:     defaultMaxUTXOCacheSize,
-		ServiceOptions:       &ServiceOptions{},
+		ConfigFile:               defaultConfigFile,
+		LogLevel:                 defaultLogLevel,
+		DbType:                   defaultDbType,
+		LevelDBCacheSizeMiB:      defaultLevelDBCacheSizeMiB,
+		LevelDBStatsInterval:     defaultLevelDBStatsInterval,
+		LogFormat:                defaultLogFormat,
+		StdoutLogFormat:          defaultLogFormat,
+		TargetOutboundPeers:      defaultTargetOutboundPeers,
+		MaxInboundPeers:          defaultMaxInboundPeers,
+		MaxInboundPeersPerGroup:  defaultMaxInPeersPerGroup,
+		MaxOutboundPeersPerGroup: defaultMaxOutPeersPerGroup,
+		BanDuration:              defaultBanDuration,
+		BanThreshold:             defaultBanThreshold,
+		RPCMaxClients:            defaultMaxRPCClients,
+		RPCMaxWebsockets:         defaultMaxRPCWebsockets,
+		RPCMaxConcurrentReqs:     defaultMaxRPCConcurrentReqs,
+		AppDir:                   defaultDataDir,
+		RPCKey:                   defaultRPCKeyFile,
+		RPCCert:                  defaultRPCCertFile,
+		BlockMaxMass:             defaultBlockMaxMass,
+		MaxOrphanTxs:             defaultMaxOrphanTransactions,
+		SigCacheMaxSize:          defaultSigCacheMaxSize,
+		MinRelayTxFee:            defaultMinRelayTxFee,
+		MaxUTXOCacheSize:         defaultMaxUTXOCacheSize,
+		ServiceOptions:           &ServiceOptions{},
 	}
 }
 
@@ -335,9 +381,60 @@ func LoadConfig() (*Config, error) {
 		os.Exit(0)
 	}
 
+	// Validate the database type
+	if !isSupportedDbType(cfg.DbType) {
+		str := "%s: The specified database type [%s] is invalid -- supported types are %s"
+		err := errors.Errorf(str, funcName, cfg.DbType, strings.Join(SupportedDbTypes, ", "))
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the leveldb tuning
+	if cfg.LevelDBCacheSizeMiB < minLevelDBCacheSizeMiB {
+		str := "%s: The leveldb-cache-size option may not be less than %d -- parsed [%d]"
+		err := errors.Errorf(str, funcName, minLevelDBCacheSizeMiB, cfg.LevelDBCacheSizeMiB)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.LevelDBWriteBufferSizeMiB < 0 {
+		str := "%s: The leveldb-write-buffer-size option may not be negative -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.LevelDBWriteBufferSizeMiB)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.LevelDBStatsInterval < 0 {
+		str := "%s: The leveldb-stats-interval option may not be negative -- parsed [%s]"
+		err := errors.Errorf(str, funcName, cfg.LevelDBStatsInterval)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the log formats
+	logFormat, ok := logger.FormatFromString(cfg.LogFormat)
+	if !ok {
+		str := "%s: The specified log format [%s] is invalid -- supported formats are text and json"
+		err := errors.Errorf(str, funcName, cfg.LogFormat)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	stdoutLogFormat, ok := logger.FormatFromString(cfg.StdoutLogFormat)
+	if !ok {
+		str := "%s: The specified stdout log format [%s] is invalid -- supported formats are text and json"
+		err := errors.Errorf(str, funcName, cfg.StdoutLogFormat)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Initialize log rotation. After log rotation has been initialized, the
 	// logger variables may be used.
-	logger.InitLog(filepath.Join(cfg.LogDir, defaultLogFilename), filepath.Join(cfg.LogDir, defaultErrLogFilename))
+	logger.InitLogWithFormats(filepath.Join(cfg.LogDir, defaultLogFilename),
+		filepath.Join(cfg.LogDir, defaultErrLogFilename), logFormat, stdoutLogFormat)
 
 	// Parse, validate, and set debug log level(s).
 	if err := logger.ParseAndSetLogLevels(cfg.LogLevel); err != nil {
@@ -359,6 +456,18 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Validate the metrics listen address
+	if cfg.Metrics != "" {
+		_, _, err := net.SplitHostPort(cfg.Metrics)
+		if err != nil {
+			str := "%s: The metrics listen address '%s' is invalid: %s"
+			err := errors.Errorf(str, funcName, cfg.Metrics, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
 	// Don't allow ban durations that are too short.
 	if cfg.BanDuration < time.Second {
 		str := "%s: The banduration option may not be less than 1s -- parsed [%s]"
@@ -444,6 +553,17 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	cfg.RPCCert = cleanAndExpandPath(cfg.RPCCert)
+	cfg.RPCKey = cleanAndExpandPath(cfg.RPCKey)
+
+	err = validateRPCCredentials(cfg)
+	if err != nil {
+		err := errors.Errorf("%s: %s", funcName, err.Error())
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	if cfg.RPCMaxConcurrentReqs < 0 {
 		str := "%s: The rpcmaxwebsocketconcurrentrequests option may " +
 			"not be less than 0 -- parsed [%d]"
@@ -523,6 +643,28 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.DisableRPC {
+		cfg.JSONRPCListeners = nil
+	}
+	for _, jsonRPCListener := range cfg.JSONRPCListeners {
+		_, _, err := net.SplitHostPort(jsonRPCListener)
+		if err != nil {
+			str := "%s: JSON-RPC listener '%s' is invalid: %s"
+			err := errors.Errorf(str, funcName, jsonRPCListener, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
+	err = parseRPCPolicy(cfg)
+	if err != nil {
+		err := errors.Errorf("%s: %s", funcName, err.Error())
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Disallow --addpeer and --connect used together
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: --addpeer and --connect can not be used together"
@@ -580,6 +722,40 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// validateRPCCredentials makes sure that every configured set of RPC
+// credentials is complete, and that the admin and read-only credentials
+// can't be mistaken for one another
+func validateRPCCredentials(cfg *Config) error {
+	if (cfg.RPCUser == "") != (cfg.RPCPass == "") {
+		return errors.New("--rpcuser and --rpcpass must be specified together")
+	}
+	if (cfg.RPCLimitUser == "") != (cfg.RPCLimitPass == "") {
+		return errors.New("--rpclimituser and --rpclimitpass must be specified together")
+	}
+	if cfg.RPCUser != "" && cfg.RPCUser == cfg.RPCLimitUser {
+		return errors.New("--rpcuser and --rpclimituser must not specify the same username")
+	}
+	if cfg.RPCAuthToken != "" && cfg.RPCAuthToken == cfg.RPCLimitAuthToken {
+		return errors.New("--rpcauthtoken and --rpclimitauthtoken must not specify the same token")
+	}
+
+	isAuthenticationEnabled := cfg.RPCUser != "" || cfg.RPCAuthToken != "" ||
+		cfg.RPCLimitUser != "" || cfg.RPCLimitAuthToken != ""
+	if isAuthenticationEnabled && !cfg.RPCTLS {
+		log.Warnf("RPC authentication is enabled without --rpctls. Credentials will be sent in plain text")
+	}
+	return nil
+}
+
+func isSupportedDbType(dbType string) bool {
+	for _, supportedDbType := range SupportedDbTypes {
+		if dbType == supportedDbType {
+			return true
+		}
+	}
+	return false
+}
+
 // createDefaultConfig copies the file sample-kaspad.conf to the given destination path,
 // and populates it with some randomly generated RPC username and password.
 func createDefaultConfigFile(destinationPath string) error {
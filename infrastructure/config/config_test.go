@@ -70,3 +70,49 @@ func TestConstants(t *testing.T) {
 		t.Errorf("subnetworks.SubnetworkIDRegistry value was changed from 2, therefore you probably need to update the help text for SubnetworkID")
 	}
 }
+
+func TestValidateRPCCredentials(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           Flags
+		expectedError bool
+	}{
+		{
+			name: "no credentials",
+			cfg:  Flags{},
+		},
+		{
+			name: "admin and read-only credentials",
+			cfg: Flags{RPCUser: "admin", RPCPass: "pass", RPCAuthToken: "token",
+				RPCLimitUser: "user", RPCLimitPass: "pass", RPCLimitAuthToken: "limited-token"},
+		},
+		{
+			name:          "user without password",
+			cfg:           Flags{RPCUser: "admin"},
+			expectedError: true,
+		},
+		{
+			name:          "read-only password without user",
+			cfg:           Flags{RPCLimitPass: "pass"},
+			expectedError: true,
+		},
+		{
+			name:          "same user for admin and read-only",
+			cfg:           Flags{RPCUser: "user", RPCPass: "pass1", RPCLimitUser: "user", RPCLimitPass: "pass2"},
+			expectedError: true,
+		},
+		{
+			name:          "same token for admin and read-only",
+			cfg:           Flags{RPCAuthToken: "token", RPCLimitAuthToken: "token"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		err := validateRPCCredentials(&Config{Flags: &test.cfg})
+		if test.expectedError != (err != nil) {
+			t.Errorf("%s: expected error: %t, but got: %v", test.name, test.expectedError, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
+	"github.com/pkg/errors"
+)
+
+// RPCClientFlags holds the flags with which command line tools connect to
+// the RPC server of kaspad: TLS settings and credentials.
+type RPCClientFlags struct {
+	RPCUser      string `long:"rpcuser" description:"Username to authenticate to the RPC server with"`
+	RPCPass      string `long:"rpcpass" default-mask:"-" description:"Password to authenticate to the RPC server with"`
+	RPCAuthToken string `long:"rpcauthtoken" default-mask:"-" description:"Token to authenticate to the RPC server with"`
+	RPCTLS       bool   `long:"rpctls" description:"Connect to the RPC server over TLS"`
+	RPCCert      string `long:"rpccert" description:"File containing the RPC server's certificate, e.g. the one kaspad generated with --rpctls -- NOTE: Implies --rpctls"`
+}
+
+// ConnectOptions returns the options with which to connect to the RPC server
+// according to the flags
+func (rpcClientFlags *RPCClientFlags) ConnectOptions() (*grpcclient.ConnectOptions, error) {
+	if (rpcClientFlags.RPCUser == "") != (rpcClientFlags.RPCPass == "") {
+		return nil, errors.New("--rpcuser and --rpcpass must be specified together")
+	}
+	if rpcClientFlags.RPCUser != "" && rpcClientFlags.RPCAuthToken != "" {
+		return nil, errors.New("only one of --rpcuser and --rpcauthtoken may be specified")
+	}
+
+	tlsCertificateFile := ""
+	if rpcClientFlags.RPCCert != "" {
+		tlsCertificateFile = cleanAndExpandPath(rpcClientFlags.RPCCert)
+	}
+	return &grpcclient.ConnectOptions{
+		TLS:                rpcClientFlags.RPCTLS,
+		TLSCertificateFile: tlsCertificateFile,
+		Credentials: &grpcserver.RPCCredentials{
+			User:     rpcClientFlags.RPCUser,
+			Password: rpcClientFlags.RPCPass,
+			Token:    rpcClientFlags.RPCAuthToken,
+		},
+	}, nil
+}
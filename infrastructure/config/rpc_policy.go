@@ -0,0 +1,142 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/util/network"
+	"github.com/pkg/errors"
+)
+
+// RPC deny rule scopes that select RPC connections by how they were
+// authenticated. Any other scope is the address of an RPC listener.
+const (
+	// RPCDenyScopeAdmin selects connections authenticated with admin credentials
+	RPCDenyScopeAdmin = "admin"
+
+	// RPCDenyScopeLimited selects connections authenticated with read-only credentials
+	RPCDenyScopeLimited = "limited"
+
+	// RPCDenyScopeNone selects connections to an RPC server that doesn't require authentication
+	RPCDenyScopeNone = "none"
+)
+
+// defaultRPCRateLimits are the number of requests per minute every client
+// may make of each of the expensive RPC commands, unless overridden with
+// --rpcratelimit
+var defaultRPCRateLimits = map[appmessage.MessageCommand]int{
+	appmessage.CmdGetUTXOsByAddressesRequestMessage: 120,
+	appmessage.CmdGetBlocksRequestMessage:           120,
+	appmessage.CmdGetHeadersRequestMessage:          120,
+}
+
+// RPCDenyRule denies an RPC command to the RPC connections within a scope
+type RPCDenyRule struct {
+	Command appmessage.MessageCommand
+
+	// Scope is either empty, in which case the command is denied to all
+	// connections, one of the RPCDenyScope constants, or the address of
+	// one of the RPC or JSON-RPC listeners
+	Scope string
+}
+
+// parseRPCPolicy parses --rpcdeny and --rpcratelimit into cfg.RPCDenyRules
+// and cfg.RPCRateLimits. It expects cfg.RPCListeners to be normalized.
+func parseRPCPolicy(cfg *Config) error {
+	rpcListeners := append(append([]string{}, cfg.RPCListeners...), cfg.JSONRPCListeners...)
+	cfg.RPCDenyRules = make([]*RPCDenyRule, 0, len(cfg.RPCDeny))
+	for _, rpcDeny := range cfg.RPCDeny {
+		rule, err := parseRPCDenyRule(rpcDeny, rpcListeners, cfg.NetParams().RPCPort)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --rpcdeny %s", rpcDeny)
+		}
+		cfg.RPCDenyRules = append(cfg.RPCDenyRules, rule)
+	}
+
+	cfg.RPCRateLimits = make(map[appmessage.MessageCommand]int, len(defaultRPCRateLimits))
+	for command, limit := range defaultRPCRateLimits {
+		cfg.RPCRateLimits[command] = limit
+	}
+	for _, rpcRateLimit := range cfg.RPCRateLimit {
+		command, limit, err := parseRPCRateLimit(rpcRateLimit)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --rpcratelimit %s", rpcRateLimit)
+		}
+		if limit == 0 {
+			delete(cfg.RPCRateLimits, command)
+			continue
+		}
+		cfg.RPCRateLimits[command] = limit
+	}
+	return nil
+}
+
+// parseRPCDenyRule parses a rule of the form <command>[@<scope>]
+func parseRPCDenyRule(rpcDeny string, rpcListeners []string, defaultRPCPort string) (*RPCDenyRule, error) {
+	commandName, scope := rpcDeny, ""
+	if separatorIndex := strings.Index(rpcDeny, "@"); separatorIndex != -1 {
+		commandName, scope = rpcDeny[:separatorIndex], rpcDeny[separatorIndex+1:]
+		if scope == "" {
+			return nil, errors.New("the scope after @ is empty")
+		}
+	}
+	command, err := parseRPCCommand(commandName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case "", RPCDenyScopeAdmin, RPCDenyScopeLimited, RPCDenyScopeNone:
+	default:
+		scope, err = network.NormalizeAddress(scope, defaultRPCPort)
+		if err != nil {
+			return nil, err
+		}
+		if !isRPCListener(scope, rpcListeners) {
+			return nil, errors.Errorf("%s is neither one of %s, %s or %s nor one of the RPC listeners %s",
+				scope, RPCDenyScopeAdmin, RPCDenyScopeLimited, RPCDenyScopeNone, rpcListeners)
+		}
+	}
+	return &RPCDenyRule{Command: command, Scope: scope}, nil
+}
+
+func isRPCListener(address string, rpcListeners []string) bool {
+	for _, rpcListener := range rpcListeners {
+		if rpcListener == address {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRPCRateLimit parses a rate limit of the form <command>:<requests per minute>
+func parseRPCRateLimit(rpcRateLimit string) (appmessage.MessageCommand, int, error) {
+	separatorIndex := strings.LastIndex(rpcRateLimit, ":")
+	if separatorIndex == -1 {
+		return 0, 0, errors.New("expected <command>:<requests per minute>")
+	}
+	command, err := parseRPCCommand(rpcRateLimit[:separatorIndex])
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := strconv.Atoi(rpcRateLimit[separatorIndex+1:])
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid number of requests per minute")
+	}
+	if limit < 0 {
+		return 0, 0, errors.New("the number of requests per minute may not be negative")
+	}
+	return command, limit, nil
+}
+
+// parseRPCCommand returns the request command with the given name, e.g. GetBlocks
+func parseRPCCommand(name string) (appmessage.MessageCommand, error) {
+	requestName := name + "Request"
+	for command, commandName := range appmessage.RPCMessageCommandToString {
+		if commandName == requestName {
+			return command, nil
+		}
+	}
+	return 0, errors.Errorf("unknown RPC command %s", name)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestParseRPCPolicy(t *testing.T) {
+	tests := []struct {
+		name               string
+		rpcDeny            []string
+		rpcRateLimit       []string
+		expectedDenyRules  []RPCDenyRule
+		expectedRateLimits map[appmessage.MessageCommand]int
+		expectedError      bool
+	}{
+		{
+			name:               "defaults",
+			expectedDenyRules:  []RPCDenyRule{},
+			expectedRateLimits: defaultRPCRateLimits,
+		},
+		{
+			name:    "deny rules",
+			rpcDeny: []string{"GetPeerAddresses", "GetConnectedPeerInfo@limited", "GetMempoolEntries@0.0.0.0"},
+			expectedDenyRules: []RPCDenyRule{
+				{Command: appmessage.CmdGetPeerAddressesRequestMessage},
+				{Command: appmessage.CmdGetConnectedPeerInfoRequestMessage, Scope: RPCDenyScopeLimited},
+				{Command: appmessage.CmdGetMempoolEntriesRequestMessage, Scope: "0.0.0.0:16110"},
+			},
+			expectedRateLimits: defaultRPCRateLimits,
+		},
+		{
+			name:          "deny rule with unknown command",
+			rpcDeny:       []string{"GetEverything"},
+			expectedError: true,
+		},
+		{
+			name:          "deny rule with empty scope",
+			rpcDeny:       []string{"GetPeerAddresses@"},
+			expectedError: true,
+		},
+		{
+			name:          "deny rule for another listener",
+			rpcDeny:       []string{"GetPeerAddresses@127.0.0.1:16110"},
+			expectedError: true,
+		},
+		{
+			name:              "rate limits",
+			rpcRateLimit:      []string{"GetBlocks:10", "GetHeaders:0", "GetMempoolEntries:5"},
+			expectedDenyRules: []RPCDenyRule{},
+			expectedRateLimits: map[appmessage.MessageCommand]int{
+				appmessage.CmdGetUTXOsByAddressesRequestMessage: 120,
+				appmessage.CmdGetBlocksRequestMessage:           10,
+				appmessage.CmdGetMempoolEntriesRequestMessage:   5,
+			},
+		},
+		{
+			name:          "rate limit without a limit",
+			rpcRateLimit:  []string{"GetBlocks"},
+			expectedError: true,
+		},
+		{
+			name:          "negative rate limit",
+			rpcRateLimit:  []string{"GetBlocks:-1"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		cfg := DefaultConfig()
+		cfg.RPCListeners = []string{"0.0.0.0:16110"}
+		cfg.RPCDeny = test.rpcDeny
+		cfg.RPCRateLimit = test.rpcRateLimit
+
+		err := parseRPCPolicy(cfg)
+		if test.expectedError {
+			if err == nil {
+				t.Errorf("%s: expected an error, but got none", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+
+		if len(cfg.RPCDenyRules) != len(test.expectedDenyRules) {
+			t.Errorf("%s: expected %d deny rules, but got %d", test.name, len(test.expectedDenyRules), len(cfg.RPCDenyRules))
+			continue
+		}
+		for i, rule := range cfg.RPCDenyRules {
+			if *rule != test.expectedDenyRules[i] {
+				t.Errorf("%s: expected deny rule %+v, but got %+v", test.name, test.expectedDenyRules[i], *rule)
+			}
+		}
+
+		if len(cfg.RPCRateLimits) != len(test.expectedRateLimits) {
+			t.Errorf("%s: expected %d rate limits, but got %d", test.name, len(test.expectedRateLimits), len(cfg.RPCRateLimits))
+			continue
+		}
+		for command, limit := range test.expectedRateLimits {
+			if cfg.RPCRateLimits[command] != limit {
+				t.Errorf("%s: expected a rate limit of %d for %s, but got %d", test.name, limit, command, cfg.RPCRateLimits[command])
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Backuper defines the interface of a database that can back itself up
+// while it's being written to.
+type Backuper interface {
+	// Backup writes every record of the database, as it was when Backup
+	// was called, into a new database of the same type in the given path.
+	// Writes that are made to the database while the backup is written
+	// are not included in it. The path must not exist yet.
+	Backup(path string) (*BackupResult, error)
+}
+
+// BackupResult describes a backup written by Backuper.Backup.
+type BackupResult struct {
+	RecordCount uint64
+
+	// Size is the total size of the keys and values of the records, which
+	// may differ from the size of the backup on disk
+	Size uint64
+}
+
+// backupBatchSize is the size of the keys and values after which a batch
+// of copied records should be written to the backup
+const backupBatchSize = 4 * 1024 * 1024
+
+// CheckBackupPath returns an error if the given path, into which a backup
+// is about to be written, already exists
+func CheckBackupPath(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		return errors.Errorf("cannot back up the database into '%s', because it already exists", path)
+	}
+	if !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// BackupCounter keeps the BackupResult of a backup while its records are
+// copied, and tells when the copied records should be written in a batch.
+type BackupCounter struct {
+	result    BackupResult
+	batchSize int
+}
+
+// AddRecord counts a record that was added to the current batch of the backup.
+// It returns whether the batch is large enough to be written.
+func (counter *BackupCounter) AddRecord(key []byte, value []byte) (isBatchFull bool) {
+	recordSize := len(key) + len(value)
+	counter.result.RecordCount++
+	counter.result.Size += uint64(recordSize)
+	counter.batchSize += recordSize
+
+	if counter.batchSize < backupBatchSize {
+		return false
+	}
+	counter.batchSize = 0
+	return true
+}
+
+// Result returns the BackupResult of the records that were counted
+func (counter *BackupCounter) Result() *BackupResult {
+	result := counter.result
+	return &result
+}
@@ -0,0 +1,126 @@
+package database_test
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/logdb"
+)
+
+func TestDatabaseBackup(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestDatabaseBackup", testDatabaseBackup)
+}
+
+func testDatabaseBackup(t *testing.T, db database.Database, testName string) {
+	// Every transaction puts the same key into both buckets, so a
+	// consistent backup has exactly the same keys in both of them
+	bucketA := database.MakeBucket([]byte("a"))
+	bucketB := database.MakeBucket([]byte("b"))
+	putPair := func(i uint64) error {
+		var suffix [8]byte
+		binary.BigEndian.PutUint64(suffix[:], i)
+		dbTx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer dbTx.RollbackUnlessClosed()
+		err = dbTx.Put(bucketA.Key(suffix[:]), suffix[:])
+		if err != nil {
+			return err
+		}
+		err = dbTx.Put(bucketB.Key(suffix[:]), suffix[:])
+		if err != nil {
+			return err
+		}
+		return dbTx.Commit()
+	}
+
+	const initialPairCount = 1000
+	for i := uint64(0); i < initialPairCount; i++ {
+		err := putPair(i)
+		if err != nil {
+			t.Fatalf("%s: putPair unexpectedly failed: %s", testName, err)
+		}
+	}
+
+	// Keep writing pairs while the backup is written
+	stop := make(chan struct{})
+	writerErr := make(chan error)
+	go func() {
+		for i := uint64(initialPairCount); ; i++ {
+			select {
+			case <-stop:
+				writerErr <- nil
+				return
+			default:
+			}
+			err := putPair(i)
+			if err != nil {
+				writerErr <- err
+				return
+			}
+		}
+	}()
+
+	backupPath := filepath.Join(t.TempDir(), "backup")
+	result, err := db.(database.Backuper).Backup(backupPath)
+	close(stop)
+	if err != nil {
+		t.Fatalf("%s: Backup unexpectedly failed: %s", testName, err)
+	}
+	err = <-writerErr
+	if err != nil {
+		t.Fatalf("%s: putPair unexpectedly failed: %s", testName, err)
+	}
+
+	var backupDB database.Database
+	switch db.(type) {
+	case *ldb.LevelDB:
+		backupDB, err = ldb.NewLevelDB(backupPath, 8)
+	case *logdb.LogDB:
+		backupDB, err = logdb.NewLogDB(backupPath)
+	default:
+		t.Fatalf("%s: unexpected database type %T", testName, db)
+	}
+	if err != nil {
+		t.Fatalf("%s: opening the backup unexpectedly failed: %s", testName, err)
+	}
+	defer backupDB.Close()
+
+	countA := countBucketKeys(t, backupDB, bucketA, testName)
+	countB := countBucketKeys(t, backupDB, bucketB, testName)
+	if countA < initialPairCount {
+		t.Fatalf("%s: the backup has %d pairs, but at least %d were written before it started",
+			testName, countA, initialPairCount)
+	}
+	if countA != countB {
+		t.Fatalf("%s: the backup is inconsistent. It has %d keys in one bucket and %d in the other",
+			testName, countA, countB)
+	}
+	if result.RecordCount != uint64(countA+countB) {
+		t.Fatalf("%s: Backup reported %d records, but the backup has %d",
+			testName, result.RecordCount, countA+countB)
+	}
+
+	_, err = db.(database.Backuper).Backup(backupPath)
+	if err == nil {
+		t.Fatalf("%s: Backup into an existing path unexpectedly succeeded", testName)
+	}
+}
+
+func countBucketKeys(t *testing.T, db database.Database, bucket *database.Bucket, testName string) int {
+	cursor, err := db.Cursor(bucket)
+	if err != nil {
+		t.Fatalf("%s: Cursor unexpectedly failed: %s", testName, err)
+	}
+	defer cursor.Close()
+
+	count := 0
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		count++
+	}
+	return count
+}
@@ -6,8 +6,9 @@ Overview
 This package provides a database layer to store and retrieve data in a simple
 and efficient manner.
 
-The current backend is ffldb, which makes use of leveldb, flat files, and strict
-checksums in key areas to ensure data integrity.
+There are two backends, selected with kaspad's --dbtype flag: ldb, which is a
+thin wrapper around leveldb, and logdb, a pure-Go log-structured store that keeps
+an index of all of its keys in memory and checksums every write.
 
 Implementors of additional backends are required to implement the following interfaces:
 
@@ -0,0 +1,79 @@
+package ldb
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// Backup writes every record of the database, as it was when Backup was
+// called, into a new leveldb instance in the given path. It reads from a
+// leveldb snapshot, so the database may be written to while the backup is
+// written. The backup is opened with the config of the database. The path
+// must not exist yet.
+func (db *LevelDB) Backup(path string) (*database.BackupResult, error) {
+	err := database.CheckBackupPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := db.ldb.GetSnapshot()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer snapshot.Release()
+
+	// Unlike the database itself, the backup is synced to disk, since it's
+	// only useful if it survives a crash of the machine
+	options := db.config.options()
+	options.NoSync = false
+	options.ErrorIfExist = true
+	backupDB, err := leveldb.OpenFile(path, &options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result, err := copySnapshot(snapshot, backupDB)
+	if err != nil {
+		backupDB.Close()
+		return nil, err
+	}
+	err = backupDB.Close()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+func copySnapshot(snapshot *leveldb.Snapshot, backupDB *leveldb.DB) (*database.BackupResult, error) {
+	iterator := snapshot.NewIterator(nil, nil)
+	defer iterator.Release()
+
+	counter := &database.BackupCounter{}
+	batch := new(leveldb.Batch)
+	for iterator.Next() {
+		// The batch copies the key and value, so they may be reused by
+		// the iterator
+		batch.Put(iterator.Key(), iterator.Value())
+		if counter.AddRecord(iterator.Key(), iterator.Value()) {
+			err := backupDB.Write(batch, nil)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			batch.Reset()
+		}
+	}
+	err := iterator.Error()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// Syncing the last batch syncs the write-ahead log, and with it all
+	// the batches that preceded it
+	err = backupDB.Write(batch, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return counter.Result(), nil
+}
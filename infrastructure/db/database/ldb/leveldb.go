@@ -1,6 +1,9 @@
 package ldb
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -10,15 +13,21 @@ import (
 
 // LevelDB defines a thin wrapper around leveldb.
 type LevelDB struct {
-	ldb *leveldb.DB
+	ldb          *leveldb.DB
+	config       *Config
+	writeOptions *opt.WriteOptions
 }
 
 // NewLevelDB opens a leveldb instance defined by the given path.
 func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
+	return NewLevelDBWithConfig(path, DefaultConfig(cacheSizeMiB))
+}
+
+// NewLevelDBWithConfig opens a leveldb instance defined by the given path,
+// tuned by the given config.
+func NewLevelDBWithConfig(path string, config *Config) (*LevelDB, error) {
 	// Open leveldb. If it doesn't exist, create it.
-	options := Options()
-	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
-	options.WriteBuffer = (cacheSizeMiB * opt.MiB) / 2
+	options := config.options()
 	ldb, err := leveldb.OpenFile(path, &options)
 
 	// If the database is corrupted, attempt to recover.
@@ -42,7 +51,38 @@ func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
 	}
 
 	db := &LevelDB{
-		ldb: ldb,
+		ldb:          ldb,
+		config:       config,
+		writeOptions: config.writeOptions(),
+	}
+	return db, nil
+}
+
+// IsLevelDB returns whether the given path holds a leveldb instance.
+func IsLevelDB(path string) bool {
+	// Every leveldb instance has a CURRENT file, which names its manifest
+	_, err := os.Stat(filepath.Join(path, "CURRENT"))
+	return err == nil
+}
+
+// NewLevelDBReadOnly opens the existing leveldb instance defined by the given
+// path for reading only. Unlike NewLevelDB, it doesn't attempt to recover a
+// corrupted database, since recovering it requires writing to it.
+func NewLevelDBReadOnly(path string, cacheSizeMiB int) (*LevelDB, error) {
+	options := Options()
+	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
+	options.ErrorIfMissing = true
+	options.ReadOnly = true
+	ldb, err := leveldb.OpenFile(path, &options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	config := DefaultConfig(cacheSizeMiB)
+	db := &LevelDB{
+		ldb:          ldb,
+		config:       config,
+		writeOptions: config.writeOptions(),
 	}
 	return db, nil
 }
@@ -56,7 +96,7 @@ func (db *LevelDB) Close() error {
 // Put sets the value for the given key. It overwrites
 // any previous value for that key.
 func (db *LevelDB) Put(key *database.Key, value []byte) error {
-	err := db.ldb.Put(key.Bytes(), value, nil)
+	err := db.ldb.Put(key.Bytes(), value, db.writeOptions)
 	return errors.WithStack(err)
 }
 
@@ -87,6 +127,6 @@ func (db *LevelDB) Has(key *database.Key) (bool, error) {
 // Delete deletes the value for the given key. Will not
 // return an error if the key doesn't exist.
 func (db *LevelDB) Delete(key *database.Key) error {
-	err := db.ldb.Delete(key.Bytes(), nil)
+	err := db.ldb.Delete(key.Bytes(), db.writeOptions)
 	return errors.WithStack(err)
 }
@@ -151,3 +151,146 @@ func TestLevelDBTransactionSanity(t *testing.T) {
 			"returned unexpected error: %s", err)
 	}
 }
+
+func TestLevelDBReadOnly(t *testing.T) {
+	path, err := ioutil.TempDir("", "TestLevelDBReadOnly")
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: TempDir unexpectedly "+
+			"failed: %s", err)
+	}
+
+	// Opening a database that doesn't exist should fail
+	_, err = NewLevelDBReadOnly(path, 8)
+	if err == nil {
+		t.Fatalf("TestLevelDBReadOnly: NewLevelDBReadOnly " +
+			"unexpectedly succeeded opening a missing database")
+	}
+
+	ldb, err := NewLevelDB(path, 8)
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: NewLevelDB unexpectedly "+
+			"failed: %s", err)
+	}
+	key := database.MakeBucket(nil).Key([]byte("key"))
+	putData := []byte("Hello world!")
+	err = ldb.Put(key, putData)
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: Put returned "+
+			"unexpected error: %s", err)
+	}
+	err = ldb.Close()
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: Close unexpectedly "+
+			"failed: %s", err)
+	}
+
+	readOnlyLDB, err := NewLevelDBReadOnly(path, 8)
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: NewLevelDBReadOnly unexpectedly "+
+			"failed: %s", err)
+	}
+	defer readOnlyLDB.Close()
+
+	getData, err := readOnlyLDB.Get(key)
+	if err != nil {
+		t.Fatalf("TestLevelDBReadOnly: Get returned "+
+			"unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(getData, putData) {
+		t.Fatalf("TestLevelDBReadOnly: get data and "+
+			"put data are not equal. Put: %s, got: %s",
+			string(putData), string(getData))
+	}
+
+	err = readOnlyLDB.Put(key, []byte("Goodbye world!"))
+	if err == nil {
+		t.Fatalf("TestLevelDBReadOnly: Put unexpectedly " +
+			"succeeded on a read-only database")
+	}
+}
+
+func TestLevelDBWithConfig(t *testing.T) {
+	path, err := ioutil.TempDir("", "TestLevelDBWithConfig")
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: TempDir unexpectedly "+
+			"failed: %s", err)
+	}
+
+	config := &Config{
+		CacheSizeMiB:       8,
+		WriteBufferSizeMiB: 1,
+		Compression:        true,
+		SyncWrites:         true,
+	}
+	ldb, err := NewLevelDBWithConfig(path, config)
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: NewLevelDBWithConfig unexpectedly "+
+			"failed: %s", err)
+	}
+
+	// Write more than the write buffer can hold, so that it's flushed
+	// into a table
+	bucket := database.MakeBucket([]byte("bucket"))
+	value := make([]byte, 1024)
+	dbTx, err := ldb.Begin()
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Begin unexpectedly "+
+			"failed: %s", err)
+	}
+	for i := 0; i < 2048; i++ {
+		err := dbTx.Put(bucket.Key([]byte{byte(i >> 8), byte(i)}), value)
+		if err != nil {
+			t.Fatalf("TestLevelDBWithConfig: Put returned "+
+				"unexpected error: %s", err)
+		}
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Commit unexpectedly "+
+			"failed: %s", err)
+	}
+	err = ldb.Put(bucket.Key([]byte("last")), value)
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Put returned "+
+			"unexpected error: %s", err)
+	}
+
+	stats, err := ldb.Stats()
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Stats unexpectedly "+
+			"failed: %s", err)
+	}
+	if len(stats.Levels) == 0 {
+		t.Fatalf("TestLevelDBWithConfig: expected the write buffer "+
+			"to be flushed into a table, got stats:\n%s", stats)
+	}
+	// The values are all zeros, so compressed tables are far
+	// smaller than the values written to them
+	if stats.Levels[0].Size >= 2048*int64(len(value)) {
+		t.Fatalf("TestLevelDBWithConfig: expected the tables "+
+			"to be compressed, got stats:\n%s", stats)
+	}
+	err = ldb.Close()
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Close unexpectedly "+
+			"failed: %s", err)
+	}
+
+	// A database that was written with compression must remain readable
+	// without it
+	ldb, err = NewLevelDB(path, 8)
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: NewLevelDB unexpectedly "+
+			"failed: %s", err)
+	}
+	defer ldb.Close()
+	getData, err := ldb.Get(bucket.Key([]byte{0, 1}))
+	if err != nil {
+		t.Fatalf("TestLevelDBWithConfig: Get returned "+
+			"unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(getData, value) {
+		t.Fatalf("TestLevelDBWithConfig: get data and " +
+			"put data are not equal")
+	}
+}
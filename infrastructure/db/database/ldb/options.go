@@ -11,3 +11,50 @@ func Options() opt.Options {
 		NoSync:                 true,
 	}
 }
+
+// Config is the tunable configuration of a leveldb instance.
+type Config struct {
+	// CacheSizeMiB is the capacity of the block cache
+	CacheSizeMiB int
+
+	// WriteBufferSizeMiB is the size of the in-memory table that writes
+	// are buffered in before they're flushed to disk. If it's 0, half of
+	// CacheSizeMiB is used
+	WriteBufferSizeMiB int
+
+	// Compression enables Snappy compression of new tables. Tables that
+	// were written with a different setting remain readable, and are
+	// rewritten with the new setting as they're compacted
+	Compression bool
+
+	// SyncWrites makes every write fsync the write-ahead log before it
+	// returns, so that committed writes survive a crash of the machine and
+	// not only of the process
+	SyncWrites bool
+}
+
+// DefaultConfig returns the configuration that NewLevelDB uses for the
+// given cache size.
+func DefaultConfig(cacheSizeMiB int) *Config {
+	return &Config{CacheSizeMiB: cacheSizeMiB}
+}
+
+func (config *Config) options() opt.Options {
+	options := Options()
+	options.BlockCacheCapacity = config.CacheSizeMiB * opt.MiB
+	options.WriteBuffer = config.CacheSizeMiB * opt.MiB / 2
+	if config.WriteBufferSizeMiB != 0 {
+		options.WriteBuffer = config.WriteBufferSizeMiB * opt.MiB
+	}
+	if config.Compression {
+		options.Compression = opt.SnappyCompression
+	}
+	if config.SyncWrites {
+		options.NoSync = false
+	}
+	return options
+}
+
+func (config *Config) writeOptions() *opt.WriteOptions {
+	return &opt.WriteOptions{Sync: config.SyncWrites}
+}
@@ -0,0 +1,94 @@
+package ldb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// LevelStats are the statistics of a single level of a leveldb instance.
+type LevelStats struct {
+	Level      int
+	TableCount int
+	Size       int64
+
+	// ReadBytes, WriteBytes and CompactionDuration are the totals of the
+	// compactions into this level since the database was opened
+	ReadBytes          int64
+	WriteBytes         int64
+	CompactionDuration time.Duration
+}
+
+// Stats are the statistics of a leveldb instance since it was opened.
+type Stats struct {
+	Levels []*LevelStats
+
+	MemoryCompactionCount    uint32
+	Level0CompactionCount    uint32
+	NonLevel0CompactionCount uint32
+	SeekCompactionCount      uint32
+
+	// WriteDelayCount and WriteDelayDuration measure how often and for how
+	// long writes were stalled waiting for compactions
+	WriteDelayCount    int32
+	WriteDelayDuration time.Duration
+
+	BlockCacheSize   int
+	OpenedTableCount int
+}
+
+// Stats returns the statistics of the leveldb instance.
+func (db *LevelDB) Stats() (*Stats, error) {
+	var dbStats leveldb.DBStats
+	err := db.ldb.Stats(&dbStats)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	levels := make([]*LevelStats, 0, len(dbStats.LevelSizes))
+	for level := range dbStats.LevelSizes {
+		// Levels that were never written to are omitted
+		if dbStats.LevelTablesCounts[level] == 0 && dbStats.LevelWrite[level] == 0 {
+			continue
+		}
+		levels = append(levels, &LevelStats{
+			Level:              level,
+			TableCount:         dbStats.LevelTablesCounts[level],
+			Size:               dbStats.LevelSizes[level],
+			ReadBytes:          dbStats.LevelRead[level],
+			WriteBytes:         dbStats.LevelWrite[level],
+			CompactionDuration: dbStats.LevelDurations[level],
+		})
+	}
+
+	return &Stats{
+		Levels:                   levels,
+		MemoryCompactionCount:    dbStats.MemComp,
+		Level0CompactionCount:    dbStats.Level0Comp,
+		NonLevel0CompactionCount: dbStats.NonLevel0Comp,
+		SeekCompactionCount:      dbStats.SeekComp,
+		WriteDelayCount:          dbStats.WriteDelayCount,
+		WriteDelayDuration:       dbStats.WriteDelayDuration,
+		BlockCacheSize:           dbStats.BlockCacheSize,
+		OpenedTableCount:         dbStats.OpenedTablesCount,
+	}, nil
+}
+
+// String returns a multi-line, human readable summary of the statistics.
+func (stats *Stats) String() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Compactions: %d memory, %d level-0, %d non-level-0, %d seek. "+
+		"Write delays: %d, totaling %s. Block cache: %d bytes. Open tables: %d",
+		stats.MemoryCompactionCount, stats.Level0CompactionCount, stats.NonLevel0CompactionCount,
+		stats.SeekCompactionCount, stats.WriteDelayCount, stats.WriteDelayDuration,
+		stats.BlockCacheSize, stats.OpenedTableCount)
+	for _, level := range stats.Levels {
+		fmt.Fprintf(builder, "\nLevel %d: %d tables, %d bytes. Compactions read %d bytes and "+
+			"wrote %d bytes in %s", level.Level, level.TableCount, level.Size,
+			level.ReadBytes, level.WriteBytes, level.CompactionDuration)
+	}
+	return builder.String()
+}
@@ -0,0 +1,78 @@
+package logdb
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// Backup writes every record of the database, as it was when Backup was
+// called, into a new logdb instance in the given path. It reads through a
+// cursor, which iterates over a snapshot of the index, so the database may be
+// written to while the backup is written. The path must not exist yet.
+func (db *LogDB) Backup(path string) (*database.BackupResult, error) {
+	err := database.CheckBackupPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := db.Cursor(database.MakeBucket(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	backupDB, err := NewLogDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := copyCursor(cursor, backupDB)
+	if err != nil {
+		backupDB.Close()
+		return nil, err
+	}
+
+	// Unlike the writes to the database itself, the backup is synced to
+	// disk, since it's only useful if it survives a crash of the machine
+	for _, segment := range backupDB.segments {
+		err := segment.file.Sync()
+		if err != nil {
+			backupDB.Close()
+			return nil, errors.WithStack(err)
+		}
+	}
+	err = backupDB.Close()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func copyCursor(cursor database.Cursor, backupDB *LogDB) (*database.BackupResult, error) {
+	counter := &database.BackupCounter{}
+	var batch []*operation
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		keyBytes := key.Bytes()
+		batch = append(batch, &operation{kind: operationPut, key: keyBytes, value: value})
+		if counter.AddRecord(keyBytes, value) {
+			err := backupDB.write(batch)
+			if err != nil {
+				return nil, err
+			}
+			batch = nil
+		}
+	}
+	err := backupDB.write(batch)
+	if err != nil {
+		return nil, err
+	}
+	return counter.Result(), nil
+}
@@ -0,0 +1,135 @@
+package logdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// LogDBCursor iterates over a snapshot of the keys of a bucket, taken when
+// the cursor was opened. Writes to the database after that are not visible
+// through the cursor.
+type LogDBCursor struct {
+	db       *LogDB
+	bucket   *database.Bucket
+	prefix   string
+	iterator *indexIterator
+
+	isStarted bool
+	isClosed  bool
+}
+
+// Cursor begins a new cursor over the given prefix.
+func (db *LogDB) Cursor(bucket *database.Bucket) (database.Cursor, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.isClosed {
+		return nil, errors.New("cannot open a cursor from a closed database")
+	}
+	db.openCursorCount++
+
+	return &LogDBCursor{
+		db:       db,
+		bucket:   bucket,
+		prefix:   string(bucket.Path()),
+		iterator: newIndexIterator(db.index),
+	}, nil
+}
+
+// isInBucket returns whether the iterator points to a key in the bucket
+func (c *LogDBCursor) isInBucket() bool {
+	return c.iterator.current != nil && strings.HasPrefix(c.iterator.current.key, c.prefix)
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted. Panics if the cursor is closed.
+func (c *LogDBCursor) Next() bool {
+	if c.isClosed {
+		panic("cannot call next on a closed cursor")
+	}
+	if !c.isStarted {
+		return c.First()
+	}
+	return c.iterator.next() && c.isInBucket()
+}
+
+// First moves the iterator to the first key/value pair. It returns false if
+// such a pair does not exist. Panics if the cursor is closed.
+func (c *LogDBCursor) First() bool {
+	if c.isClosed {
+		panic("cannot call first on a closed cursor")
+	}
+	c.isStarted = true
+	return c.iterator.seek(c.prefix) && c.isInBucket()
+}
+
+// Seek moves the iterator to the first key/value pair whose key is greater
+// than or equal to the given key. It returns ErrNotFound if such pair does not
+// exist.
+func (c *LogDBCursor) Seek(key *database.Key) error {
+	if c.isClosed {
+		return errors.New("cannot seek a closed cursor")
+	}
+	c.isStarted = true
+
+	found := c.iterator.seek(string(key.Bytes())) && c.isInBucket()
+	if !found || c.iterator.current.key != string(key.Bytes()) {
+		return errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+	return nil
+}
+
+// Key returns the key of the current key/value pair, or ErrNotFound if done.
+// Note that the key is trimmed to not include the prefix the cursor was opened
+// with.
+func (c *LogDBCursor) Key() (*database.Key, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the key of a closed cursor")
+	}
+	if !c.isInBucket() {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"key of an exhausted cursor")
+	}
+	suffix := bytes.TrimPrefix([]byte(c.iterator.current.key), c.bucket.Path())
+	return c.bucket.Key(suffix), nil
+}
+
+// Value returns the value of the current key/value pair, or ErrNotFound if done.
+func (c *LogDBCursor) Value() ([]byte, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the value of a closed cursor")
+	}
+	if !c.isInBucket() {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"value of an exhausted cursor")
+	}
+
+	// The database lock guards against reading a segment that's being
+	// closed by the database
+	c.db.lock.RLock()
+	defer c.db.lock.RUnlock()
+	if c.db.isClosed {
+		return nil, errors.New("cannot get the value from a closed database")
+	}
+	return readValue(c.iterator.current.pointer)
+}
+
+// Close releases associated resources.
+func (c *LogDBCursor) Close() error {
+	if c.isClosed {
+		return errors.New("cannot close an already closed cursor")
+	}
+	c.isClosed = true
+	c.iterator = nil
+
+	c.db.lock.Lock()
+	defer c.db.lock.Unlock()
+	c.db.openCursorCount--
+	if c.db.openCursorCount == 0 {
+		return c.db.removeObsoleteSegments()
+	}
+	return nil
+}
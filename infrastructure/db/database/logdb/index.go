@@ -0,0 +1,168 @@
+package logdb
+
+// The index maps every key in the database to the location of its value in the
+// segment files. It's a persistent treap: nodes are never modified once they're
+// part of a tree, and every insertion or removal returns a new root that shares
+// all the untouched nodes with the previous one. This makes taking a snapshot
+// of the index, as cursors do, as cheap as copying its root.
+
+// valuePointer is the location of a value in the segment files
+type valuePointer struct {
+	segment *segment
+	offset  int64
+	size    uint32
+}
+
+type indexNode struct {
+	key      string
+	priority uint64
+	pointer  valuePointer
+	left     *indexNode
+	right    *indexNode
+}
+
+// indexGet returns the pointer to the value of the given key, or false if
+// the key is not in the index
+func indexGet(root *indexNode, key string) (valuePointer, bool) {
+	node := root
+	for node != nil {
+		switch {
+		case key < node.key:
+			node = node.left
+		case key > node.key:
+			node = node.right
+		default:
+			return node.pointer, true
+		}
+	}
+	return valuePointer{}, false
+}
+
+// indexInsert returns the root of a new index in which key points to pointer
+func indexInsert(root *indexNode, key string, priority uint64, pointer valuePointer) *indexNode {
+	if root == nil {
+		return &indexNode{key: key, priority: priority, pointer: pointer}
+	}
+
+	// All the nodes along the path to key are copied, so the rotations
+	// below never modify a node that belongs to another version of the index
+	newRoot := *root
+	switch {
+	case key < root.key:
+		newRoot.left = indexInsert(root.left, key, priority, pointer)
+		if newRoot.left.priority > newRoot.priority {
+			left := newRoot.left
+			newRoot.left = left.right
+			left.right = &newRoot
+			return left
+		}
+	case key > root.key:
+		newRoot.right = indexInsert(root.right, key, priority, pointer)
+		if newRoot.right.priority > newRoot.priority {
+			right := newRoot.right
+			newRoot.right = right.left
+			right.left = &newRoot
+			return right
+		}
+	default:
+		newRoot.pointer = pointer
+	}
+	return &newRoot
+}
+
+// indexRemove returns the root of a new index without the given key
+func indexRemove(root *indexNode, key string) *indexNode {
+	if root == nil {
+		return nil
+	}
+	switch {
+	case key < root.key:
+		left := indexRemove(root.left, key)
+		if left == root.left {
+			return root
+		}
+		newRoot := *root
+		newRoot.left = left
+		return &newRoot
+	case key > root.key:
+		right := indexRemove(root.right, key)
+		if right == root.right {
+			return root
+		}
+		newRoot := *root
+		newRoot.right = right
+		return &newRoot
+	default:
+		return indexMerge(root.left, root.right)
+	}
+}
+
+// indexMerge merges two indexes, where all the keys of left are smaller
+// than all the keys of right
+func indexMerge(left *indexNode, right *indexNode) *indexNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.priority > right.priority {
+		newLeft := *left
+		newLeft.right = indexMerge(left.right, right)
+		return &newLeft
+	}
+	newRight := *right
+	newRight.left = indexMerge(left, right.left)
+	return &newRight
+}
+
+// indexIterator iterates over the keys of a snapshot of the index in order
+type indexIterator struct {
+	root    *indexNode
+	stack   []*indexNode
+	current *indexNode
+}
+
+func newIndexIterator(root *indexNode) *indexIterator {
+	return &indexIterator{root: root}
+}
+
+// seek moves the iterator to the first node whose key is greater than or
+// equal to the given key. It returns false if there's no such node
+func (it *indexIterator) seek(key string) bool {
+	it.stack = it.stack[:0]
+	node := it.root
+	for node != nil {
+		if node.key >= key {
+			it.stack = append(it.stack, node)
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return it.pop()
+}
+
+// next moves the iterator to the next node. It returns false if the
+// iterator is exhausted
+func (it *indexIterator) next() bool {
+	if it.current == nil {
+		return false
+	}
+	node := it.current.right
+	for node != nil {
+		it.stack = append(it.stack, node)
+		node = node.left
+	}
+	return it.pop()
+}
+
+func (it *indexIterator) pop() bool {
+	if len(it.stack) == 0 {
+		it.current = nil
+		return false
+	}
+	it.current = it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	return true
+}
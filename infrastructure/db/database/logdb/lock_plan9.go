@@ -0,0 +1,18 @@
+package logdb
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// lockDirectory takes an exclusive lock on the given lock file, so that the
+// database can't be opened by two processes at once
+func lockDirectory(lockFilePath string) (io.Closer, error) {
+	file, err := os.OpenFile(lockFilePath, os.O_RDWR|os.O_CREATE, os.ModeExclusive|0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "the database is used by another process")
+	}
+	return file, nil
+}
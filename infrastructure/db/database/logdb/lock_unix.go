@@ -0,0 +1,27 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logdb
+
+import (
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// lockDirectory takes an exclusive lock on the given lock file, so that the
+// database can't be opened by two processes at once
+func lockDirectory(lockFilePath string) (io.Closer, error) {
+	file, err := os.OpenFile(lockFilePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "the database is used by another process")
+	}
+	return file, nil
+}
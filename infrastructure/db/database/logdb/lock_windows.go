@@ -0,0 +1,26 @@
+package logdb
+
+import (
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// lockDirectory takes an exclusive lock on the given lock file, so that the
+// database can't be opened by two processes at once
+func lockDirectory(lockFilePath string) (io.Closer, error) {
+	path, err := syscall.UTF16PtrFromString(lockFilePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// A share mode of 0 denies other processes from opening the file
+	// for as long as it's open
+	handle, err := syscall.CreateFile(path, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "the database is used by another process")
+	}
+	return os.NewFile(uintptr(handle), lockFilePath), nil
+}
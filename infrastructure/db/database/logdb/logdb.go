@@ -0,0 +1,496 @@
+package logdb
+
+import (
+	"hash/maphash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+const (
+	// markerFileName is the name of the file that marks a directory as a
+	// logdb database, and holds the version of its format
+	markerFileName = "LOGDB"
+	formatVersion  = 1
+
+	lockFileName = "LOCK"
+)
+
+// maxSegmentSize is the size after which the active segment is closed
+// for writing, and a new segment is started
+var maxSegmentSize int64 = 64 * 1024 * 1024
+
+// LogDB is a pure-Go, log-structured database. All writes are appended to
+// segment files, and an in-memory index of all the keys in the database points
+// to the location of their values in these files. Segments that are mostly
+// taken by values that were since overwritten or deleted are compacted as
+// the database is written to.
+//
+// Since the index holds all the keys, the memory used by LogDB grows with the
+// number of keys in the database, and opening the database requires reading
+// all of its segments.
+type LogDB struct {
+	path       string
+	isReadOnly bool
+	lockFile   io.Closer
+
+	lock     sync.RWMutex
+	isClosed bool
+
+	index     *indexNode
+	indexSeed maphash.Seed
+
+	segments      []*segment
+	activeSegment *segment
+
+	// obsoleteSegments are segments that were compacted, but may still be
+	// read by open cursors. They're removed once all cursors are closed
+	obsoleteSegments []*segment
+	openCursorCount  int
+}
+
+// NewLogDB opens a logdb instance defined by the given path. If it doesn't
+// exist, it's created.
+func NewLogDB(path string) (*LogDB, error) {
+	return open(path, false)
+}
+
+// NewLogDBReadOnly opens the existing logdb instance defined by the given
+// path for reading only.
+func NewLogDBReadOnly(path string) (*LogDB, error) {
+	return open(path, true)
+}
+
+// IsLogDB returns whether the given path holds a logdb instance.
+func IsLogDB(path string) bool {
+	_, err := os.Stat(filepath.Join(path, markerFileName))
+	return err == nil
+}
+
+func open(path string, isReadOnly bool) (*LogDB, error) {
+	if isReadOnly {
+		_, err := os.Stat(filepath.Join(path, markerFileName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, errors.Errorf("there's no database in %s", path)
+			}
+			return nil, errors.WithStack(err)
+		}
+	} else {
+		err := os.MkdirAll(path, 0700)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	lockFile, err := lockDirectory(filepath.Join(path, lockFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to lock the database in %s", path)
+	}
+
+	db := &LogDB{
+		path:       path,
+		isReadOnly: isReadOnly,
+		lockFile:   lockFile,
+		indexSeed:  maphash.MakeSeed(),
+	}
+	err = db.load()
+	if err != nil {
+		db.closeFiles()
+		return nil, err
+	}
+	return db, nil
+}
+
+// load checks the format of the database and loads its segments. A new
+// database is initialized
+func (db *LogDB) load() error {
+	markerPath := filepath.Join(db.path, markerFileName)
+	markerBytes, err := ioutil.ReadFile(markerPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+		if db.isReadOnly {
+			return errors.Errorf("there's no database in %s", db.path)
+		}
+		segmentIDs, err := listSegmentIDs(db.path)
+		if err != nil {
+			return err
+		}
+		if len(segmentIDs) > 0 {
+			return errors.Errorf("the database in %s is missing its %s file", db.path, markerFileName)
+		}
+		err = ioutil.WriteFile(markerPath, []byte(strconv.Itoa(formatVersion)), 0600)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	} else {
+		version, err := strconv.Atoi(strings.TrimSpace(string(markerBytes)))
+		if err != nil || version != formatVersion {
+			return errors.Errorf("the database in %s has an unsupported format version %q",
+				db.path, string(markerBytes))
+		}
+	}
+
+	segmentIDs, err := listSegmentIDs(db.path)
+	if err != nil {
+		return err
+	}
+	for i, segmentID := range segmentIDs {
+		isLastSegment := i == len(segmentIDs)-1
+		err := db.loadSegment(segmentID, isLastSegment)
+		if err != nil {
+			return err
+		}
+	}
+
+	if db.isReadOnly {
+		return nil
+	}
+	if len(db.segments) == 0 {
+		return db.startNewSegment()
+	}
+	db.activeSegment = db.segments[len(db.segments)-1]
+	return nil
+}
+
+// loadSegment reads the given segment, and applies its batches to the index.
+// A batch that was only partially written at the end of the last segment is
+// discarded
+func (db *LogDB) loadSegment(segmentID uint32, isLastSegment bool) error {
+	segmentPath := filepath.Join(db.path, segmentFileName(segmentID))
+	flag := os.O_RDWR
+	if db.isReadOnly {
+		flag = os.O_RDONLY
+	}
+	file, err := os.OpenFile(segmentPath, flag, 0600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	segment := &segment{id: segmentID, file: file}
+	db.segments = append(db.segments, segment)
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for segment.size < int64(len(data)) {
+		batchOffset := segment.size
+		batchSize, err := decodeBatch(data[batchOffset:], func(op *operation, valueOffset int64) error {
+			db.applyOperation(op, valuePointer{
+				segment: segment,
+				offset:  batchOffset + valueOffset,
+				size:    uint32(len(op.value)),
+			})
+			return nil
+		})
+		if errors.Is(err, errIncompleteBatch) && isLastSegment {
+			log.Warnf("Discarding an incomplete write of %d bytes at the end of %s",
+				int64(len(data))-batchOffset, segmentPath)
+			if !db.isReadOnly {
+				err := file.Truncate(batchOffset)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(err, "the database segment %s is corrupted at offset %d", segmentPath, batchOffset)
+		}
+		segment.size += batchSize
+	}
+	return nil
+}
+
+func (db *LogDB) startNewSegment() error {
+	segmentID := uint32(1)
+	if len(db.segments) > 0 {
+		segmentID = db.segments[len(db.segments)-1].id + 1
+	}
+	segmentPath := filepath.Join(db.path, segmentFileName(segmentID))
+	file, err := os.OpenFile(segmentPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	segment := &segment{id: segmentID, file: file}
+	db.segments = append(db.segments, segment)
+	db.activeSegment = segment
+	return nil
+}
+
+// applyOperation applies the given operation to the index. For puts, pointer
+// is the location of the written value
+func (db *LogDB) applyOperation(op *operation, pointer valuePointer) {
+	key := string(op.key)
+	if previousPointer, ok := indexGet(db.index, key); ok {
+		previousPointer.segment.liveBytes -= int64(encodedOperationSize(
+			operationPut, len(key), int(previousPointer.size)))
+	}
+
+	if op.kind == operationDelete {
+		db.index = indexRemove(db.index, key)
+		return
+	}
+	db.index = indexInsert(db.index, key, db.indexPriority(key), pointer)
+	pointer.segment.liveBytes += int64(op.encodedSize())
+}
+
+// indexPriority returns the priority of the given key in the index. Hashing
+// the key with a random seed keeps the index balanced regardless of the keys
+func (db *LogDB) indexPriority(key string) uint64 {
+	var hash maphash.Hash
+	hash.SetSeed(db.indexSeed)
+	hash.WriteString(key)
+	return hash.Sum64()
+}
+
+// write appends the given operations to the active segment as a single batch,
+// and applies them to the index
+func (db *LogDB) write(operations []*operation) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.isClosed {
+		return errors.New("cannot write to a closed database")
+	}
+	if db.isReadOnly {
+		return errors.New("cannot write to a read-only database")
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+
+	err := db.appendBatch(operations)
+	if err != nil {
+		return err
+	}
+
+	if db.activeSegment.size >= maxSegmentSize {
+		err := db.startNewSegment()
+		if err != nil {
+			return err
+		}
+	}
+	return db.compactIfNeeded()
+}
+
+func (db *LogDB) appendBatch(operations []*operation) error {
+	segment := db.activeSegment
+	batch, valueOffsets := encodeBatch(operations)
+	_, err := segment.file.WriteAt(batch, segment.size)
+	if err != nil {
+		// Make sure that a partially written batch doesn't precede
+		// the batches that are written after it
+		truncateErr := segment.file.Truncate(segment.size)
+		if truncateErr != nil {
+			log.Errorf("Failed to truncate %s after a failed write: %s", segment.file.Name(), truncateErr)
+		}
+		return errors.WithStack(err)
+	}
+
+	batchOffset := segment.size
+	segment.size += int64(len(batch))
+	for i, op := range operations {
+		db.applyOperation(op, valuePointer{
+			segment: segment,
+			offset:  batchOffset + valueOffsets[i],
+			size:    uint32(len(op.value)),
+		})
+	}
+	return nil
+}
+
+// readValue reads the value that the given pointer points to
+func readValue(pointer valuePointer) ([]byte, error) {
+	value := make([]byte, pointer.size)
+	_, err := pointer.segment.file.ReadAt(value, pointer.offset)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return value, nil
+}
+
+// Close closes the logdb instance.
+func (db *LogDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.isClosed {
+		return errors.New("cannot close an already closed database")
+	}
+	db.isClosed = true
+
+	// Cursors can't read from a closed database, so the segments that they
+	// kept from being removed can be removed now
+	removeErr := db.removeObsoleteSegments()
+	closeErr := db.closeFiles()
+	if removeErr != nil {
+		return removeErr
+	}
+	return closeErr
+}
+
+func (db *LogDB) closeFiles() error {
+	var firstErr error
+	for _, segment := range db.segments {
+		err := segment.file.Close()
+		if err != nil && firstErr == nil {
+			firstErr = errors.WithStack(err)
+		}
+	}
+	err := db.lockFile.Close()
+	if err != nil && firstErr == nil {
+		firstErr = errors.WithStack(err)
+	}
+	return firstErr
+}
+
+// Put sets the value for the given key. It overwrites
+// any previous value for that key.
+func (db *LogDB) Put(key *database.Key, value []byte) error {
+	return db.write([]*operation{{kind: operationPut, key: key.Bytes(), value: value}})
+}
+
+// Get gets the value for the given key. It returns
+// ErrNotFound if the given key does not exist.
+func (db *LogDB) Get(key *database.Key) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.isClosed {
+		return nil, errors.New("cannot get from a closed database")
+	}
+	pointer, ok := indexGet(db.index, string(key.Bytes()))
+	if !ok {
+		return nil, errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+	return readValue(pointer)
+}
+
+// Has returns true if the database does contains the
+// given key.
+func (db *LogDB) Has(key *database.Key) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.isClosed {
+		return false, errors.New("cannot has from a closed database")
+	}
+	_, ok := indexGet(db.index, string(key.Bytes()))
+	return ok, nil
+}
+
+// Delete deletes the value for the given key. Will not
+// return an error if the key doesn't exist.
+func (db *LogDB) Delete(key *database.Key) error {
+	return db.write([]*operation{{kind: operationDelete, key: key.Bytes()}})
+}
+
+// compactIfNeeded compacts the first segment, other than the active one, of
+// which less than half is taken by live operations. At most one segment is
+// compacted per write, so that the cost of compaction is spread over writes
+func (db *LogDB) compactIfNeeded() error {
+	for _, segment := range db.segments {
+		if segment == db.activeSegment {
+			continue
+		}
+		if segment.liveBytes*2 < segment.size {
+			return db.compact(segment)
+		}
+	}
+	return nil
+}
+
+// compact copies the live operations of the given segment to the active
+// segment, and then removes it
+func (db *LogDB) compact(segment *segment) error {
+	data := make([]byte, segment.size)
+	_, err := segment.file.ReadAt(data, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	hasOlderSegments := db.segments[0] != segment
+	var liveOperations []*operation
+	for position := int64(0); position < int64(len(data)); {
+		batchOffset := position
+		batchSize, err := decodeBatch(data[batchOffset:], func(op *operation, valueOffset int64) error {
+			pointer, ok := indexGet(db.index, string(op.key))
+			switch op.kind {
+			case operationPut:
+				if ok && pointer.segment == segment && pointer.offset == batchOffset+valueOffset {
+					liveOperations = append(liveOperations, op)
+				}
+			case operationDelete:
+				// A deletion has to be kept for as long as there might be
+				// an older put of the same key in an older segment
+				if !ok && hasOlderSegments {
+					liveOperations = append(liveOperations, op)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to compact %s", segment.file.Name())
+		}
+		position += batchSize
+	}
+
+	if len(liveOperations) > 0 {
+		err := db.appendBatch(liveOperations)
+		if err != nil {
+			return err
+		}
+		// The copies must be durable before the segment is removed
+		err = db.activeSegment.file.Sync()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	log.Debugf("Compacted %s: copied %d live operations", segment.file.Name(), len(liveOperations))
+
+	for i, s := range db.segments {
+		if s == segment {
+			db.segments = append(db.segments[:i], db.segments[i+1:]...)
+			break
+		}
+	}
+	db.obsoleteSegments = append(db.obsoleteSegments, segment)
+	if db.openCursorCount == 0 {
+		err := db.removeObsoleteSegments()
+		if err != nil {
+			return err
+		}
+	}
+
+	if db.activeSegment.size >= maxSegmentSize {
+		return db.startNewSegment()
+	}
+	return nil
+}
+
+// removeObsoleteSegments removes the segments that were compacted. It must
+// only be called when there are no open cursors
+func (db *LogDB) removeObsoleteSegments() error {
+	for len(db.obsoleteSegments) > 0 {
+		segment := db.obsoleteSegments[0]
+		err := segment.file.Close()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = os.Remove(segment.file.Name())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		db.obsoleteSegments = db.obsoleteSegments[1:]
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package logdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+func prepareDatabaseForTest(t *testing.T, path string) (db *LogDB, teardownFunc func()) {
+	db, err := NewLogDB(path)
+	if err != nil {
+		t.Fatalf("NewLogDB unexpectedly failed: %s", err)
+	}
+	teardownFunc = func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatalf("Close unexpectedly failed: %s", err)
+		}
+	}
+	return db, teardownFunc
+}
+
+func testKey(i int) *database.Key {
+	var suffix [8]byte
+	binary.BigEndian.PutUint64(suffix[:], uint64(i))
+	return database.MakeBucket([]byte("test")).Key(suffix[:])
+}
+
+func testValue(i int, version int) []byte {
+	return []byte(fmt.Sprintf("value %d version %d", i, version))
+}
+
+func checkValue(t *testing.T, db database.DataAccessor, key *database.Key, expectedValue []byte) {
+	value, err := db.Get(key)
+	if expectedValue == nil {
+		if !database.IsNotFoundError(err) {
+			t.Fatalf("Get: expected key %s to be missing, got value %q and error: %v", key, value, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Get of key %s unexpectedly failed: %s", key, err)
+	}
+	if !bytes.Equal(value, expectedValue) {
+		t.Fatalf("Get: unexpected value for key %s. Want: %q, got: %q", key, expectedValue, value)
+	}
+}
+
+func TestLogDBReopen(t *testing.T) {
+	path := t.TempDir()
+	db, teardownFunc := prepareDatabaseForTest(t, path)
+
+	const keyCount = 100
+	for i := 0; i < keyCount; i++ {
+		err := db.Put(testKey(i), testValue(i, 0))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+	}
+	dbTx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %s", err)
+	}
+	for i := 0; i < keyCount; i += 2 {
+		err := dbTx.Put(testKey(i), testValue(i, 1))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+		err = dbTx.Delete(testKey(i + 1))
+		if err != nil {
+			t.Fatalf("Delete: %s", err)
+		}
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		t.Fatalf("Commit: %s", err)
+	}
+	teardownFunc()
+
+	_, err = NewLogDBReadOnly(filepath.Join(path, "missing"))
+	if err == nil {
+		t.Fatalf("NewLogDBReadOnly unexpectedly succeeded opening a missing database")
+	}
+
+	readOnlyDB, err := NewLogDBReadOnly(path)
+	if err != nil {
+		t.Fatalf("NewLogDBReadOnly: %s", err)
+	}
+	defer readOnlyDB.Close()
+	for i := 0; i < keyCount; i += 2 {
+		checkValue(t, readOnlyDB, testKey(i), testValue(i, 1))
+		checkValue(t, readOnlyDB, testKey(i+1), nil)
+	}
+	err = readOnlyDB.Put(testKey(0), testValue(0, 2))
+	if err == nil {
+		t.Fatalf("Put unexpectedly succeeded in a read-only database")
+	}
+
+	_, err = NewLogDB(path)
+	if err == nil {
+		t.Fatalf("NewLogDB unexpectedly succeeded opening a database that's already open")
+	}
+}
+
+func TestLogDBIncompleteWrite(t *testing.T) {
+	path := t.TempDir()
+	db, teardownFunc := prepareDatabaseForTest(t, path)
+	err := db.Put(testKey(0), testValue(0, 0))
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	err = db.Put(testKey(1), testValue(1, 0))
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	segmentPath := db.activeSegment.file.Name()
+	teardownFunc()
+
+	// Simulate a crash in the middle of the last write by cutting
+	// off the last byte of its batch
+	fileInfo, err := os.Stat(segmentPath)
+	if err != nil {
+		t.Fatalf("Stat: %s", err)
+	}
+	err = os.Truncate(segmentPath, fileInfo.Size()-1)
+	if err != nil {
+		t.Fatalf("Truncate: %s", err)
+	}
+
+	db, teardownFunc = prepareDatabaseForTest(t, path)
+	checkValue(t, db, testKey(0), testValue(0, 0))
+	checkValue(t, db, testKey(1), nil)
+
+	// Writes after the recovery must not be lost behind the discarded batch
+	err = db.Put(testKey(2), testValue(2, 0))
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+	teardownFunc()
+
+	db, teardownFunc = prepareDatabaseForTest(t, path)
+	defer teardownFunc()
+	checkValue(t, db, testKey(0), testValue(0, 0))
+	checkValue(t, db, testKey(2), testValue(2, 0))
+}
+
+func TestLogDBCompaction(t *testing.T) {
+	originalMaxSegmentSize := maxSegmentSize
+	maxSegmentSize = 1024
+	defer func() { maxSegmentSize = originalMaxSegmentSize }()
+
+	path := t.TempDir()
+	db, teardownFunc := prepareDatabaseForTest(t, path)
+
+	// Overwrite a small set of keys many times, so that most of every
+	// segment but the active one is taken by overwritten values
+	const keyCount = 10
+	const versionCount = 40
+	for version := 0; version < versionCount; version++ {
+		for i := 0; i < keyCount; i++ {
+			err := db.Put(testKey(i), testValue(i, version))
+			if err != nil {
+				t.Fatalf("Put: %s", err)
+			}
+		}
+	}
+	// Delete a key whose previous values may only remain in segments
+	// that weren't compacted yet
+	err := db.Delete(testKey(0))
+	if err != nil {
+		t.Fatalf("Delete: %s", err)
+	}
+
+	segmentIDs, err := listSegmentIDs(path)
+	if err != nil {
+		t.Fatalf("listSegmentIDs: %s", err)
+	}
+	if len(segmentIDs) > 3 {
+		t.Fatalf("Expected segments to be compacted, but there are %d segments", len(segmentIDs))
+	}
+	teardownFunc()
+
+	db, teardownFunc = prepareDatabaseForTest(t, path)
+	defer teardownFunc()
+	checkValue(t, db, testKey(0), nil)
+	for i := 1; i < keyCount; i++ {
+		checkValue(t, db, testKey(i), testValue(i, versionCount-1))
+	}
+}
+
+func TestLogDBCursorSnapshot(t *testing.T) {
+	originalMaxSegmentSize := maxSegmentSize
+	maxSegmentSize = 1024
+	defer func() { maxSegmentSize = originalMaxSegmentSize }()
+
+	db, teardownFunc := prepareDatabaseForTest(t, t.TempDir())
+	defer teardownFunc()
+
+	const keyCount = 10
+	for i := 0; i < keyCount; i++ {
+		err := db.Put(testKey(i), testValue(i, 0))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+	}
+
+	cursor, err := db.Cursor(testKey(0).Bucket())
+	if err != nil {
+		t.Fatalf("Cursor: %s", err)
+	}
+
+	// Overwrite and delete the keys enough times for the segments that the
+	// cursor reads from to be compacted
+	for version := 1; version < 40; version++ {
+		for i := 0; i < keyCount; i++ {
+			err := db.Put(testKey(i), testValue(i, version))
+			if err != nil {
+				t.Fatalf("Put: %s", err)
+			}
+		}
+	}
+	for i := 0; i < keyCount; i += 2 {
+		err := db.Delete(testKey(i))
+		if err != nil {
+			t.Fatalf("Delete: %s", err)
+		}
+	}
+
+	i := 0
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			t.Fatalf("Key: %s", err)
+		}
+		if !bytes.Equal(key.Bytes(), testKey(i).Bytes()) {
+			t.Fatalf("Unexpected key. Want: %s, got: %s", testKey(i), key)
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			t.Fatalf("Value: %s", err)
+		}
+		if !bytes.Equal(value, testValue(i, 0)) {
+			t.Fatalf("Unexpected value for key %s. Want: %q, got: %q", key, testValue(i, 0), value)
+		}
+		i++
+	}
+	if i != keyCount {
+		t.Fatalf("Unexpected number of keys in the cursor. Want: %d, got: %d", keyCount, i)
+	}
+
+	if len(db.obsoleteSegments) == 0 {
+		t.Fatalf("Expected compacted segments to be kept while the cursor is open")
+	}
+	err = cursor.Close()
+	if err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	if len(db.obsoleteSegments) != 0 {
+		t.Fatalf("Expected compacted segments to be removed once the cursor is closed")
+	}
+}
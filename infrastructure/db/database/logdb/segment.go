@@ -0,0 +1,199 @@
+package logdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The database is kept in a series of append-only segment files. Every write
+// to the database appends a single batch to the active segment, which is the
+// one with the highest ID. A batch is encoded as follows:
+//
+//	payload length (4 bytes, little endian)
+//	CRC-32C of the payload (4 bytes, little endian)
+//	payload: a series of operations, each of which is either
+//		operationPut, key length (uvarint), key, value length (uvarint), value
+//		operationDelete, key length (uvarint), key
+//
+// A batch is applied in its entirety or not at all: a batch that was only
+// partially written when the node crashed fails its checksum, and is
+// discarded when the database is opened.
+
+const (
+	segmentFileExtension = ".seg"
+	batchHeaderSize      = 8
+
+	operationPut    = byte(1)
+	operationDelete = byte(2)
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// segment is a single segment file
+type segment struct {
+	id   uint32
+	file *os.File
+
+	// size is the number of bytes that were written to the segment
+	size int64
+
+	// liveBytes is the number of bytes of the segment that are taken by
+	// operations that are still in effect, that is puts that weren't
+	// overwritten or deleted since
+	liveBytes int64
+}
+
+func segmentFileName(id uint32) string {
+	return fmt.Sprintf("%08d%s", id, segmentFileExtension)
+}
+
+// listSegmentIDs returns the IDs of the segment files in the given
+// directory, in ascending order
+func listSegmentIDs(path string) ([]uint32, error) {
+	fileInfos, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var ids []uint32
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !strings.HasSuffix(name, segmentFileExtension) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentFileExtension), 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("unexpected segment file name %s", filepath.Join(path, name))
+		}
+		ids = append(ids, uint32(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// operation is a single put or delete of a batch
+type operation struct {
+	kind  byte
+	key   []byte
+	value []byte
+}
+
+// encodedSize returns the number of bytes the operation takes in a batch
+func (op *operation) encodedSize() int {
+	return encodedOperationSize(op.kind, len(op.key), len(op.value))
+}
+
+func encodedOperationSize(kind byte, keyLength int, valueLength int) int {
+	size := 1 + uvarintSize(uint64(keyLength)) + keyLength
+	if kind == operationPut {
+		size += uvarintSize(uint64(valueLength)) + valueLength
+	}
+	return size
+}
+
+func uvarintSize(x uint64) int {
+	var buffer [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buffer[:], x)
+}
+
+// encodeBatch encodes the given operations as a batch. It also returns, for
+// every operation, the offset of its value within the batch
+func encodeBatch(operations []*operation) (batch []byte, valueOffsets []int64) {
+	payloadSize := 0
+	for _, op := range operations {
+		payloadSize += op.encodedSize()
+	}
+
+	batch = make([]byte, batchHeaderSize, batchHeaderSize+payloadSize)
+	valueOffsets = make([]int64, len(operations))
+	for i, op := range operations {
+		batch = append(batch, op.kind)
+		batch = appendUvarint(batch, uint64(len(op.key)))
+		batch = append(batch, op.key...)
+		if op.kind == operationPut {
+			batch = appendUvarint(batch, uint64(len(op.value)))
+			valueOffsets[i] = int64(len(batch))
+			batch = append(batch, op.value...)
+		}
+	}
+
+	payload := batch[batchHeaderSize:]
+	binary.LittleEndian.PutUint32(batch[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(batch[4:8], crc32.Checksum(payload, crcTable))
+	return batch, valueOffsets
+}
+
+func appendUvarint(buffer []byte, x uint64) []byte {
+	var varint [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(varint[:], x)
+	return append(buffer, varint[:n]...)
+}
+
+// errIncompleteBatch is returned from decodeBatch when the data ends in
+// the middle of a batch, or when the batch fails its checksum
+var errIncompleteBatch = errors.New("incomplete batch")
+
+// decodeBatch decodes the batch at the start of data. It calls handleOperation
+// for every operation of the batch, along with the offset of its value within
+// data, and returns the size of the batch
+func decodeBatch(data []byte, handleOperation func(op *operation, valueOffset int64) error) (int64, error) {
+	if len(data) < batchHeaderSize {
+		return 0, errIncompleteBatch
+	}
+	payloadLength := int64(binary.LittleEndian.Uint32(data[0:4]))
+	if int64(len(data)-batchHeaderSize) < payloadLength {
+		return 0, errIncompleteBatch
+	}
+	payload := data[batchHeaderSize : batchHeaderSize+payloadLength]
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(data[4:8]) {
+		return 0, errIncompleteBatch
+	}
+
+	position := int64(0)
+	readBytes := func() ([]byte, error) {
+		length, n := binary.Uvarint(payload[position:])
+		if n <= 0 || uint64(int64(len(payload))-position-int64(n)) < length {
+			return nil, errors.New("malformed batch")
+		}
+		position += int64(n)
+		bytes := payload[position : position+int64(length)]
+		position += int64(length)
+		return bytes, nil
+	}
+	for position < payloadLength {
+		op := &operation{kind: payload[position]}
+		position++
+		if op.kind != operationPut && op.kind != operationDelete {
+			return 0, errors.Errorf("malformed batch: unknown operation %d", op.kind)
+		}
+		key, err := readBytes()
+		if err != nil {
+			return 0, err
+		}
+		op.key = key
+
+		valueOffset := int64(0)
+		if op.kind == operationPut {
+			// The value starts right after its length
+			lengthPosition := position
+			value, err := readBytes()
+			if err != nil {
+				return 0, err
+			}
+			op.value = value
+			valueOffset = batchHeaderSize + lengthPosition + int64(uvarintSize(uint64(len(value))))
+		}
+		err = handleOperation(op, valueOffset)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return batchHeaderSize + payloadLength, nil
+}
@@ -0,0 +1,150 @@
+package migration
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// checkpointKey holds the progress of the migration step that's currently
+// running, so that the step can resume where it stopped if it's interrupted
+var checkpointKey = database.MakeBucket(nil).Key([]byte("migration-checkpoint"))
+
+// Step migrates the database from version Version-1 to version Version.
+//
+// Steps must be resumable: a step that's interrupted is run again the next
+// time the node starts, and is given the last checkpoint it saved through
+// StepContext.Commit. Changes that were made but not yet committed together
+// with a checkpoint may or may not have been written, so a step must also be
+// able to redo the work that follows its last checkpoint.
+type Step struct {
+	Version     int
+	Description string
+	Migrate     func(context *StepContext) error
+}
+
+// Registry holds the migration steps of a database, by the version
+// they migrate to
+type Registry struct {
+	steps map[int]*Step
+}
+
+// NewRegistry returns a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		steps: make(map[int]*Step),
+	}
+}
+
+// Register adds the given step to the registry. It panics if a step that
+// migrates to the same version was already registered
+func (r *Registry) Register(step *Step) {
+	if _, ok := r.steps[step.Version]; ok {
+		panic(errors.Errorf("a migration step to version %d is already registered", step.Version))
+	}
+	r.steps[step.Version] = step
+}
+
+// Plan returns the steps needed to migrate a database from fromVersion to
+// toVersion, in the order they need to run. It returns an error if any of
+// these steps is missing, or if toVersion is older than fromVersion
+func (r *Registry) Plan(fromVersion int, toVersion int) ([]*Step, error) {
+	if toVersion < fromVersion {
+		return nil, errors.Errorf("cannot migrate the database from version %d to the older "+
+			"version %d", fromVersion, toVersion)
+	}
+
+	steps := make([]*Step, 0, toVersion-fromVersion)
+	for version := fromVersion + 1; version <= toVersion; version++ {
+		step, ok := r.steps[version]
+		if !ok {
+			return nil, errors.Errorf("cannot migrate the database from version %d to version %d: "+
+				"there's no migration step to version %d", fromVersion, toVersion, version)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// Check is the dry-run mode of Migrate. It logs the steps needed to migrate db
+// from fromVersion to toVersion, and whether a previous migration was
+// interrupted, without changing the database. It returns the same errors
+// Migrate would return before running any step
+func (r *Registry) Check(db database.DataAccessor, fromVersion int, toVersion int) ([]*Step, error) {
+	steps, err := r.Plan(fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		log.Infof("The database is at version %d. No migration is needed", fromVersion)
+		return steps, nil
+	}
+
+	checkpoint, err := readCheckpoint(db)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Migrating the database from version %d to version %d requires %d steps:",
+		fromVersion, toVersion, len(steps))
+	for _, step := range steps {
+		resumeNote := ""
+		if checkpoint != nil && checkpoint.version == step.Version {
+			resumeNote = " (was interrupted, and will resume from its last checkpoint)"
+		}
+		log.Infof("Migration to version %d: %s%s", step.Version, step.Description, resumeNote)
+	}
+	return steps, nil
+}
+
+// Migrate runs the steps needed to migrate db from fromVersion to toVersion,
+// in order. After each step completes, setVersion is called with the version
+// the database was migrated to. setVersion should persist the version, so
+// that completed steps don't run again
+func (r *Registry) Migrate(db database.Database, fromVersion int, toVersion int,
+	setVersion func(version int) error) error {
+
+	steps, err := r.Plan(fromVersion, toVersion)
+	if err != nil {
+		return err
+	}
+
+	checkpoint, err := readCheckpoint(db)
+	if err != nil {
+		return err
+	}
+	// Any step that runs may leave a checkpoint of its own behind
+	shouldDeleteCheckpoint := checkpoint != nil || len(steps) > 0
+
+	for i, step := range steps {
+		context := &StepContext{
+			db:      db,
+			version: step.Version,
+		}
+		if checkpoint != nil && checkpoint.version == step.Version {
+			log.Infof("Resuming migration to version %d (step %d/%d): %s",
+				step.Version, i+1, len(steps), step.Description)
+			context.checkpoint = checkpoint.data
+		} else {
+			log.Infof("Running migration to version %d (step %d/%d): %s",
+				step.Version, i+1, len(steps), step.Description)
+		}
+
+		err := step.Migrate(context)
+		if err != nil {
+			return errors.Wrapf(err, "migration to version %d failed", step.Version)
+		}
+
+		err = setVersion(step.Version)
+		if err != nil {
+			return err
+		}
+		checkpoint = nil
+		log.Infof("Migrated the database to version %d", step.Version)
+	}
+
+	// A checkpoint may be left behind if a step was interrupted after it
+	// had completed, but before the database version was updated
+	if !shouldDeleteCheckpoint {
+		return nil
+	}
+	return db.Delete(checkpointKey)
+}
@@ -0,0 +1,228 @@
+package migration
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+func prepareDatabaseForTest(t *testing.T) (db database.Database, teardownFunc func()) {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %s", err)
+	}
+	return db, func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatalf("Close: %s", err)
+		}
+	}
+}
+
+func noopStep(version int) *Step {
+	return &Step{
+		Version:     version,
+		Description: "noop",
+		Migrate:     func(context *StepContext) error { return nil },
+	}
+}
+
+func TestPlan(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(noopStep(3))
+	registry.Register(noopStep(2))
+	registry.Register(noopStep(5))
+
+	steps, err := registry.Plan(1, 3)
+	if err != nil {
+		t.Fatalf("Plan: %s", err)
+	}
+	if len(steps) != 2 || steps[0].Version != 2 || steps[1].Version != 3 {
+		t.Fatalf("Unexpected plan from version 1 to version 3: %v", steps)
+	}
+
+	steps, err = registry.Plan(3, 3)
+	if err != nil {
+		t.Fatalf("Plan: %s", err)
+	}
+	if len(steps) != 0 {
+		t.Fatalf("Expected no steps when the database is up to date, got %d", len(steps))
+	}
+
+	_, err = registry.Plan(1, 5)
+	if err == nil {
+		t.Fatalf("Expected Plan to fail when the step to version 4 is missing")
+	}
+	_, err = registry.Plan(3, 2)
+	if err == nil {
+		t.Fatalf("Expected Plan to fail when migrating to an older version")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected registering a second step to the same version to panic")
+		}
+	}()
+	registry.Register(noopStep(2))
+}
+
+func TestMigrateResumesFromCheckpoint(t *testing.T) {
+	db, teardown := prepareDatabaseForTest(t)
+	defer teardown()
+
+	// The step to version 2 writes itemCount items in batches of batchSize,
+	// and is interrupted after interruptAfterBatches batches the first time
+	const itemCount = 10
+	const batchSize = 3
+	const interruptAfterBatches = 2
+	errInterrupted := errors.New("interrupted")
+	itemsBucket := database.MakeBucket([]byte("items"))
+	writeCounts := make(map[uint64]int)
+	shouldInterrupt := true
+
+	registry := NewRegistry()
+	registry.Register(noopStep(1))
+	registry.Register(&Step{
+		Version:     2,
+		Description: "write items",
+		Migrate: func(context *StepContext) error {
+			next := uint64(0)
+			if checkpoint := context.Checkpoint(); checkpoint != nil {
+				next = binary.LittleEndian.Uint64(checkpoint)
+			}
+			batchCount := 0
+			for next < itemCount {
+				if shouldInterrupt && batchCount == interruptAfterBatches {
+					return errInterrupted
+				}
+				dbTx, err := context.Database().Begin()
+				if err != nil {
+					return err
+				}
+				for i := 0; i < batchSize && next < itemCount; i++ {
+					var key [8]byte
+					binary.LittleEndian.PutUint64(key[:], next)
+					err := dbTx.Put(itemsBucket.Key(key[:]), []byte{1})
+					if err != nil {
+						return err
+					}
+					writeCounts[next]++
+					next++
+				}
+				checkpoint := make([]byte, 8)
+				binary.LittleEndian.PutUint64(checkpoint, next)
+				err = context.Commit(dbTx, checkpoint)
+				if err != nil {
+					return err
+				}
+				batchCount++
+				context.LogProgress(next, itemCount)
+			}
+			return nil
+		},
+	})
+
+	version := 0
+	setVersion := func(newVersion int) error {
+		version = newVersion
+		return nil
+	}
+
+	err := registry.Migrate(db, version, 2, setVersion)
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("Expected the migration to be interrupted, got: %v", err)
+	}
+	if version != 1 {
+		t.Fatalf("Unexpected version after the interruption. Want: 1, got: %d", version)
+	}
+
+	// The dry run should report the interrupted step without changing anything
+	steps, err := registry.Check(db, version, 2)
+	if err != nil {
+		t.Fatalf("Check: %s", err)
+	}
+	if len(steps) != 1 || steps[0].Version != 2 {
+		t.Fatalf("Unexpected steps returned from Check: %v", steps)
+	}
+
+	shouldInterrupt = false
+	err = registry.Migrate(db, version, 2, setVersion)
+	if err != nil {
+		t.Fatalf("Migrate: %s", err)
+	}
+	if version != 2 {
+		t.Fatalf("Unexpected version after the migration. Want: 2, got: %d", version)
+	}
+	for i := uint64(0); i < itemCount; i++ {
+		if writeCounts[i] != 1 {
+			t.Fatalf("Item %d was written %d times, while it should have been written once", i, writeCounts[i])
+		}
+	}
+
+	hasCheckpoint, err := db.Has(checkpointKey)
+	if err != nil {
+		t.Fatalf("Has: %s", err)
+	}
+	if hasCheckpoint {
+		t.Fatalf("The checkpoint was not removed after the migration completed")
+	}
+}
+
+func TestMigrateIgnoresCheckpointOfCompletedStep(t *testing.T) {
+	db, teardown := prepareDatabaseForTest(t)
+	defer teardown()
+
+	// Simulate a step to version 1 that was interrupted after it completed,
+	// but before its checkpoint was removed
+	err := db.Put(checkpointKey, serializeCheckpoint(1, []byte("done")))
+	if err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	var receivedCheckpoint []byte
+	registry := NewRegistry()
+	registry.Register(&Step{
+		Version:     2,
+		Description: "record checkpoint",
+		Migrate: func(context *StepContext) error {
+			receivedCheckpoint = context.Checkpoint()
+			return nil
+		},
+	})
+
+	err = registry.Migrate(db, 1, 2, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Migrate: %s", err)
+	}
+	if receivedCheckpoint != nil {
+		t.Fatalf("The step to version 2 received the checkpoint of the step to version 1")
+	}
+}
+
+func TestMigrateWithoutStepsDoesNotWrite(t *testing.T) {
+	dbPath := t.TempDir()
+	db, err := ldb.NewLevelDB(dbPath, 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %s", err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+
+	readOnlyDB, err := ldb.NewLevelDBReadOnly(dbPath, 8)
+	if err != nil {
+		t.Fatalf("NewLevelDBReadOnly: %s", err)
+	}
+	defer readOnlyDB.Close()
+
+	registry := NewRegistry()
+	registry.Register(noopStep(1))
+	err = registry.Migrate(readOnlyDB, 1, 1, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Migrate wrote to the database even though there was nothing to migrate: %s", err)
+	}
+}
@@ -0,0 +1,96 @@
+package migration
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+// progressLogInterval is the minimal interval between two progress
+// logs of a single migration step
+const progressLogInterval = 10 * time.Second
+
+// StepContext is given to a running migration step. It provides the
+// database, and the means to save the step's progress
+type StepContext struct {
+	db         database.Database
+	version    int
+	checkpoint []byte
+
+	lastProgressLogTime time.Time
+}
+
+// Database returns the database that's being migrated
+func (c *StepContext) Database() database.Database {
+	return c.db
+}
+
+// Checkpoint returns the checkpoint that the step last committed before it
+// was interrupted, or nil if the step is starting from the beginning
+func (c *StepContext) Checkpoint() []byte {
+	return c.checkpoint
+}
+
+// Commit saves the given checkpoint as part of the given transaction, and
+// commits it. Since the changes of the transaction and the checkpoint are
+// written atomically, a step that's interrupted resumes exactly after the
+// last committed transaction
+func (c *StepContext) Commit(dbTx database.Transaction, checkpoint []byte) error {
+	err := dbTx.Put(checkpointKey, serializeCheckpoint(c.version, checkpoint))
+	if err != nil {
+		return err
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		return err
+	}
+	c.checkpoint = checkpoint
+	return nil
+}
+
+// LogProgress logs the progress of the step, given how much of its work is
+// done out of the total. It logs at most once every progressLogInterval, as
+// well as once the work is complete, so it may be called as often as needed
+func (c *StepContext) LogProgress(done uint64, total uint64) {
+	if done < total && time.Since(c.lastProgressLogTime) < progressLogInterval {
+		return
+	}
+	c.lastProgressLogTime = time.Now()
+
+	percent := 100.0
+	if total > 0 {
+		percent = float64(done) / float64(total) * 100
+	}
+	log.Infof("Migration to version %d: %d/%d (%.2f%%)", c.version, done, total, percent)
+}
+
+type checkpoint struct {
+	version int
+	data    []byte
+}
+
+func readCheckpoint(db database.DataAccessor) (*checkpoint, error) {
+	serializedCheckpoint, err := db.Get(checkpointKey)
+	if database.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deserializeCheckpoint(serializedCheckpoint), nil
+}
+
+func serializeCheckpoint(version int, data []byte) []byte {
+	serializedCheckpoint := make([]byte, 4+len(data)) // version + data
+	binary.LittleEndian.PutUint32(serializedCheckpoint, uint32(version))
+	copy(serializedCheckpoint[4:], data)
+	return serializedCheckpoint
+}
+
+func deserializeCheckpoint(serializedCheckpoint []byte) *checkpoint {
+	return &checkpoint{
+		version: int(binary.LittleEndian.Uint32(serializedCheckpoint)),
+		data:    serializedCheckpoint[4:],
+	}
+}
@@ -56,7 +56,7 @@ type Backend struct {
 	flag      uint32
 	isRunning uint32
 	writers   []logWriter
-	writeChan chan logEntry
+	writeChan chan *logEntry
 	syncClose sync.Mutex // used to sync that the logger finished writing everything
 }
 
@@ -64,7 +64,7 @@ type Backend struct {
 // the package's defaults as determined through the LOGFLAGS environment
 // variable.
 func NewBackendWithFlags(flags uint32) *Backend {
-	return &Backend{flag: flags, writeChan: make(chan logEntry, logsBuffer)}
+	return &Backend{flag: flags, writeChan: make(chan *logEntry, logsBuffer)}
 }
 
 // NewBackend creates a new logger backend.
@@ -80,17 +80,23 @@ const (
 type logWriter interface {
 	io.WriteCloser
 	LogLevel() Level
+	Format() Format
 }
 
 type logWriterWrap struct {
 	io.WriteCloser
 	logLevel Level
+	format   Format
 }
 
 func (lw logWriterWrap) LogLevel() Level {
 	return lw.logLevel
 }
 
+func (lw logWriterWrap) Format() Format {
+	return lw.format
+}
+
 // AddLogFile adds a file which the log will write into on a certain
 // log level with the default log rotation settings. It'll create the file if it doesn't exist.
 func (b *Backend) AddLogFile(logFile string, logLevel Level) error {
@@ -98,22 +104,38 @@ func (b *Backend) AddLogFile(logFile string, logLevel Level) error {
 }
 
 // AddLogWriter adds a type implementing io.WriteCloser which the log will write into on a certain
-// log level with the default log rotation settings. It'll create the file if it doesn't exist.
+// log level in the text format.
 func (b *Backend) AddLogWriter(logWriter io.WriteCloser, logLevel Level) error {
+	return b.AddLogWriterWithFormat(logWriter, logLevel, FormatText)
+}
+
+// AddLogWriterWithFormat adds a type implementing io.WriteCloser which the log will write into on a certain
+// log level in the given format.
+func (b *Backend) AddLogWriterWithFormat(logWriter io.WriteCloser, logLevel Level, format Format) error {
 	if b.IsRunning() {
 		return errors.New("The logger is already running")
 	}
 	b.writers = append(b.writers, logWriterWrap{
 		WriteCloser: logWriter,
 		logLevel:    logLevel,
+		format:      format,
 	})
 	return nil
 }
 
 // AddLogFileWithCustomRotator adds a file which the log will write into on a certain
-// log level, with the specified log rotation settings.
+// log level in the text format, with the specified log rotation settings.
 // It'll create the file if it doesn't exist.
 func (b *Backend) AddLogFileWithCustomRotator(logFile string, logLevel Level, thresholdKB int64, maxRolls int) error {
+	return b.AddLogFileWithCustomRotatorAndFormat(logFile, logLevel, thresholdKB, maxRolls, FormatText)
+}
+
+// AddLogFileWithCustomRotatorAndFormat adds a file which the log will write into on a certain
+// log level in the given format, with the specified log rotation settings.
+// It'll create the file if it doesn't exist.
+func (b *Backend) AddLogFileWithCustomRotatorAndFormat(logFile string, logLevel Level, thresholdKB int64,
+	maxRolls int, format Format) error {
+
 	if b.IsRunning() {
 		return errors.New("The logger is already running")
 	}
@@ -132,6 +154,7 @@ func (b *Backend) AddLogFileWithCustomRotator(logFile string, logLevel Level, th
 	b.writers = append(b.writers, logWriterWrap{
 		WriteCloser: r,
 		logLevel:    logLevel,
+		format:      format,
 	})
 	return nil
 }
@@ -158,11 +181,18 @@ func (b *Backend) runBlocking() {
 	b.syncClose.Lock()
 	defer b.syncClose.Unlock()
 
-	for log := range b.writeChan {
+	for entry := range b.writeChan {
+		// Entries are formatted at most once per format
+		var formattedEntries [len(formatStrs)][]byte
 		for _, writer := range b.writers {
-			if log.level >= writer.LogLevel() {
-				_, _ = writer.Write(log.log)
+			if entry.level < writer.LogLevel() {
+				continue
+			}
+			format := writer.Format()
+			if formattedEntries[format] == nil {
+				formattedEntries[format] = entry.format(format)
 			}
+			_, _ = writer.Write(formattedEntries[format])
 		}
 	}
 }
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+// Format is the format in which a log writer writes log entries
+type Format uint32
+
+// Format constants.
+const (
+	// FormatText writes every entry as a human-readable line in the form
+	// 'YYYY-MM-DD hh:mm:ss.sss [LVL] TAG: message'
+	FormatText Format = iota
+
+	// FormatJSON writes every entry as a JSON object on a line of its own,
+	// with the entry's attributes as separate fields
+	FormatJSON
+)
+
+var formatStrs = [...]string{"text", "json"}
+
+// FormatFromString returns a format based on the input string s. If the input
+// can't be interpreted as a valid format, the text format and false is returned.
+func FormatFromString(s string) (f Format, ok bool) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, true
+	case "json":
+		return FormatJSON, true
+	default:
+		return FormatText, false
+	}
+}
+
+// String returns the name of the format as accepted by FormatFromString
+func (f Format) String() string {
+	if int(f) >= len(formatStrs) {
+		return "unknown"
+	}
+	return formatStrs[f]
+}
+
+// Attribute is a key-value pair that structured log formats emit as a
+// separate field of the log entry. Within the message itself an attribute is
+// formatted as its value, so it can be passed to the logging functions as a
+// regular operand:
+//
+//	log.Infof("Accepted block %s", logger.Attr("blockHash", blockHash))
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr returns an Attribute with the given key and value
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Format implements fmt.Formatter by formatting the attribute's value with
+// the same verb and flags
+func (attribute Attribute) Format(state fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(state, formatDirective(state, verb), attribute.Value)
+}
+
+// formatDirective reconstructs the formatting directive, e.g. %-10s, that
+// fmt called a Formatter with
+func formatDirective(state fmt.State, verb rune) string {
+	directive := make([]byte, 0, 8)
+	directive = append(directive, '%')
+	for _, flag := range "+-# 0" {
+		if state.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := state.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if precision, ok := state.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(precision), 10)
+	}
+	var verbBytes [utf8.UTFMax]byte
+	directive = append(directive, verbBytes[:utf8.EncodeRune(verbBytes[:], verb)]...)
+	return string(directive)
+}
+
+// extractAttributes returns all the Attribute operands within args, with
+// their values already converted to the values they're emitted with in JSON.
+// The conversion is done here, on the calling goroutine, so that the backend
+// never reads a value the caller might have changed since the log call
+func extractAttributes(args []interface{}) []Attribute {
+	var attributes []Attribute
+	for _, arg := range args {
+		if attribute, ok := arg.(Attribute); ok {
+			attributes = append(attributes, Attribute{Key: attribute.Key, Value: attributeJSONValue(attribute.Value)})
+		}
+	}
+	return attributes
+}
+
+// logEntry is a single log message along with everything the writers need
+// in order to format it
+type logEntry struct {
+	time       mstime.Time
+	level      Level
+	tag        string
+	file       string
+	line       int
+	message    string
+	attributes []Attribute
+}
+
+// format returns the entry formatted in the given format, including the
+// trailing newline
+func (entry *logEntry) format(format Format) []byte {
+	if format == FormatJSON {
+		return entry.formatJSON()
+	}
+	return entry.formatText()
+}
+
+func (entry *logEntry) formatText() []byte {
+	buf := make([]byte, 0, normalLogSize)
+	formatHeader(&buf, entry.time, entry.level.String(), entry.tag, entry.file, entry.line)
+	buf = append(buf, entry.message...)
+	return append(buf, '\n')
+}
+
+// reservedJSONKeys are the keys of the fields that every JSON entry carries.
+// Attributes with these keys are prefixed with "attr_" so that they don't
+// override them
+var reservedJSONKeys = map[string]struct{}{
+	"time": {}, "level": {}, "subsystem": {}, "file": {}, "line": {}, "message": {},
+}
+
+func (entry *logEntry) formatJSON() []byte {
+	buffer := bytes.NewBuffer(make([]byte, 0, normalLogSize))
+	buffer.WriteByte('{')
+	writeJSONField(buffer, "time", entry.time.ToNativeTime().UTC().Format("2006-01-02T15:04:05.000Z07:00"), true)
+	writeJSONField(buffer, "level", entry.level.Name(), false)
+	writeJSONField(buffer, "subsystem", entry.tag, false)
+	if entry.file != "" {
+		writeJSONField(buffer, "file", entry.file, false)
+		writeJSONField(buffer, "line", entry.line, false)
+	}
+	writeJSONField(buffer, "message", entry.message, false)
+	for _, attribute := range entry.attributes {
+		key := attribute.Key
+		if _, ok := reservedJSONKeys[key]; ok {
+			key = "attr_" + key
+		}
+		writeJSONField(buffer, key, attribute.Value, false)
+	}
+	buffer.WriteString("}\n")
+	return buffer.Bytes()
+}
+
+func writeJSONField(buffer *bytes.Buffer, key string, value interface{}, isFirst bool) {
+	if !isFirst {
+		buffer.WriteByte(',')
+	}
+	encodedKey, _ := json.Marshal(key)
+	buffer.Write(encodedKey)
+	buffer.WriteByte(':')
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		// Values such as NaN can't be represented in JSON
+		encodedValue, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buffer.Write(encodedValue)
+}
+
+// attributeJSONValue returns the value an attribute is emitted with in JSON.
+// Booleans, numbers and strings are emitted as is, and anything else is
+// emitted as its default string representation
+func attributeJSONValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
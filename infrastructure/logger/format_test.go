@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+type testStringer struct{}
+
+func (testStringer) String() string {
+	return "stringer"
+}
+
+func TestAttributeFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{format: "%s", args: []interface{}{Attr("key", "value")}, expected: "value"},
+		{format: "%d blocks", args: []interface{}{Attr("count", 5)}, expected: "5 blocks"},
+		{format: "%05.1f", args: []interface{}{Attr("rate", 1.25)}, expected: "001.2"},
+		{format: "%-6s|", args: []interface{}{Attr("key", "ab")}, expected: "ab    |"},
+		{format: "%+v", args: []interface{}{Attr("key", struct{ A int }{1})}, expected: "{A:1}"},
+		{format: "%s", args: []interface{}{Attr("key", testStringer{})}, expected: "stringer"},
+	}
+	for _, test := range tests {
+		result := fmt.Sprintf(test.format, test.args...)
+		if result != test.expected {
+			t.Errorf("Sprintf(%q): expected %q but got %q", test.format, test.expected, result)
+		}
+	}
+}
+
+func TestLogEntryFormat(t *testing.T) {
+	entryTime := mstime.ToMSTime(time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.UTC))
+	args := []interface{}{Attr("blockHash", "abcd"), Attr("peer", testStringer{}), Attr("message", 1),
+		Attr("ratio", math.NaN())}
+	entry := &logEntry{
+		time:       entryTime,
+		level:      LevelWarn,
+		tag:        "PROT",
+		file:       "blocks.go",
+		line:       12,
+		message:    fmt.Sprintf("Rejected block %s from %s (%d, %f)", args...),
+		attributes: extractAttributes(append(args, "not an attribute")),
+	}
+
+	expectedText := entryTime.ToNativeTime().Local().Format("2006-01-02 15:04:05.000") +
+		" [WRN] PROT blocks.go:12: Rejected block abcd from stringer (1, NaN)\n"
+	if text := string(entry.format(FormatText)); text != expectedText {
+		t.Fatalf("Expected text entry %q but got %q", expectedText, text)
+	}
+
+	expectedJSON := `{"time":"2021-03-04T05:06:07.008Z","level":"warn","subsystem":"PROT","file":"blocks.go",` +
+		`"line":12,"message":"Rejected block abcd from stringer (1, NaN)","blockHash":"abcd","peer":"stringer",` +
+		`"attr_message":1,"ratio":"NaN"}` + "\n"
+	jsonEntry := entry.format(FormatJSON)
+	if string(jsonEntry) != expectedJSON {
+		t.Fatalf("Expected JSON entry %s but got %s", expectedJSON, jsonEntry)
+	}
+	if !json.Valid(jsonEntry) {
+		t.Fatalf("Entry %s is not valid JSON", jsonEntry)
+	}
+}
+
+type testCounter struct {
+	count int
+}
+
+func (counter *testCounter) String() string {
+	return fmt.Sprintf("count %d", counter.count)
+}
+
+func TestExtractAttributesAtCallTime(t *testing.T) {
+	counter := &testCounter{count: 1}
+	attributes := extractAttributes([]interface{}{Attr("counter", counter), Attr("height", 5)})
+	counter.count = 2
+
+	if attributes[0].Value != "count 1" {
+		t.Fatalf("Expected the counter to be converted when extracted, but got %v", attributes[0].Value)
+	}
+	if attributes[1].Value != 5 {
+		t.Fatalf("Expected numbers to be kept as is, but got %v", attributes[1].Value)
+	}
+}
+
+func TestFormatFromString(t *testing.T) {
+	for _, format := range []Format{FormatText, FormatJSON} {
+		parsedFormat, ok := FormatFromString(format.String())
+		if !ok || parsedFormat != format {
+			t.Errorf("Expected %s to be parsed back into itself, but got %s (ok: %t)", format, parsedFormat, ok)
+		}
+	}
+	if _, ok := FormatFromString("xml"); ok {
+		t.Errorf("Expected xml to be an invalid format")
+	}
+}
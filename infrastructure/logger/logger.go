@@ -47,7 +47,11 @@ func RegisterSubSystem(subsystem string) *Logger {
 
 // InitLogStdout attaches stdout to the backend log and starts the logger.
 func InitLogStdout(logLevel Level) {
-	err := BackendLog.AddLogWriter(os.Stdout, logLevel)
+	initLogStdout(logLevel, FormatText)
+}
+
+func initLogStdout(logLevel Level, format Format) {
+	err := BackendLog.AddLogWriterWithFormat(os.Stdout, logLevel, format)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error adding stdout to the loggerfor level %s: %s", LevelWarn, err)
 		os.Exit(1)
@@ -62,19 +66,26 @@ func InitLogStdout(logLevel Level) {
 
 // InitLog attaches log file and error log file to the backend log.
 func InitLog(logFile, errLogFile string) {
+	InitLogWithFormats(logFile, errLogFile, FormatText, FormatText)
+}
+
+// InitLogWithFormats attaches log file and error log file to the backend log.
+// Both files are written in fileFormat, while stdout is written in stdoutFormat.
+func InitLogWithFormats(logFile, errLogFile string, fileFormat Format, stdoutFormat Format) {
 	// 280 MB (MB=1000^2 bytes)
-	err := BackendLog.AddLogFileWithCustomRotator(logFile, LevelTrace, 1000*280, 64)
+	err := BackendLog.AddLogFileWithCustomRotatorAndFormat(logFile, LevelTrace, 1000*280, 64, fileFormat)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error adding log file %s as log rotator for level %s: %s", logFile, LevelTrace, err)
 		os.Exit(1)
 	}
-	err = BackendLog.AddLogFile(errLogFile, LevelWarn)
+	err = BackendLog.AddLogFileWithCustomRotatorAndFormat(errLogFile, LevelWarn, defaultThresholdKB, defaultMaxRolls,
+		fileFormat)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error adding log file %s as log rotator for level %s: %s", errLogFile, LevelWarn, err)
 		os.Exit(1)
 	}
 
-	InitLogStdout(LevelInfo)
+	initLogStdout(LevelInfo, stdoutFormat)
 }
 
 // SetLogLevel sets the logging level for provided subsystem. Invalid
@@ -135,6 +146,18 @@ func SupportedSubsystems() []string {
 	return subsystems
 }
 
+// LogLevels returns the current logging level of every subsystem, keyed by
+// the subsystem identifier.
+func LogLevels() map[string]Level {
+	subsystemLoggersMutex.Lock()
+	defer subsystemLoggersMutex.Unlock()
+	levels := make(map[string]Level, len(subsystemLoggers))
+	for subsysID, logger := range subsystemLoggers {
+		levels[subsysID] = logger.Level()
+	}
+	return levels
+}
+
 func getSubsystem(tag string) (logger *Logger, ok bool) {
 	subsystemLoggersMutex.Lock()
 	defer subsystemLoggersMutex.Unlock()
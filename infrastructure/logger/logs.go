@@ -33,7 +33,6 @@
 package logger
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/kaspanet/kaspad/util/mstime"
 	"os"
@@ -46,12 +45,7 @@ type Logger struct {
 	lvl       Level // atomic
 	tag       string
 	b         *Backend
-	writeChan chan<- logEntry
-}
-
-type logEntry struct {
-	log   []byte
-	level Level
+	writeChan chan<- *logEntry
 }
 
 // Trace formats message using the default formats for its operands, prepends
@@ -159,10 +153,9 @@ func (l *Logger) Backend() *Backend {
 	return l.b
 }
 
-// printf outputs a log message to the writer associated with the backend after
-// creating a prefix for the given level and tag according to the formatHeader
-// function and formatting the provided arguments according to the given format
-// specifier.
+// printf sends a log message to the writers associated with the backend,
+// formatting the provided arguments according to the given format specifier.
+// Each writer adds the level, tag and time in its own format.
 func (l *Logger) printf(lvl Level, tag string, format string, args ...interface{}) {
 	t := mstime.Now() // get as early as possible
 
@@ -172,24 +165,26 @@ func (l *Logger) printf(lvl Level, tag string, format string, args ...interface{
 		file, line = callsite(l.b.flag)
 	}
 
-	buf := make([]byte, 0, normalLogSize)
-
-	formatHeader(&buf, t, lvl.String(), tag, file, line)
-	bytesBuf := bytes.NewBuffer(buf)
-	_, _ = fmt.Fprintf(bytesBuf, format, args...)
-	bytesBuf.WriteByte('\n')
+	entry := &logEntry{
+		time:       t,
+		level:      lvl,
+		tag:        tag,
+		file:       file,
+		line:       line,
+		message:    fmt.Sprintf(format, args...),
+		attributes: extractAttributes(args),
+	}
 
 	if !l.b.IsRunning() {
-		_, _ = fmt.Fprintf(os.Stderr, bytesBuf.String())
+		_, _ = os.Stderr.Write(entry.formatText())
 		panic("Writing to the logger when it's not running")
 	}
-	l.writeChan <- logEntry{bytesBuf.Bytes(), lvl}
+	l.writeChan <- entry
 }
 
-// print outputs a log message to the writer associated with the backend after
-// creating a prefix for the given level and tag according to the formatHeader
-// function and formatting the provided arguments using the default formatting
-// rules.
+// print sends a log message to the writers associated with the backend,
+// formatting the provided arguments using the default formatting rules.
+// Each writer adds the level, tag and time in its own format.
 func (l *Logger) print(lvl Level, tag string, args ...interface{}) {
 	if atomic.LoadUint32(&l.b.isRunning) == 0 {
 		panic("printing log without initializing")
@@ -202,15 +197,21 @@ func (l *Logger) print(lvl Level, tag string, args ...interface{}) {
 		file, line = callsite(l.b.flag)
 	}
 
-	buf := make([]byte, 0, normalLogSize)
-	formatHeader(&buf, t, lvl.String(), tag, file, line)
-	bytesBuf := bytes.NewBuffer(buf)
-	_, _ = fmt.Fprintln(bytesBuf, args...)
+	message := fmt.Sprintln(args...)
+	entry := &logEntry{
+		time:       t,
+		level:      lvl,
+		tag:        tag,
+		file:       file,
+		line:       line,
+		message:    message[:len(message)-1], // Remove the newline added by Sprintln
+		attributes: extractAttributes(args),
+	}
 
 	if !l.b.IsRunning() {
 		panic("Writing to the logger when it's not running")
 	}
-	l.writeChan <- logEntry{bytesBuf.Bytes(), lvl}
+	l.writeChan <- entry
 }
 
 // From stdlib log package.
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a metric whose value only goes up, such as the number
+// of processed blocks
+type Counter struct {
+	desc  *description
+	value uint64
+}
+
+// NewCounter creates a new Counter and registers it in the default registry
+func NewCounter(name string, help string) *Counter {
+	return defaultRegistry.NewCounter(name, help)
+}
+
+// NewCounter creates a new Counter and registers it in the registry
+func (r *Registry) NewCounter(name string, help string) *Counter {
+	counter := &Counter{
+		desc: &description{name: name, help: help, metricType: typeCounter},
+	}
+	r.register(counter)
+	return counter
+}
+
+// Inc increments the counter by 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by the given value
+func (c *Counter) Add(value uint64) {
+	atomic.AddUint64(&c.value, value)
+}
+
+// Value returns the current value of the counter
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c *Counter) description() *description {
+	return c.desc
+}
+
+func (c *Counter) samples() []*sample {
+	return []*sample{{value: float64(c.Value())}}
+}
+
+// CounterVec is a set of Counters that share a name and are told apart by
+// the values of their labels, such as the number of rejected blocks by
+// rejection reason
+type CounterVec struct {
+	desc       *description
+	labelNames []string
+
+	counters map[string]*labeledCounter
+	lock     sync.RWMutex
+}
+
+type labeledCounter struct {
+	labels  []*labelPair
+	counter *Counter
+}
+
+// NewCounterVec creates a new CounterVec with the given label names and
+// registers it in the default registry
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	return defaultRegistry.NewCounterVec(name, help, labelNames...)
+}
+
+// NewCounterVec creates a new CounterVec with the given label names and
+// registers it in the registry
+func (r *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	for _, labelName := range labelNames {
+		if !validNameRegexp.MatchString(labelName) || strings.Contains(labelName, ":") {
+			panic(fmt.Sprintf("invalid label name %s for metric %s", labelName, name))
+		}
+	}
+	counterVec := &CounterVec{
+		desc:       &description{name: name, help: help, metricType: typeCounter},
+		labelNames: labelNames,
+		counters:   make(map[string]*labeledCounter),
+	}
+	r.register(counterVec)
+	return counterVec
+}
+
+// WithLabelValues returns the Counter for the given label values, creating
+// it if it doesn't exist yet. The values must be given in the order of the
+// CounterVec's label names
+func (cv *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	if len(labelValues) != len(cv.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values but got %d",
+			cv.desc.name, len(cv.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	cv.lock.RLock()
+	labeled, ok := cv.counters[key]
+	cv.lock.RUnlock()
+	if ok {
+		return labeled.counter
+	}
+
+	cv.lock.Lock()
+	defer cv.lock.Unlock()
+
+	labeled, ok = cv.counters[key]
+	if ok {
+		return labeled.counter
+	}
+	labels := make([]*labelPair, len(labelValues))
+	for i, labelValue := range labelValues {
+		labels[i] = &labelPair{name: cv.labelNames[i], value: labelValue}
+	}
+	labeled = &labeledCounter{labels: labels, counter: &Counter{desc: cv.desc}}
+	cv.counters[key] = labeled
+	return labeled.counter
+}
+
+func (cv *CounterVec) description() *description {
+	return cv.desc
+}
+
+func (cv *CounterVec) samples() []*sample {
+	cv.lock.RLock()
+	defer cv.lock.RUnlock()
+
+	keys := make([]string, 0, len(cv.counters))
+	for key := range cv.counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	samples := make([]*sample, len(keys))
+	for i, key := range keys {
+		labeled := cv.counters[key]
+		samples[i] = &sample{labels: labeled.labels, value: float64(labeled.counter.Value())}
+	}
+	return samples
+}
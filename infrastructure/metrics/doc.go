@@ -0,0 +1,11 @@
+/*
+Package metrics implements the operational metrics of kaspad and exports them
+in the Prometheus text exposition format.
+
+Metrics are declared as package-level variables next to the code that updates
+them, using the constructors of this package (NewCounter, NewGauge, etc.).
+Every metric is registered in a default registry that is served over HTTP under
+/metrics once Start is called. Updating a metric is cheap and safe for
+concurrent use whether or not the metrics server is running.
+*/
+package metrics
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Gauge is a metric whose value can go up and down, such as the
+// size of the UTXO set
+type Gauge struct {
+	desc  *description
+	value int64
+}
+
+// NewGauge creates a new Gauge and registers it in the default registry
+func NewGauge(name string, help string) *Gauge {
+	return defaultRegistry.NewGauge(name, help)
+}
+
+// NewGauge creates a new Gauge and registers it in the registry
+func (r *Registry) NewGauge(name string, help string) *Gauge {
+	gauge := &Gauge{
+		desc: &description{name: name, help: help, metricType: typeGauge},
+	}
+	r.register(gauge)
+	return gauge
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(value int64) {
+	atomic.StoreInt64(&g.value, value)
+}
+
+// Add adds the given value, which may be negative, to the gauge
+func (g *Gauge) Add(value int64) {
+	atomic.AddInt64(&g.value, value)
+}
+
+// Value returns the current value of the gauge
+func (g *Gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+func (g *Gauge) description() *description {
+	return g.desc
+}
+
+func (g *Gauge) samples() []*sample {
+	return []*sample{{value: float64(g.Value())}}
+}
+
+// GaugeFunc is a gauge whose value is computed by a function whenever the
+// metrics are collected. It's useful for values that are already tracked
+// elsewhere, such as the number of transactions in the mempool.
+// A GaugeFunc isn't exported until its function is set
+type GaugeFunc struct {
+	desc *description
+
+	function func() float64
+	lock     sync.RWMutex
+}
+
+// NewGaugeFunc creates a new GaugeFunc and registers it in the default registry
+func NewGaugeFunc(name string, help string) *GaugeFunc {
+	return defaultRegistry.NewGaugeFunc(name, help)
+}
+
+// NewGaugeFunc creates a new GaugeFunc and registers it in the registry
+func (r *Registry) NewGaugeFunc(name string, help string) *GaugeFunc {
+	gaugeFunc := &GaugeFunc{
+		desc: &description{name: name, help: help, metricType: typeGauge},
+	}
+	r.register(gaugeFunc)
+	return gaugeFunc
+}
+
+// SetFunction sets the function that computes the value of the gauge,
+// replacing any previously set function. The function must be safe
+// for concurrent use
+func (gf *GaugeFunc) SetFunction(function func() float64) {
+	gf.lock.Lock()
+	defer gf.lock.Unlock()
+
+	gf.function = function
+}
+
+func (gf *GaugeFunc) description() *description {
+	return gf.desc
+}
+
+func (gf *GaugeFunc) samples() []*sample {
+	gf.lock.RLock()
+	function := gf.function
+	gf.lock.RUnlock()
+
+	if function == nil {
+		return nil
+	}
+	return []*sample{{value: function()}}
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DurationBuckets are histogram buckets, in seconds, that fit the durations
+// of operations that usually take between a millisecond and a few seconds
+var DurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram is a metric that samples observations, such as request
+// durations, and counts them in configurable buckets
+type Histogram struct {
+	desc *description
+
+	upperBounds  []float64
+	bucketCounts []uint64
+	count        uint64
+	sum          float64
+	lock         sync.Mutex
+}
+
+// NewHistogram creates a new Histogram with the given bucket upper bounds
+// and registers it in the default registry
+func NewHistogram(name string, help string, buckets []float64) *Histogram {
+	return defaultRegistry.NewHistogram(name, help, buckets)
+}
+
+// NewHistogram creates a new Histogram with the given bucket upper bounds
+// and registers it in the registry
+func (r *Registry) NewHistogram(name string, help string, buckets []float64) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("the buckets of histogram %s are not sorted", name))
+	}
+	histogram := &Histogram{
+		desc:         &description{name: name, help: help, metricType: typeHistogram},
+		upperBounds:  buckets,
+		bucketCounts: make([]uint64, len(buckets)),
+	}
+	r.register(histogram)
+	return histogram
+}
+
+// Observe adds a single observation to the histogram
+func (h *Histogram) Observe(value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	index := sort.SearchFloat64s(h.upperBounds, value)
+	if index < len(h.bucketCounts) {
+		h.bucketCounts[index]++
+	}
+	h.count++
+	h.sum += value
+}
+
+// ObserveDuration adds the given duration, in seconds, to the histogram
+func (h *Histogram) ObserveDuration(duration time.Duration) {
+	h.Observe(duration.Seconds())
+}
+
+func (h *Histogram) description() *description {
+	return h.desc
+}
+
+func (h *Histogram) samples() []*sample {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	samples := make([]*sample, 0, len(h.upperBounds)+3)
+	cumulativeCount := uint64(0)
+	for i, upperBound := range h.upperBounds {
+		cumulativeCount += h.bucketCounts[i]
+		samples = append(samples, &sample{
+			nameSuffix: "_bucket",
+			labels:     []*labelPair{{name: "le", value: formatValue(upperBound)}},
+			value:      float64(cumulativeCount),
+		})
+	}
+	samples = append(samples,
+		&sample{
+			nameSuffix: "_bucket",
+			labels:     []*labelPair{{name: "le", value: formatValue(math.Inf(1))}},
+			value:      float64(h.count),
+		},
+		&sample{nameSuffix: "_sum", value: h.sum},
+		&sample{nameSuffix: "_count", value: float64(h.count)},
+	)
+	return samples
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+var validNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// metric is a single metric family that can be exported by a Registry
+type metric interface {
+	description() *description
+	samples() []*sample
+}
+
+// description describes a metric family
+type description struct {
+	name       string
+	help       string
+	metricType string
+}
+
+// sample is a single exported value of a metric family. Histograms, for
+// example, export several samples with different name suffixes
+type sample struct {
+	nameSuffix string
+	labels     []*labelPair
+	value      float64
+}
+
+type labelPair struct {
+	name  string
+	value string
+}
+
+// Registry holds a set of metrics and exports them in the Prometheus
+// text exposition format
+type Registry struct {
+	metrics map[string]metric
+	lock    sync.RWMutex
+}
+
+// NewRegistry returns a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]metric),
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// register adds the given metric to the registry. It panics if the metric's
+// name is invalid or already registered, since metrics are declared
+// statically and such an error is a programming error
+func (r *Registry) register(metric metric) {
+	name := metric.description().name
+	if !validNameRegexp.MatchString(name) {
+		panic(fmt.Sprintf("invalid metric name %s", name))
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("metric %s is already registered", name))
+	}
+	r.metrics[name] = metric
+}
+
+// WriteText writes all the metrics in the registry to the given writer in
+// the Prometheus text exposition format, sorted by name
+func (r *Registry) WriteText(writer io.Writer) error {
+	r.lock.RLock()
+	metrics := make([]metric, 0, len(r.metrics))
+	for _, metric := range r.metrics {
+		metrics = append(metrics, metric)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].description().name < metrics[j].description().name
+	})
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, metric := range metrics {
+		samples := metric.samples()
+		if len(samples) == 0 {
+			continue
+		}
+
+		description := metric.description()
+		_, err := fmt.Fprintf(bufferedWriter, "# HELP %s %s\n# TYPE %s %s\n",
+			description.name, escapeHelp(description.help), description.name, description.metricType)
+		if err != nil {
+			return err
+		}
+		for _, sample := range samples {
+			_, err := fmt.Fprintf(bufferedWriter, "%s%s%s %s\n", description.name, sample.nameSuffix,
+				formatLabels(sample.labels), formatValue(sample.value))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return bufferedWriter.Flush()
+}
+
+func formatLabels(labels []*labelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	formattedLabels := make([]string, len(labels))
+	for i, label := range labels {
+		formattedLabels[i] = fmt.Sprintf(`%s="%s"`, label.name, escapeLabelValue(label.value))
+	}
+	return "{" + strings.Join(formattedLabels, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
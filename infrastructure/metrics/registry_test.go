@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("test_counter_total", "A counter")
+	counter.Inc()
+	counter.Add(2)
+
+	counterVec := registry.NewCounterVec("test_counter_vec_total", "A counter\nwith labels", "method", "status")
+	counterVec.WithLabelValues("b", "ok").Inc()
+	counterVec.WithLabelValues("a", `quoted "value"`).Add(5)
+	counterVec.WithLabelValues("b", "ok").Inc()
+
+	gauge := registry.NewGauge("test_gauge", "A gauge")
+	gauge.Set(10)
+	gauge.Add(-3)
+
+	gaugeFunc := registry.NewGaugeFunc("test_gauge_func", "A gauge func")
+	registry.NewGaugeFunc("test_unset_gauge_func", "A gauge func that is never set")
+	gaugeFunc.SetFunction(func() float64 { return 1.5 })
+
+	histogram := registry.NewHistogram("test_histogram_seconds", "A histogram", []float64{0.1, 1})
+	histogram.Observe(0.05)
+	histogram.Observe(0.1)
+	histogram.Observe(0.5)
+	histogram.Observe(3)
+
+	expectedText := `# HELP test_counter_total A counter
+# TYPE test_counter_total counter
+test_counter_total 3
+# HELP test_counter_vec_total A counter\nwith labels
+# TYPE test_counter_vec_total counter
+test_counter_vec_total{method="a",status="quoted \"value\""} 5
+test_counter_vec_total{method="b",status="ok"} 2
+# HELP test_gauge A gauge
+# TYPE test_gauge gauge
+test_gauge 7
+# HELP test_gauge_func A gauge func
+# TYPE test_gauge_func gauge
+test_gauge_func 1.5
+# HELP test_histogram_seconds A histogram
+# TYPE test_histogram_seconds histogram
+test_histogram_seconds_bucket{le="0.1"} 2
+test_histogram_seconds_bucket{le="1"} 3
+test_histogram_seconds_bucket{le="+Inf"} 4
+test_histogram_seconds_sum 3.65
+test_histogram_seconds_count 4
+`
+	text := &strings.Builder{}
+	err := registry.WriteText(text)
+	if err != nil {
+		t.Fatalf("WriteText: %s", err)
+	}
+	if text.String() != expectedText {
+		t.Fatalf("Unexpected text.\nExpected:\n%s\nGot:\n%s", expectedText, text.String())
+	}
+}
+
+func TestRegistryRejectsInvalidMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(registry *Registry)
+	}{
+		{
+			name: "duplicate name",
+			register: func(registry *Registry) {
+				registry.NewCounter("test_total", "")
+				registry.NewGauge("test_total", "")
+			},
+		},
+		{
+			name: "invalid name",
+			register: func(registry *Registry) {
+				registry.NewCounter("test-total", "")
+			},
+		},
+		{
+			name: "invalid label name",
+			register: func(registry *Registry) {
+				registry.NewCounterVec("test_total", "", "method:name")
+			},
+		},
+		{
+			name: "unsorted buckets",
+			register: func(registry *Registry) {
+				registry.NewHistogram("test_seconds", "", []float64{1, 0.1})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected registration to panic", test.name)
+				}
+			}()
+			test.register(NewRegistry())
+		}()
+	}
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("test_counter_total", "A counter").Inc()
+	handler := Handler(registry)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, recorder.Code)
+	}
+	if recorder.Header().Get("Content-Type") != contentType {
+		t.Fatalf("Unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(recorder.Body.String(), "test_counter_total 1\n") {
+		t.Fatalf("Unexpected body:\n%s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status %d, but got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// contentType is the content type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Start starts an HTTP server that serves the metrics of the default
+// registry on the given address under /metrics
+func Start(listenAddress string) error {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", listenAddress)
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/metrics", Handler(defaultRegistry))
+
+	spawn("metrics.Start", func() {
+		log.Infof("Metrics server listening on %s", listener.Addr())
+		log.Error(http.Serve(listener, serveMux))
+	})
+	return nil
+}
+
+// Handler returns an HTTP handler that serves the metrics of the given
+// registry in the Prometheus text exposition format
+func Handler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet && request.Method != http.MethodHead {
+			writer.Header().Set("Allow", "GET, HEAD")
+			http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		writer.Header().Set("Content-Type", contentType)
+		err := registry.WriteText(writer)
+		if err != nil {
+			log.Debugf("Error writing metrics to %s: %s", request.RemoteAddr, err)
+		}
+	})
+}
@@ -7,7 +7,9 @@ package addressmanager
 import (
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/util/mstime"
+	"math"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,8 +17,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-const maxAddresses = 4096
-
 // addressRandomizer is the interface for the randomizer needed for the AddressManager.
 type addressRandomizer interface {
 	RandomAddress(addresses []*appmessage.NetAddress) *appmessage.NetAddress
@@ -32,6 +32,11 @@ type addressKey struct {
 type address struct {
 	netAddress            *appmessage.NetAddress
 	connectionFailedCount uint64
+
+	// isTried is true if we've ever successfully connected to the address,
+	// and source is the IP of the peer that told us about the address
+	isTried bool
+	source  ipv6
 }
 
 type ipv6 [net.IPv6len]byte
@@ -44,6 +49,13 @@ func (i ipv6) equal(other ipv6) bool {
 // given address is not found in the address manager
 var ErrAddressNotFound = errors.New("address not found")
 
+// ipv6FromIP returns the V6 representation of the given IP
+func ipv6FromIP(ip net.IP) ipv6 {
+	var result ipv6
+	copy(result[:], ip.To16())
+	return result
+}
+
 // NetAddressKey returns a key of the ip address to use it in maps.
 func netAddressKey(netAddress *appmessage.NetAddress) addressKey {
 	key := addressKey{port: netAddress.Port}
@@ -60,6 +72,9 @@ type AddressManager struct {
 	mutex          sync.Mutex
 	cfg            *Config
 	random         addressRandomizer
+
+	newBuckets   [newBucketCount]bucket
+	triedBuckets [triedBucketCount]bucket
 }
 
 // New returns a new Kaspa address manager.
@@ -73,67 +88,84 @@ func New(cfg *Config, database database.Database) (*AddressManager, error) {
 		return nil, err
 	}
 
-	return &AddressManager{
+	addressManager := &AddressManager{
 		store:          addressStore,
 		localAddresses: localAddresses,
 		random:         NewAddressRandomize(),
 		cfg:            cfg,
-	}, nil
+	}
+	err = addressManager.restoreBuckets()
+	if err != nil {
+		return nil, err
+	}
+	return addressManager, nil
 }
 
-func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress) error {
+func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress, source *appmessage.NetAddress) error {
 	if !IsRoutable(netAddress, am.cfg.AcceptUnroutable) {
 		return nil
 	}
 
 	key := netAddressKey(netAddress)
-	address := &address{netAddress: netAddress, connectionFailedCount: 0}
-	err := am.store.add(key, address)
-	if err != nil {
-		return err
+	if am.store.isNotBanned(key) {
+		return nil
 	}
 
-	if am.store.notBannedCount() > maxAddresses {
-		allAddresses := am.store.getAllNotBanned()
-
-		maxConnectionFailedCount := uint64(0)
-		toRemove := allAddresses[0]
-		for _, address := range allAddresses[1:] {
-			if address.connectionFailedCount > maxConnectionFailedCount {
-				maxConnectionFailedCount = address.connectionFailedCount
-				toRemove = address
-			}
-		}
-
-		toRemoveKey := netAddressKey(toRemove.netAddress)
-		err := am.store.remove(toRemoveKey)
-		if err != nil {
-			return err
-		}
+	address := &address{
+		netAddress:            netAddress,
+		connectionFailedCount: 0,
+		source:                ipv6FromIP(source.IP),
 	}
-	return nil
+	err := am.addToNewBucketNoLock(address)
+	if err != nil {
+		return err
+	}
+	return am.store.add(key, address)
 }
 
 func (am *AddressManager) removeAddressNoLock(address *appmessage.NetAddress) error {
 	key := netAddressKey(address)
+	return am.removeAddressByKeyNoLock(key)
+}
+
+func (am *AddressManager) removeAddressByKeyNoLock(key addressKey) error {
+	if address, ok := am.store.getNotBanned(key); ok {
+		delete(am.bucketOf(address), key)
+	}
 	return am.store.remove(key)
 }
 
-// AddAddress adds address to the address manager
+// AddAddress adds address to the address manager, as its own source
 func (am *AddressManager) AddAddress(address *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.addAddressNoLock(address)
+	return am.addAddressNoLock(address, address)
 }
 
-// AddAddresses adds addresses to the address manager
+// AddAddresses adds addresses to the address manager, each as its own source
 func (am *AddressManager) AddAddresses(addresses ...*appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	for _, address := range addresses {
-		err := am.addAddressNoLock(address)
+		err := am.addAddressNoLock(address, address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAddressesFromSource adds addresses that were received from the given
+// source to the address manager. Addresses from a single source may only
+// take up a limited portion of the address manager
+func (am *AddressManager) AddAddressesFromSource(source *appmessage.NetAddress, addresses ...*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for _, address := range addresses {
+		err := am.addAddressNoLock(address, source)
 		if err != nil {
 			return err
 		}
@@ -176,6 +208,19 @@ func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress)
 		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
 	}
 	entry.connectionFailedCount = 0
+	entry.netAddress = &appmessage.NetAddress{
+		IP:        entry.netAddress.IP,
+		Port:      entry.netAddress.Port,
+		Timestamp: mstime.Now(),
+	}
+	if !entry.isTried {
+		delete(am.bucketOf(entry), key)
+		entry.isTried = true
+		err := am.addToTriedBucketNoLock(entry)
+		if err != nil {
+			return err
+		}
+	}
 	return am.store.updateNotBanned(key, entry)
 }
 
@@ -187,16 +232,27 @@ func (am *AddressManager) Addresses() []*appmessage.NetAddress {
 	return am.store.getAllNotBannedNetAddresses()
 }
 
-// BannedAddresses returns all banned addresses
-func (am *AddressManager) BannedAddresses() []*appmessage.NetAddress {
+// Bans returns all the bans that haven't expired yet
+func (am *AddressManager) Bans() ([]*BanInfo, error) {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.store.getAllBannedNetAddresses()
+	err := am.removeExpiredBansNoLock()
+	if err != nil {
+		return nil, err
+	}
+	bans := am.store.getAllBans()
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].CreatedAt.Before(bans[j].CreatedAt)
+	})
+	return bans, nil
 }
 
-// notBannedAddressesWithException returns all not banned addresses with excpetion
-func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
+// notBannedAddressesWithException returns all not banned addresses with excpetion,
+// separated to tried and new addresses
+func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) (
+	triedAddresses []*appmessage.NetAddress, newAddresses []*appmessage.NetAddress) {
+
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
@@ -205,14 +261,31 @@ func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessa
 
 // RandomAddress returns a random address that isn't banned and isn't in exceptions
 func (am *AddressManager) RandomAddress(exceptions []*appmessage.NetAddress) *appmessage.NetAddress {
-	validAddresses := am.notBannedAddressesWithException(exceptions)
-	return am.random.RandomAddress(validAddresses)
+	addresses := am.RandomAddresses(1, exceptions)
+	if len(addresses) == 0 {
+		return nil
+	}
+	return addresses[0]
 }
 
-// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions
+// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions.
+// Half of the addresses are taken from the tried table and half from the new table, as long as
+// there are enough addresses in both.
 func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
-	validAddresses := am.notBannedAddressesWithException(exceptions)
-	return am.random.RandomAddresses(validAddresses, count)
+	triedAddresses, newAddresses := am.notBannedAddressesWithException(exceptions)
+
+	newCount := count / 2
+	if newCount > len(newAddresses) {
+		newCount = len(newAddresses)
+	}
+	triedCount := count - newCount
+	if triedCount > len(triedAddresses) {
+		triedCount = len(triedAddresses)
+		newCount = count - triedCount
+	}
+
+	randomAddresses := am.random.RandomAddresses(triedAddresses, triedCount)
+	return append(randomAddresses, am.random.RandomAddresses(newAddresses, newCount)...)
 }
 
 // BestLocalAddress returns the most appropriate local address to use
@@ -221,77 +294,137 @@ func (am *AddressManager) BestLocalAddress(remoteAddress *appmessage.NetAddress)
 	return am.localAddresses.bestLocalAddress(remoteAddress)
 }
 
-// Ban marks the given address as banned
-func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress) error {
+// Ban bans the IP of the given address for the given duration
+func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress, duration time.Duration, reason string) error {
+	return am.BanSubnet(HostSubnet(addressToBan.IP), duration, reason)
+}
+
+// BanSubnet bans all the IPs of the given subnet for the given duration.
+// Banning an already banned subnet replaces its ban.
+func (am *AddressManager) BanSubnet(subnet *net.IPNet, duration time.Duration, reason string) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	keyToBan := netAddressKey(addressToBan)
 	keysToDelete := make([]addressKey, 0)
 	for _, address := range am.store.getAllNotBannedNetAddresses() {
-		key := netAddressKey(address)
-		if key.address.equal(keyToBan.address) {
-			keysToDelete = append(keysToDelete, key)
+		if subnet.Contains(address.IP) {
+			keysToDelete = append(keysToDelete, netAddressKey(address))
 		}
 	}
 	for _, key := range keysToDelete {
-		err := am.store.remove(key)
+		err := am.removeAddressByKeyNoLock(key)
 		if err != nil {
 			return err
 		}
 	}
 
-	address := &address{netAddress: addressToBan}
-	return am.store.addBanned(keyToBan, address)
+	// The ban takes the place of the ban scores, so that the IPs start
+	// over once the ban expires
+	err := am.store.removeBanScoresInSubnet(subnet)
+	if err != nil {
+		return err
+	}
+
+	now := mstime.Now()
+	return am.store.addBan(&BanInfo{
+		Subnet:    subnet,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	})
 }
 
-// Unban unmarks the given address as banned
+// Unban removes the ban of the IP of the given address. It doesn't remove
+// the bans of subnets that contain it.
 func (am *AddressManager) Unban(address *appmessage.NetAddress) error {
+	return am.UnbanSubnet(HostSubnet(address.IP))
+}
+
+// UnbanSubnet removes the ban of the given subnet
+func (am *AddressManager) UnbanSubnet(subnet *net.IPNet) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	key := netAddressKey(address)
-	if !am.store.isBanned(key) {
-		return errors.Wrapf(ErrAddressNotFound, "address %s "+
-			"is not registered with the address manager as banned", address.TCPAddress())
+	key := subnetKeyFromIPNet(subnet)
+	if _, ok := am.store.getBan(key); !ok {
+		return errors.Wrapf(ErrAddressNotFound, "%s "+
+			"is not registered with the address manager as banned", subnet)
 	}
 
-	return am.store.removeBanned(key)
+	return am.store.removeBan(key)
 }
 
-// IsBanned returns true if the given address is marked as banned
+// IsBanned returns true if the given address is banned, either by itself or
+// as part of a banned subnet
 func (am *AddressManager) IsBanned(address *appmessage.NetAddress) (bool, error) {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	key := netAddressKey(address)
-	err := am.unbanIfOldEnough(key)
+	err := am.removeExpiredBansNoLock()
 	if err != nil {
 		return false, err
 	}
-	if !am.store.isBanned(key) {
-		if !am.store.isNotBanned(key) {
-			return false, errors.Wrapf(ErrAddressNotFound, "address %s "+
-				"is not registered with the address manager", address.TCPAddress())
+	for _, ban := range am.store.getAllBans() {
+		if ban.Subnet.Contains(address.IP) {
+			return true, nil
 		}
-		return false, nil
 	}
 
-	return true, nil
+	key := netAddressKey(address)
+	if !am.store.isNotBanned(key) {
+		return false, errors.Wrapf(ErrAddressNotFound, "address %s "+
+			"is not registered with the address manager", address.TCPAddress())
+	}
+	return false, nil
 }
 
-func (am *AddressManager) unbanIfOldEnough(key addressKey) error {
-	address, ok := am.store.getBanned(key)
+func (am *AddressManager) removeExpiredBansNoLock() error {
+	now := mstime.Now()
+	for _, ban := range am.store.getAllBans() {
+		if ban.isExpired(now) {
+			err := am.store.removeBan(subnetKeyFromIPNet(ban.Subnet))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IncreaseBanScore adds the given increment to the decaying ban score of the
+// given IP, and returns the resulting score
+func (am *AddressManager) IncreaseBanScore(ip net.IP, increment uint32) (uint32, error) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	key := ipv6FromIP(ip)
+	now := mstime.Now()
+	score := float64(increment)
+	if currentBanScore, ok := am.store.getBanScore(key); ok {
+		score += currentBanScore.decayedScore(now)
+	}
+
+	err := am.store.setBanScore(key, &banScore{score: score, lastUpdated: now})
+	if err != nil {
+		return 0, err
+	}
+	return uint32(math.Round(score)), nil
+}
+
+// BanScore returns the current ban score of the given IP
+func (am *AddressManager) BanScore(ip net.IP) (uint32, error) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	key := ipv6FromIP(ip)
+	currentBanScore, ok := am.store.getBanScore(key)
 	if !ok {
-		return nil
+		return 0, nil
 	}
 
-	const maxBanTime = 24 * time.Hour
-	if mstime.Since(address.netAddress.Timestamp) > maxBanTime {
-		err := am.store.removeBanned(key)
-		if err != nil {
-			return err
-		}
+	score := currentBanScore.decayedScore(mstime.Now())
+	if score < minBanScore {
+		return 0, am.store.removeBanScore(key)
 	}
-	return nil
+	return uint32(math.Round(score)), nil
 }
@@ -8,6 +8,7 @@ import (
 	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/config"
@@ -178,7 +179,7 @@ func TestAddressManager(t *testing.T) {
 
 	// Ban a different address
 	addressToBan := testAddress3
-	err = addressManager.Ban(addressToBan)
+	err = addressManager.Ban(addressToBan, time.Hour, "test ban")
 	if err != nil {
 		t.Fatalf("Ban() failed: %s", err)
 	}
@@ -204,14 +205,17 @@ func TestAddressManager(t *testing.T) {
 		t.Fatalf("Adderss %s is unexpectedly not banned", addressToBan.IP)
 	}
 
-	// Check that BannedAddresses() returns the banned address
-	bannedAddresses := addressManager.BannedAddresses()
-	if len(bannedAddresses) != 1 {
-		t.Fatalf("Unexpected amount of addresses returned from BannedAddresses(). "+
-			"Want: %d, got: %d", 1, len(bannedAddresses))
+	// Check that Bans() returns the ban of the banned address
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 1 {
+		t.Fatalf("Unexpected amount of bans returned from Bans(). "+
+			"Want: %d, got: %d", 1, len(bans))
 	}
-	if !reflect.DeepEqual(addressToBan, bannedAddresses[0]) {
-		t.Fatalf("Banned address %s not returned from BannedAddresses()", addressToBan.IP)
+	if !bans[0].Subnet.IP.Equal(addressToBan.IP) || bans[0].Reason != "test ban" {
+		t.Fatalf("Ban of address %s not returned from Bans()", addressToBan.IP)
 	}
 
 	// Unban the address
@@ -220,11 +224,14 @@ func TestAddressManager(t *testing.T) {
 		t.Fatalf("Unban() failed: %s", err)
 	}
 
-	// Check that BannedAddresses() not longer returns the banned address
-	bannedAddresses = addressManager.BannedAddresses()
-	if len(bannedAddresses) != 0 {
-		t.Fatalf("Unexpected amount of addresses returned from BannedAddresses(). "+
-			"Want: %d, got: %d", 0, len(bannedAddresses))
+	// Check that Bans() no longer returns the ban of the banned address
+	bans, err = addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 0 {
+		t.Fatalf("Unexpected amount of bans returned from Bans(). "+
+			"Want: %d, got: %d", 0, len(bans))
 	}
 }
 
@@ -258,7 +265,7 @@ func TestRestoreAddressManager(t *testing.T) {
 
 	// Ban one of the addresses
 	addressToBan := testAddress1
-	err = addressManager.Ban(addressToBan)
+	err = addressManager.Ban(addressToBan, time.Hour, "test ban")
 	if err != nil {
 		t.Fatalf("Ban() failed: %s", err)
 	}
@@ -294,14 +301,17 @@ func TestRestoreAddressManager(t *testing.T) {
 		}
 	}
 
-	// Make sure that BannedAddresses() returns the correct addresses
-	bannedAddresses := addressManager.BannedAddresses()
-	if len(bannedAddresses) != 1 {
-		t.Fatalf("Unexpected amount of addresses returned from BannedAddresses(). "+
-			"Want: %d, got: %d", 1, len(bannedAddresses))
+	// Make sure that Bans() returns the correct bans
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 1 {
+		t.Fatalf("Unexpected amount of bans returned from Bans(). "+
+			"Want: %d, got: %d", 1, len(bans))
 	}
-	if !reflect.DeepEqual(addressToBan, bannedAddresses[0]) {
-		t.Fatalf("Banned address %s not returned from BannedAddresses()", addressToBan.IP)
+	if !bans[0].Subnet.IP.Equal(addressToBan.IP) || bans[0].Reason != "test ban" {
+		t.Fatalf("Ban of address %s not returned from Bans()", addressToBan.IP)
 	}
 }
 
@@ -333,42 +343,59 @@ func TestOverfillAddressManager(t *testing.T) {
 		t.Fatalf("AddAddress: %s", err)
 	}
 
-	// Add `maxAddresses-1` addresses to the address manager
-	addresses := generateTestAddresses(maxAddresses - 1)
+	// Add twice `bucketSize` addresses of a single network group to
+	// the address manager. Since they are their own source, they all
+	// fall in the same new bucket
+	addresses := generateTestAddresses(2 * bucketSize)
 	err = addressManager.AddAddresses(addresses...)
 	if err != nil {
 		t.Fatalf("AddAddresses: %s", err)
 	}
 
-	// Make sure that it now contains exactly `maxAddresses` entries
+	// Make sure that it now contains exactly `bucketSize` entries of that
+	// network group, in addition to the first test address
 	returnedAddresses := addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	if len(returnedAddresses) != bucketSize+1 {
+		t.Fatalf("Unexpected address amount. Want: %d, got: %d", bucketSize+1, len(returnedAddresses))
 	}
 
-	// Mark the first test address as a connection failure
-	err = addressManager.MarkConnectionFailure(testAddress)
+	// Mark one of the addresses of the full bucket as a connection failure
+	var failedAddress *appmessage.NetAddress
+	for _, address := range returnedAddresses {
+		if !address.IP.Equal(testAddress.IP) {
+			failedAddress = address
+			break
+		}
+	}
+	err = addressManager.MarkConnectionFailure(failedAddress)
 	if err != nil {
 		t.Fatalf("MarkConnectionFailure: %s", err)
 	}
 
-	// Add one more address to the address manager
-	err = addressManager.AddAddress(&appmessage.NetAddress{IP: net.IP{7, 8, 0, 0}, Timestamp: mstime.Now()})
+	// Add one more address of that network group to the address manager
+	err = addressManager.AddAddress(&appmessage.NetAddress{IP: net.IP{1, 2, 200, 0}, Timestamp: mstime.Now()})
 	if err != nil {
 		t.Fatalf("AddAddress: %s", err)
 	}
 
-	// Make sure that it now still contains exactly `maxAddresses` entries
+	// Make sure that it now still contains exactly `bucketSize+1` entries
 	returnedAddresses = addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	if len(returnedAddresses) != bucketSize+1 {
+		t.Fatalf("Unexpected address amount. Want: %d, got: %d", bucketSize+1, len(returnedAddresses))
 	}
 
-	// Make sure that the first address is no longer in the
-	// connection manager
+	// Make sure that the failed address is no longer in the address
+	// manager, and that the first test address is
+	foundTestAddress := false
 	for _, address := range returnedAddresses {
+		if address.IP.Equal(failedAddress.IP) {
+			t.Fatalf("Unexpectedly found the failed address in the returned addresses")
+		}
 		if address.IP.Equal(testAddress.IP) {
-			t.Fatalf("Unexpectedly found testAddress returned addresses")
+			foundTestAddress = true
 		}
 	}
+	if !foundTestAddress {
+		t.Fatalf("Unexpectedly didn't find testAddress in the returned addresses")
+	}
 }
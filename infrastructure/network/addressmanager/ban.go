@@ -0,0 +1,82 @@
+package addressmanager
+
+import (
+	"net"
+	"strings"
+
+	"github.com/kaspanet/kaspad/util/mstime"
+	"github.com/pkg/errors"
+)
+
+// BanInfo describes the ban of a single IP or of a whole subnet
+type BanInfo struct {
+	Subnet    *net.IPNet
+	Reason    string
+	CreatedAt mstime.Time
+	ExpiresAt mstime.Time
+}
+
+func (bi *BanInfo) isExpired(now mstime.Time) bool {
+	return !now.Before(bi.ExpiresAt)
+}
+
+// subnetKey represents a subnet in V6 representation, to use it in maps
+type subnetKey struct {
+	address      ipv6
+	prefixLength uint8
+}
+
+func subnetKeyFromIPNet(subnet *net.IPNet) subnetKey {
+	ones, bits := subnet.Mask.Size()
+	if bits == net.IPv4len*8 {
+		// all IPv4 subnets can be represented as IPv4-mapped IPv6 subnets.
+		ones += (net.IPv6len - net.IPv4len) * 8
+	}
+	return subnetKey{
+		address:      ipv6FromIP(subnet.IP.Mask(subnet.Mask)),
+		prefixLength: uint8(ones),
+	}
+}
+
+func (key subnetKey) ipNet() *net.IPNet {
+	const ipv4MappedPrefixLength = (net.IPv6len - net.IPv4len) * 8
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, key.address[:])
+	if ip4 := ip.To4(); ip4 != nil && key.prefixLength >= ipv4MappedPrefixLength {
+		return &net.IPNet{
+			IP:   ip4,
+			Mask: net.CIDRMask(int(key.prefixLength)-ipv4MappedPrefixLength, net.IPv4len*8),
+		}
+	}
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(int(key.prefixLength), net.IPv6len*8),
+	}
+}
+
+// HostSubnet returns the subnet that contains only the given IP
+func HostSubnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(net.IPv4len*8, net.IPv4len*8)}
+	}
+	return &net.IPNet{IP: ip.To16(), Mask: net.CIDRMask(net.IPv6len*8, net.IPv6len*8)}
+}
+
+// ParseSubnet parses either a single IP or a subnet in CIDR notation,
+// e.g. "192.168.0.1" or "192.168.0.0/16"
+func ParseSubnet(subnetString string) (*net.IPNet, error) {
+	if !strings.Contains(subnetString, "/") {
+		ip := net.ParseIP(subnetString)
+		if ip == nil {
+			return nil, errors.Errorf("could not parse IP %s", subnetString)
+		}
+		return HostSubnet(ip), nil
+	}
+
+	_, subnet, err := net.ParseCIDR(subnetString)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse subnet %s", subnetString)
+	}
+	return subnet, nil
+}
@@ -0,0 +1,159 @@
+package addressmanager
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+func TestSubnetKey(t *testing.T) {
+	tests := []string{
+		"1.2.3.4",
+		"10.0.0.0/8",
+		"0.0.0.0/0",
+		"2602:100:abcd::102",
+		"2602:100::/32",
+		"::/0",
+	}
+	for _, test := range tests {
+		subnet, err := ParseSubnet(test)
+		if err != nil {
+			t.Fatalf("ParseSubnet(%s) failed: %s", test, err)
+		}
+		key := subnetKeyFromIPNet(subnet)
+		if key.ipNet().String() != subnet.String() {
+			t.Errorf("Subnet %s was converted to %s", subnet, key.ipNet())
+		}
+	}
+
+	for _, invalid := range []string{"1.2.3", "1.2.3.4/33", "not-an-ip"} {
+		_, err := ParseSubnet(invalid)
+		if err == nil {
+			t.Errorf("ParseSubnet(%s) unexpectedly succeeded", invalid)
+		}
+	}
+}
+
+func TestBanSubnet(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestBanSubnet")
+	defer teardown()
+
+	inSubnet := &appmessage.NetAddress{IP: net.ParseIP("10.1.2.3"), Port: 16111, Timestamp: mstime.Now()}
+	outOfSubnet := &appmessage.NetAddress{IP: net.ParseIP("11.1.2.3"), Port: 16111, Timestamp: mstime.Now()}
+	err := addressManager.AddAddresses(inSubnet, outOfSubnet)
+	if err != nil {
+		t.Fatalf("AddAddresses() failed: %s", err)
+	}
+
+	subnet, err := ParseSubnet("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("ParseSubnet() failed: %s", err)
+	}
+	err = addressManager.BanSubnet(subnet, time.Hour, "test subnet ban")
+	if err != nil {
+		t.Fatalf("BanSubnet() failed: %s", err)
+	}
+
+	isBanned, err := addressManager.IsBanned(inSubnet)
+	if err != nil {
+		t.Fatalf("IsBanned() failed: %s", err)
+	}
+	if !isBanned {
+		t.Fatalf("Address %s in the banned subnet is unexpectedly not banned", inSubnet.IP)
+	}
+	isBanned, err = addressManager.IsBanned(outOfSubnet)
+	if err != nil {
+		t.Fatalf("IsBanned() failed: %s", err)
+	}
+	if isBanned {
+		t.Fatalf("Address %s out of the banned subnet is unexpectedly banned", outOfSubnet.IP)
+	}
+	addresses := addressManager.Addresses()
+	if len(addresses) != 1 || !addresses[0].IP.Equal(outOfSubnet.IP) {
+		t.Fatalf("Addresses in the banned subnet were not removed: %v", addresses)
+	}
+
+	err = addressManager.UnbanSubnet(subnet)
+	if err != nil {
+		t.Fatalf("UnbanSubnet() failed: %s", err)
+	}
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 0 {
+		t.Fatalf("Unexpected amount of bans after UnbanSubnet(). Want: %d, got: %d", 0, len(bans))
+	}
+}
+
+func TestBanExpiry(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestBanExpiry")
+	defer teardown()
+
+	address := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: mstime.Now()}
+	err := addressManager.Ban(address, -time.Second, "expired ban")
+	if err != nil {
+		t.Fatalf("Ban() failed: %s", err)
+	}
+
+	_, err = addressManager.IsBanned(address)
+	if err == nil {
+		t.Fatalf("IsBanned() of an address with an expired ban unexpectedly succeeded")
+	}
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 0 {
+		t.Fatalf("Unexpected amount of bans after expiry. Want: %d, got: %d", 0, len(bans))
+	}
+}
+
+func TestMigrateLegacyBannedAddresses(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	// Write a ban the way older versions did
+	bannedAt := mstime.Now()
+	legacyBan := &address{netAddress: &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: bannedAt}}
+	var legacyKey ipv6
+	copy(legacyKey[:], legacyBan.netAddress.IP.To16())
+	err = database.Put(legacyBannedAddressBucket.Key(legacyKey[:]), (&addressStore{}).serializeAddress(legacyBan))
+	if err != nil {
+		t.Fatalf("Put() failed: %s", err)
+	}
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans() failed: %s", err)
+	}
+	if len(bans) != 1 {
+		t.Fatalf("Unexpected amount of migrated bans. Want: %d, got: %d", 1, len(bans))
+	}
+	if bans[0].Subnet.String() != "1.2.3.4/32" || bans[0].ExpiresAt != bannedAt.Add(legacyBanDuration) {
+		t.Fatalf("Unexpected migrated ban %+v", bans[0])
+	}
+
+	hasLegacyBan, err := database.Has(legacyBannedAddressBucket.Key(legacyKey[:]))
+	if err != nil {
+		t.Fatalf("Has() failed: %s", err)
+	}
+	if hasLegacyBan {
+		t.Fatalf("The legacy ban was not removed")
+	}
+}
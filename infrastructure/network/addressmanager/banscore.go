@@ -0,0 +1,32 @@
+package addressmanager
+
+import (
+	"math"
+	"time"
+
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+// banScoreHalfLife is the time it takes for a ban score to decay to
+// half of its value
+const banScoreHalfLife = time.Hour
+
+// minBanScore is the score below which a decaying ban score is forgotten
+const minBanScore = 1
+
+// banScore is the accumulated misbehavior score of an IP. The score decays
+// exponentially over time, so that only misbehavior that accumulates faster
+// than it decays ends in a ban.
+type banScore struct {
+	score       float64
+	lastUpdated mstime.Time
+}
+
+// decayedScore returns the score as it has decayed by the given time
+func (bs *banScore) decayedScore(now mstime.Time) float64 {
+	elapsed := now.Sub(bs.lastUpdated)
+	if elapsed <= 0 {
+		return bs.score
+	}
+	return bs.score * math.Exp2(-float64(elapsed)/float64(banScoreHalfLife))
+}
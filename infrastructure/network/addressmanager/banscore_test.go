@@ -0,0 +1,118 @@
+package addressmanager
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+func TestBanScoreDecay(t *testing.T) {
+	now := mstime.Now()
+	tests := []struct {
+		elapsed       mstime.Time
+		expectedScore float64
+	}{
+		{elapsed: now, expectedScore: 80},
+		{elapsed: now.Add(banScoreHalfLife), expectedScore: 40},
+		{elapsed: now.Add(2 * banScoreHalfLife), expectedScore: 20},
+		{elapsed: now.Add(-banScoreHalfLife), expectedScore: 80},
+	}
+
+	banScore := &banScore{score: 80, lastUpdated: now}
+	for _, test := range tests {
+		score := banScore.decayedScore(test.elapsed)
+		if score != test.expectedScore {
+			t.Errorf("Unexpected decayed score after %s. Want: %f, got: %f",
+				test.elapsed.Sub(now), test.expectedScore, score)
+		}
+	}
+}
+
+func TestBanScore(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	ip := net.ParseIP("1.2.3.4")
+	otherIP := net.ParseIP("5.6.7.8")
+
+	// Ban scores accumulate
+	_, err = addressManager.IncreaseBanScore(ip, 20)
+	if err != nil {
+		t.Fatalf("IncreaseBanScore() failed: %s", err)
+	}
+	score, err := addressManager.IncreaseBanScore(ip, 30)
+	if err != nil {
+		t.Fatalf("IncreaseBanScore() failed: %s", err)
+	}
+	if score != 50 {
+		t.Fatalf("Unexpected ban score. Want: %d, got: %d", 50, score)
+	}
+
+	// Decay a score completely, so that it's forgotten on restore
+	_, err = addressManager.IncreaseBanScore(otherIP, 10)
+	if err != nil {
+		t.Fatalf("IncreaseBanScore() failed: %s", err)
+	}
+	err = addressManager.store.setBanScore(ipv6FromIP(otherIP), &banScore{
+		score:       10,
+		lastUpdated: mstime.Now().Add(-10 * banScoreHalfLife),
+	})
+	if err != nil {
+		t.Fatalf("setBanScore() failed: %s", err)
+	}
+
+	// Ban scores persist across restarts
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	score, err = addressManager.BanScore(ip)
+	if err != nil {
+		t.Fatalf("BanScore() failed: %s", err)
+	}
+	if score != 50 {
+		t.Fatalf("Unexpected ban score after restore. Want: %d, got: %d", 50, score)
+	}
+	if _, ok := addressManager.store.getBanScore(ipv6FromIP(otherIP)); ok {
+		t.Fatalf("Decayed ban score of %s was restored", otherIP)
+	}
+
+	// A ban resets the ban score
+	err = addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0), time.Hour, "test ban")
+	if err != nil {
+		t.Fatalf("Ban() failed: %s", err)
+	}
+	score, err = addressManager.BanScore(ip)
+	if err != nil {
+		t.Fatalf("BanScore() failed: %s", err)
+	}
+	if score != 0 {
+		t.Fatalf("Unexpected ban score after ban. Want: %d, got: %d", 0, score)
+	}
+}
@@ -0,0 +1,212 @@
+package addressmanager
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+// The address manager keeps the addresses it knows of in two tables: the "new"
+// table holds addresses we've heard of but haven't connected to yet, and the
+// "tried" table holds addresses we've successfully connected to at least once.
+//
+// Each table is divided into fixed size buckets. The bucket of an address is
+// chosen by hashing its network group (and, for new addresses, the network group
+// of the peer that told us about it) together with a secret key that's unique
+// to the node. This way, a single peer, or a group of peers that share a network
+// group, may only fill a small portion of the new table no matter how many
+// addresses it sends us, and an attacker can't predict which bucket an address
+// would fall in.
+const (
+	// newBucketCount is the number of buckets of the new table
+	newBucketCount = 256
+
+	// newBucketsPerSourceGroup is the number of new buckets that the
+	// addresses received from a single network group are spread over
+	newBucketsPerSourceGroup = 32
+
+	// triedBucketCount is the number of buckets of the tried table
+	triedBucketCount = 64
+
+	// triedBucketsPerGroup is the number of tried buckets that the
+	// addresses of a single network group are spread over
+	triedBucketsPerGroup = 8
+
+	// bucketSize is the maximum number of addresses in a single bucket
+	bucketSize = 64
+
+	// staleAddressAge is the age after which an address that we haven't
+	// heard of or connected to is considered stale
+	staleAddressAge = 30 * 24 * time.Hour
+
+	// maxNewAddressConnectionFailures is the number of consecutive failed
+	// connection attempts after which a new address is considered stale
+	maxNewAddressConnectionFailures = 3
+
+	// maxTriedAddressConnectionFailures is the number of consecutive failed
+	// connection attempts after which a tried address is considered stale
+	maxTriedAddressConnectionFailures = 10
+)
+
+// bucket is a set of the keys of the addresses that fall in it
+type bucket map[addressKey]struct{}
+
+// isStale returns whether the given address is no longer worth keeping,
+// either because we haven't heard of it in a long while or because we
+// failed to connect to it too many times
+func (a *address) isStale(now mstime.Time) bool {
+	if a.netAddress.Timestamp.Add(staleAddressAge).Before(now) {
+		return true
+	}
+	if a.isTried {
+		return a.connectionFailedCount >= maxTriedAddressConnectionFailures
+	}
+	return a.connectionFailedCount >= maxNewAddressConnectionFailures
+}
+
+// bucketHash hashes the given data together with the node's secret key
+func (am *AddressManager) bucketHash(data ...[]byte) uint64 {
+	hasher := sha256.New()
+	hasher.Write(am.store.secretKey)
+	for _, datum := range data {
+		hasher.Write(datum)
+	}
+	return binary.LittleEndian.Uint64(hasher.Sum(nil))
+}
+
+func (am *AddressManager) newBucketIndex(netAddress *appmessage.NetAddress, source ipv6) int {
+	group := []byte(am.GroupKey(netAddress))
+	sourceGroup := []byte(am.GroupKey(&appmessage.NetAddress{IP: net.IP(source[:])}))
+
+	var slot [8]byte
+	binary.LittleEndian.PutUint64(slot[:], am.bucketHash(group, sourceGroup)%newBucketsPerSourceGroup)
+	return int(am.bucketHash(sourceGroup, slot[:]) % newBucketCount)
+}
+
+func (am *AddressManager) triedBucketIndex(netAddress *appmessage.NetAddress) int {
+	key := netAddressKey(netAddress)
+	group := []byte(am.GroupKey(netAddress))
+
+	var slot [8]byte
+	binary.LittleEndian.PutUint64(slot[:], am.bucketHash(am.store.serializeAddressKey(key))%triedBucketsPerGroup)
+	return int(am.bucketHash(group, slot[:]) % triedBucketCount)
+}
+
+// bucketOf returns the bucket the given address falls in
+func (am *AddressManager) bucketOf(address *address) bucket {
+	if address.isTried {
+		return am.triedBuckets[am.triedBucketIndex(address.netAddress)]
+	}
+	return am.newBuckets[am.newBucketIndex(address.netAddress, address.source)]
+}
+
+// restoreBuckets places the addresses loaded from the database in their
+// buckets. Addresses that don't fit in their buckets are evicted, as they
+// would have been had they been added to the buckets one by one.
+func (am *AddressManager) restoreBuckets() error {
+	for i := range am.newBuckets {
+		am.newBuckets[i] = bucket{}
+	}
+	for i := range am.triedBuckets {
+		am.triedBuckets[i] = bucket{}
+	}
+
+	// Tried addresses go first, since the ones that don't fit in the tried
+	// table go back to the new table
+	allAddresses := am.store.getAllNotBanned()
+	for _, address := range allAddresses {
+		if address.isTried {
+			err := am.addToTriedBucketNoLock(address)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, address := range allAddresses {
+		key := netAddressKey(address.netAddress)
+		if !address.isTried && am.store.isNotBanned(key) {
+			err := am.addToNewBucketNoLock(address)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addToNewBucketNoLock places the given address in its new bucket, evicting
+// the worst address of the bucket if it's full
+func (am *AddressManager) addToNewBucketNoLock(address *address) error {
+	key := netAddressKey(address.netAddress)
+	newBucket := am.bucketOf(address)
+	if _, ok := newBucket[key]; ok {
+		return nil
+	}
+	if len(newBucket) >= bucketSize {
+		toEvictKey := am.evictionCandidate(newBucket)
+		err := am.removeAddressByKeyNoLock(toEvictKey)
+		if err != nil {
+			return err
+		}
+	}
+	newBucket[key] = struct{}{}
+	return nil
+}
+
+// addToTriedBucketNoLock places the given address in its tried bucket. If the
+// bucket is full, its worst address is moved back to the new table to make room
+func (am *AddressManager) addToTriedBucketNoLock(address *address) error {
+	key := netAddressKey(address.netAddress)
+	triedBucket := am.bucketOf(address)
+	if len(triedBucket) >= bucketSize {
+		toDemoteKey := am.evictionCandidate(triedBucket)
+		toDemote, _ := am.store.getNotBanned(toDemoteKey)
+		delete(triedBucket, toDemoteKey)
+
+		toDemote.isTried = false
+		err := am.store.updateNotBanned(toDemoteKey, toDemote)
+		if err != nil {
+			return err
+		}
+		err = am.addToNewBucketNoLock(toDemote)
+		if err != nil {
+			return err
+		}
+	}
+	triedBucket[key] = struct{}{}
+	return nil
+}
+
+// evictionCandidate returns the key of the address of the given bucket that
+// should be evicted first: a stale address if there is one, otherwise the one
+// we've failed to connect to the most times, and between those, the one we've
+// heard of the longest time ago
+func (am *AddressManager) evictionCandidate(bucket bucket) addressKey {
+	now := mstime.Now()
+
+	var candidateKey addressKey
+	var candidate *address
+	for key := range bucket {
+		address, _ := am.store.getNotBanned(key)
+		if candidate == nil || isWorseAddress(address, candidate, now) {
+			candidateKey = key
+			candidate = address
+		}
+	}
+	return candidateKey
+}
+
+func isWorseAddress(address *address, other *address, now mstime.Time) bool {
+	isStale, isOtherStale := address.isStale(now), other.isStale(now)
+	if isStale != isOtherStale {
+		return isStale
+	}
+	if address.connectionFailedCount != other.connectionFailedCount {
+		return address.connectionFailedCount > other.connectionFailedCount
+	}
+	return address.netAddress.Timestamp.Before(other.netAddress.Timestamp)
+}
@@ -0,0 +1,191 @@
+package addressmanager
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+func TestAddressesFromSingleSourceAreLimited(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestAddressesFromSingleSourceAreLimited")
+	defer teardown()
+
+	// Flood the address manager with addresses of many different network
+	// groups, all from a single source
+	source := &appmessage.NetAddress{IP: net.ParseIP("9.9.9.9")}
+	var floodAddresses []*appmessage.NetAddress
+	for i := byte(1); i <= 100; i++ {
+		for j := 0; j < 256; j++ {
+			floodAddresses = append(floodAddresses,
+				&appmessage.NetAddress{IP: net.IP{i, byte(j), 1, 1}, Timestamp: mstime.Now()})
+		}
+	}
+	err := addressManager.AddAddressesFromSource(source, floodAddresses...)
+	if err != nil {
+		t.Fatalf("AddAddressesFromSource: %s", err)
+	}
+
+	const maxAddressesFromSource = newBucketsPerSourceGroup * bucketSize
+	addressCount := len(addressManager.Addresses())
+	if addressCount > maxAddressesFromSource {
+		t.Fatalf("A single source filled %d addresses, which is more than the allowed %d",
+			addressCount, maxAddressesFromSource)
+	}
+
+	// Make sure that addresses from other sources are still accepted
+	otherSource := &appmessage.NetAddress{IP: net.ParseIP("8.8.8.8")}
+	otherAddress := &appmessage.NetAddress{IP: net.ParseIP("1.0.1.1"), Timestamp: mstime.Now()}
+	err = addressManager.AddAddressesFromSource(otherSource, otherAddress)
+	if err != nil {
+		t.Fatalf("AddAddressesFromSource: %s", err)
+	}
+	if !addressManager.store.isNotBanned(netAddressKey(otherAddress)) {
+		t.Fatalf("An address from a different source was not added")
+	}
+}
+
+func TestMarkConnectionSuccessMovesToTried(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	testAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Port: 16111, Timestamp: mstime.Now()}
+	testAddressKey := netAddressKey(testAddress)
+	err = addressManager.AddAddress(testAddress)
+	if err != nil {
+		t.Fatalf("AddAddress: %s", err)
+	}
+	newBucketIndex := addressManager.newBucketIndex(testAddress, ipv6FromIP(testAddress.IP))
+	if _, ok := addressManager.newBuckets[newBucketIndex][testAddressKey]; !ok {
+		t.Fatalf("A new address is not in its new bucket")
+	}
+
+	err = addressManager.MarkConnectionSuccess(testAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess: %s", err)
+	}
+	if _, ok := addressManager.newBuckets[newBucketIndex][testAddressKey]; ok {
+		t.Fatalf("A tried address is still in its new bucket")
+	}
+	triedBucketIndex := addressManager.triedBucketIndex(testAddress)
+	if _, ok := addressManager.triedBuckets[triedBucketIndex][testAddressKey]; !ok {
+		t.Fatalf("A tried address is not in its tried bucket")
+	}
+
+	// Reopen the database and make sure that the address is still tried,
+	// and that it's placed in the same bucket
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	if _, ok := addressManager.triedBuckets[triedBucketIndex][testAddressKey]; !ok {
+		t.Fatalf("A restored tried address is not in its tried bucket")
+	}
+}
+
+func TestRandomAddressesFromBothTables(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestRandomAddressesFromBothTables")
+	defer teardown()
+
+	var triedAddresses []*appmessage.NetAddress
+	for i := byte(0); i < 10; i++ {
+		newAddress := &appmessage.NetAddress{IP: net.IP{1, i, 0, 1}, Timestamp: mstime.Now()}
+		triedAddress := &appmessage.NetAddress{IP: net.IP{2, i, 0, 1}, Timestamp: mstime.Now()}
+		err := addressManager.AddAddresses(newAddress, triedAddress)
+		if err != nil {
+			t.Fatalf("AddAddresses: %s", err)
+		}
+		err = addressManager.MarkConnectionSuccess(triedAddress)
+		if err != nil {
+			t.Fatalf("MarkConnectionSuccess: %s", err)
+		}
+		triedAddresses = append(triedAddresses, triedAddress)
+	}
+
+	isTried := func(netAddress *appmessage.NetAddress) bool {
+		address, _ := addressManager.store.getNotBanned(netAddressKey(netAddress))
+		return address.isTried
+	}
+
+	randomAddresses := addressManager.RandomAddresses(4, nil)
+	if len(randomAddresses) != 4 {
+		t.Fatalf("Unexpected amount of random addresses. Want: %d, got: %d", 4, len(randomAddresses))
+	}
+	triedCount := 0
+	for _, randomAddress := range randomAddresses {
+		if isTried(randomAddress) {
+			triedCount++
+		}
+	}
+	if triedCount != 2 {
+		t.Fatalf("Unexpected amount of tried random addresses. Want: %d, got: %d", 2, triedCount)
+	}
+
+	// When there aren't enough tried addresses, the rest are new
+	randomAddresses = addressManager.RandomAddresses(15, triedAddresses[:8])
+	triedCount = 0
+	for _, randomAddress := range randomAddresses {
+		if isTried(randomAddress) {
+			triedCount++
+		}
+	}
+	if len(randomAddresses) != 12 || triedCount != 2 {
+		t.Fatalf("Unexpected random addresses. Want: 12 addresses of which 2 tried, "+
+			"got: %d addresses of which %d tried", len(randomAddresses), triedCount)
+	}
+}
+
+func TestLegacyAddressDeserialization(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestLegacyAddressDeserialization")
+	defer teardown()
+	addressStore := addressManager.store
+
+	testAddress := &address{
+		netAddress: &appmessage.NetAddress{
+			IP:        net.ParseIP("2602:100:abcd::102"),
+			Port:      12345,
+			Timestamp: mstime.Now(),
+		},
+		connectionFailedCount: 5,
+		isTried:               true,
+		source:                ipv6FromIP(net.ParseIP("1.2.3.4")),
+	}
+
+	// Addresses of older versions are regarded as new addresses that
+	// were their own source
+	legacySerializedAddress := addressStore.serializeAddress(testAddress)[:legacySerializedAddressSize]
+	deserializedAddress := addressStore.deserializeAddress(legacySerializedAddress)
+
+	expectedAddress := *testAddress
+	expectedAddress.isTried = false
+	expectedAddress.source = ipv6FromIP(testAddress.netAddress.IP)
+	if !reflect.DeepEqual(&expectedAddress, deserializedAddress) {
+		t.Fatalf("Unexpected deserialized legacy address\n"+
+			"expected:%+v\ndeserialized:%+v", &expectedAddress, deserializedAddress)
+	}
+}
@@ -1,44 +1,98 @@
 package addressmanager
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/util/mstime"
 	"github.com/pkg/errors"
+	"math"
 	"net"
+	"time"
 )
 
 var notBannedAddressBucket = database.MakeBucket([]byte("not-banned-addresses"))
-var bannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+var banBucket = database.MakeBucket([]byte("bans"))
+
+// legacyBannedAddressBucket holds the bans of single IPs of older versions,
+// which didn't record the reason and the expiry of bans
+var legacyBannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+
+// legacyBanDuration is the duration of the bans of older versions
+const legacyBanDuration = 24 * time.Hour
+
+var banScoreBucket = database.MakeBucket([]byte("ban-scores"))
+
+// secretKeyKey holds the node's secret key, which is used to spread
+// addresses over the buckets of the address tables
+var secretKeyKey = database.MakeBucket(nil).Key([]byte("address-manager-secret-key"))
+
+const secretKeySize = 32
 
 type addressStore struct {
 	database           database.Database
 	notBannedAddresses map[addressKey]*address
-	bannedAddresses    map[ipv6]*address
+	bans               map[subnetKey]*BanInfo
+	banScores          map[ipv6]*banScore
+	secretKey          []byte
 }
 
 func newAddressStore(database database.Database) (*addressStore, error) {
 	addressStore := &addressStore{
 		database:           database,
 		notBannedAddresses: map[addressKey]*address{},
-		bannedAddresses:    map[ipv6]*address{},
+		bans:               map[subnetKey]*BanInfo{},
+		banScores:          map[ipv6]*banScore{},
+	}
+	err := addressStore.restoreSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	err = addressStore.restoreNotBannedAddresses()
+	if err != nil {
+		return nil, err
+	}
+	err = addressStore.migrateLegacyBannedAddresses()
+	if err != nil {
+		return nil, err
 	}
-	err := addressStore.restoreNotBannedAddresses()
+	err = addressStore.restoreBans()
 	if err != nil {
 		return nil, err
 	}
-	err = addressStore.restoreBannedAddresses()
+	err = addressStore.restoreBanScores()
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("Loaded %d addresses and %d banned addresses",
-		len(addressStore.notBannedAddresses), len(addressStore.bannedAddresses))
+	log.Infof("Loaded %d addresses and %d bans",
+		len(addressStore.notBannedAddresses), len(addressStore.bans))
 
 	return addressStore, nil
 }
 
+// restoreSecretKey loads the node's secret key, and generates and stores
+// one if this is the node's first run
+func (as *addressStore) restoreSecretKey() error {
+	secretKey, err := as.database.Get(secretKeyKey)
+	if err == nil {
+		as.secretKey = secretKey
+		return nil
+	}
+	if !database.IsNotFoundError(err) {
+		return err
+	}
+
+	secretKey = make([]byte, secretKeySize)
+	_, err = rand.Read(secretKey)
+	if err != nil {
+		return errors.Wrap(err, "could not generate the address manager secret key")
+	}
+	as.secretKey = secretKey
+	return as.database.Put(secretKeyKey, secretKey)
+}
+
 func (as *addressStore) restoreNotBannedAddresses() error {
 	cursor, err := as.database.Cursor(notBannedAddressBucket)
 	if err != nil {
@@ -63,32 +117,111 @@ func (as *addressStore) restoreNotBannedAddresses() error {
 	return nil
 }
 
-func (as *addressStore) restoreBannedAddresses() error {
-	cursor, err := as.database.Cursor(bannedAddressBucket)
+// migrateLegacyBannedAddresses converts the bans of older versions to bans
+// that expire legacyBanDuration after they were made
+func (as *addressStore) migrateLegacyBannedAddresses() error {
+	cursor, err := as.database.Cursor(legacyBannedAddressBucket)
 	if err != nil {
 		return err
 	}
 	defer cursor.Close()
+
+	var legacyKeys []*database.Key
+	var migratedBans []*BanInfo
 	for ok := cursor.First(); ok; ok = cursor.Next() {
 		databaseKey, err := cursor.Key()
 		if err != nil {
 			return err
 		}
-		var ipv6 ipv6
-		copy(ipv6[:], databaseKey.Suffix())
-
 		serializedNetAddress, err := cursor.Value()
 		if err != nil {
 			return err
 		}
-		netAddress := as.deserializeAddress(serializedNetAddress)
-		as.bannedAddresses[ipv6] = netAddress
+		bannedAddress := as.deserializeAddress(serializedNetAddress)
+
+		legacyKeys = append(legacyKeys, databaseKey)
+		migratedBans = append(migratedBans, &BanInfo{
+			Subnet:    HostSubnet(bannedAddress.netAddress.IP),
+			CreatedAt: bannedAddress.netAddress.Timestamp,
+			ExpiresAt: bannedAddress.netAddress.Timestamp.Add(legacyBanDuration),
+		})
+	}
+
+	for i, migratedBan := range migratedBans {
+		err := as.addBan(migratedBan)
+		if err != nil {
+			return err
+		}
+		err = as.database.Delete(legacyKeys[i])
+		if err != nil {
+			return err
+		}
+	}
+	if len(migratedBans) > 0 {
+		log.Infof("Migrated %d bans of an older version", len(migratedBans))
 	}
 	return nil
 }
 
-func (as *addressStore) notBannedCount() int {
-	return len(as.notBannedAddresses)
+func (as *addressStore) restoreBans() error {
+	cursor, err := as.database.Cursor(banBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		databaseKey, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		key := as.deserializeSubnetKey(databaseKey.Suffix())
+
+		serializedBan, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		as.bans[key] = as.deserializeBan(key, serializedBan)
+	}
+	return nil
+}
+
+func (as *addressStore) restoreBanScores() error {
+	cursor, err := as.database.Cursor(banScoreBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	now := mstime.Now()
+	var forgottenBanScoreKeys []*database.Key
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		databaseKey, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		var ipv6 ipv6
+		copy(ipv6[:], databaseKey.Suffix())
+
+		serializedBanScore, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		banScore := as.deserializeBanScore(serializedBanScore)
+		if banScore.decayedScore(now) < minBanScore {
+			forgottenBanScoreKeys = append(forgottenBanScoreKeys, databaseKey)
+			continue
+		}
+		as.banScores[ipv6] = banScore
+	}
+
+	// Forget the scores that decayed completely while kaspad was down
+	for _, databaseKey := range forgottenBanScoreKeys {
+		err := as.database.Delete(databaseKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (as *addressStore) add(key addressKey, address *address) error {
@@ -144,16 +277,22 @@ func (as *addressStore) getAllNotBannedNetAddresses() []*appmessage.NetAddress {
 	return addresses
 }
 
-func (as *addressStore) getAllNotBannedNetAddressesWithout(ignoredAddresses []*appmessage.NetAddress) []*appmessage.NetAddress {
+func (as *addressStore) getAllNotBannedNetAddressesWithout(ignoredAddresses []*appmessage.NetAddress) (
+	triedAddresses []*appmessage.NetAddress, newAddresses []*appmessage.NetAddress) {
+
 	ignoredKeys := netAddressesKeys(ignoredAddresses)
 
-	addresses := make([]*appmessage.NetAddress, 0, len(as.notBannedAddresses))
 	for key, address := range as.notBannedAddresses {
-		if !ignoredKeys[key] {
-			addresses = append(addresses, address.netAddress)
+		if ignoredKeys[key] {
+			continue
+		}
+		if address.isTried {
+			triedAddresses = append(triedAddresses, address.netAddress)
+		} else {
+			newAddresses = append(newAddresses, address.netAddress)
 		}
 	}
-	return addresses
+	return triedAddresses, newAddresses
 }
 
 func (as *addressStore) isNotBanned(key addressKey) bool {
@@ -161,41 +300,68 @@ func (as *addressStore) isNotBanned(key addressKey) bool {
 	return ok
 }
 
-func (as *addressStore) addBanned(key addressKey, address *address) error {
-	if _, ok := as.bannedAddresses[key.address]; ok {
-		return nil
-	}
+func (as *addressStore) addBan(ban *BanInfo) error {
+	key := subnetKeyFromIPNet(ban.Subnet)
+	as.bans[key] = ban
 
-	as.bannedAddresses[key.address] = address
-
-	databaseKey := as.bannedDatabaseKey(key)
-	serializedAddress := as.serializeAddress(address)
-	return as.database.Put(databaseKey, serializedAddress)
+	databaseKey := as.banDatabaseKey(key)
+	serializedBan := as.serializeBan(ban)
+	return as.database.Put(databaseKey, serializedBan)
 }
 
-func (as *addressStore) removeBanned(key addressKey) error {
-	delete(as.bannedAddresses, key.address)
+func (as *addressStore) removeBan(key subnetKey) error {
+	delete(as.bans, key)
 
-	databaseKey := as.bannedDatabaseKey(key)
+	databaseKey := as.banDatabaseKey(key)
 	return as.database.Delete(databaseKey)
 }
 
-func (as *addressStore) getAllBannedNetAddresses() []*appmessage.NetAddress {
-	bannedAddresses := make([]*appmessage.NetAddress, 0, len(as.bannedAddresses))
-	for _, bannedAddress := range as.bannedAddresses {
-		bannedAddresses = append(bannedAddresses, bannedAddress.netAddress)
+func (as *addressStore) getBan(key subnetKey) (*BanInfo, bool) {
+	ban, ok := as.bans[key]
+	return ban, ok
+}
+
+func (as *addressStore) getAllBans() []*BanInfo {
+	bans := make([]*BanInfo, 0, len(as.bans))
+	for _, ban := range as.bans {
+		bans = append(bans, ban)
 	}
-	return bannedAddresses
+	return bans
 }
 
-func (as *addressStore) isBanned(key addressKey) bool {
-	_, ok := as.bannedAddresses[key.address]
-	return ok
+func (as *addressStore) getBanScore(ip ipv6) (*banScore, bool) {
+	banScore, ok := as.banScores[ip]
+	return banScore, ok
 }
 
-func (as *addressStore) getBanned(key addressKey) (*address, bool) {
-	bannedAddress, ok := as.bannedAddresses[key.address]
-	return bannedAddress, ok
+func (as *addressStore) setBanScore(ip ipv6, banScore *banScore) error {
+	as.banScores[ip] = banScore
+
+	databaseKey := as.banScoreDatabaseKey(ip)
+	serializedBanScore := as.serializeBanScore(banScore)
+	return as.database.Put(databaseKey, serializedBanScore)
+}
+
+func (as *addressStore) removeBanScore(ip ipv6) error {
+	if _, ok := as.banScores[ip]; !ok {
+		return nil
+	}
+	delete(as.banScores, ip)
+
+	databaseKey := as.banScoreDatabaseKey(ip)
+	return as.database.Delete(databaseKey)
+}
+
+func (as *addressStore) removeBanScoresInSubnet(subnet *net.IPNet) error {
+	for ip := range as.banScores {
+		if subnet.Contains(ip[:]) {
+			err := as.removeBanScore(ip)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // netAddressKeys returns a key of the ip address to use it in maps.
@@ -213,8 +379,12 @@ func (as *addressStore) notBannedDatabaseKey(key addressKey) *database.Key {
 	return notBannedAddressBucket.Key(serializedKey)
 }
 
-func (as *addressStore) bannedDatabaseKey(key addressKey) *database.Key {
-	return bannedAddressBucket.Key(key.address[:])
+func (as *addressStore) banDatabaseKey(key subnetKey) *database.Key {
+	return banBucket.Key(as.serializeSubnetKey(key))
+}
+
+func (as *addressStore) banScoreDatabaseKey(ip ipv6) *database.Key {
+	return banScoreBucket.Key(ip[:])
 }
 
 func (as *addressStore) serializeAddressKey(key addressKey) []byte {
@@ -240,17 +410,26 @@ func (as *addressStore) deserializeAddressKey(serializedKey []byte) addressKey {
 }
 
 func (as *addressStore) serializeAddress(address *address) []byte {
-	serializedSize := 16 + 2 + 8 + 8 // ipv6 + port + timestamp + connectionFailedCount
+	// ipv6 + port + timestamp + connectionFailedCount + isTried + source
+	serializedSize := 16 + 2 + 8 + 8 + 1 + 16
 	serializedNetAddress := make([]byte, serializedSize)
 
 	copy(serializedNetAddress[:], address.netAddress.IP.To16()[:])
 	binary.LittleEndian.PutUint16(serializedNetAddress[16:], address.netAddress.Port)
 	binary.LittleEndian.PutUint64(serializedNetAddress[18:], uint64(address.netAddress.Timestamp.UnixMilliseconds()))
 	binary.LittleEndian.PutUint64(serializedNetAddress[26:], uint64(address.connectionFailedCount))
+	if address.isTried {
+		serializedNetAddress[34] = 1
+	}
+	copy(serializedNetAddress[35:], address.source[:])
 
 	return serializedNetAddress
 }
 
+// legacySerializedAddressSize is the size of addresses serialized by older
+// versions, which didn't record the tried flag and the source of addresses
+const legacySerializedAddressSize = 16 + 2 + 8 + 8
+
 func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
 	ip := make(net.IP, 16)
 	copy(ip[:], serializedAddress[:])
@@ -259,6 +438,15 @@ func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
 	timestamp := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedAddress[18:])))
 	connectionFailedCount := binary.LittleEndian.Uint64(serializedAddress[26:])
 
+	// Addresses of older versions are regarded as new addresses that
+	// were their own source
+	isTried := false
+	source := ipv6FromIP(ip)
+	if len(serializedAddress) > legacySerializedAddressSize {
+		isTried = serializedAddress[34] != 0
+		copy(source[:], serializedAddress[35:])
+	}
+
 	return &address{
 		netAddress: &appmessage.NetAddress{
 			IP:        ip,
@@ -266,5 +454,71 @@ func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
 			Timestamp: timestamp,
 		},
 		connectionFailedCount: connectionFailedCount,
+		isTried:               isTried,
+		source:                source,
+	}
+}
+
+func (as *addressStore) serializeBanScore(banScore *banScore) []byte {
+	serializedSize := 8 + 8 // score + lastUpdated
+	serializedBanScore := make([]byte, serializedSize)
+
+	binary.LittleEndian.PutUint64(serializedBanScore[:], math.Float64bits(banScore.score))
+	binary.LittleEndian.PutUint64(serializedBanScore[8:], uint64(banScore.lastUpdated.UnixMilliseconds()))
+
+	return serializedBanScore
+}
+
+func (as *addressStore) deserializeBanScore(serializedBanScore []byte) *banScore {
+	score := math.Float64frombits(binary.LittleEndian.Uint64(serializedBanScore[:]))
+	lastUpdated := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedBanScore[8:])))
+
+	return &banScore{
+		score:       score,
+		lastUpdated: lastUpdated,
+	}
+}
+
+func (as *addressStore) serializeSubnetKey(key subnetKey) []byte {
+	serializedSize := 16 + 1 // ipv6 + prefixLength
+	serializedKey := make([]byte, serializedSize)
+
+	copy(serializedKey[:], key.address[:])
+	serializedKey[16] = key.prefixLength
+
+	return serializedKey
+}
+
+func (as *addressStore) deserializeSubnetKey(serializedKey []byte) subnetKey {
+	var ip ipv6
+	copy(ip[:], serializedKey[:])
+
+	return subnetKey{
+		address:      ip,
+		prefixLength: serializedKey[16],
+	}
+}
+
+func (as *addressStore) serializeBan(ban *BanInfo) []byte {
+	serializedSize := 8 + 8 + len(ban.Reason) // createdAt + expiresAt + reason
+	serializedBan := make([]byte, serializedSize)
+
+	binary.LittleEndian.PutUint64(serializedBan[:], uint64(ban.CreatedAt.UnixMilliseconds()))
+	binary.LittleEndian.PutUint64(serializedBan[8:], uint64(ban.ExpiresAt.UnixMilliseconds()))
+	copy(serializedBan[16:], ban.Reason)
+
+	return serializedBan
+}
+
+func (as *addressStore) deserializeBan(key subnetKey, serializedBan []byte) *BanInfo {
+	createdAt := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedBan[:])))
+	expiresAt := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedBan[8:])))
+	reason := string(serializedBan[16:])
+
+	return &BanInfo{
+		Subnet:    key.ipNet(),
+		Reason:    reason,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
 	}
 }
@@ -36,6 +36,8 @@ func TestAddressSerialization(t *testing.T) {
 			Timestamp: mstime.Now(),
 		},
 		connectionFailedCount: 98465,
+		isTried:               true,
+		source:                ipv6FromIP(net.ParseIP("1.2.3.4")),
 	}
 
 	serializedTestAddress := addressStore.serializeAddress(testAddress)
@@ -0,0 +1,53 @@
+package connmanager
+
+import (
+	"net"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+	"github.com/pkg/errors"
+)
+
+// IncreaseBanScore increases the ban score of the IP of the given netConnection
+// by the given increment, and bans the IP for the given reason once its score
+// reaches the ban threshold. Whitelisted peers don't accumulate a ban score.
+func (c *ConnectionManager) IncreaseBanScore(netConnection *netadapter.NetConnection, increment uint32, reason string) (
+	banScore uint32, isBanned bool, err error) {
+
+	if c.isPermanent(netConnection.Address()) {
+		return 0, false, errors.Wrapf(ErrCannotBanPermanent,
+			"Cannot increase the ban score of %s because it's a permanent connection", netConnection.Address())
+	}
+
+	ip := netConnection.NetAddress().IP
+	if c.isWhitelisted(ip) {
+		return 0, false, nil
+	}
+
+	banScore, err = c.addressManager.IncreaseBanScore(ip, increment)
+	if err != nil {
+		return 0, false, err
+	}
+	if banScore < c.cfg.BanThreshold {
+		return banScore, false, nil
+	}
+
+	err = c.addressManager.Ban(netConnection.NetAddress(), c.cfg.BanDuration, reason)
+	if err != nil {
+		return 0, false, err
+	}
+	return banScore, true, nil
+}
+
+// BanScore returns the current ban score of the given IP
+func (c *ConnectionManager) BanScore(ip net.IP) (uint32, error) {
+	return c.addressManager.BanScore(ip)
+}
+
+func (c *ConnectionManager) isWhitelisted(ip net.IP) bool {
+	for _, whitelist := range c.cfg.Whitelists {
+		if whitelist.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
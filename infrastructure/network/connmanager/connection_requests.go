@@ -1,6 +1,7 @@
 package connmanager
 
 import (
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"time"
 )
 
@@ -66,7 +67,7 @@ func (c *ConnectionManager) checkRequestedConnections(connSet connectionSet) {
 		log.Debugf("Connecting to connection request %s", connReq.address)
 		err := c.initiateConnection(connReq.address)
 		if err != nil {
-			log.Infof("Couldn't connect to %s: %s", address, err)
+			log.Infof("Couldn't connect to %s: %s", logger.Attr("address", address), err)
 			// if connection request is one try - remove from pending and ignore failure
 			if !connReq.isPermanent {
 				delete(c.pendingRequested, address)
@@ -1,7 +1,7 @@
 package connmanager
 
 import (
-	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/pkg/errors"
 	"net"
 	"sync"
@@ -42,6 +42,8 @@ type ConnectionManager struct {
 
 	resetLoopChan chan struct{}
 	loopTicker    *time.Ticker
+
+	peerInfoProvider PeerInfoProvider
 }
 
 // New instantiates a new instance of a ConnectionManager
@@ -97,7 +99,7 @@ func (c *ConnectionManager) run() {
 }
 
 func (c *ConnectionManager) initiateConnection(address string) error {
-	log.Infof("Connecting to %s", address)
+	log.Infof("Connecting to %s", logger.Attr("address", address))
 	return c.netAdapter.P2PConnect(address)
 }
 
@@ -128,37 +130,59 @@ func (c *ConnectionManager) ConnectionCount() int {
 	return c.netAdapter.P2PConnectionCount()
 }
 
+// InboundAndOutboundConnectionCounts returns the counts of the connected
+// inbound and outbound connections
+func (c *ConnectionManager) InboundAndOutboundConnectionCounts() (inboundCount int, outboundCount int) {
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if connection.IsOutbound() {
+			outboundCount++
+		} else {
+			inboundCount++
+		}
+	}
+	return inboundCount, outboundCount
+}
+
 // ErrCannotBanPermanent is the error returned when trying to ban a permanent peer.
 var ErrCannotBanPermanent = errors.New("ErrCannotBanPermanent")
 
-// Ban marks the given netConnection as banned
-func (c *ConnectionManager) Ban(netConnection *netadapter.NetConnection) error {
+// Ban bans the IP of the given netConnection for the duration set by --banduration
+func (c *ConnectionManager) Ban(netConnection *netadapter.NetConnection, reason string) error {
 	if c.isPermanent(netConnection.Address()) {
 		return errors.Wrapf(ErrCannotBanPermanent, "Cannot ban %s because it's a permanent connection", netConnection.Address())
 	}
 
-	return c.addressManager.Ban(netConnection.NetAddress())
+	return c.addressManager.Ban(netConnection.NetAddress(), c.cfg.BanDuration, reason)
 }
 
-// BanByIP bans the given IP and disconnects from all the connection with that IP.
-func (c *ConnectionManager) BanByIP(ip net.IP) error {
-	ipHasPermanentConnection, err := c.ipHasPermanentConnection(ip)
+// BanSubnet bans all the IPs of the given subnet for the given duration, or for the
+// duration set by --banduration if it's zero, and disconnects from all the
+// connections with these IPs.
+func (c *ConnectionManager) BanSubnet(subnet *net.IPNet, duration time.Duration, reason string) error {
+	subnetHasPermanentConnection, err := c.subnetHasPermanentConnection(subnet)
 	if err != nil {
 		return err
 	}
 
-	if ipHasPermanentConnection {
-		return errors.Wrapf(ErrCannotBanPermanent, "Cannot ban %s because it's a permanent connection", ip)
+	if subnetHasPermanentConnection {
+		return errors.Wrapf(ErrCannotBanPermanent, "Cannot ban %s because it contains a permanent connection", subnet)
+	}
+
+	if duration == 0 {
+		duration = c.cfg.BanDuration
+	}
+	err = c.addressManager.BanSubnet(subnet, duration, reason)
+	if err != nil {
+		return err
 	}
 
 	connections := c.netAdapter.P2PConnections()
 	for _, conn := range connections {
-		if conn.NetAddress().IP.Equal(ip) {
+		if subnet.Contains(conn.NetAddress().IP) {
 			conn.Disconnect()
 		}
 	}
-
-	return c.addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0))
+	return nil
 }
 
 // IsBanned returns whether the given netConnection is banned
@@ -193,7 +217,16 @@ func (c *ConnectionManager) isPermanent(addressString string) bool {
 	return false
 }
 
-func (c *ConnectionManager) ipHasPermanentConnection(ip net.IP) (bool, error) {
+func (c *ConnectionManager) isRequested(addressString string) bool {
+	c.connectionRequestsLock.RLock()
+	defer c.connectionRequestsLock.RUnlock()
+
+	_, isActive := c.activeRequested[addressString]
+	_, isPending := c.pendingRequested[addressString]
+	return isActive || isPending
+}
+
+func (c *ConnectionManager) subnetHasPermanentConnection(subnet *net.IPNet) (bool, error) {
 	c.connectionRequestsLock.RLock()
 	defer c.connectionRequestsLock.RUnlock()
 
@@ -208,7 +241,7 @@ func (c *ConnectionManager) ipHasPermanentConnection(ip net.IP) (bool, error) {
 		}
 
 		for _, extractedIP := range ips {
-			if extractedIP.Equal(ip) {
+			if subnet.Contains(extractedIP) {
 				return true, nil
 			}
 		}
@@ -225,7 +258,7 @@ func (c *ConnectionManager) ipHasPermanentConnection(ip net.IP) (bool, error) {
 		}
 
 		for _, extractedIP := range ips {
-			if extractedIP.Equal(ip) {
+			if subnet.Contains(extractedIP) {
 				return true, nil
 			}
 		}
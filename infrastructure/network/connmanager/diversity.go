@@ -0,0 +1,218 @@
+package connmanager
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+)
+
+// evictionProtectedCount is the number of inbound peers that are protected
+// from eviction by each of the criteria in protectedInboundConnections
+const evictionProtectedCount = 4
+
+// PeerInfo holds the information about a connected peer that the
+// ConnectionManager uses to decide which inbound peers to evict
+type PeerInfo struct {
+	TimeConnected      time.Duration
+	LastPingDuration   time.Duration
+	LastBlockRelayTime time.Time
+}
+
+// PeerInfoProvider returns the PeerInfo of the peer of the given connection,
+// or false if the connection hasn't completed its handshake yet
+type PeerInfoProvider func(netConnection *netadapter.NetConnection) (*PeerInfo, bool)
+
+// SetPeerInfoProvider sets the function that provides the information
+// about connected peers that's used when evicting inbound peers
+func (c *ConnectionManager) SetPeerInfoProvider(peerInfoProvider PeerInfoProvider) {
+	c.peerInfoProvider = peerInfoProvider
+}
+
+// NetworkGroup returns the network group of the given connection, that is
+// its IPv4 /16 or IPv6 /32
+func (c *ConnectionManager) NetworkGroup(netConnection *netadapter.NetConnection) string {
+	return c.addressManager.GroupKey(netConnection.NetAddress())
+}
+
+// isExemptFromGroupLimits returns whether the given connection isn't subject to
+// the limits of connections per network group. Connections we were requested to
+// make, whitelisted connections and local connections are exempt
+func (c *ConnectionManager) isExemptFromGroupLimits(netConnection *netadapter.NetConnection) bool {
+	if c.isRequested(netConnection.Address()) || c.isWhitelisted(netConnection.NetAddress().IP) {
+		return true
+	}
+	return isExemptGroup(c.NetworkGroup(netConnection))
+}
+
+// isExemptGroup returns whether the given network group isn't subject to the
+// limits of connections per network group. All local addresses, and all
+// unroutable addresses on networks that accept them, share a single group
+// each, so limiting them would make local test networks impossible
+func isExemptGroup(group string) bool {
+	return group == "local" || group == "unroutable"
+}
+
+func (c *ConnectionManager) maxConnectionsPerGroup(isOutbound bool) int {
+	if isOutbound {
+		return c.cfg.MaxOutboundPeersPerGroup
+	}
+	return c.cfg.MaxInboundPeersPerGroup
+}
+
+// IsOverGroupLimit returns whether the given connection exceeds the limit of
+// connections of its direction per network group, set by --maxinpeerspergroup
+// and --maxoutpeerspergroup
+func (c *ConnectionManager) IsOverGroupLimit(netConnection *netadapter.NetConnection) bool {
+	maxConnectionsPerGroup := c.maxConnectionsPerGroup(netConnection.IsOutbound())
+	if maxConnectionsPerGroup == 0 || c.isExemptFromGroupLimits(netConnection) {
+		return false
+	}
+
+	group := c.NetworkGroup(netConnection)
+	groupConnectionCount := 0
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if connection.IsOutbound() == netConnection.IsOutbound() && c.NetworkGroup(connection) == group {
+			groupConnectionCount++
+		}
+	}
+	if groupConnectionCount <= maxConnectionsPerGroup {
+		return false
+	}
+
+	direction := "inbound"
+	if netConnection.IsOutbound() {
+		direction = "outbound"
+	}
+	log.Infof("Rejecting %s: network group %s already has %d %s connections, which is the maximum",
+		logger.Attr("peer", netConnection), group, maxConnectionsPerGroup, direction)
+	return true
+}
+
+// ProtectedInboundConnections returns the inbound connections that are currently
+// protected from eviction
+func (c *ConnectionManager) ProtectedInboundConnections() map[*netadapter.NetConnection]struct{} {
+	var inboundConnections []*netadapter.NetConnection
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if !connection.IsOutbound() {
+			inboundConnections = append(inboundConnections, connection)
+		}
+	}
+	return protectedInboundConnections(c.evictionCandidates(inboundConnections))
+}
+
+// evictionCandidate is an inbound connection along with the information
+// that decides whether it's evicted
+type evictionCandidate struct {
+	connection    *netadapter.NetConnection
+	group         string
+	isWhitelisted bool
+
+	// info is nil if the connection hasn't completed its handshake yet
+	info *PeerInfo
+}
+
+func (c *ConnectionManager) evictionCandidates(connections []*netadapter.NetConnection) []*evictionCandidate {
+	candidates := make([]*evictionCandidate, len(connections))
+	for i, connection := range connections {
+		candidates[i] = &evictionCandidate{
+			connection:    connection,
+			group:         c.NetworkGroup(connection),
+			isWhitelisted: c.isWhitelisted(connection.NetAddress().IP),
+		}
+		if c.peerInfoProvider != nil {
+			info, ok := c.peerInfoProvider(connection)
+			if ok {
+				candidates[i].info = info
+			}
+		}
+	}
+	return candidates
+}
+
+// timeConnected returns how long the connection has been connected,
+// which is zero for connections that haven't completed their handshake
+func (candidate *evictionCandidate) timeConnected() time.Duration {
+	if candidate.info == nil {
+		return 0
+	}
+	return candidate.info.TimeConnected
+}
+
+// protectedInboundConnections returns the inbound connections that are protected
+// from eviction: whitelisted ones, and the ones that are hard for an attacker to
+// imitate in bulk - the ones that relayed new blocks to us most recently, the
+// ones with the lowest latency, and the ones that have been connected the longest
+func protectedInboundConnections(candidates []*evictionCandidate) map[*netadapter.NetConnection]struct{} {
+	protected := make(map[*netadapter.NetConnection]struct{})
+	var candidatesWithInfo []*evictionCandidate
+	for _, candidate := range candidates {
+		if candidate.isWhitelisted {
+			protected[candidate.connection] = struct{}{}
+			continue
+		}
+		if candidate.info != nil {
+			candidatesWithInfo = append(candidatesWithInfo, candidate)
+		}
+	}
+
+	protect := func(isBetter func(a, b *PeerInfo) bool, isEligible func(info *PeerInfo) bool) {
+		sort.SliceStable(candidatesWithInfo, func(i, j int) bool {
+			return isBetter(candidatesWithInfo[i].info, candidatesWithInfo[j].info)
+		})
+		protectedCount := 0
+		for _, candidate := range candidatesWithInfo {
+			if protectedCount == evictionProtectedCount {
+				break
+			}
+			if _, ok := protected[candidate.connection]; ok || !isEligible(candidate.info) {
+				continue
+			}
+			protected[candidate.connection] = struct{}{}
+			protectedCount++
+		}
+	}
+
+	protect(func(a, b *PeerInfo) bool { return a.LastBlockRelayTime.After(b.LastBlockRelayTime) },
+		func(info *PeerInfo) bool { return !info.LastBlockRelayTime.IsZero() })
+	protect(func(a, b *PeerInfo) bool { return a.LastPingDuration < b.LastPingDuration },
+		func(info *PeerInfo) bool { return info.LastPingDuration > 0 })
+	protect(func(a, b *PeerInfo) bool { return a.TimeConnected > b.TimeConnected },
+		func(info *PeerInfo) bool { return true })
+
+	return protected
+}
+
+// selectInboundConnectionToEvict selects the inbound connection to evict out of
+// the given ones. It's the most recent connection out of the network group that
+// has the most unprotected connections, so that a single network can't take up
+// all the inbound slots. It returns false if all the connections are protected
+func selectInboundConnectionToEvict(candidates []*evictionCandidate) (*evictionCandidate, bool) {
+	protected := protectedInboundConnections(candidates)
+	candidatesByGroup := make(map[string][]*evictionCandidate)
+	for _, candidate := range candidates {
+		if _, ok := protected[candidate.connection]; ok {
+			continue
+		}
+		candidatesByGroup[candidate.group] = append(candidatesByGroup[candidate.group], candidate)
+	}
+
+	var largestGroup []*evictionCandidate
+	for _, groupCandidates := range candidatesByGroup {
+		if len(groupCandidates) > len(largestGroup) {
+			largestGroup = groupCandidates
+		}
+	}
+	if len(largestGroup) == 0 {
+		return nil, false
+	}
+
+	toEvict := largestGroup[0]
+	for _, candidate := range largestGroup[1:] {
+		if candidate.timeConnected() < toEvict.timeConnected() {
+			toEvict = candidate
+		}
+	}
+	return toEvict, true
+}
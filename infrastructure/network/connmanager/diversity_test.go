@@ -0,0 +1,231 @@
+package connmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+)
+
+type testEvictionCandidate struct {
+	group         string
+	isWhitelisted bool
+	noInfo        bool
+
+	// lastBlockRelayAgo is how long ago the peer relayed a block, or zero if it never did
+	lastBlockRelayAgo time.Duration
+	lastPingDuration  time.Duration
+	timeConnected     time.Duration
+}
+
+func makeEvictionCandidates(testCandidates []testEvictionCandidate) []*evictionCandidate {
+	now := time.Now()
+	candidates := make([]*evictionCandidate, len(testCandidates))
+	for i, testCandidate := range testCandidates {
+		candidates[i] = &evictionCandidate{
+			connection:    &netadapter.NetConnection{},
+			group:         testCandidate.group,
+			isWhitelisted: testCandidate.isWhitelisted,
+		}
+		if testCandidate.noInfo {
+			continue
+		}
+		candidates[i].info = &PeerInfo{
+			TimeConnected:    testCandidate.timeConnected,
+			LastPingDuration: testCandidate.lastPingDuration,
+		}
+		if testCandidate.lastBlockRelayAgo != 0 {
+			candidates[i].info.LastBlockRelayTime = now.Add(-testCandidate.lastBlockRelayAgo)
+		}
+	}
+	return candidates
+}
+
+func TestProtectedInboundConnections(t *testing.T) {
+	tests := []struct {
+		name              string
+		candidates        []testEvictionCandidate
+		expectedProtected []int
+	}{
+		{
+			name: "only whitelisted connections are protected before the handshake",
+			candidates: []testEvictionCandidate{
+				{noInfo: true},
+				{noInfo: true},
+				{noInfo: true, isWhitelisted: true},
+			},
+			expectedProtected: []int{2},
+		},
+		{
+			name: "every class protects up to evictionProtectedCount connections",
+			candidates: []testEvictionCandidate{
+				// Relayed blocks
+				{lastBlockRelayAgo: 1 * time.Minute, timeConnected: time.Second},
+				{lastBlockRelayAgo: 2 * time.Minute, timeConnected: time.Second},
+				{lastBlockRelayAgo: 3 * time.Minute, timeConnected: time.Second},
+				{lastBlockRelayAgo: 4 * time.Minute, timeConnected: time.Second},
+				{lastBlockRelayAgo: 5 * time.Minute, timeConnected: time.Second},
+				// Low latency
+				{lastPingDuration: 10 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 20 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 30 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 40 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 50 * time.Millisecond, timeConnected: time.Second},
+				// Long connected
+				{timeConnected: 10 * time.Hour},
+				{timeConnected: 11 * time.Hour},
+				{timeConnected: 12 * time.Hour},
+				{timeConnected: 13 * time.Hour},
+				{timeConnected: 14 * time.Hour},
+			},
+			expectedProtected: []int{0, 1, 2, 3, 5, 6, 7, 8, 11, 12, 13, 14},
+		},
+		{
+			name: "connections that are protected by one class don't take the slots of the others",
+			candidates: []testEvictionCandidate{
+				{lastBlockRelayAgo: time.Minute, lastPingDuration: 1 * time.Millisecond, timeConnected: 20 * time.Hour},
+				{lastBlockRelayAgo: time.Minute, lastPingDuration: 2 * time.Millisecond, timeConnected: 21 * time.Hour},
+				{lastBlockRelayAgo: time.Minute, lastPingDuration: 3 * time.Millisecond, timeConnected: 22 * time.Hour},
+				{lastBlockRelayAgo: time.Minute, lastPingDuration: 4 * time.Millisecond, timeConnected: 23 * time.Hour},
+				{lastPingDuration: 5 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 6 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 7 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 8 * time.Millisecond, timeConnected: time.Second},
+				{lastPingDuration: 9 * time.Millisecond, timeConnected: time.Second},
+				{timeConnected: 10 * time.Hour},
+				{timeConnected: 11 * time.Hour},
+				{timeConnected: 12 * time.Hour},
+				{timeConnected: 13 * time.Hour},
+				{timeConnected: 14 * time.Hour},
+			},
+			expectedProtected: []int{0, 1, 2, 3, 4, 5, 6, 7, 10, 11, 12, 13},
+		},
+		{
+			name: "whitelisted connections don't take the slots of the other classes",
+			candidates: []testEvictionCandidate{
+				{isWhitelisted: true, lastBlockRelayAgo: time.Second, timeConnected: time.Hour},
+				{lastBlockRelayAgo: 1 * time.Minute},
+				{lastBlockRelayAgo: 2 * time.Minute},
+				{lastBlockRelayAgo: 3 * time.Minute},
+				{lastBlockRelayAgo: 4 * time.Minute},
+				{lastBlockRelayAgo: 5 * time.Minute},
+				{timeConnected: 1 * time.Second},
+				{timeConnected: 2 * time.Second},
+				{timeConnected: 3 * time.Second},
+				{timeConnected: 4 * time.Second},
+				{timeConnected: 5 * time.Second},
+			},
+			expectedProtected: []int{0, 1, 2, 3, 4, 7, 8, 9, 10},
+		},
+		{
+			name: "peers that never relayed a block or answered a ping are protected only by time connected",
+			candidates: []testEvictionCandidate{
+				{timeConnected: 1 * time.Second},
+				{timeConnected: 2 * time.Second},
+				{timeConnected: 3 * time.Second},
+				{timeConnected: 4 * time.Second},
+				{timeConnected: 5 * time.Second},
+				{timeConnected: 6 * time.Second},
+			},
+			expectedProtected: []int{2, 3, 4, 5},
+		},
+	}
+
+	for _, test := range tests {
+		candidates := makeEvictionCandidates(test.candidates)
+		protected := protectedInboundConnections(candidates)
+		if len(protected) != len(test.expectedProtected) {
+			t.Errorf("%s: expected %d protected connections, got %d",
+				test.name, len(test.expectedProtected), len(protected))
+			continue
+		}
+		for _, index := range test.expectedProtected {
+			if _, ok := protected[candidates[index].connection]; !ok {
+				t.Errorf("%s: expected connection %d to be protected", test.name, index)
+			}
+		}
+	}
+}
+
+func TestSelectInboundConnectionToEvict(t *testing.T) {
+	// longConnected are protected by their time connected,
+	// so the other connections in the tests aren't
+	longConnected := []testEvictionCandidate{
+		{group: "protected", timeConnected: 10 * time.Hour},
+		{group: "protected", timeConnected: 11 * time.Hour},
+		{group: "protected", timeConnected: 12 * time.Hour},
+		{group: "protected", timeConnected: 13 * time.Hour},
+	}
+
+	tests := []struct {
+		name       string
+		candidates []testEvictionCandidate
+		// expectedToEvict is the index of the connection to evict, or -1 if none should be
+		expectedToEvict int
+	}{
+		{
+			name: "the most recent connection of the most crowded group is evicted",
+			candidates: append([]testEvictionCandidate{
+				{group: "a", timeConnected: 1 * time.Minute},
+				{group: "a", timeConnected: 2 * time.Minute},
+				{group: "b", timeConnected: 5 * time.Minute},
+				{group: "b", timeConnected: 3 * time.Minute},
+				{group: "b", timeConnected: 4 * time.Minute},
+				{group: "c", timeConnected: 30 * time.Second},
+			}, longConnected...),
+			expectedToEvict: 3,
+		},
+		{
+			name: "protected connections don't count towards the size of their group",
+			candidates: []testEvictionCandidate{
+				{group: "a", timeConnected: 2 * time.Minute},
+				{group: "a", timeConnected: 1 * time.Minute},
+				{group: "a", timeConnected: 3 * time.Minute},
+				{group: "b", timeConnected: 10 * time.Hour},
+				{group: "b", timeConnected: 11 * time.Hour},
+				{group: "b", timeConnected: 12 * time.Hour},
+				{group: "b", timeConnected: 13 * time.Hour},
+				{group: "b", timeConnected: 1 * time.Second},
+				{group: "b", timeConnected: 2 * time.Second},
+			},
+			expectedToEvict: 1,
+		},
+		{
+			name: "a connection that didn't complete its handshake is the most recent",
+			candidates: append([]testEvictionCandidate{
+				{group: "a", timeConnected: 1 * time.Minute},
+				{group: "a", noInfo: true},
+			}, longConnected...),
+			expectedToEvict: 1,
+		},
+		{
+			name: "nothing is evicted when all the connections are protected",
+			candidates: []testEvictionCandidate{
+				{group: "a", isWhitelisted: true},
+				{group: "a", isWhitelisted: true, noInfo: true},
+				{group: "b", timeConnected: time.Minute},
+			},
+			expectedToEvict: -1,
+		},
+	}
+
+	for _, test := range tests {
+		candidates := makeEvictionCandidates(test.candidates)
+		toEvict, ok := selectInboundConnectionToEvict(candidates)
+		if test.expectedToEvict == -1 {
+			if ok {
+				t.Errorf("%s: expected no connection to be evicted, got one of group %s", test.name, toEvict.group)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: expected connection %d to be evicted, but none was", test.name, test.expectedToEvict)
+			continue
+		}
+		if toEvict != candidates[test.expectedToEvict] {
+			t.Errorf("%s: expected connection %d of group %s to be evicted, got one of group %s with time "+
+				"connected %s", test.name, test.expectedToEvict, test.candidates[test.expectedToEvict].group,
+				toEvict.group, toEvict.timeConnected())
+		}
+	}
+}
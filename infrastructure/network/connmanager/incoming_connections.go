@@ -1,7 +1,13 @@
 package connmanager
 
+import (
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+)
+
 // checkIncomingConnections makes sure there's no more than maxIncoming incoming connections
-// if there are - it randomly disconnects enough to go below that number
+// if there are - it evicts enough to go below that number. See selectInboundConnectionToEvict
+// for which connections are evicted
 func (c *ConnectionManager) checkIncomingConnections(incomingConnectionSet connectionSet) {
 	if len(incomingConnectionSet) <= c.maxIncoming {
 		return
@@ -11,14 +17,28 @@ func (c *ConnectionManager) checkIncomingConnections(incomingConnectionSet conne
 	log.Debugf("Got %d incoming connections while only %d are allowed. Disconnecting "+
 		"%d", len(incomingConnectionSet), c.maxIncoming, numConnectionsOverMax)
 
-	// randomly disconnect nodes until the number of incoming connections is smaller than maxIncoming
+	connections := make([]*netadapter.NetConnection, 0, len(incomingConnectionSet))
 	for _, connection := range incomingConnectionSet {
-		log.Debugf("Disconnecting %s due to exceeding incoming connections", connection)
-		connection.Disconnect()
+		connections = append(connections, connection)
+	}
+	candidates := c.evictionCandidates(connections)
+
+	for ; numConnectionsOverMax > 0; numConnectionsOverMax-- {
+		toEvict, ok := selectInboundConnectionToEvict(candidates)
+		if !ok {
+			log.Debugf("All the remaining incoming connections are protected from eviction")
+			return
+		}
+
+		log.Infof("Evicting %s of network group %s due to exceeding incoming connections",
+			logger.Attr("peer", toEvict.connection), toEvict.group)
+		toEvict.connection.Disconnect()
 
-		numConnectionsOverMax--
-		if numConnectionsOverMax == 0 {
-			break
+		for i, candidate := range candidates {
+			if candidate == toEvict {
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				break
+			}
 		}
 	}
 }
@@ -1,6 +1,14 @@
 package connmanager
 
-import "github.com/kaspanet/kaspad/app/appmessage"
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+// outgoingCandidatesFactor is how many candidate addresses are considered for
+// each needed outgoing connection, so that there's room to pick ones of
+// different network groups
+const outgoingCandidatesFactor = 8
 
 // checkOutgoingConnections goes over all activeOutgoing and makes sure they are still active.
 // Then it opens connections so that we have targetOutgoing active connections
@@ -18,8 +26,12 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 
 	connections := c.netAdapter.P2PConnections()
 	connectedAddresses := make([]*appmessage.NetAddress, len(connections))
+	outgoingGroupCounts := make(map[string]int)
 	for i, connection := range connections {
 		connectedAddresses[i] = connection.NetAddress()
+		if connection.IsOutbound() {
+			outgoingGroupCounts[c.NetworkGroup(connection)]++
+		}
 	}
 
 	liveConnections := len(c.activeOutgoing)
@@ -31,7 +43,8 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		liveConnections, c.targetOutgoing, c.targetOutgoing-liveConnections)
 
 	connectionsNeededCount := c.targetOutgoing - len(c.activeOutgoing)
-	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses)
+	candidates := c.addressManager.RandomAddresses(connectionsNeededCount*outgoingCandidatesFactor, connectedAddresses)
+	netAddresses := c.selectDiverseAddresses(candidates, connectionsNeededCount, outgoingGroupCounts)
 
 	for _, netAddress := range netAddresses {
 		addressString := netAddress.TCPAddress().String()
@@ -41,7 +54,7 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 
 		err := c.initiateConnection(addressString)
 		if err != nil {
-			log.Infof("Couldn't connect to %s: %s", addressString, err)
+			log.Infof("Couldn't connect to %s: %s", logger.Attr("address", addressString), err)
 			c.addressManager.MarkConnectionFailure(netAddress)
 			continue
 		}
@@ -50,3 +63,44 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		c.activeOutgoing[addressString] = struct{}{}
 	}
 }
+
+// selectDiverseAddresses selects up to count of the given candidates, preferring
+// ones whose network groups differ from each other and from the network groups
+// of the existing outgoing connections, so that a single network can't take up
+// all of our outgoing connections. Candidates that share a network group are
+// only selected if there aren't enough candidates of different groups, and as
+// long as the group stays within --maxoutpeerspergroup
+func (c *ConnectionManager) selectDiverseAddresses(candidates []*appmessage.NetAddress, count int,
+	groupCounts map[string]int) []*appmessage.NetAddress {
+
+	selected := make([]*appmessage.NetAddress, 0, count)
+	var sameGroupCandidates []*appmessage.NetAddress
+	for _, candidate := range candidates {
+		if len(selected) == count {
+			return selected
+		}
+		group := c.addressManager.GroupKey(candidate)
+		if groupCounts[group] > 0 {
+			sameGroupCandidates = append(sameGroupCandidates, candidate)
+			continue
+		}
+		groupCounts[group]++
+		selected = append(selected, candidate)
+	}
+
+	maxOutboundPeersPerGroup := c.cfg.MaxOutboundPeersPerGroup
+	for _, candidate := range sameGroupCandidates {
+		if len(selected) == count {
+			break
+		}
+		group := c.addressManager.GroupKey(candidate)
+		if maxOutboundPeersPerGroup != 0 && !isExemptGroup(group) && groupCounts[group] >= maxOutboundPeersPerGroup {
+			log.Debugf("Not connecting to %s because network group %s already has %d outgoing connections",
+				candidate.TCPAddress(), group, groupCounts[group])
+			continue
+		}
+		groupCounts[group]++
+		selected = append(selected, candidate)
+	}
+	return selected
+}
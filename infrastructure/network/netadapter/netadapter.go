@@ -1,6 +1,7 @@
 package netadapter
 
 import (
+	"crypto/tls"
 	"sync"
 	"sync/atomic"
 
@@ -10,6 +11,7 @@ import (
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/jsonrpcserver"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +30,7 @@ type NetAdapter struct {
 	p2pServer            server.P2PServer
 	p2pRouterInitializer RouterInitializer
 	rpcServer            server.Server
+	jsonRPCServer        server.Server
 	rpcRouterInitializer RouterInitializer
 	stop                 uint32
 
@@ -46,25 +49,56 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	rpcServer, err := grpcserver.NewRPCServer(cfg.RPCListeners)
+	rpcServer, jsonRPCServer, err := newRPCServers(cfg)
 	if err != nil {
 		return nil, err
 	}
 	adapter := NetAdapter{
-		cfg:       cfg,
-		id:        netAdapterID,
-		p2pServer: p2pServer,
-		rpcServer: rpcServer,
+		cfg:           cfg,
+		id:            netAdapterID,
+		p2pServer:     p2pServer,
+		rpcServer:     rpcServer,
+		jsonRPCServer: jsonRPCServer,
 
 		p2pConnections: make(map[*NetConnection]struct{}),
 	}
 
 	adapter.p2pServer.SetOnConnectedHandler(adapter.onP2PConnectedHandler)
 	adapter.rpcServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
+	adapter.jsonRPCServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
 
 	return &adapter, nil
 }
 
+// newRPCServers creates the gRPC and the JSON-RPC servers. Both share
+// the same TLS configuration and credentials.
+func newRPCServers(cfg *config.Config) (rpcServer server.Server, jsonRPCServer server.Server, err error) {
+	var tlsConfig *tls.Config
+	if cfg.RPCTLS && !cfg.DisableRPC {
+		tlsConfig, err = grpcserver.LoadRPCTLSConfig(cfg.RPCCert, cfg.RPCKey, cfg.RPCTLSExtraHosts)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	adminCredentials := &grpcserver.RPCCredentials{
+		User:     cfg.RPCUser,
+		Password: cfg.RPCPass,
+		Token:    cfg.RPCAuthToken,
+	}
+	limitedCredentials := &grpcserver.RPCCredentials{
+		User:     cfg.RPCLimitUser,
+		Password: cfg.RPCLimitPass,
+		Token:    cfg.RPCLimitAuthToken,
+	}
+	rpcServer, err = grpcserver.NewRPCServer(cfg.RPCListeners, tlsConfig, adminCredentials, limitedCredentials)
+	if err != nil {
+		return nil, nil, err
+	}
+	jsonRPCServer = jsonrpcserver.NewJSONRPCServer(cfg.JSONRPCListeners, tlsConfig, adminCredentials,
+		limitedCredentials, cfg.JSONRPCAllowedOrigins, cfg.RPCMaxWebsockets)
+	return rpcServer, jsonRPCServer, nil
+}
+
 // Start begins the operation of the NetAdapter
 func (na *NetAdapter) Start() error {
 	if na.p2pRouterInitializer == nil {
@@ -82,6 +116,10 @@ func (na *NetAdapter) Start() error {
 	if err != nil {
 		return err
 	}
+	err = na.jsonRPCServer.Start()
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -95,7 +133,11 @@ func (na *NetAdapter) Stop() error {
 	if err != nil {
 		return err
 	}
-	return na.rpcServer.Stop()
+	err = na.rpcServer.Stop()
+	if err != nil {
+		return err
+	}
+	return na.jsonRPCServer.Stop()
 }
 
 // P2PConnect tells the NetAdapter's underlying p2p server to initiate a connection
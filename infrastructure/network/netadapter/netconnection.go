@@ -3,6 +3,7 @@ package netadapter
 import (
 	"fmt"
 	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
 	"sync/atomic"
@@ -29,7 +30,7 @@ func newNetConnection(connection server.Connection, routerInitializer RouterInit
 	}
 
 	netConnection.connection.SetOnDisconnectedHandler(func() {
-		log.Infof("Disconnected from %s", netConnection)
+		log.Infof("Disconnected from %s", logger.Attr("peer", netConnection))
 		// If the disconnection came because of a network error and not because of the application layer, we
 		// need to close the router as well.
 		if atomic.AddUint32(&netConnection.isRouterClosed, 1) == 1 {
@@ -75,6 +76,26 @@ func (c *NetConnection) IsOutbound() bool {
 	return c.connection.IsOutbound()
 }
 
+// RPCAuthentication returns how this connection was authenticated,
+// if it's an RPC connection
+func (c *NetConnection) RPCAuthentication() server.RPCAuthentication {
+	rpcConnection, ok := c.connection.(server.RPCConnection)
+	if !ok {
+		return server.RPCAuthenticationNone
+	}
+	return rpcConnection.Authentication()
+}
+
+// RPCListenAddress returns the address of the RPC listener that accepted
+// this connection, if it's an RPC connection
+func (c *NetConnection) RPCListenAddress() string {
+	rpcConnection, ok := c.connection.(server.RPCConnection)
+	if !ok {
+		return ""
+	}
+	return rpcConnection.ListenAddress()
+}
+
 // NetAddress returns the NetAddress associated with this connection
 func (c *NetConnection) NetAddress() *appmessage.NetAddress {
 	return appmessage.NewNetAddress(c.connection.Address())
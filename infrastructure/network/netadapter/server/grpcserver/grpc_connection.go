@@ -34,6 +34,10 @@ type gRPCConnection struct {
 	onInvalidMessageHandler server.OnInvalidMessageHandler
 
 	isConnected uint32
+
+	// These are only set for RPC connections. See Authentication and ListenAddress
+	rpcAuthentication server.RPCAuthentication
+	listenAddress     string
 }
 
 type grpcStream interface {
@@ -100,6 +104,21 @@ func (c *gRPCConnection) IsOutbound() bool {
 	return c.lowLevelClientConnection != nil
 }
 
+// Authentication returns how the connection was authenticated
+//
+// This is part of the RPCConnection interface
+func (c *gRPCConnection) Authentication() server.RPCAuthentication {
+	return c.rpcAuthentication
+}
+
+// ListenAddress returns the address of the listener that accepted the connection,
+// as it was given to the server
+//
+// This is part of the RPCConnection interface
+func (c *gRPCConnection) ListenAddress() string {
+	return c.listenAddress
+}
+
 // Disconnect disconnects the connection
 // Calling this function a second time doesn't do anything
 //
@@ -3,6 +3,7 @@ package grpcserver
 import (
 	"context"
 	"fmt"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/util/panics"
 	"github.com/pkg/errors"
@@ -20,10 +21,13 @@ type gRPCServer struct {
 }
 
 // newGRPCServer creates a gRPC server
-func newGRPCServer(listeningAddresses []string, maxMessageSize int, name string) *gRPCServer {
+func newGRPCServer(listeningAddresses []string, maxMessageSize int, name string,
+	serverOptions ...grpc.ServerOption) *gRPCServer {
+
 	log.Debugf("Created new %s GRPC server with maxMessageSize %d", name, maxMessageSize)
+	serverOptions = append(serverOptions, grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize))
 	return &gRPCServer{
-		server:             grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize)),
+		server:             grpc.NewServer(serverOptions...),
 		listeningAddresses: listeningAddresses,
 		name:               name,
 	}
@@ -49,6 +53,7 @@ func (s *gRPCServer) listenOn(listenAddr string) error {
 	if err != nil {
 		return errors.Wrapf(err, "%s error listening on %s", s.name, listenAddr)
 	}
+	listener = &taggingListener{Listener: listener, listenAddress: listenAddr}
 
 	spawn(fmt.Sprintf("%s.gRPCServer.listenOn-Serve", s.name), func() {
 		err := s.server.Serve(listener)
@@ -86,23 +91,33 @@ func (s *gRPCServer) SetOnConnectedHandler(onConnectedHandler server.OnConnected
 }
 
 func (s *gRPCServer) handleInboundConnection(ctx context.Context, stream grpcStream) error {
+	connection, err := s.newInboundConnection(ctx, stream)
+	if err != nil {
+		return err
+	}
+	return s.serveInboundConnection(connection)
+}
+
+func (s *gRPCServer) newInboundConnection(ctx context.Context, stream grpcStream) (*gRPCConnection, error) {
 	peerInfo, ok := peer.FromContext(ctx)
 	if !ok {
-		return errors.Errorf("Error getting stream peer info from context")
+		return nil, errors.Errorf("Error getting stream peer info from context")
 	}
 	tcpAddress, ok := peerInfo.Addr.(*net.TCPAddr)
 	if !ok {
-		return errors.Errorf("non-tcp connections are not supported")
+		return nil, errors.Errorf("non-tcp connections are not supported")
 	}
 
-	connection := newConnection(s, tcpAddress, stream, nil)
+	return newConnection(s, tcpAddress, stream, nil), nil
+}
 
+func (s *gRPCServer) serveInboundConnection(connection *gRPCConnection) error {
 	err := s.onConnectedHandler(connection)
 	if err != nil {
 		return err
 	}
 
-	log.Infof("%s Incoming connection from %s", s.name, peerInfo.Addr)
+	log.Infof("%s Incoming connection from %s", s.name, logger.Attr("address", connection.address))
 
 	<-connection.stopChan
 
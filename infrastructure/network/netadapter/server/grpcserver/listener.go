@@ -0,0 +1,73 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/stats"
+)
+
+// taggingListener is a net.Listener that tags the local address of every
+// connection it accepts with the address it listens on, as it was given
+// to the server. This allows telling apart connections that arrived
+// through different listeners of the same gRPC server.
+type taggingListener struct {
+	net.Listener
+	listenAddress string
+}
+
+func (l *taggingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &taggedConn{
+		Conn:      conn,
+		localAddr: &taggedAddr{Addr: conn.LocalAddr(), listenAddress: l.listenAddress},
+	}, nil
+}
+
+type taggedConn struct {
+	net.Conn
+	localAddr *taggedAddr
+}
+
+func (c *taggedConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+type taggedAddr struct {
+	net.Addr
+	listenAddress string
+}
+
+type listenAddressContextKey struct{}
+
+// listenAddressStatsHandler is a stats.Handler that exposes the listen address
+// with which taggingListener tagged a connection to the streams served over it.
+// See listenAddressFromContext.
+type listenAddressStatsHandler struct{}
+
+func (listenAddressStatsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	localAddr, ok := info.LocalAddr.(*taggedAddr)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, listenAddressContextKey{}, localAddr.listenAddress)
+}
+
+func (listenAddressStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (listenAddressStatsHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+func (listenAddressStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// listenAddressFromContext returns the listen address of the connection over
+// which the stream with the given context is served. It requires the server
+// to be set up with listenAddressStatsHandler.
+func listenAddressFromContext(ctx context.Context) string {
+	listenAddress, _ := ctx.Value(listenAddressContextKey{}).(string)
+	return listenAddress
+}
@@ -132,6 +132,22 @@ type KaspadMessage struct {
 	//	*KaspadMessage_NotifyVirtualDaaScoreChangedRequest
 	//	*KaspadMessage_NotifyVirtualDaaScoreChangedResponse
 	//	*KaspadMessage_VirtualDaaScoreChangedNotification
+	//	*KaspadMessage_GetUtxoHistoryByAddressesRequest
+	//	*KaspadMessage_GetUtxoHistoryByAddressesResponse
+	//	*KaspadMessage_GetTransactionRequest
+	//	*KaspadMessage_GetTransactionResponse
+	//	*KaspadMessage_GetTransactionsByAddressesRequest
+	//	*KaspadMessage_GetTransactionsByAddressesResponse
+	//	*KaspadMessage_GetFeeEstimateRequest
+	//	*KaspadMessage_GetFeeEstimateResponse
+	//	*KaspadMessage_GetLogLevelsRequest
+	//	*KaspadMessage_GetLogLevelsResponse
+	//	*KaspadMessage_SetLogLevelRequest
+	//	*KaspadMessage_SetLogLevelResponse
+	//	*KaspadMessage_GetBannedPeersRequest
+	//	*KaspadMessage_GetBannedPeersResponse
+	//	*KaspadMessage_BackupDatabaseRequest
+	//	*KaspadMessage_BackupDatabaseResponse
 	Payload isKaspadMessage_Payload `protobuf_oneof:"payload"`
 }
 
@@ -916,6 +932,118 @@ func (x *KaspadMessage) GetVirtualDaaScoreChangedNotification() *VirtualDaaScore
 	return nil
 }
 
+func (x *KaspadMessage) GetGetUtxoHistoryByAddressesRequest() *GetUtxoHistoryByAddressesRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetUtxoHistoryByAddressesRequest); ok {
+		return x.GetUtxoHistoryByAddressesRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetUtxoHistoryByAddressesResponse() *GetUtxoHistoryByAddressesResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetUtxoHistoryByAddressesResponse); ok {
+		return x.GetUtxoHistoryByAddressesResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionRequest() *GetTransactionRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionRequest); ok {
+		return x.GetTransactionRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionResponse() *GetTransactionResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionResponse); ok {
+		return x.GetTransactionResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionsByAddressesRequest() *GetTransactionsByAddressesRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionsByAddressesRequest); ok {
+		return x.GetTransactionsByAddressesRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionsByAddressesResponse() *GetTransactionsByAddressesResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionsByAddressesResponse); ok {
+		return x.GetTransactionsByAddressesResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetFeeEstimateRequest() *GetFeeEstimateRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetFeeEstimateRequest); ok {
+		return x.GetFeeEstimateRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetFeeEstimateResponse() *GetFeeEstimateResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetFeeEstimateResponse); ok {
+		return x.GetFeeEstimateResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetLogLevelsRequest() *GetLogLevelsRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetLogLevelsRequest); ok {
+		return x.GetLogLevelsRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetLogLevelsResponse() *GetLogLevelsResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetLogLevelsResponse); ok {
+		return x.GetLogLevelsResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSetLogLevelRequest() *SetLogLevelRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SetLogLevelRequest); ok {
+		return x.SetLogLevelRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSetLogLevelResponse() *SetLogLevelResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SetLogLevelResponse); ok {
+		return x.SetLogLevelResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetBannedPeersRequest() *GetBannedPeersRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetBannedPeersRequest); ok {
+		return x.GetBannedPeersRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetBannedPeersResponse() *GetBannedPeersResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetBannedPeersResponse); ok {
+		return x.GetBannedPeersResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetBackupDatabaseRequest() *BackupDatabaseRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_BackupDatabaseRequest); ok {
+		return x.BackupDatabaseRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetBackupDatabaseResponse() *BackupDatabaseResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_BackupDatabaseResponse); ok {
+		return x.BackupDatabaseResponse
+	}
+	return nil
+}
+
 type isKaspadMessage_Payload interface {
 	isKaspadMessage_Payload()
 }
@@ -1344,6 +1472,70 @@ type KaspadMessage_VirtualDaaScoreChangedNotification struct {
 	VirtualDaaScoreChangedNotification *VirtualDaaScoreChangedNotificationMessage `protobuf:"bytes,1074,opt,name=virtualDaaScoreChangedNotification,proto3,oneof"`
 }
 
+type KaspadMessage_GetUtxoHistoryByAddressesRequest struct {
+	GetUtxoHistoryByAddressesRequest *GetUtxoHistoryByAddressesRequestMessage `protobuf:"bytes,1075,opt,name=getUtxoHistoryByAddressesRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetUtxoHistoryByAddressesResponse struct {
+	GetUtxoHistoryByAddressesResponse *GetUtxoHistoryByAddressesResponseMessage `protobuf:"bytes,1076,opt,name=getUtxoHistoryByAddressesResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionRequest struct {
+	GetTransactionRequest *GetTransactionRequestMessage `protobuf:"bytes,1077,opt,name=getTransactionRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionResponse struct {
+	GetTransactionResponse *GetTransactionResponseMessage `protobuf:"bytes,1078,opt,name=getTransactionResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionsByAddressesRequest struct {
+	GetTransactionsByAddressesRequest *GetTransactionsByAddressesRequestMessage `protobuf:"bytes,1079,opt,name=getTransactionsByAddressesRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionsByAddressesResponse struct {
+	GetTransactionsByAddressesResponse *GetTransactionsByAddressesResponseMessage `protobuf:"bytes,1080,opt,name=getTransactionsByAddressesResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetFeeEstimateRequest struct {
+	GetFeeEstimateRequest *GetFeeEstimateRequestMessage `protobuf:"bytes,1081,opt,name=getFeeEstimateRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetFeeEstimateResponse struct {
+	GetFeeEstimateResponse *GetFeeEstimateResponseMessage `protobuf:"bytes,1082,opt,name=getFeeEstimateResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetLogLevelsRequest struct {
+	GetLogLevelsRequest *GetLogLevelsRequestMessage `protobuf:"bytes,1083,opt,name=getLogLevelsRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetLogLevelsResponse struct {
+	GetLogLevelsResponse *GetLogLevelsResponseMessage `protobuf:"bytes,1084,opt,name=getLogLevelsResponse,proto3,oneof"`
+}
+
+type KaspadMessage_SetLogLevelRequest struct {
+	SetLogLevelRequest *SetLogLevelRequestMessage `protobuf:"bytes,1085,opt,name=setLogLevelRequest,proto3,oneof"`
+}
+
+type KaspadMessage_SetLogLevelResponse struct {
+	SetLogLevelResponse *SetLogLevelResponseMessage `protobuf:"bytes,1086,opt,name=setLogLevelResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetBannedPeersRequest struct {
+	GetBannedPeersRequest *GetBannedPeersRequestMessage `protobuf:"bytes,1087,opt,name=getBannedPeersRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetBannedPeersResponse struct {
+	GetBannedPeersResponse *GetBannedPeersResponseMessage `protobuf:"bytes,1088,opt,name=getBannedPeersResponse,proto3,oneof"`
+}
+
+type KaspadMessage_BackupDatabaseRequest struct {
+	BackupDatabaseRequest *BackupDatabaseRequestMessage `protobuf:"bytes,1089,opt,name=backupDatabaseRequest,proto3,oneof"`
+}
+
+type KaspadMessage_BackupDatabaseResponse struct {
+	BackupDatabaseResponse *BackupDatabaseResponseMessage `protobuf:"bytes,1090,opt,name=backupDatabaseResponse,proto3,oneof"`
+}
+
 func (*KaspadMessage_Addresses) isKaspadMessage_Payload() {}
 
 func (*KaspadMessage_Block) isKaspadMessage_Payload() {}
@@ -1556,13 +1748,45 @@ func (*KaspadMessage_NotifyVirtualDaaScoreChangedResponse) isKaspadMessage_Paylo
 
 func (*KaspadMessage_VirtualDaaScoreChangedNotification) isKaspadMessage_Payload() {}
 
+func (*KaspadMessage_GetUtxoHistoryByAddressesRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetUtxoHistoryByAddressesResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionsByAddressesRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionsByAddressesResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetFeeEstimateRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetFeeEstimateResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetLogLevelsRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetLogLevelsResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SetLogLevelRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SetLogLevelResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetBannedPeersRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetBannedPeersResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_BackupDatabaseRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_BackupDatabaseResponse) isKaspadMessage_Payload() {}
+
 var File_messages_proto protoreflect.FileDescriptor
 
 var file_messages_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x1a, 0x09, 0x70, 0x32, 0x70,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0xcb, 0x58, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
+	0x6f, 0x22, 0xf3, 0x65, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
 	0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69,
 	0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x4d, 0x65, 0x73, 0x73,
@@ -2270,21 +2494,127 @@ var file_messages_proto_rawDesc = []byte{
 	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
 	0x52, 0x22, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x44, 0x61, 0x61, 0x53, 0x63, 0x6f, 0x72,
 	0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32,
-	0x50, 0x0a, 0x03, 0x50, 0x32, 0x50, 0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
-	0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61,
-	0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30,
-	0x01, 0x32, 0x50, 0x0a, 0x03, 0x52, 0x50, 0x43, 0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x81, 0x01, 0x0a, 0x20, 0x67, 0x65, 0x74, 0x55, 0x74, 0x78, 0x6f,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xb3, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x55, 0x74, 0x78, 0x6f, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x20, 0x67, 0x65, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x84, 0x01, 0x0a, 0x21, 0x67, 0x65, 0x74,
+	0x55, 0x74, 0x78, 0x6f, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xb4,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x21, 0x67, 0x65,
+	0x74, 0x55, 0x74, 0x78, 0x6f, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x60, 0x0a, 0x15, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xb5, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x63, 0x0a, 0x16, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xb6, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16,
+	0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x84, 0x01, 0x0a, 0x21, 0x67, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xb7, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42,
+	0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x21, 0x67, 0x65, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x79, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x87, 0x01,
+	0x0a, 0x22, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x18, 0xb8, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x22, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x42, 0x79, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x15, 0x67, 0x65, 0x74, 0x46, 0x65,
+	0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x18, 0xb9, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
+	0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x63, 0x0a, 0x16, 0x67, 0x65, 0x74,
+	0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0xba, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a,
+	0x0a, 0x13, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xbb, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x13, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5d, 0x0a, 0x14, 0x67, 0x65,
+	0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x18, 0xbc, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x14, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x12, 0x73, 0x65, 0x74,
+	0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18,
+	0xbd, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69,
+	0x72, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x12,
+	0x73, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x5a, 0x0a, 0x13, 0x73, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xbe, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x65, 0x74,
+	0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x13, 0x73, 0x65, 0x74, 0x4c, 0x6f,
+	0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60,
+	0x0a, 0x15, 0x67, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xbf, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x42, 0x61,
+	0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x63, 0x0a, 0x16, 0x67, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xc0, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65,
+	0x74, 0x42, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67,
+	0x65, 0x74, 0x42, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x15, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xc1,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
+	0x65, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x15, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x63, 0x0a, 0x16, 0x62, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0xc2, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x09, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x50, 0x0a, 0x03, 0x50, 0x32, 0x50, 0x12, 0x49,
+	0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70,
+	0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
-	0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28,
-	0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x6e, 0x65, 0x74, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61,
-	0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x50, 0x0a, 0x03, 0x52, 0x50, 0x43,
+	0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61,
+	0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x6e,
+	0x65, 0x74, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
+	0x69, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2407,6 +2737,22 @@ var file_messages_proto_goTypes = []interface{}{
 	(*NotifyVirtualDaaScoreChangedRequestMessage)(nil),                 // 103: protowire.NotifyVirtualDaaScoreChangedRequestMessage
 	(*NotifyVirtualDaaScoreChangedResponseMessage)(nil),                // 104: protowire.NotifyVirtualDaaScoreChangedResponseMessage
 	(*VirtualDaaScoreChangedNotificationMessage)(nil),                  // 105: protowire.VirtualDaaScoreChangedNotificationMessage
+	(*GetUtxoHistoryByAddressesRequestMessage)(nil),                    // 106: protowire.GetUtxoHistoryByAddressesRequestMessage
+	(*GetUtxoHistoryByAddressesResponseMessage)(nil),                   // 107: protowire.GetUtxoHistoryByAddressesResponseMessage
+	(*GetTransactionRequestMessage)(nil),                               // 108: protowire.GetTransactionRequestMessage
+	(*GetTransactionResponseMessage)(nil),                              // 109: protowire.GetTransactionResponseMessage
+	(*GetTransactionsByAddressesRequestMessage)(nil),                   // 110: protowire.GetTransactionsByAddressesRequestMessage
+	(*GetTransactionsByAddressesResponseMessage)(nil),                  // 111: protowire.GetTransactionsByAddressesResponseMessage
+	(*GetFeeEstimateRequestMessage)(nil),                               // 112: protowire.GetFeeEstimateRequestMessage
+	(*GetFeeEstimateResponseMessage)(nil),                              // 113: protowire.GetFeeEstimateResponseMessage
+	(*GetLogLevelsRequestMessage)(nil),                                 // 114: protowire.GetLogLevelsRequestMessage
+	(*GetLogLevelsResponseMessage)(nil),                                // 115: protowire.GetLogLevelsResponseMessage
+	(*SetLogLevelRequestMessage)(nil),                                  // 116: protowire.SetLogLevelRequestMessage
+	(*SetLogLevelResponseMessage)(nil),                                 // 117: protowire.SetLogLevelResponseMessage
+	(*GetBannedPeersRequestMessage)(nil),                               // 118: protowire.GetBannedPeersRequestMessage
+	(*GetBannedPeersResponseMessage)(nil),                              // 119: protowire.GetBannedPeersResponseMessage
+	(*BackupDatabaseRequestMessage)(nil),                               // 120: protowire.BackupDatabaseRequestMessage
+	(*BackupDatabaseResponseMessage)(nil),                              // 121: protowire.BackupDatabaseResponseMessage
 }
 var file_messages_proto_depIdxs = []int32{
 	1,   // 0: protowire.KaspadMessage.addresses:type_name -> protowire.AddressesMessage
@@ -2515,15 +2861,31 @@ var file_messages_proto_depIdxs = []int32{
 	103, // 103: protowire.KaspadMessage.notifyVirtualDaaScoreChangedRequest:type_name -> protowire.NotifyVirtualDaaScoreChangedRequestMessage
 	104, // 104: protowire.KaspadMessage.notifyVirtualDaaScoreChangedResponse:type_name -> protowire.NotifyVirtualDaaScoreChangedResponseMessage
 	105, // 105: protowire.KaspadMessage.virtualDaaScoreChangedNotification:type_name -> protowire.VirtualDaaScoreChangedNotificationMessage
-	0,   // 106: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 107: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 108: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
-	0,   // 109: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
-	108, // [108:110] is the sub-list for method output_type
-	106, // [106:108] is the sub-list for method input_type
-	106, // [106:106] is the sub-list for extension type_name
-	106, // [106:106] is the sub-list for extension extendee
-	0,   // [0:106] is the sub-list for field type_name
+	106, // 106: protowire.KaspadMessage.getUtxoHistoryByAddressesRequest:type_name -> protowire.GetUtxoHistoryByAddressesRequestMessage
+	107, // 107: protowire.KaspadMessage.getUtxoHistoryByAddressesResponse:type_name -> protowire.GetUtxoHistoryByAddressesResponseMessage
+	108, // 108: protowire.KaspadMessage.getTransactionRequest:type_name -> protowire.GetTransactionRequestMessage
+	109, // 109: protowire.KaspadMessage.getTransactionResponse:type_name -> protowire.GetTransactionResponseMessage
+	110, // 110: protowire.KaspadMessage.getTransactionsByAddressesRequest:type_name -> protowire.GetTransactionsByAddressesRequestMessage
+	111, // 111: protowire.KaspadMessage.getTransactionsByAddressesResponse:type_name -> protowire.GetTransactionsByAddressesResponseMessage
+	112, // 112: protowire.KaspadMessage.getFeeEstimateRequest:type_name -> protowire.GetFeeEstimateRequestMessage
+	113, // 113: protowire.KaspadMessage.getFeeEstimateResponse:type_name -> protowire.GetFeeEstimateResponseMessage
+	114, // 114: protowire.KaspadMessage.getLogLevelsRequest:type_name -> protowire.GetLogLevelsRequestMessage
+	115, // 115: protowire.KaspadMessage.getLogLevelsResponse:type_name -> protowire.GetLogLevelsResponseMessage
+	116, // 116: protowire.KaspadMessage.setLogLevelRequest:type_name -> protowire.SetLogLevelRequestMessage
+	117, // 117: protowire.KaspadMessage.setLogLevelResponse:type_name -> protowire.SetLogLevelResponseMessage
+	118, // 118: protowire.KaspadMessage.getBannedPeersRequest:type_name -> protowire.GetBannedPeersRequestMessage
+	119, // 119: protowire.KaspadMessage.getBannedPeersResponse:type_name -> protowire.GetBannedPeersResponseMessage
+	120, // 120: protowire.KaspadMessage.backupDatabaseRequest:type_name -> protowire.BackupDatabaseRequestMessage
+	121, // 121: protowire.KaspadMessage.backupDatabaseResponse:type_name -> protowire.BackupDatabaseResponseMessage
+	0,   // 122: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 123: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 124: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
+	0,   // 125: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
+	124, // [124:126] is the sub-list for method output_type
+	122, // [122:124] is the sub-list for method input_type
+	122, // [122:122] is the sub-list for extension type_name
+	122, // [122:122] is the sub-list for extension extendee
+	0,   // [0:122] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -2654,6 +3016,22 @@ func file_messages_proto_init() {
 		(*KaspadMessage_NotifyVirtualDaaScoreChangedRequest)(nil),
 		(*KaspadMessage_NotifyVirtualDaaScoreChangedResponse)(nil),
 		(*KaspadMessage_VirtualDaaScoreChangedNotification)(nil),
+		(*KaspadMessage_GetUtxoHistoryByAddressesRequest)(nil),
+		(*KaspadMessage_GetUtxoHistoryByAddressesResponse)(nil),
+		(*KaspadMessage_GetTransactionRequest)(nil),
+		(*KaspadMessage_GetTransactionResponse)(nil),
+		(*KaspadMessage_GetTransactionsByAddressesRequest)(nil),
+		(*KaspadMessage_GetTransactionsByAddressesResponse)(nil),
+		(*KaspadMessage_GetFeeEstimateRequest)(nil),
+		(*KaspadMessage_GetFeeEstimateResponse)(nil),
+		(*KaspadMessage_GetLogLevelsRequest)(nil),
+		(*KaspadMessage_GetLogLevelsResponse)(nil),
+		(*KaspadMessage_SetLogLevelRequest)(nil),
+		(*KaspadMessage_SetLogLevelResponse)(nil),
+		(*KaspadMessage_GetBannedPeersRequest)(nil),
+		(*KaspadMessage_GetBannedPeersResponse)(nil),
+		(*KaspadMessage_BackupDatabaseRequest)(nil),
+		(*KaspadMessage_BackupDatabaseResponse)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
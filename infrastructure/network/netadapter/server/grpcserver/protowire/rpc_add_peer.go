@@ -40,7 +40,8 @@ func (x *KaspadMessage_AddPeerResponse) toAppMessage() (appmessage.Message, erro
 func (x *KaspadMessage_AddPeerResponse) fromAppMessage(message *appmessage.AddPeerResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.AddPeerResponse = &AddPeerResponseMessage{
 		Error: err,
@@ -0,0 +1,68 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_BackupDatabaseRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_BackupDatabaseRequest is nil")
+	}
+	return x.BackupDatabaseRequest.toAppMessage()
+}
+
+func (x *BackupDatabaseRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "BackupDatabaseRequestMessage is nil")
+	}
+	return &appmessage.BackupDatabaseRequestMessage{
+		TargetDirectory: x.TargetDirectory,
+	}, nil
+}
+
+func (x *KaspadMessage_BackupDatabaseRequest) fromAppMessage(message *appmessage.BackupDatabaseRequestMessage) error {
+	x.BackupDatabaseRequest = &BackupDatabaseRequestMessage{
+		TargetDirectory: message.TargetDirectory,
+	}
+	return nil
+}
+
+func (x *KaspadMessage_BackupDatabaseResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_BackupDatabaseResponse is nil")
+	}
+	return x.BackupDatabaseResponse.toAppMessage()
+}
+
+func (x *BackupDatabaseResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "BackupDatabaseResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.BackupDatabaseResponseMessage{
+		DatabasePath: x.DatabasePath,
+		RecordCount:  x.RecordCount,
+		Size:         x.Size,
+		Error:        rpcErr,
+	}, nil
+}
+
+func (x *KaspadMessage_BackupDatabaseResponse) fromAppMessage(message *appmessage.BackupDatabaseResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	x.BackupDatabaseResponse = &BackupDatabaseResponseMessage{
+		DatabasePath: message.DatabasePath,
+		RecordCount:  message.RecordCount,
+		Size:         message.Size,
+		Error:        err,
+	}
+	return nil
+}
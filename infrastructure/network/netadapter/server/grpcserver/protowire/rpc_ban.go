@@ -17,12 +17,16 @@ func (x *BanRequestMessage) toAppMessage() (appmessage.Message, error) {
 		return nil, errors.Wrapf(errorNil, "BanRequestMessage is nil")
 	}
 	return &appmessage.BanRequestMessage{
-		IP: x.Ip,
+		IP:              x.Ip,
+		DurationSeconds: x.DurationSeconds,
 	}, nil
 }
 
 func (x *KaspadMessage_BanRequest) fromAppMessage(message *appmessage.BanRequestMessage) error {
-	x.BanRequest = &BanRequestMessage{Ip: message.IP}
+	x.BanRequest = &BanRequestMessage{
+		Ip:              message.IP,
+		DurationSeconds: message.DurationSeconds,
+	}
 	return nil
 }
 
@@ -50,7 +54,8 @@ func (x *BanResponseMessage) toAppMessage() (appmessage.Message, error) {
 func (x *KaspadMessage_BanResponse) fromAppMessage(message *appmessage.BanResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.BanResponse = &BanResponseMessage{
 		Error: err,
@@ -1,6 +1,8 @@
 package protowire
 
 import (
+	"time"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/pkg/errors"
 )
@@ -9,5 +11,17 @@ func (x *RPCError) toAppMessage() (*appmessage.RPCError, error) {
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "RPCError is nil")
 	}
-	return &appmessage.RPCError{Message: x.Message}, nil
+	return &appmessage.RPCError{
+		Message:    x.Message,
+		Code:       appmessage.RPCErrorCode(x.Code),
+		RetryAfter: time.Duration(x.RetryAfterMilliseconds) * time.Millisecond,
+	}, nil
+}
+
+func (x *RPCError) fromAppMessage(message *appmessage.RPCError) {
+	*x = RPCError{
+		Message:                message.Message,
+		Code:                   RPCError_Code(message.Code),
+		RetryAfterMilliseconds: uint64(message.RetryAfter.Milliseconds()),
+	}
 }
@@ -0,0 +1,84 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetBannedPeersRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetBannedPeersRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetBannedPeersRequest) fromAppMessage(_ *appmessage.GetBannedPeersRequestMessage) error {
+	x.GetBannedPeersRequest = &GetBannedPeersRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetBannedPeersResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetBannedPeersResponse is nil")
+	}
+	return x.GetBannedPeersResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetBannedPeersResponse) fromAppMessage(message *appmessage.GetBannedPeersResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	bannedPeers := make([]*RpcBannedPeer, len(message.BannedPeers))
+	for i, bannedPeer := range message.BannedPeers {
+		bannedPeers[i] = &RpcBannedPeer{
+			Ip:        bannedPeer.IP,
+			Reason:    bannedPeer.Reason,
+			CreatedAt: bannedPeer.CreatedAt,
+			ExpiresAt: bannedPeer.ExpiresAt,
+		}
+	}
+	x.GetBannedPeersResponse = &GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *GetBannedPeersResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBannedPeersResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.BannedPeers) != 0 {
+		return nil, errors.New("GetBannedPeersResponseMessage contains both an error and a response")
+	}
+
+	bannedPeers := make([]*appmessage.RPCBannedPeer, len(x.BannedPeers))
+	for i, bannedPeer := range x.BannedPeers {
+		bannedPeers[i], err = bannedPeer.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *RpcBannedPeer) toAppMessage() (*appmessage.RPCBannedPeer, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcBannedPeer is nil")
+	}
+	return &appmessage.RPCBannedPeer{
+		IP:        x.Ip,
+		Reason:    x.Reason,
+		CreatedAt: x.CreatedAt,
+		ExpiresAt: x.ExpiresAt,
+	}, nil
+}
@@ -66,7 +66,8 @@ func (x *GetBlockResponseMessage) toAppMessage() (appmessage.Message, error) {
 func (x *KaspadMessage_GetBlockResponse) fromAppMessage(message *appmessage.GetBlockResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	var block *RpcBlock
 	if message.Block != nil {
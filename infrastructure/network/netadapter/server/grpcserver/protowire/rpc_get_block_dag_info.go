@@ -53,7 +53,8 @@ func (x *GetBlockDagInfoResponseMessage) toAppMessage() (appmessage.Message, err
 func (x *KaspadMessage_GetBlockDagInfoResponse) fromAppMessage(message *appmessage.GetBlockDAGInfoResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.GetBlockDagInfoResponse = &GetBlockDagInfoResponseMessage{
 		NetworkName:         message.NetworkName,
@@ -42,7 +42,8 @@ func (x *KaspadMessage_GetBlocksResponse) toAppMessage() (appmessage.Message, er
 func (x *KaspadMessage_GetBlocksResponse) fromAppMessage(message *appmessage.GetBlocksResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.GetBlocksResponse = &GetBlocksResponseMessage{
 		Error: err,
@@ -42,7 +42,8 @@ func (x *KaspadMessage_GetHeadersResponse) toAppMessage() (appmessage.Message, e
 func (x *KaspadMessage_GetHeadersResponse) fromAppMessage(message *appmessage.GetHeadersResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.GetHeadersResponse = &GetHeadersResponseMessage{
 		Headers: message.Headers,
@@ -24,12 +24,19 @@ func (x *KaspadMessage_GetInfoResponse) toAppMessage() (appmessage.Message, erro
 func (x *KaspadMessage_GetInfoResponse) fromAppMessage(message *appmessage.GetInfoResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	var databaseStats *RpcDatabaseStats
+	if message.DatabaseStats != nil {
+		databaseStats = &RpcDatabaseStats{}
+		databaseStats.fromAppMessage(message.DatabaseStats)
 	}
 	x.GetInfoResponse = &GetInfoResponseMessage{
-		P2PId:       message.P2PID,
-		MempoolSize: message.MempoolSize,
-		Error:       err,
+		P2PId:         message.P2PID,
+		MempoolSize:   message.MempoolSize,
+		DatabaseStats: databaseStats,
+		Error:         err,
 	}
 	return nil
 }
@@ -48,9 +55,59 @@ func (x *GetInfoResponseMessage) toAppMessage() (appmessage.Message, error) {
 		return nil, errors.New("GetInfoResponseMessage contains both an error and a response")
 	}
 
+	// DatabaseStats is an optional field
+	var databaseStats *appmessage.RPCDatabaseStats
+	if x.DatabaseStats != nil {
+		databaseStats = x.DatabaseStats.toAppMessage()
+	}
+
 	return &appmessage.GetInfoResponseMessage{
-		P2PID:       x.P2PId,
-		MempoolSize: x.MempoolSize,
-		Error:       rpcErr,
+		P2PID:         x.P2PId,
+		MempoolSize:   x.MempoolSize,
+		DatabaseStats: databaseStats,
+		Error:         rpcErr,
 	}, nil
 }
+
+func (x *RpcDatabaseStats) toAppMessage() *appmessage.RPCDatabaseStats {
+	levels := make([]*appmessage.RPCDatabaseLevelStats, len(x.Levels))
+	for i, level := range x.Levels {
+		levels[i] = &appmessage.RPCDatabaseLevelStats{
+			Level:              level.Level,
+			TableCount:         level.TableCount,
+			Size:               level.Size,
+			ReadBytes:          level.ReadBytes,
+			WriteBytes:         level.WriteBytes,
+			CompactionDuration: level.CompactionDuration,
+		}
+	}
+	return &appmessage.RPCDatabaseStats{
+		Levels:                   levels,
+		MemoryCompactionCount:    x.MemoryCompactionCount,
+		Level0CompactionCount:    x.Level0CompactionCount,
+		NonLevel0CompactionCount: x.NonLevel0CompactionCount,
+		SeekCompactionCount:      x.SeekCompactionCount,
+		WriteDelayCount:          x.WriteDelayCount,
+		WriteDelayDuration:       x.WriteDelayDuration,
+	}
+}
+
+func (x *RpcDatabaseStats) fromAppMessage(message *appmessage.RPCDatabaseStats) {
+	x.Levels = make([]*RpcDatabaseLevelStats, len(message.Levels))
+	for i, level := range message.Levels {
+		x.Levels[i] = &RpcDatabaseLevelStats{
+			Level:              level.Level,
+			TableCount:         level.TableCount,
+			Size:               level.Size,
+			ReadBytes:          level.ReadBytes,
+			WriteBytes:         level.WriteBytes,
+			CompactionDuration: level.CompactionDuration,
+		}
+	}
+	x.MemoryCompactionCount = message.MemoryCompactionCount
+	x.Level0CompactionCount = message.Level0CompactionCount
+	x.NonLevel0CompactionCount = message.NonLevel0CompactionCount
+	x.SeekCompactionCount = message.SeekCompactionCount
+	x.WriteDelayCount = message.WriteDelayCount
+	x.WriteDelayDuration = message.WriteDelayDuration
+}
@@ -0,0 +1,80 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetLogLevelsRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetLogLevelsRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetLogLevelsRequest) fromAppMessage(_ *appmessage.GetLogLevelsRequestMessage) error {
+	x.GetLogLevelsRequest = &GetLogLevelsRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetLogLevelsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetLogLevelsResponse is nil")
+	}
+	return x.GetLogLevelsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetLogLevelsResponse) fromAppMessage(message *appmessage.GetLogLevelsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	logLevels := make([]*RpcSubsystemLogLevel, len(message.LogLevels))
+	for i, logLevel := range message.LogLevels {
+		logLevels[i] = &RpcSubsystemLogLevel{
+			Subsystem: logLevel.Subsystem,
+			Level:     logLevel.Level,
+		}
+	}
+	x.GetLogLevelsResponse = &GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+		Error:     err,
+	}
+	return nil
+}
+
+func (x *GetLogLevelsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetLogLevelsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.LogLevels) != 0 {
+		return nil, errors.New("GetLogLevelsResponseMessage contains both an error and a response")
+	}
+
+	logLevels := make([]*appmessage.RPCSubsystemLogLevel, len(x.LogLevels))
+	for i, logLevel := range x.LogLevels {
+		logLevels[i], err = logLevel.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+		Error:     rpcErr,
+	}, nil
+}
+
+func (x *RpcSubsystemLogLevel) toAppMessage() (*appmessage.RPCSubsystemLogLevel, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcSubsystemLogLevel is nil")
+	}
+	return &appmessage.RPCSubsystemLogLevel{
+		Subsystem: x.Subsystem,
+		Level:     x.Level,
+	}, nil
+}
@@ -27,7 +27,8 @@ func (x *KaspadMessage_GetMempoolEntriesResponse) toAppMessage() (appmessage.Mes
 func (x *KaspadMessage_GetMempoolEntriesResponse) fromAppMessage(message *appmessage.GetMempoolEntriesResponseMessage) error {
 	var rpcErr *RPCError
 	if message.Error != nil {
-		rpcErr = &RPCError{Message: message.Error.Message}
+		rpcErr = &RPCError{}
+		rpcErr.fromAppMessage(message.Error)
 	}
 	entries := make([]*MempoolEntry, len(message.Entries))
 	for i, entry := range message.Entries {
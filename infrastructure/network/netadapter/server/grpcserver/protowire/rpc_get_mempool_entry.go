@@ -38,7 +38,8 @@ func (x *KaspadMessage_GetMempoolEntryResponse) toAppMessage() (appmessage.Messa
 func (x *KaspadMessage_GetMempoolEntryResponse) fromAppMessage(message *appmessage.GetMempoolEntryResponseMessage) error {
 	var rpcErr *RPCError
 	if message.Error != nil {
-		rpcErr = &RPCError{Message: message.Error.Message}
+		rpcErr = &RPCError{}
+		rpcErr.fromAppMessage(message.Error)
 	}
 	var entry *MempoolEntry
 	if message.Entry != nil {
@@ -23,7 +23,8 @@ func (x *KaspadMessage_GetSelectedTipHashResponse) toAppMessage() (appmessage.Me
 func (x *KaspadMessage_GetSelectedTipHashResponse) fromAppMessage(message *appmessage.GetSelectedTipHashResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.GetSelectedTipHashResponse = &GetSelectedTipHashResponseMessage{
 		SelectedTipHash: message.SelectedTipHash,
@@ -0,0 +1,129 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetTransactionsByAddressesRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionsByAddressesRequest is nil")
+	}
+	return x.GetTransactionsByAddressesRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionsByAddressesRequest) fromAppMessage(message *appmessage.GetTransactionsByAddressesRequestMessage) error {
+	var startAfter *TransactionsByAddressesCursor
+	if message.StartAfter != nil {
+		startAfter = &TransactionsByAddressesCursor{
+			AcceptingBlockBlueScore: message.StartAfter.AcceptingBlockBlueScore,
+			TransactionId:           message.StartAfter.TransactionID,
+		}
+	}
+	x.GetTransactionsByAddressesRequest = &GetTransactionsByAddressesRequestMessage{
+		Addresses:  message.Addresses,
+		StartAfter: startAfter,
+		Limit:      message.Limit,
+	}
+	return nil
+}
+
+func (x *GetTransactionsByAddressesRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionsByAddressesRequestMessage is nil")
+	}
+	// StartAfter is an optional field
+	var startAfter *appmessage.TransactionsByAddressesCursor
+	if x.StartAfter != nil {
+		startAfter = &appmessage.TransactionsByAddressesCursor{
+			AcceptingBlockBlueScore: x.StartAfter.AcceptingBlockBlueScore,
+			TransactionID:           x.StartAfter.TransactionId,
+		}
+	}
+	return &appmessage.GetTransactionsByAddressesRequestMessage{
+		Addresses:  x.Addresses,
+		StartAfter: startAfter,
+		Limit:      x.Limit,
+	}, nil
+}
+
+func (x *KaspadMessage_GetTransactionsByAddressesResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionsByAddressesResponse is nil")
+	}
+	return x.GetTransactionsByAddressesResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionsByAddressesResponse) fromAppMessage(message *appmessage.GetTransactionsByAddressesResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	entries := make([]*TransactionsByAddressesEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = &TransactionsByAddressesEntry{}
+		entries[i].fromAppMessage(entry)
+	}
+	x.GetTransactionsByAddressesResponse = &GetTransactionsByAddressesResponseMessage{
+		Entries: entries,
+		Error:   err,
+	}
+	return nil
+}
+
+func (x *GetTransactionsByAddressesResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionsByAddressesResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetTransactionsByAddressesResponseMessage contains both an error and a response")
+	}
+
+	entries := make([]*appmessage.TransactionsByAddressesEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entryAsAppMessage, err := entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entryAsAppMessage
+	}
+
+	return &appmessage.GetTransactionsByAddressesResponseMessage{
+		Entries: entries,
+		Error:   rpcErr,
+	}, nil
+}
+
+func (x *TransactionsByAddressesEntry) toAppMessage() (*appmessage.TransactionsByAddressesEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TransactionsByAddressesEntry is nil")
+	}
+	return &appmessage.TransactionsByAddressesEntry{
+		Address:                 x.Address,
+		TransactionID:           x.TransactionId,
+		IncludingBlockHash:      x.IncludingBlockHash,
+		AcceptingBlockHash:      x.AcceptingBlockHash,
+		AcceptingBlockBlueScore: x.AcceptingBlockBlueScore,
+		Received:                x.Received,
+		Sent:                    x.Sent,
+	}, nil
+}
+
+func (x *TransactionsByAddressesEntry) fromAppMessage(message *appmessage.TransactionsByAddressesEntry) {
+	*x = TransactionsByAddressesEntry{
+		Address:                 message.Address,
+		TransactionId:           message.TransactionID,
+		IncludingBlockHash:      message.IncludingBlockHash,
+		AcceptingBlockHash:      message.AcceptingBlockHash,
+		AcceptingBlockBlueScore: message.AcceptingBlockBlueScore,
+		Received:                message.Received,
+		Sent:                    message.Sent,
+	}
+}
@@ -0,0 +1,126 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetUtxoHistoryByAddressesRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetUtxoHistoryByAddressesRequest is nil")
+	}
+	return x.GetUtxoHistoryByAddressesRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetUtxoHistoryByAddressesRequest) fromAppMessage(message *appmessage.GetUTXOHistoryByAddressesRequestMessage) error {
+	x.GetUtxoHistoryByAddressesRequest = &GetUtxoHistoryByAddressesRequestMessage{
+		Addresses: message.Addresses,
+	}
+	return nil
+}
+
+func (x *GetUtxoHistoryByAddressesRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetUtxoHistoryByAddressesRequestMessage is nil")
+	}
+	return &appmessage.GetUTXOHistoryByAddressesRequestMessage{
+		Addresses: x.Addresses,
+	}, nil
+}
+
+func (x *KaspadMessage_GetUtxoHistoryByAddressesResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetUtxoHistoryByAddressesResponse is nil")
+	}
+	return x.GetUtxoHistoryByAddressesResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetUtxoHistoryByAddressesResponse) fromAppMessage(message *appmessage.GetUTXOHistoryByAddressesResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
+	}
+	entries := make([]*UtxoHistoryByAddressesEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = &UtxoHistoryByAddressesEntry{}
+		entries[i].fromAppMessage(entry)
+	}
+	x.GetUtxoHistoryByAddressesResponse = &GetUtxoHistoryByAddressesResponseMessage{
+		Entries: entries,
+		Error:   err,
+	}
+	return nil
+}
+
+func (x *GetUtxoHistoryByAddressesResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetUtxoHistoryByAddressesResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetUtxoHistoryByAddressesResponseMessage contains both an error and a response")
+	}
+
+	entries := make([]*appmessage.UTXOHistoryByAddressesEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entryAsAppMessage, err := entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entryAsAppMessage
+	}
+
+	return &appmessage.GetUTXOHistoryByAddressesResponseMessage{
+		Entries: entries,
+		Error:   rpcErr,
+	}, nil
+}
+
+func (x *UtxoHistoryByAddressesEntry) toAppMessage() (*appmessage.UTXOHistoryByAddressesEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "UtxoHistoryByAddressesEntry is nil")
+	}
+	outpoint, err := x.Outpoint.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := x.UtxoEntry.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.UTXOHistoryByAddressesEntry{
+		Address:                 x.Address,
+		Outpoint:                outpoint,
+		UTXOEntry:               entry,
+		AcceptingBlockHash:      x.AcceptingBlockHash,
+		AcceptingBlockBlueScore: x.AcceptingBlockBlueScore,
+		SpendingTransactionID:   x.SpendingTransactionId,
+		SpendingBlockHash:       x.SpendingBlockHash,
+		SpendingBlockBlueScore:  x.SpendingBlockBlueScore,
+		SpendingTransactionFee:  x.SpendingTransactionFee,
+	}, nil
+}
+
+func (x *UtxoHistoryByAddressesEntry) fromAppMessage(message *appmessage.UTXOHistoryByAddressesEntry) {
+	outpoint := &RpcOutpoint{}
+	outpoint.fromAppMessage(message.Outpoint)
+	utxoEntry := &RpcUtxoEntry{}
+	utxoEntry.fromAppMessage(message.UTXOEntry)
+	*x = UtxoHistoryByAddressesEntry{
+		Address:                 message.Address,
+		Outpoint:                outpoint,
+		UtxoEntry:               utxoEntry,
+		AcceptingBlockHash:      message.AcceptingBlockHash,
+		AcceptingBlockBlueScore: message.AcceptingBlockBlueScore,
+		SpendingTransactionId:   message.SpendingTransactionID,
+		SpendingBlockHash:       message.SpendingBlockHash,
+		SpendingBlockBlueScore:  message.SpendingBlockBlueScore,
+		SpendingTransactionFee:  message.SpendingTransactionFee,
+	}
+}
@@ -38,7 +38,8 @@ func (x *KaspadMessage_GetUtxosByAddressesResponse) toAppMessage() (appmessage.M
 func (x *KaspadMessage_GetUtxosByAddressesResponse) fromAppMessage(message *appmessage.GetUTXOsByAddressesResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	entries := make([]*UtxosByAddressesEntry, len(message.Entries))
 	for i, entry := range message.Entries {
@@ -24,7 +24,8 @@ func (x *KaspadMessage_NotifyBlockAddedResponse) toAppMessage() (appmessage.Mess
 func (x *KaspadMessage_NotifyBlockAddedResponse) fromAppMessage(message *appmessage.NotifyBlockAddedResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.NotifyBlockAddedResponse = &NotifyBlockAddedResponseMessage{
 		Error: err,
@@ -24,7 +24,8 @@ func (x *KaspadMessage_NotifyFinalityConflictsResponse) toAppMessage() (appmessa
 func (x *KaspadMessage_NotifyFinalityConflictsResponse) fromAppMessage(message *appmessage.NotifyFinalityConflictsResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.NotifyFinalityConflictsResponse = &NotifyFinalityConflictsResponseMessage{
 		Error: err,
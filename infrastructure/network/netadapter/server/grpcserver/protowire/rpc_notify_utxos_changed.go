@@ -38,7 +38,8 @@ func (x *KaspadMessage_NotifyUtxosChangedResponse) toAppMessage() (appmessage.Me
 func (x *KaspadMessage_NotifyUtxosChangedResponse) fromAppMessage(message *appmessage.NotifyUTXOsChangedResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.NotifyUtxosChangedResponse = &NotifyUtxosChangedResponseMessage{
 		Error: err,
@@ -38,7 +38,8 @@ func (x *KaspadMessage_ResolveFinalityConflictResponse) toAppMessage() (appmessa
 func (x *KaspadMessage_ResolveFinalityConflictResponse) fromAppMessage(message *appmessage.ResolveFinalityConflictResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.ResolveFinalityConflictResponse = &ResolveFinalityConflictResponseMessage{
 		Error: err,
@@ -38,7 +38,8 @@ func (x *KaspadMessage_StopNotifyingUtxosChangedResponse) toAppMessage() (appmes
 func (x *KaspadMessage_StopNotifyingUtxosChangedResponse) fromAppMessage(message *appmessage.StopNotifyingUTXOsChangedResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.StopNotifyingUtxosChangedResponse = &StopNotifyingUtxosChangedResponseMessage{
 		Error: err,
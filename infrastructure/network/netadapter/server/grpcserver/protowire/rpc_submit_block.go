@@ -41,7 +41,8 @@ func (x *KaspadMessage_SubmitBlockResponse) toAppMessage() (appmessage.Message,
 func (x *KaspadMessage_SubmitBlockResponse) fromAppMessage(message *appmessage.SubmitBlockResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.SubmitBlockResponse = &SubmitBlockResponseMessage{
 		RejectReason: SubmitBlockResponseMessage_RejectReason(message.RejectReason),
@@ -36,7 +36,8 @@ func (x *KaspadMessage_UnbanResponse) toAppMessage() (appmessage.Message, error)
 func (x *KaspadMessage_UnbanResponse) fromAppMessage(message *appmessage.UnbanResponseMessage) error {
 	var err *RPCError
 	if message.Error != nil {
-		err = &RPCError{Message: message.Error.Message}
+		err = &RPCError{}
+		err.fromAppMessage(message.Error)
 	}
 	x.UnbanResponse = &UnbanResponseMessage{
 		Error: err,
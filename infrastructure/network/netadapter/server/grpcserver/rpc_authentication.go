@@ -0,0 +1,152 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RPCAuthorizationMetadataKey is the gRPC metadata key with which RPC
+// clients send their credentials
+const RPCAuthorizationMetadataKey = "authorization"
+
+// rpcAuthenticatedMetadataKey is the gRPC header metadata key with which
+// the RPC server lets clients know that they had been authenticated
+const rpcAuthenticatedMetadataKey = "kaspad-rpc-authenticated"
+
+// RPCCredentials are the credentials with which an RPC client authenticates.
+// Either a user and password or a token may be used.
+type RPCCredentials struct {
+	User     string
+	Password string
+	Token    string
+}
+
+// IsSet returns whether any credentials are set
+func (c *RPCCredentials) IsSet() bool {
+	return c != nil && (c.User != "" || c.Password != "" || c.Token != "")
+}
+
+// Authorization returns the value of the authorization metadata that
+// authenticates with these credentials. A token takes precedence over a
+// user and password.
+func (c *RPCCredentials) Authorization() string {
+	if c.Token != "" {
+		return bearerAuthorization(c.Token)
+	}
+	return basicAuthorization(c.User, c.Password)
+}
+
+// authorizations returns all the authorization metadata values that
+// authenticate with these credentials
+func (c *RPCCredentials) authorizations() []string {
+	if !c.IsSet() {
+		return nil
+	}
+	var authorizations []string
+	if c.User != "" || c.Password != "" {
+		authorizations = append(authorizations, basicAuthorization(c.User, c.Password))
+	}
+	if c.Token != "" {
+		authorizations = append(authorizations, bearerAuthorization(c.Token))
+	}
+	return authorizations
+}
+
+func basicAuthorization(user string, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
+
+func bearerAuthorization(token string) string {
+	return "Bearer " + token
+}
+
+// ErrMissingRPCCredentials and ErrInvalidRPCCredentials are the errors with
+// which RPCAuthenticator rejects clients
+var (
+	ErrMissingRPCCredentials = errors.New("missing RPC credentials")
+	ErrInvalidRPCCredentials = errors.New("invalid RPC credentials")
+)
+
+// RPCAuthenticator authenticates incoming RPC connections against
+// admin and limited (read-only) credentials
+type RPCAuthenticator struct {
+	adminAuthorizationHashes   [][sha256.Size]byte
+	limitedAuthorizationHashes [][sha256.Size]byte
+}
+
+// NewRPCAuthenticator returns a new RPCAuthenticator for the given credentials.
+// Either of them may be unset.
+func NewRPCAuthenticator(adminCredentials *RPCCredentials, limitedCredentials *RPCCredentials) *RPCAuthenticator {
+	return &RPCAuthenticator{
+		adminAuthorizationHashes:   hashAuthorizations(adminCredentials.authorizations()),
+		limitedAuthorizationHashes: hashAuthorizations(limitedCredentials.authorizations()),
+	}
+}
+
+func hashAuthorizations(authorizations []string) [][sha256.Size]byte {
+	hashes := make([][sha256.Size]byte, len(authorizations))
+	for i, authorization := range authorizations {
+		hashes[i] = sha256.Sum256([]byte(authorization))
+	}
+	return hashes
+}
+
+// IsEnabled returns whether any credentials are required
+func (a *RPCAuthenticator) IsEnabled() bool {
+	return len(a.adminAuthorizationHashes) > 0 || len(a.limitedAuthorizationHashes) > 0
+}
+
+// Authenticate checks the given values of the authorization metadata (or of
+// the HTTP Authorization header). It returns how the client was authenticated,
+// or ErrMissingRPCCredentials or ErrInvalidRPCCredentials.
+func (a *RPCAuthenticator) Authenticate(authorizations []string) (server.RPCAuthentication, error) {
+	if !a.IsEnabled() {
+		return server.RPCAuthenticationNone, nil
+	}
+	if len(authorizations) == 0 {
+		return 0, ErrMissingRPCCredentials
+	}
+
+	// Hash the authorization so that the comparisons below are made
+	// in constant time regardless of the authorization's length
+	authorizationHash := sha256.Sum256([]byte(authorizations[0]))
+	if containsHash(a.adminAuthorizationHashes, authorizationHash) {
+		return server.RPCAuthenticationAdmin, nil
+	}
+	if containsHash(a.limitedAuthorizationHashes, authorizationHash) {
+		return server.RPCAuthenticationLimited, nil
+	}
+	return 0, ErrInvalidRPCCredentials
+}
+
+// authenticate checks the credentials sent with the given stream context.
+// It returns how the client was authenticated, or an Unauthenticated status
+// error if the credentials are missing or invalid.
+func (a *RPCAuthenticator) authenticate(ctx context.Context) (server.RPCAuthentication, error) {
+	var authorizations []string
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		authorizations = md.Get(RPCAuthorizationMetadataKey)
+	}
+	authentication, err := a.Authenticate(authorizations)
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return authentication, nil
+}
+
+func containsHash(hashes [][sha256.Size]byte, hash [sha256.Size]byte) bool {
+	found := 0
+	for _, candidate := range hashes {
+		found |= subtle.ConstantTimeCompare(candidate[:], hash[:])
+	}
+	return found == 1
+}
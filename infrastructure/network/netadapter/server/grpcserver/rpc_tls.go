@@ -0,0 +1,76 @@
+package grpcserver
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+const rpcCertificateValidity = 10 * 365 * 24 * time.Hour
+
+// LoadRPCTLSConfig loads the RPC server's TLS certificate pair from the given
+// files. If neither of the files exists, a new self-signed certificate pair is
+// generated and written to them first, valid for the local interfaces as well
+// as for the given extra hosts.
+func LoadRPCTLSConfig(certificateFile string, keyFile string, extraHosts []string) (*tls.Config, error) {
+	certificateExists := fileExists(certificateFile)
+	keyExists := fileExists(keyFile)
+	if certificateExists != keyExists {
+		return nil, errors.Errorf("only one of the RPC certificate file %s and the RPC key file %s exists. "+
+			"Either provide both or remove the existing one to generate a new pair", certificateFile, keyFile)
+	}
+	if !certificateExists {
+		err := generateRPCCertificatePair(certificateFile, keyFile, extraHosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(certificateFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading the RPC certificate pair")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func generateRPCCertificatePair(certificateFile string, keyFile string, extraHosts []string) error {
+	log.Infof("Generating a self-signed TLS certificate pair for the RPC server")
+
+	validUntil := time.Now().Add(rpcCertificateValidity)
+	certificate, key, err := util.NewTLSCertPair("kaspad autogenerated cert", validUntil, extraHosts)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []string{certificateFile, keyFile} {
+		err := os.MkdirAll(filepath.Dir(file), 0700)
+		if err != nil {
+			return err
+		}
+	}
+	err = ioutil.WriteFile(certificateFile, certificate, 0644)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(keyFile, key, 0600)
+	if err != nil {
+		_ = os.Remove(certificateFile)
+		return err
+	}
+
+	log.Infof("Wrote the RPC certificate to %s and its key to %s", certificateFile, keyFile)
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
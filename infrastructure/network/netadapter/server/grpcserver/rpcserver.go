@@ -1,23 +1,41 @@
 package grpcserver
 
 import (
+	"crypto/tls"
+
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/kaspanet/kaspad/util/panics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 type rpcServer struct {
 	protowire.UnimplementedRPCServer
 	gRPCServer
+	authenticator *RPCAuthenticator
 }
 
 // RPCMaxMessageSize is the max message size for the RPC server to send and receive
 const RPCMaxMessageSize = 1024 * 1024 * 1024 // 1 GB
 
-// NewRPCServer creates a new RPCServer
-func NewRPCServer(listeningAddresses []string) (server.Server, error) {
-	gRPCServer := newGRPCServer(listeningAddresses, RPCMaxMessageSize, "RPC")
-	rpcServer := &rpcServer{gRPCServer: *gRPCServer}
+// NewRPCServer creates a new RPCServer. If tlsConfig is not nil, the server
+// only accepts TLS connections. If any of adminCredentials and limitedCredentials
+// is set, clients must authenticate with either of them. Clients that authenticate
+// with limitedCredentials are not granted admin permissions.
+func NewRPCServer(listeningAddresses []string, tlsConfig *tls.Config,
+	adminCredentials *RPCCredentials, limitedCredentials *RPCCredentials) (server.Server, error) {
+
+	serverOptions := []grpc.ServerOption{grpc.StatsHandler(listenAddressStatsHandler{})}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	gRPCServer := newGRPCServer(listeningAddresses, RPCMaxMessageSize, "RPC", serverOptions...)
+	rpcServer := &rpcServer{
+		gRPCServer:    *gRPCServer,
+		authenticator: NewRPCAuthenticator(adminCredentials, limitedCredentials),
+	}
 	protowire.RegisterRPCServer(gRPCServer.server, rpcServer)
 	return rpcServer, nil
 }
@@ -25,5 +43,22 @@ func NewRPCServer(listeningAddresses []string) (server.Server, error) {
 func (r *rpcServer) MessageStream(stream protowire.RPC_MessageStreamServer) error {
 	defer panics.HandlePanic(log, "rpcServer.MessageStream", nil)
 
-	return r.handleInboundConnection(stream.Context(), stream)
+	authentication, err := r.authenticator.authenticate(stream.Context())
+	if err != nil {
+		log.Warnf("%s Rejected connection: %s", r.name, err)
+		return err
+	}
+	// Let the client know it had been authenticated. See grpcclient.waitForAuthentication
+	err = stream.SendHeader(metadata.Pairs(rpcAuthenticatedMetadataKey, "true"))
+	if err != nil {
+		return err
+	}
+
+	connection, err := r.newInboundConnection(stream.Context(), stream)
+	if err != nil {
+		return err
+	}
+	connection.rpcAuthentication = authentication
+	connection.listenAddress = listenAddressFromContext(stream.Context())
+	return r.serveInboundConnection(connection)
 }
@@ -0,0 +1,292 @@
+package jsonrpcserver
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/pkg/errors"
+)
+
+// connection is a JSON-RPC client, connected either over a single
+// HTTP request or over a WebSocket. It passes the JSON-RPC requests
+// it handles to the router it's started with, and answers them with
+// the responses that come back through the router.
+type connection struct {
+	address        *net.TCPAddr
+	listenAddress  string
+	authentication server.RPCAuthentication
+
+	// allowsNotifications is set for WebSocket connections, which
+	// remain open for the server to send notifications to
+	allowsNotifications bool
+
+	// emit sends the given payload to the client. A nil payload means
+	// that there's nothing to send: the client only sent notifications
+	emit func(payload interface{})
+
+	// closeTransport, if set, closes the underlying transport
+	closeTransport func()
+
+	router                  *router.Router
+	stopChan                chan struct{}
+	onDisconnectedHandler   server.OnDisconnectedHandler
+	onInvalidMessageHandler server.OnInvalidMessageHandler
+	isConnected             uint32
+
+	// pendingRequests are the requests that were passed to the router,
+	// in order. The router answers them in the same order.
+	pendingRequests     []*pendingRequest
+	pendingRequestsLock sync.Mutex
+}
+
+// call is a single request or a batch of requests, which is answered
+// once all of its requests are
+type call struct {
+	isBatch           bool
+	remainingRequests int
+	responses         []*response
+}
+
+type pendingRequest struct {
+	request *request
+	call    *call
+}
+
+func newConnection(address *net.TCPAddr, listenAddress string, authentication server.RPCAuthentication,
+	allowsNotifications bool, emit func(payload interface{}), closeTransport func()) *connection {
+
+	return &connection{
+		address:             address,
+		listenAddress:       listenAddress,
+		authentication:      authentication,
+		allowsNotifications: allowsNotifications,
+		emit:                emit,
+		closeTransport:      closeTransport,
+		stopChan:            make(chan struct{}),
+		isConnected:         1,
+	}
+}
+
+func (c *connection) Start(router *router.Router) {
+	if c.onDisconnectedHandler == nil {
+		panic(errors.New("onDisconnectedHandler is nil"))
+	}
+
+	c.router = router
+
+	spawn("jsonrpcserver.connection.Start-sendLoop", func() {
+		for {
+			message, err := router.OutgoingRoute().Dequeue()
+			if err != nil {
+				// The router was closed
+				c.Disconnect()
+				return
+			}
+			outgoingMessage, err := convertOutgoingMessage(message)
+			if err != nil {
+				log.Errorf("Error converting %s to JSON for %s: %s", message.Command(), c, err)
+				continue
+			}
+			c.handleOutgoingMessage(outgoingMessage)
+		}
+	})
+}
+
+func (c *connection) String() string {
+	return c.address.String()
+}
+
+func (c *connection) IsConnected() bool {
+	return atomic.LoadUint32(&c.isConnected) != 0
+}
+
+func (c *connection) SetOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
+func (c *connection) SetOnInvalidMessageHandler(onInvalidMessageHandler server.OnInvalidMessageHandler) {
+	c.onInvalidMessageHandler = onInvalidMessageHandler
+}
+
+func (c *connection) IsOutbound() bool {
+	return false
+}
+
+func (c *connection) Address() *net.TCPAddr {
+	return c.address
+}
+
+// Authentication returns how the connection was authenticated
+//
+// This is part of the RPCConnection interface
+func (c *connection) Authentication() server.RPCAuthentication {
+	return c.authentication
+}
+
+// ListenAddress returns the address of the listener that accepted the connection
+//
+// This is part of the RPCConnection interface
+func (c *connection) ListenAddress() string {
+	return c.listenAddress
+}
+
+// Disconnect disconnects the connection
+// Calling this function a second time doesn't do anything
+//
+// This is part of the Connection interface
+func (c *connection) Disconnect() {
+	if !atomic.CompareAndSwapUint32(&c.isConnected, 1, 0) {
+		return
+	}
+	close(c.stopChan)
+
+	if c.closeTransport != nil {
+		c.closeTransport()
+	}
+
+	log.Debugf("Disconnected from %s", c)
+	if c.onDisconnectedHandler != nil {
+		c.onDisconnectedHandler()
+	}
+}
+
+// handle handles the given JSON-RPC payload, which is either a single request or a batch
+func (c *connection) handle(payload []byte) {
+	var rawRequests []json.RawMessage
+	isBatch := len(payload) > 0 && payload[0] == '['
+	if isBatch {
+		err := json.Unmarshal(payload, &rawRequests)
+		if err != nil {
+			c.emit(newErrorResponse(nil, errorCodeParseError, err.Error()))
+			return
+		}
+		if len(rawRequests) == 0 {
+			c.emit(newErrorResponse(nil, errorCodeInvalidRequest, "empty batch"))
+			return
+		}
+		if len(rawRequests) > maxBatchSize {
+			c.emit(newErrorResponse(nil, errorCodeInvalidRequest, "batches may contain at most 50 requests"))
+			return
+		}
+	} else {
+		if !json.Valid(payload) {
+			c.emit(newErrorResponse(nil, errorCodeParseError, "invalid JSON"))
+			return
+		}
+		rawRequests = []json.RawMessage{payload}
+	}
+
+	call := &call{isBatch: isBatch, remainingRequests: len(rawRequests)}
+	for _, rawRequest := range rawRequests {
+		c.handleRequest(call, rawRequest)
+	}
+}
+
+func (c *connection) handleRequest(call *call, rawRequest json.RawMessage) {
+	request := &request{}
+	err := json.Unmarshal(rawRequest, request)
+	if err != nil {
+		// Invalid requests are always answered, since it's unknown
+		// whether they're notifications
+		c.answer(call, false, newErrorResponse(nil, errorCodeInvalidRequest, err.Error()))
+		return
+	}
+	if request.JSONRPC != jsonRPCVersion || request.Method == "" {
+		c.answer(call, false,
+			newErrorResponse(request.ID, errorCodeInvalidRequest, "expected a JSON-RPC 2.0 request"))
+		return
+	}
+	if !c.allowsNotifications && isNotificationMethod(request.Method) {
+		c.answer(call, request.isNotification(), newErrorResponse(request.ID, errorCodeInvalidRequest,
+			"notifications are only available over WebSocket"))
+		return
+	}
+
+	message, err := parseRequest(request.Method, request.Params)
+	if err != nil {
+		code := errorCodeInvalidParams
+		if errors.Is(err, errMethodNotFound) {
+			code = errorCodeMethodNotFound
+		}
+		c.answer(call, request.isNotification(), newErrorResponse(request.ID, code, err.Error()))
+		return
+	}
+
+	// The request is added to pendingRequests before it's enqueued,
+	// since it might be answered before EnqueueIncomingMessage returns
+	c.pendingRequestsLock.Lock()
+	c.pendingRequests = append(c.pendingRequests, &pendingRequest{request: request, call: call})
+	c.pendingRequestsLock.Unlock()
+
+	err = c.router.EnqueueIncomingMessage(message)
+	if err != nil {
+		// Requests are only enqueued from this goroutine, so the
+		// request that failed to be enqueued is the last one
+		c.pendingRequestsLock.Lock()
+		c.pendingRequests = c.pendingRequests[:len(c.pendingRequests)-1]
+		c.pendingRequestsLock.Unlock()
+
+		log.Debugf("Error handling %s from %s: %s", request.Method, c, err)
+		c.answer(call, request.isNotification(), newErrorResponse(request.ID, errorCodeInternalError, "the server is busy"))
+	}
+}
+
+func (c *connection) handleOutgoingMessage(message *outgoingMessage) {
+	if message.isNotification() {
+		if c.allowsNotifications {
+			c.emit(&notification{JSONRPC: jsonRPCVersion, Method: message.name, Params: message.json})
+		}
+		return
+	}
+	if !message.isResponse() {
+		log.Warnf("Dropping unexpected message %s to %s", message.name, c)
+		return
+	}
+
+	c.pendingRequestsLock.Lock()
+	if len(c.pendingRequests) == 0 {
+		c.pendingRequestsLock.Unlock()
+		log.Warnf("Dropping %s to %s: there are no pending requests", message.name, c)
+		return
+	}
+	pendingRequest := c.pendingRequests[0]
+	c.pendingRequests = c.pendingRequests[1:]
+	c.pendingRequestsLock.Unlock()
+
+	id := pendingRequest.request.ID
+	isNotification := pendingRequest.request.isNotification()
+	if message.rpcError != nil {
+		c.answer(pendingRequest.call, isNotification, newRPCErrorResponse(id, message.rpcError))
+		return
+	}
+	c.answer(pendingRequest.call, isNotification, newResultResponse(id, message.json))
+}
+
+// answer records the response to a request of the given call, and emits
+// the call's responses once all of its requests are answered. Responses to
+// notifications are not emitted.
+func (c *connection) answer(call *call, isNotification bool, response *response) {
+	c.pendingRequestsLock.Lock()
+	call.remainingRequests--
+	if !isNotification {
+		call.responses = append(call.responses, response)
+	}
+	isDone := call.remainingRequests == 0
+	c.pendingRequestsLock.Unlock()
+
+	if !isDone {
+		return
+	}
+	switch {
+	case len(call.responses) == 0:
+		c.emit(nil)
+	case call.isBatch:
+		c.emit(call.responses)
+	default:
+		c.emit(call.responses[0])
+	}
+}
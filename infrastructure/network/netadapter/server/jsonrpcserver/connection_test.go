@@ -0,0 +1,194 @@
+package jsonrpcserver
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+)
+
+// startTestConnection starts a connection whose router answers every
+// GetBlockCount request with the number of requests it had answered.
+// It returns the connection, its router, a function that closes the
+// router and a channel of the payloads that the connection emits.
+func startTestConnection(t *testing.T, allowsNotifications bool) (
+	*connection, *router.Router, func(), chan interface{}) {
+
+	emitted := make(chan interface{}, 10)
+	emit := func(payload interface{}) {
+		emitted <- payload
+	}
+	connection := newConnection(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}, "127.0.0.1:16120",
+		server.RPCAuthenticationNone, allowsNotifications, emit, nil)
+
+	testRouter := router.NewRouter()
+	incomingRoute, err := testRouter.AddIncomingRoute([]appmessage.MessageCommand{appmessage.CmdGetBlockCountRequestMessage})
+	if err != nil {
+		t.Fatalf("AddIncomingRoute: %s", err)
+	}
+	go func() {
+		for blockCount := uint64(0); ; blockCount++ {
+			_, err := incomingRoute.Dequeue()
+			if err != nil {
+				return
+			}
+			response := appmessage.NewGetBlockCountResponseMessage(&externalapi.SyncInfo{BlockCount: blockCount})
+			err = testRouter.OutgoingRoute().Enqueue(response)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Like NetConnection, close the router only once
+	var closeRouterOnce sync.Once
+	closeRouter := func() {
+		closeRouterOnce.Do(testRouter.Close)
+	}
+	connection.SetOnDisconnectedHandler(closeRouter)
+	connection.Start(testRouter)
+	return connection, testRouter, closeRouter, emitted
+}
+
+func receiveEmitted(t *testing.T, emitted chan interface{}) interface{} {
+	select {
+	case payload := <-emitted:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the connection to emit")
+		return nil
+	}
+}
+
+func TestConnectionSingleRequest(t *testing.T) {
+	connection, _, _, emitted := startTestConnection(t, false)
+	defer connection.Disconnect()
+
+	connection.handle([]byte(`{"jsonrpc": "2.0", "method": "getBlockCount", "id": 7}`))
+	singleResponse, ok := receiveEmitted(t, emitted).(*response)
+	if !ok {
+		t.Fatalf("Expected a single response")
+	}
+	if string(singleResponse.ID) != "7" || singleResponse.Error != nil || !jsonEqual(t, singleResponse.Result, `{"blockCount":"0","headerCount":"0"}`) {
+		t.Fatalf("Unexpected response: %+v, result: %s", singleResponse, singleResponse.Result)
+	}
+
+	connection.handle([]byte(`{"jsonrpc": "2.0", "method": "getBlockCount"`))
+	singleResponse = receiveEmitted(t, emitted).(*response)
+	if singleResponse.Error == nil || singleResponse.Error.Code != errorCodeParseError || string(singleResponse.ID) != "" {
+		t.Fatalf("Expected a parse error, but got: %+v", singleResponse)
+	}
+
+	connection.handle([]byte(`{"jsonrpc": "2.0", "method": "notifyBlockAdded", "id": 8}`))
+	singleResponse = receiveEmitted(t, emitted).(*response)
+	if singleResponse.Error == nil || singleResponse.Error.Code != errorCodeInvalidRequest || string(singleResponse.ID) != "8" {
+		t.Fatalf("Expected notifications to be unavailable, but got: %+v", singleResponse)
+	}
+
+	// A notification is handled, but not answered
+	connection.handle([]byte(`{"jsonrpc": "2.0", "method": "getBlockCount"}`))
+	if payload := receiveEmitted(t, emitted); payload != nil {
+		t.Fatalf("Expected nothing to be emitted for a notification, but got: %+v", payload)
+	}
+	connection.handle([]byte(`{"jsonrpc": "2.0", "method": "getBlockCount", "id": 9}`))
+	singleResponse = receiveEmitted(t, emitted).(*response)
+	if !jsonEqual(t, singleResponse.Result, `{"blockCount":"2","headerCount":"0"}`) {
+		t.Fatalf("Expected the notification to be handled before, but got: %s", singleResponse.Result)
+	}
+}
+
+func TestConnectionBatch(t *testing.T) {
+	connection, _, _, emitted := startTestConnection(t, false)
+	defer connection.Disconnect()
+
+	connection.handle([]byte(`[
+		{"jsonrpc": "2.0", "method": "getBlockCount", "id": 1},
+		{"jsonrpc": "2.0", "method": "getBlockCount"},
+		{"jsonrpc": "2.0", "method": "noSuchMethod", "id": 2},
+		{"foo": "bar"},
+		{"jsonrpc": "2.0", "method": "getBlockCount", "id": "three"}
+	]`))
+	responses, ok := receiveEmitted(t, emitted).([]*response)
+	if !ok {
+		t.Fatalf("Expected a batch response")
+	}
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses, but got %d", len(responses))
+	}
+
+	responsesByID := make(map[string]*response)
+	for _, response := range responses {
+		responsesByID[string(response.ID)] = response
+	}
+	if response := responsesByID["1"]; response == nil || !jsonEqual(t, response.Result, `{"blockCount":"0","headerCount":"0"}`) {
+		t.Fatalf("Unexpected response to request 1: %+v", response)
+	}
+	if response := responsesByID[`"three"`]; response == nil || !jsonEqual(t, response.Result, `{"blockCount":"2","headerCount":"0"}`) {
+		t.Fatalf("Unexpected response to request three: %+v", response)
+	}
+	if response := responsesByID["2"]; response == nil || response.Error == nil || response.Error.Code != errorCodeMethodNotFound {
+		t.Fatalf("Expected method not found for request 2, but got: %+v", response)
+	}
+	if response := responsesByID[""]; response == nil || response.Error == nil || response.Error.Code != errorCodeInvalidRequest {
+		t.Fatalf("Expected an invalid request error, but got: %+v", response)
+	}
+
+	connection.handle([]byte(`[]`))
+	singleResponse := receiveEmitted(t, emitted).(*response)
+	if singleResponse.Error == nil || singleResponse.Error.Code != errorCodeInvalidRequest {
+		t.Fatalf("Expected an invalid request error for an empty batch, but got: %+v", singleResponse)
+	}
+
+	connection.handle([]byte(`[{"jsonrpc": "2.0", "method": "getBlockCount"}, {"jsonrpc": "2.0", "method": "getBlockCount"}]`))
+	if payload := receiveEmitted(t, emitted); payload != nil {
+		t.Fatalf("Expected nothing to be emitted for a batch of notifications, but got: %+v", payload)
+	}
+}
+
+func TestConnectionNotifications(t *testing.T) {
+	for _, allowsNotifications := range []bool{true, false} {
+		connection, testRouter, _, emitted := startTestConnection(t, allowsNotifications)
+
+		err := testRouter.OutgoingRoute().Enqueue(appmessage.NewVirtualDaaScoreChangedNotificationMessage(5))
+		if err != nil {
+			t.Fatalf("Enqueue: %s", err)
+		}
+		connection.handle([]byte(`{"jsonrpc": "2.0", "method": "getBlockCount", "id": 1}`))
+
+		payload := receiveEmitted(t, emitted)
+		if allowsNotifications {
+			notification, ok := payload.(*notification)
+			if !ok {
+				t.Fatalf("Expected a notification, but got: %+v", payload)
+			}
+			if notification.Method != "virtualDaaScoreChangedNotification" ||
+				!jsonEqual(t, notification.Params, `{"virtualDaaScore":"5"}`) {
+				t.Fatalf("Unexpected notification: %s %s", notification.Method, notification.Params)
+			}
+			payload = receiveEmitted(t, emitted)
+		}
+		if _, ok := payload.(*response); !ok {
+			t.Fatalf("Expected a response, but got: %+v", payload)
+		}
+		connection.Disconnect()
+	}
+}
+
+func TestConnectionDisconnectsWhenRouterCloses(t *testing.T) {
+	connection, _, closeRouter, _ := startTestConnection(t, true)
+	closeRouter()
+
+	select {
+	case <-connection.stopChan:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the connection to disconnect")
+	}
+	if connection.IsConnected() {
+		t.Fatalf("Expected the connection to be disconnected")
+	}
+}
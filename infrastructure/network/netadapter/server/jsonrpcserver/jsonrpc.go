@@ -0,0 +1,75 @@
+package jsonrpcserver
+
+import (
+	"encoding/json"
+)
+
+const jsonRPCVersion = "2.0"
+
+// The error codes defined by the JSON-RPC 2.0 specification
+const (
+	errorCodeParseError     = -32700
+	errorCodeInvalidRequest = -32600
+	errorCodeMethodNotFound = -32601
+	errorCodeInvalidParams  = -32602
+	errorCodeInternalError  = -32603
+)
+
+// The error codes of the errors returned by the RPC handlers.
+// See appmessage.RPCErrorCode
+const (
+	errorCodeRPCError         = -32000
+	errorCodePermissionDenied = -32001
+	errorCodeRateLimited      = -32002
+)
+
+// maxBatchSize is the maximum number of requests in a single batch. It's
+// kept below the capacity of the RPC router's incoming route
+const maxBatchSize = 50
+
+// request is a JSON-RPC request. Requests without an ID are notifications,
+// which the server doesn't respond to
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+func (r *request) isNotification() bool {
+	return r.ID == nil
+}
+
+// response is a JSON-RPC response. Exactly one of Result and Error is set
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type responseError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// notification is a JSON-RPC notification, which the server sends
+// to WebSocket clients that subscribed to it
+type notification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+func newResultResponse(id json.RawMessage, result json.RawMessage) *response {
+	return &response{JSONRPC: jsonRPCVersion, Result: result, ID: id}
+}
+
+func newErrorResponse(id json.RawMessage, code int, message string) *response {
+	return &response{
+		JSONRPC: jsonRPCVersion,
+		Error:   &responseError{Code: code, Message: message},
+		ID:      id,
+	}
+}
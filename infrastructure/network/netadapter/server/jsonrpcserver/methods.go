@@ -0,0 +1,173 @@
+package jsonrpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The JSON-RPC methods are named after the fields of the KaspadMessage
+// payload that hold their requests, without the "Request" suffix. For
+// example, getBlockCount is sent as getBlockCountRequest. The params and
+// results of the methods are the JSON representations of the request and
+// response messages, as documented in rpc.md.
+const (
+	requestFieldSuffix      = "Request"
+	responseFieldSuffix     = "Response"
+	notificationFieldSuffix = "Notification"
+
+	// The fields of the RPC messages are numbered from 1000 on. Lower
+	// numbers belong to P2P messages, which are not exposed over JSON-RPC
+	minRPCFieldNumber = 1000
+
+	payloadOneofName = "payload"
+	errorFieldName   = "error"
+)
+
+var (
+	errMethodNotFound = errors.New("method not found")
+	errInvalidParams  = errors.New("invalid params")
+)
+
+var requestFieldsByMethod = func() map[string]protoreflect.FieldDescriptor {
+	payloadFields := (&protowire.KaspadMessage{}).ProtoReflect().Descriptor().Oneofs().ByName(payloadOneofName).Fields()
+	requestFieldsByMethod := make(map[string]protoreflect.FieldDescriptor)
+	for i := 0; i < payloadFields.Len(); i++ {
+		field := payloadFields.Get(i)
+		if field.Number() < minRPCFieldNumber || !strings.HasSuffix(field.JSONName(), requestFieldSuffix) {
+			continue
+		}
+		method := strings.TrimSuffix(field.JSONName(), requestFieldSuffix)
+		requestFieldsByMethod[method] = field
+	}
+	return requestFieldsByMethod
+}()
+
+// isNotificationMethod returns whether the given method subscribes to or
+// unsubscribes from notifications
+func isNotificationMethod(method string) bool {
+	return strings.HasPrefix(method, "notify") || strings.HasPrefix(method, "stopNotifying")
+}
+
+// parseRequest converts the given method and params to the respective request
+// message. It returns errMethodNotFound or errInvalidParams if they're invalid.
+func parseRequest(method string, params json.RawMessage) (appmessage.Message, error) {
+	field, ok := requestFieldsByMethod[method]
+	if !ok {
+		return nil, errors.Wrapf(errMethodNotFound, "unknown method %s", method)
+	}
+
+	kaspadMessage := &protowire.KaspadMessage{}
+	requestMessage := kaspadMessage.ProtoReflect().NewField(field).Message()
+	params = bytes.TrimSpace(params)
+	if len(params) > 0 && !bytes.Equal(params, []byte("null")) {
+		if params[0] != '{' {
+			return nil, errors.Wrapf(errInvalidParams, "params must be an object")
+		}
+		err := protojson.Unmarshal(params, requestMessage.Interface())
+		if err != nil {
+			return nil, errors.Wrapf(errInvalidParams, "%s", err)
+		}
+	}
+	kaspadMessage.ProtoReflect().Set(field, protoreflect.ValueOfMessage(requestMessage))
+
+	request, err := kaspadMessage.ToAppMessage()
+	if err != nil {
+		return nil, errors.Wrapf(errInvalidParams, "%s", err)
+	}
+	return request, nil
+}
+
+// outgoingMessage is the JSON representation of a response or a notification
+type outgoingMessage struct {
+	// name is the name of the KaspadMessage payload field that holds
+	// the message, e.g. getBlockCountResponse
+	name string
+
+	// json is the JSON representation of the message, without its error
+	json json.RawMessage
+
+	// rpcError is the error of the message, if it's a response with an error
+	rpcError *protowire.RPCError
+}
+
+func (m *outgoingMessage) isNotification() bool {
+	return strings.HasSuffix(m.name, notificationFieldSuffix)
+}
+
+func (m *outgoingMessage) isResponse() bool {
+	return strings.HasSuffix(m.name, responseFieldSuffix)
+}
+
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// convertOutgoingMessage converts the given response or notification to its
+// JSON representation
+func convertOutgoingMessage(message appmessage.Message) (*outgoingMessage, error) {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	kaspadMessageReflection := kaspadMessage.ProtoReflect()
+	payloadOneof := kaspadMessageReflection.Descriptor().Oneofs().ByName(payloadOneofName)
+	field := kaspadMessageReflection.WhichOneof(payloadOneof)
+	if field == nil {
+		return nil, errors.Errorf("%s has no payload", message.Command())
+	}
+	payload := kaspadMessageReflection.Get(field).Message()
+
+	var rpcError *protowire.RPCError
+	errorField := payload.Descriptor().Fields().ByName(errorFieldName)
+	if errorField != nil && payload.Has(errorField) {
+		rpcError = payload.Get(errorField).Message().Interface().(*protowire.RPCError)
+		payload.Clear(errorField)
+	}
+
+	payloadJSON, err := marshalOptions.Marshal(payload.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if errorField != nil {
+		payloadJSON, err = removeJSONField(payloadJSON, errorField.JSONName())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &outgoingMessage{
+		name:     field.JSONName(),
+		json:     payloadJSON,
+		rpcError: rpcError,
+	}, nil
+}
+
+// removeJSONField removes the field with the given name from the given JSON
+// object. The error field of responses is removed from their results, since
+// it's returned as the JSON-RPC error instead.
+func removeJSONField(objectJSON json.RawMessage, name string) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(objectJSON, &fields)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, name)
+	return json.Marshal(fields)
+}
+
+// newRPCErrorResponse converts the error of a response to a JSON-RPC error response
+func newRPCErrorResponse(id json.RawMessage, rpcError *protowire.RPCError) *response {
+	response := newErrorResponse(id, errorCodeRPCError, rpcError.Message)
+	switch rpcError.Code {
+	case protowire.RPCError_PERMISSION_DENIED:
+		response.Error.Code = errorCodePermissionDenied
+	case protowire.RPCError_RATE_LIMITED:
+		response.Error.Code = errorCodeRateLimited
+		response.Error.Data = map[string]uint64{"retryAfterMilliseconds": rpcError.RetryAfterMilliseconds}
+	}
+	return response
+}
@@ -0,0 +1,114 @@
+package jsonrpcserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		method        string
+		params        string
+		expectedError error
+	}{
+		{method: "getBlockCount"},
+		{method: "getBlockCount", params: "null"},
+		{method: "getBlock", params: `{"hash": "abcd", "includeTransactionVerboseData": true}`},
+		{method: "getBlockCountRequest", expectedError: errMethodNotFound},
+		{method: "requestAddresses", expectedError: errMethodNotFound},
+		{method: "getBlock", params: `["abcd"]`, expectedError: errInvalidParams},
+		{method: "getBlock", params: `{"noSuchField": 1}`, expectedError: errInvalidParams},
+		{method: "submitBlock", params: `{}`, expectedError: errInvalidParams},
+	}
+	for _, test := range tests {
+		message, err := parseRequest(test.method, json.RawMessage(test.params))
+		if test.expectedError != nil {
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("%s %s: expected error %s, but got: %v", test.method, test.params, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %s", test.method, test.params, err)
+			continue
+		}
+		if message.Command().String() == "" {
+			t.Errorf("%s %s: got a message with an unknown command", test.method, test.params)
+		}
+	}
+
+	message, err := parseRequest("getBlock", json.RawMessage(`{"hash": "abcd", "includeTransactionVerboseData": true}`))
+	if err != nil {
+		t.Fatalf("parseRequest: %s", err)
+	}
+	getBlockRequest, ok := message.(*appmessage.GetBlockRequestMessage)
+	if !ok {
+		t.Fatalf("Expected a GetBlockRequestMessage, but got %T", message)
+	}
+	if getBlockRequest.Hash != "abcd" || !getBlockRequest.IncludeTransactionVerboseData {
+		t.Fatalf("Unexpected params: %+v", getBlockRequest)
+	}
+}
+
+func TestConvertOutgoingMessage(t *testing.T) {
+	message, err := convertOutgoingMessage(appmessage.NewGetBlockCountResponseMessage(&externalapi.SyncInfo{BlockCount: 3, HeaderCount: 5}))
+	if err != nil {
+		t.Fatalf("convertOutgoingMessage: %s", err)
+	}
+	if message.name != "getBlockCountResponse" || !message.isResponse() || message.rpcError != nil {
+		t.Fatalf("Unexpected outgoing message: %+v", message)
+	}
+	expectedJSON := `{"blockCount":"3","headerCount":"5"}`
+	if !jsonEqual(t, message.json, expectedJSON) {
+		t.Fatalf("Expected %s, but got %s", expectedJSON, message.json)
+	}
+
+	errorResponse := &appmessage.GetBlockCountResponseMessage{Error: &appmessage.RPCError{
+		Message:    "rate limited",
+		Code:       appmessage.RPCErrorCodeRateLimited,
+		RetryAfter: 1500000000,
+	}}
+	message, err = convertOutgoingMessage(errorResponse)
+	if err != nil {
+		t.Fatalf("convertOutgoingMessage: %s", err)
+	}
+	if message.rpcError == nil {
+		t.Fatalf("Expected the error to be extracted from the response")
+	}
+	response := newRPCErrorResponse(json.RawMessage("1"), message.rpcError)
+	if response.Error.Code != errorCodeRateLimited || response.Error.Message != "rate limited" {
+		t.Fatalf("Unexpected error response: %+v", response.Error)
+	}
+	expectedData := map[string]uint64{"retryAfterMilliseconds": 1500}
+	data, ok := response.Error.Data.(map[string]uint64)
+	if !ok || data["retryAfterMilliseconds"] != expectedData["retryAfterMilliseconds"] {
+		t.Fatalf("Expected error data %v, but got %v", expectedData, response.Error.Data)
+	}
+
+	message, err = convertOutgoingMessage(appmessage.NewVirtualDaaScoreChangedNotificationMessage(7))
+	if err != nil {
+		t.Fatalf("convertOutgoingMessage: %s", err)
+	}
+	if message.name != "virtualDaaScoreChangedNotification" || !message.isNotification() {
+		t.Fatalf("Unexpected outgoing message: %+v", message)
+	}
+}
+
+func jsonEqual(t *testing.T, actual json.RawMessage, expected string) bool {
+	var actualValue, expectedValue interface{}
+	err := json.Unmarshal(actual, &actualValue)
+	if err != nil {
+		t.Fatalf("Error unmarshalling %s: %s", actual, err)
+	}
+	err = json.Unmarshal([]byte(expected), &expectedValue)
+	if err != nil {
+		t.Fatalf("Error unmarshalling %s: %s", expected, err)
+	}
+	actualJSON, _ := json.Marshal(actualValue)
+	expectedJSON, _ := json.Marshal(expectedValue)
+	return string(actualJSON) == string(expectedJSON)
+}
@@ -0,0 +1,335 @@
+package jsonrpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/util/panics"
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketPath is the path on which the server accepts WebSocket
+// connections. Every other path accepts HTTP POST requests.
+const webSocketPath = "/ws"
+
+// anyOrigin allows web pages from any origin to call the server
+const anyOrigin = "*"
+
+type jsonRPCServer struct {
+	listeningAddresses []string
+	tlsConfig          *tls.Config
+	authenticator      *grpcserver.RPCAuthenticator
+	allowedOrigins     map[string]struct{}
+	maxWebSockets      int
+	onConnectedHandler server.OnConnectedHandler
+
+	httpServers []*http.Server
+
+	webSockets     map[*connection]struct{}
+	webSocketsLock sync.Mutex
+}
+
+// NewJSONRPCServer creates a new JSON-RPC 2.0 server, which serves the RPC
+// over HTTP POST requests and over WebSocket. Only WebSocket clients may
+// subscribe to notifications. The TLS configuration and the credentials are
+// the same as NewRPCServer's. allowedOrigins are the origins of the web pages
+// that may call the server, or "*" to allow any.
+func NewJSONRPCServer(listeningAddresses []string, tlsConfig *tls.Config,
+	adminCredentials *grpcserver.RPCCredentials, limitedCredentials *grpcserver.RPCCredentials,
+	allowedOrigins []string, maxWebSockets int) server.Server {
+
+	allowedOriginsSet := make(map[string]struct{}, len(allowedOrigins))
+	for _, allowedOrigin := range allowedOrigins {
+		allowedOriginsSet[strings.TrimSuffix(allowedOrigin, "/")] = struct{}{}
+	}
+	return &jsonRPCServer{
+		listeningAddresses: listeningAddresses,
+		tlsConfig:          tlsConfig,
+		authenticator:      grpcserver.NewRPCAuthenticator(adminCredentials, limitedCredentials),
+		allowedOrigins:     allowedOriginsSet,
+		maxWebSockets:      maxWebSockets,
+		webSockets:         make(map[*connection]struct{}),
+	}
+}
+
+func (s *jsonRPCServer) Start() error {
+	if s.onConnectedHandler == nil {
+		return errors.New("onConnectedHandler is nil")
+	}
+
+	for _, listenAddress := range s.listeningAddresses {
+		err := s.listenOn(listenAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *jsonRPCServer) listenOn(listenAddress string) error {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "JSON-RPC error listening on %s", listenAddress)
+	}
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		s.serveHTTP(listenAddress, writer, request)
+	})
+	mux.HandleFunc(webSocketPath, func(writer http.ResponseWriter, request *http.Request) {
+		s.serveWebSocket(listenAddress, writer, request)
+	})
+	httpServer := &http.Server{Handler: mux}
+	s.httpServers = append(s.httpServers, httpServer)
+
+	spawn("jsonRPCServer.listenOn-Serve", func() {
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panics.Exit(log, fmt.Sprintf("error serving JSON-RPC on %s: %+v", listenAddress, err))
+		}
+	})
+
+	log.Infof("JSON-RPC Server listening on %s", listener.Addr())
+	return nil
+}
+
+func (s *jsonRPCServer) Stop() error {
+	const stopTimeout = 2 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	for _, httpServer := range s.httpServers {
+		err := httpServer.Shutdown(ctx)
+		if err != nil {
+			log.Warnf("Could not gracefully stop the JSON-RPC server: %s", err)
+			_ = httpServer.Close()
+		}
+	}
+
+	// WebSocket connections are hijacked from the HTTP servers,
+	// so they have to be closed separately
+	s.webSocketsLock.Lock()
+	webSockets := make([]*connection, 0, len(s.webSockets))
+	for webSocket := range s.webSockets {
+		webSockets = append(webSockets, webSocket)
+	}
+	s.webSocketsLock.Unlock()
+	for _, webSocket := range webSockets {
+		webSocket.Disconnect()
+	}
+	return nil
+}
+
+// SetOnConnectedHandler sets the client connected handler
+// function for the server
+func (s *jsonRPCServer) SetOnConnectedHandler(onConnectedHandler server.OnConnectedHandler) {
+	s.onConnectedHandler = onConnectedHandler
+}
+
+// serveHTTP handles a JSON-RPC request, or a batch of them, sent over HTTP POST
+func (s *jsonRPCServer) serveHTTP(listenAddress string, writer http.ResponseWriter, request *http.Request) {
+	if !s.checkOrigin(writer, request) {
+		return
+	}
+	if request.Method == http.MethodOptions {
+		// A CORS preflight request
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		http.Error(writer, "JSON-RPC requests must be sent with POST", http.StatusMethodNotAllowed)
+		return
+	}
+	authentication, ok := s.authenticate(writer, request)
+	if !ok {
+		return
+	}
+	address, err := net.ResolveTCPAddr("tcp", request.RemoteAddr)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, grpcserver.RPCMaxMessageSize))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Every HTTP request is handled over a connection of its own, which
+	// emits exactly once: the response to the request or to the batch
+	emitted := make(chan interface{}, 1)
+	emit := func(payload interface{}) {
+		emitted <- payload
+	}
+	connection := newConnection(address, listenAddress, authentication, false, emit, nil)
+	defer connection.Disconnect()
+	err = s.onConnectedHandler(connection)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	connection.handle(payload)
+
+	select {
+	case responsePayload := <-emitted:
+		if responsePayload == nil {
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(writer).Encode(responsePayload)
+		if err != nil {
+			log.Debugf("Error writing the JSON-RPC response to %s: %s", connection, err)
+		}
+	case <-connection.stopChan:
+		http.Error(writer, "the connection was closed by the server", http.StatusServiceUnavailable)
+	case <-request.Context().Done():
+	}
+}
+
+// serveWebSocket upgrades the given request to a WebSocket, and handles the
+// JSON-RPC requests sent over it until it's closed
+func (s *jsonRPCServer) serveWebSocket(listenAddress string, writer http.ResponseWriter, request *http.Request) {
+	if !s.checkOrigin(writer, request) {
+		return
+	}
+	authentication, ok := s.authenticate(writer, request)
+	if !ok {
+		return
+	}
+	address, err := net.ResolveTCPAddr("tcp", request.RemoteAddr)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if s.webSocketCount() >= s.maxWebSockets {
+		log.Warnf("Rejected a WebSocket connection from %s: reached the maximum of %d WebSocket connections",
+			address, s.maxWebSockets)
+		http.Error(writer, "too many WebSocket connections", http.StatusServiceUnavailable)
+		return
+	}
+
+	webSocketServer := websocket.Server{
+		// The origin is already checked above
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(webSocket *websocket.Conn) {
+			s.handleWebSocket(webSocket, address, listenAddress, authentication)
+		},
+	}
+	webSocketServer.ServeHTTP(writer, request)
+}
+
+func (s *jsonRPCServer) handleWebSocket(webSocket *websocket.Conn, address *net.TCPAddr,
+	listenAddress string, authentication server.RPCAuthentication) {
+
+	webSocket.MaxPayloadBytes = grpcserver.RPCMaxMessageSize
+
+	var connection *connection
+	var writeLock sync.Mutex
+	emit := func(payload interface{}) {
+		if payload == nil {
+			return
+		}
+		writeLock.Lock()
+		defer writeLock.Unlock()
+
+		err := websocket.JSON.Send(webSocket, payload)
+		if err != nil {
+			log.Debugf("Error writing to the WebSocket of %s: %s", connection, err)
+			connection.Disconnect()
+		}
+	}
+	closeTransport := func() {
+		_ = webSocket.Close()
+	}
+	connection = newConnection(address, listenAddress, authentication, true, emit, closeTransport)
+	defer connection.Disconnect()
+
+	s.webSocketsLock.Lock()
+	s.webSockets[connection] = struct{}{}
+	s.webSocketsLock.Unlock()
+	defer func() {
+		s.webSocketsLock.Lock()
+		delete(s.webSockets, connection)
+		s.webSocketsLock.Unlock()
+	}()
+
+	err := s.onConnectedHandler(connection)
+	if err != nil {
+		log.Warnf("Error handling the WebSocket connection from %s: %s", connection, err)
+		return
+	}
+	log.Debugf("Incoming WebSocket connection from %s", connection)
+
+	for {
+		var payload []byte
+		err := websocket.Message.Receive(webSocket, &payload)
+		if err != nil {
+			log.Debugf("Stopped receiving from the WebSocket of %s: %s", connection, err)
+			return
+		}
+		connection.handle(payload)
+	}
+}
+
+func (s *jsonRPCServer) webSocketCount() int {
+	s.webSocketsLock.Lock()
+	defer s.webSocketsLock.Unlock()
+
+	return len(s.webSockets)
+}
+
+// checkOrigin rejects requests from web pages of origins that were not
+// allowed, and lets browsers know which origins are. Requests without an
+// origin don't come from web pages, and are always allowed.
+func (s *jsonRPCServer) checkOrigin(writer http.ResponseWriter, request *http.Request) bool {
+	origin := request.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	_, isAllowed := s.allowedOrigins[origin]
+	_, isAnyOriginAllowed := s.allowedOrigins[anyOrigin]
+	if !isAllowed && !isAnyOriginAllowed {
+		log.Warnf("Rejected a JSON-RPC request from %s: origin %s is not allowed", request.RemoteAddr, origin)
+		http.Error(writer, "origin not allowed", http.StatusForbidden)
+		return false
+	}
+
+	header := writer.Header()
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Methods", http.MethodPost)
+	header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	header.Add("Vary", "Origin")
+	return true
+}
+
+// authenticate checks the credentials in the Authorization header of the
+// given request. If they're missing or invalid, the request is rejected.
+func (s *jsonRPCServer) authenticate(writer http.ResponseWriter,
+	request *http.Request) (server.RPCAuthentication, bool) {
+
+	authentication, err := s.authenticator.Authenticate(request.Header.Values("Authorization"))
+	if err != nil {
+		log.Warnf("Rejected a JSON-RPC request from %s: %s", request.RemoteAddr, err)
+		writer.Header().Set("WWW-Authenticate", `Basic realm="kaspad"`)
+		http.Error(writer, err.Error(), http.StatusUnauthorized)
+		return 0, false
+	}
+	return authentication, true
+}
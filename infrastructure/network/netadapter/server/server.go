@@ -44,3 +44,35 @@ type Connection interface {
 	SetOnInvalidMessageHandler(onInvalidMessageHandler OnInvalidMessageHandler)
 	Address() *net.TCPAddr
 }
+
+// RPCAuthentication describes how an RPC connection was authenticated
+type RPCAuthentication byte
+
+// RPCAuthentication constants
+const (
+	// RPCAuthenticationNone means that the RPC server doesn't require authentication
+	RPCAuthenticationNone RPCAuthentication = iota
+
+	// RPCAuthenticationAdmin means that the connection authenticated with admin credentials
+	RPCAuthenticationAdmin
+
+	// RPCAuthenticationLimited means that the connection authenticated with read-only credentials
+	RPCAuthenticationLimited
+)
+
+var rpcAuthenticationToString = map[RPCAuthentication]string{
+	RPCAuthenticationNone:    "none",
+	RPCAuthenticationAdmin:   "admin",
+	RPCAuthenticationLimited: "limited",
+}
+
+func (authentication RPCAuthentication) String() string {
+	return rpcAuthenticationToString[authentication]
+}
+
+// RPCConnection represents an RPC server connection.
+type RPCConnection interface {
+	Connection
+	Authentication() RPCAuthentication
+	ListenAddress() string
+}
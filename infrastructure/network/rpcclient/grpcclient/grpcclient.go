@@ -2,12 +2,14 @@ package grpcclient
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding/gzip"
 	"io"
 	"time"
@@ -26,13 +28,38 @@ type GRPCClient struct {
 	onDisconnectedHandler OnDisconnectedHandler
 }
 
+// ConnectOptions are the options with which a GRPCClient connects to an RPC server
+type ConnectOptions struct {
+	// TLS enables TLS. The server's certificate is verified against the
+	// system's certificate authorities, unless TLSCertificateFile is set
+	TLS bool
+
+	// TLSCertificateFile is a file containing the server's certificate or
+	// the certificate of the authority that signed it. Setting it implies TLS
+	TLSCertificateFile string
+
+	// Credentials are the credentials to authenticate with, if the server
+	// requires authentication
+	Credentials *grpcserver.RPCCredentials
+}
+
 // Connect connects to the RPC server with the given address
 func Connect(address string) (*GRPCClient, error) {
+	return ConnectWithOptions(address, &ConnectOptions{})
+}
+
+// ConnectWithOptions connects to the RPC server with the given address, using the given options
+func ConnectWithOptions(address string, options *ConnectOptions) (*GRPCClient, error) {
 	const dialTimeout = 5 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
-	gRPCConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	dialOptions, err := options.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	dialOptions = append(dialOptions, grpc.WithBlock())
+	gRPCConnection, err := grpc.DialContext(ctx, address, dialOptions...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error connecting to %s", address)
 	}
@@ -43,9 +70,72 @@ func Connect(address string) (*GRPCClient, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting client stream for %s", address)
 	}
+	if options.Credentials.IsSet() {
+		err := waitForAuthentication(stream)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error authenticating to %s", address)
+		}
+	}
 	return &GRPCClient{stream: stream}, nil
 }
 
+// waitForAuthentication waits for the server to accept or reject the
+// client's credentials. The RPC server sends its header right after it
+// authenticates a client, so that authentication errors surface here
+// rather than with the first request.
+func waitForAuthentication(stream protowire.RPC_MessageStreamClient) error {
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	if header.Len() == 0 {
+		// The stream was terminated without a header. Its status
+		// is only available by receiving from it
+		return stream.RecvMsg(&protowire.KaspadMessage{})
+	}
+	return nil
+}
+
+func (options *ConnectOptions) dialOptions() ([]grpc.DialOption, error) {
+	var dialOptions []grpc.DialOption
+	switch {
+	case options.TLSCertificateFile != "":
+		transportCredentials, err := credentials.NewClientTLSFromFile(options.TLSCertificateFile, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "error loading the RPC server's certificate")
+		}
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(transportCredentials))
+	case options.TLS:
+		transportCredentials := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(transportCredentials))
+	default:
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+
+	if options.Credentials.IsSet() {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(&rpcCredentials{
+			authorization: options.Credentials.Authorization(),
+			requireTLS:    options.TLS || options.TLSCertificateFile != "",
+		}))
+	}
+	return dialOptions, nil
+}
+
+// rpcCredentials implements credentials.PerRPCCredentials. It attaches
+// the authorization metadata to every stream opened to the RPC server
+type rpcCredentials struct {
+	authorization string
+	requireTLS    bool
+}
+
+func (c *rpcCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{grpcserver.RPCAuthorizationMetadataKey: c.authorization}, nil
+}
+
+func (c *rpcCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
+
 // Disconnect disconnects from the RPC server
 func (c *GRPCClient) Disconnect() error {
 	return c.stream.CloseSend()
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// Ban sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) Ban(ip string, durationSeconds uint64) (*appmessage.BanResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewBanRequestMessage(ip, durationSeconds))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdBanResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	banResponse := response.(*appmessage.BanResponseMessage)
+	if banResponse.Error != nil {
+		return nil, c.convertRPCError(banResponse.Error)
+	}
+	return banResponse, nil
+}
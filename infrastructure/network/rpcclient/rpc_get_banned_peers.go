@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetBannedPeers sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBannedPeers() (*appmessage.GetBannedPeersResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBannedPeersRequestMessage())
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetBannedPeersResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getBannedPeersResponse := response.(*appmessage.GetBannedPeersResponseMessage)
+	if getBannedPeersResponse.Error != nil {
+		return nil, c.convertRPCError(getBannedPeersResponse.Error)
+	}
+	return getBannedPeersResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetUTXOHistoryByAddresses sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetUTXOHistoryByAddresses(addresses []string) (*appmessage.GetUTXOHistoryByAddressesResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetUTXOHistoryByAddressesRequestMessage(addresses))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetUTXOHistoryByAddressesResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getUTXOHistoryByAddressesResponse := response.(*appmessage.GetUTXOHistoryByAddressesResponseMessage)
+	if getUTXOHistoryByAddressesResponse.Error != nil {
+		return nil, c.convertRPCError(getUTXOHistoryByAddressesResponse.Error)
+	}
+	return getUTXOHistoryByAddressesResponse, nil
+}
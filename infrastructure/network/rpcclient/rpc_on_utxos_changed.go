@@ -4,10 +4,13 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
+	"sync/atomic"
 )
 
 // RegisterForUTXOsChangedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
-// Additionally, it starts listening for the appropriate notification using the given handler function
+// Additionally, it starts listening for the appropriate notification using the given handler function.
+// Calling it again on the same connection adds the given addresses to the ones already listened to,
+// and replaces the handler function.
 func (c *RPCClient) RegisterForUTXOsChangedNotifications(addresses []string,
 	onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
 
@@ -23,9 +26,17 @@ func (c *RPCClient) RegisterForUTXOsChangedNotifications(addresses []string,
 	if notifyUTXOsChangedResponse.Error != nil {
 		return c.convertRPCError(notifyUTXOsChangedResponse.Error)
 	}
+	c.setOnUTXOsChangedHandler(onUTXOsChanged)
+
+	// Only one listener may dequeue from the notification route, otherwise
+	// notifications might be handled out of order
+	rpcRouter := c.rpcRouter
+	if !atomic.CompareAndSwapUint32(&rpcRouter.isUTXOsChangedListenerRunning, 0, 1) {
+		return nil
+	}
 	spawn("RegisterForUTXOsChangedNotifications", func() {
 		for {
-			notification, err := c.route(appmessage.CmdUTXOsChangedNotificationMessage).Dequeue()
+			notification, err := rpcRouter.routes[appmessage.CmdUTXOsChangedNotificationMessage].Dequeue()
 			if err != nil {
 				if errors.Is(err, routerpkg.ErrRouteClosed) {
 					break
@@ -33,8 +44,20 @@ func (c *RPCClient) RegisterForUTXOsChangedNotifications(addresses []string,
 				panic(err)
 			}
 			UTXOsChangedNotification := notification.(*appmessage.UTXOsChangedNotificationMessage)
-			onUTXOsChanged(UTXOsChangedNotification)
+			c.onUTXOsChangedHandler()(UTXOsChangedNotification)
 		}
 	})
 	return nil
 }
+
+func (c *RPCClient) setOnUTXOsChangedHandler(onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) {
+	c.onUTXOsChangedHandlerLock.Lock()
+	defer c.onUTXOsChangedHandlerLock.Unlock()
+	c.onUTXOsChanged = onUTXOsChanged
+}
+
+func (c *RPCClient) onUTXOsChangedHandler() func(notification *appmessage.UTXOsChangedNotificationMessage) {
+	c.onUTXOsChangedHandlerLock.Lock()
+	defer c.onUTXOsChangedHandlerLock.Unlock()
+	return c.onUTXOsChanged
+}
@@ -1,12 +1,16 @@
 package rpcclient
 
 import (
+	"fmt"
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 	"github.com/kaspanet/kaspad/util/panics"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -18,19 +22,32 @@ type RPCClient struct {
 	*grpcclient.GRPCClient
 
 	rpcAddress     string
+	connectOptions *grpcclient.ConnectOptions
 	rpcRouter      *rpcRouter
 	isConnected    uint32
 	isClosed       uint32
 	isReconnecting uint32
 
+	onReconnectedHandler func()
+
+	onUTXOsChanged            func(notification *appmessage.UTXOsChangedNotificationMessage)
+	onUTXOsChangedHandlerLock sync.Mutex
+
 	timeout time.Duration
 }
 
 // NewRPCClient creates a new RPC client
 func NewRPCClient(rpcAddress string) (*RPCClient, error) {
+	return NewRPCClientWithOptions(rpcAddress, &grpcclient.ConnectOptions{})
+}
+
+// NewRPCClientWithOptions creates a new RPC client that connects
+// with the given options, for example over TLS and with credentials
+func NewRPCClientWithOptions(rpcAddress string, connectOptions *grpcclient.ConnectOptions) (*RPCClient, error) {
 	rpcClient := &RPCClient{
-		rpcAddress: rpcAddress,
-		timeout:    defaultTimeout,
+		rpcAddress:     rpcAddress,
+		connectOptions: connectOptions,
+		timeout:        defaultTimeout,
 	}
 	err := rpcClient.connect()
 	if err != nil {
@@ -40,7 +57,7 @@ func NewRPCClient(rpcAddress string) (*RPCClient, error) {
 }
 
 func (c *RPCClient) connect() error {
-	rpcClient, err := grpcclient.Connect(c.rpcAddress)
+	rpcClient, err := grpcclient.ConnectWithOptions(c.rpcAddress, c.connectOptions)
 	if err != nil {
 		return errors.Wrapf(err, "error connecting to address %s", c.rpcAddress)
 	}
@@ -100,6 +117,9 @@ func (c *RPCClient) Reconnect() error {
 	for {
 		err := c.connect()
 		if err == nil {
+			if c.onReconnectedHandler != nil {
+				spawn("RPCClient.Reconnect-onReconnectedHandler", c.onReconnectedHandler)
+			}
 			return nil
 		}
 		log.Warnf("Could not automatically reconnect to %s: %s", c.rpcAddress, err)
@@ -121,10 +141,23 @@ func (c *RPCClient) handleClientDisconnected() {
 }
 
 func (c *RPCClient) handleClientError(err error) {
+	if status.Code(err) == codes.Unauthenticated {
+		// Reconnecting with the same credentials is bound to fail as well
+		log.Errorf("The RPC server at %s did not accept the client's credentials: %s", c.rpcAddress, err)
+		atomic.StoreUint32(&c.isConnected, 0)
+		return
+	}
 	log.Warnf("Received error from client: %s", err)
 	c.handleClientDisconnected()
 }
 
+// SetOnReconnectedHandler sets the handler that is called whenever the client
+// reconnects to the RPC server. Note that notification registrations don't survive
+// a reconnection, so this handler is the place to renew them.
+func (c *RPCClient) SetOnReconnectedHandler(onReconnectedHandler func()) {
+	c.onReconnectedHandler = onReconnectedHandler
+}
+
 // SetTimeout sets the timeout by which to wait for RPC responses
 func (c *RPCClient) SetTimeout(timeout time.Duration) {
 	c.timeout = timeout
@@ -152,8 +185,24 @@ func (c *RPCClient) route(command appmessage.MessageCommand) *routerpkg.Route {
 // ErrRPC is an error in the RPC protocol
 var ErrRPC = errors.New("rpc error")
 
+// RPCError is an error returned by the RPC server. It wraps ErrRPC, and
+// exposes the error's code, e.g. for clients that want to back off when
+// they are rate limited. Use errors.As to extract it from returned errors.
+type RPCError struct {
+	*appmessage.RPCError
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Message, ErrRPC)
+}
+
+// Unwrap returns ErrRPC, so that errors.Is(err, ErrRPC) holds for every RPCError
+func (e *RPCError) Unwrap() error {
+	return ErrRPC
+}
+
 func (c *RPCClient) convertRPCError(rpcError *appmessage.RPCError) error {
-	return errors.Wrap(ErrRPC, rpcError.Message)
+	return errors.WithStack(&RPCError{RPCError: rpcError})
 }
 
 // SetLogger uses a specified Logger to output package logging info
@@ -8,6 +8,8 @@ import (
 type rpcRouter struct {
 	router *routerpkg.Router
 	routes map[appmessage.MessageCommand]*routerpkg.Route
+
+	isUTXOsChangedListenerRunning uint32
 }
 
 func buildRPCRouter() (*rpcRouter, error) {
@@ -40,6 +40,8 @@ func checkSyncRate(syncerClient, syncedClient *rpc.Client) error {
 			return errors.Errorf("SYNCED is not synced in the expected rate")
 		}
 	}
-	log.Infof("IBD took approximately %s", time.Since(start))
+	ibdDuration := time.Since(start)
+	log.Infof("IBD took approximately %s (%.2f blocks per second, using %s)", ibdDuration,
+		float64(syncerBlockCount)/ibdDuration.Seconds(), activeConfig().DbType)
 	return nil
 }
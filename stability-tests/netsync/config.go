@@ -27,6 +27,7 @@ type configFlags struct {
 	MiningDataDirectory string `long:"mining-data-dir" description:"Mining Data directory (will generate a random one if omitted)"`
 	SyncerDataDirectory string `long:"syncer-data-dir" description:"Syncer Data directory (will generate a random one if omitted)"`
 	SynceeDataDirectory string `long:"syncee-data-dir" description:"Syncee Data directory (will generate a random one if omitted)"`
+	DbType              string `long:"dbtype" description:"The database backend of the syncer and the syncee {leveldb, logdb}" default:"leveldb"`
 	config.NetworkFlags
 }
 
@@ -38,6 +38,7 @@ func startNode(name string, rpcAddress, listen, connect, profilePort, dataDir st
 		"--listen", listen,
 		"--profile", profilePort,
 		"--loglevel", "debug",
+		"--dbtype", activeConfig().DbType,
 	}
 	if connect != "" {
 		args = append(args, "--connect", connect)
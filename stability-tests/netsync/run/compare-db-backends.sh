@@ -0,0 +1,23 @@
+#!/bin/bash
+set -eo pipefail
+
+# Syncs every DAG in the given directory (../dags-fast by default) once with
+# each database backend, and prints the IBD throughput of every run
+DAGS_DIR="${1:-../dags-fast}"
+DB_TYPES=("leveldb" "logdb")
+mapfile -t DAGS < <( ls $DAGS_DIR)
+
+RESULTS=()
+for dagArchive in "${DAGS[@]}"
+do
+  JSON_FILE=$DAGS_DIR/$dagArchive
+  for dbType in "${DB_TYPES[@]}"
+  do
+    OUTPUT=$(netsync --simnet --dag-file $JSON_FILE --dbtype=$dbType --profile=7000 | tee /dev/stderr)
+    RESULTS+=("$dagArchive $dbType: $(echo "$OUTPUT" | grep -o 'IBD took.*')")
+    rm -rf /tmp/STABILITY_TEMP_DIR_*
+  done
+done
+
+printf '%s\n' "${RESULTS[@]}"
+exit 0
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestAddressIndex(t *testing.T) {
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+		addressIndex:            true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine enough blocks for the first coinbase outputs to mature
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	// Spend the oldest UTXO, which was certainly accepted by the selected parent chain
+	spentEntry := utxosByAddressesResponse.Entries[0]
+	for _, entry := range utxosByAddressesResponse.Entries {
+		if entry.UTXOEntry.BlockDAAScore < spentEntry.UTXOEntry.BlockDAAScore {
+			spentEntry = entry
+		}
+	}
+	rpcTransaction := buildTransactionForUTXOIndexTest(t, spentEntry)
+	_, err = kaspad.rpcClient.SubmitTransaction(rpcTransaction)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	transaction, err := appmessage.RPCTransactionToDomainTransaction(rpcTransaction)
+	if err != nil {
+		t.Fatalf("Error converting transaction: %s", err)
+	}
+	transactionID := consensushashing.TransactionID(transaction).String()
+
+	// Mine a block to include the transaction, and another one to add the
+	// block that accepts it to the selected parent chain
+	includingBlock := mineNextBlock(t, kaspad)
+	mineNextBlock(t, kaspad)
+
+	allEntriesResponse, err := kaspad.rpcClient.GetTransactionsByAddresses([]string{miningAddress1}, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %s", err)
+	}
+
+	var transactionEntry *appmessage.TransactionsByAddressesEntry
+	for i, entry := range allEntriesResponse.Entries {
+		if i > 0 && entry.AcceptingBlockBlueScore < allEntriesResponse.Entries[i-1].AcceptingBlockBlueScore {
+			t.Fatalf("Transactions are not ordered by their accepting block blue score")
+		}
+		if entry.TransactionID == transactionID {
+			transactionEntry = entry
+		}
+	}
+	if transactionEntry == nil {
+		t.Fatalf("Missing entry for transaction %s", transactionID)
+	}
+	if transactionEntry.IncludingBlockHash != consensushashing.BlockHash(includingBlock).String() {
+		t.Fatalf("Unexpected including block. Want: %s, got: %s",
+			consensushashing.BlockHash(includingBlock), transactionEntry.IncludingBlockHash)
+	}
+	if transactionEntry.Sent != spentEntry.UTXOEntry.Amount {
+		t.Fatalf("Unexpected sent amount. Want: %d, got: %d", spentEntry.UTXOEntry.Amount, transactionEntry.Sent)
+	}
+	if transactionEntry.Received != spentEntry.UTXOEntry.Amount-1000 {
+		t.Fatalf("Unexpected received amount. Want: %d, got: %d",
+			spentEntry.UTXOEntry.Amount-1000, transactionEntry.Received)
+	}
+
+	// Paging through the entries should give the same result as getting all of them at once
+	const pageSize = 7
+	var pagedEntries []*appmessage.TransactionsByAddressesEntry
+	var startAfter *appmessage.TransactionsByAddressesCursor
+	for {
+		pageResponse, err := kaspad.rpcClient.GetTransactionsByAddresses([]string{miningAddress1}, startAfter, pageSize)
+		if err != nil {
+			t.Fatalf("Failed to get transactions: %s", err)
+		}
+		pagedEntries = append(pagedEntries, pageResponse.Entries...)
+		if len(pageResponse.Entries) < pageSize {
+			break
+		}
+		lastEntry := pageResponse.Entries[len(pageResponse.Entries)-1]
+		startAfter = &appmessage.TransactionsByAddressesCursor{
+			AcceptingBlockBlueScore: lastEntry.AcceptingBlockBlueScore,
+			TransactionID:           lastEntry.TransactionID,
+		}
+	}
+	if !reflect.DeepEqual(pagedEntries, allEntriesResponse.Entries) {
+		t.Fatalf("Paged entries are different from the entries that were got at once")
+	}
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBan(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	const banDurationSeconds = 60
+	_, err := harness.rpcClient.Ban("10.0.0.0/8", banDurationSeconds)
+	if err != nil {
+		t.Fatalf("Ban: %s", err)
+	}
+	_, err = harness.rpcClient.Ban("1.2.3.4", 0)
+	if err != nil {
+		t.Fatalf("Ban: %s", err)
+	}
+	_, err = harness.rpcClient.Ban("not an IP", 0)
+	if err == nil {
+		t.Fatalf("Expected Ban of an invalid IP to fail")
+	}
+	_, err = harness.rpcClient.Ban("5.6.7.8", math.MaxInt64)
+	if err == nil {
+		t.Fatalf("Expected Ban with a duration that overflows to fail")
+	}
+	const maxBanDurationSeconds = math.MaxInt64 / uint64(time.Second)
+	_, err = harness.rpcClient.Ban("5.6.7.8", maxBanDurationSeconds+1)
+	if err == nil {
+		t.Fatalf("Expected Ban with a duration above the maximum to fail")
+	}
+
+	response, err := harness.rpcClient.GetBannedPeers()
+	if err != nil {
+		t.Fatalf("GetBannedPeers: %s", err)
+	}
+	if len(response.BannedPeers) != 2 {
+		t.Fatalf("Unexpected amount of banned peers. Want: 2, got: %d", len(response.BannedPeers))
+	}
+	subnetBan, hostBan := response.BannedPeers[0], response.BannedPeers[1]
+	if subnetBan.IP != "10.0.0.0/8" {
+		t.Fatalf("Unexpected banned subnet. Want: 10.0.0.0/8, got: %s", subnetBan.IP)
+	}
+	subnetBanDuration := time.Duration(subnetBan.ExpiresAt-subnetBan.CreatedAt) * time.Millisecond
+	if subnetBanDuration != banDurationSeconds*time.Second {
+		t.Fatalf("Unexpected ban duration. Want: %s, got: %s", banDurationSeconds*time.Second, subnetBanDuration)
+	}
+	if subnetBan.Reason == "" {
+		t.Fatalf("Expected the ban to have a reason")
+	}
+	if hostBan.IP != "1.2.3.4" {
+		t.Fatalf("Unexpected banned IP. Want: 1.2.3.4, got: %s", hostBan.IP)
+	}
+	if hostBan.ExpiresAt <= hostBan.CreatedAt {
+		t.Fatalf("Expected a ban with no duration to use the default ban duration")
+	}
+
+	_, err = harness.rpcClient.Unban("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("Unban: %s", err)
+	}
+	response, err = harness.rpcClient.GetBannedPeers()
+	if err != nil {
+		t.Fatalf("GetBannedPeers: %s", err)
+	}
+	if len(response.BannedPeers) != 1 || response.BannedPeers[0].IP != "1.2.3.4" {
+		t.Fatalf("Unexpected banned peers after Unban: %v", response.BannedPeers)
+	}
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"io/ioutil"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,6 +19,8 @@ const (
 	rpcAddress2 = "127.0.0.1:12346"
 	rpcAddress3 = "127.0.0.1:12347"
 
+	jsonRPCAddress1 = "127.0.0.1:12355"
+
 	miningAddress1           = "kaspasim:qqqqnc0pxg7qw3qkc7l6sge8kfhsvvyt7mkw8uamtndqup27ftnd6c769gn66"
 	miningAddress1PrivateKey = "0d81045b0deb2af36a25403c2154c87aa82d89dd337b575bae27ce7f5de53cee"
 
@@ -36,6 +39,27 @@ func setConfig(t *testing.T, harness *appHarness) {
 	harness.config.Listeners = []string{harness.p2pAddress}
 	harness.config.RPCListeners = []string{harness.rpcAddress}
 	harness.config.UTXOIndex = harness.utxoIndex
+	harness.config.TXIndex = harness.txIndex
+	harness.config.AddressIndex = harness.addressIndex
+	harness.config.RPCTLS = harness.rpcTLS
+	harness.config.RPCCert = filepath.Join(harness.config.AppDir, "rpc.cert")
+	harness.config.RPCKey = filepath.Join(harness.config.AppDir, "rpc.key")
+	if harness.rpcAdminCredentials != nil {
+		harness.config.RPCUser = harness.rpcAdminCredentials.User
+		harness.config.RPCPass = harness.rpcAdminCredentials.Password
+		harness.config.RPCAuthToken = harness.rpcAdminCredentials.Token
+	}
+	if harness.rpcLimitedCredentials != nil {
+		harness.config.RPCLimitUser = harness.rpcLimitedCredentials.User
+		harness.config.RPCLimitPass = harness.rpcLimitedCredentials.Password
+		harness.config.RPCLimitAuthToken = harness.rpcLimitedCredentials.Token
+	}
+	if harness.jsonRPCAddress != "" {
+		harness.config.JSONRPCListeners = []string{harness.jsonRPCAddress}
+		harness.config.JSONRPCAllowedOrigins = harness.jsonRPCAllowedOrigins
+	}
+	harness.config.RPCDenyRules = harness.rpcDenyRules
+	harness.config.RPCRateLimits = harness.rpcRateLimits
 
 	if harness.overrideDAGParams != nil {
 		harness.config.ActiveNetParams = harness.overrideDAGParams
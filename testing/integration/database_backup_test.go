@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+)
+
+func TestBackupDatabase(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	const blockCount = 10
+	for i := 0; i < blockCount; i++ {
+		mineNextBlock(t, harness)
+	}
+
+	selectedTipResponse, err := harness.rpcClient.GetSelectedTipHash()
+	if err != nil {
+		t.Fatalf("GetSelectedTipHash: %s", err)
+	}
+
+	targetDirectory := randomDirectory(t)
+	response, err := harness.rpcClient.BackupDatabase(targetDirectory)
+	if err != nil {
+		t.Fatalf("BackupDatabase: %s", err)
+	}
+	expectedDatabasePath := filepath.Join(targetDirectory, harness.config.NetParams().Name, "data")
+	if response.DatabasePath != expectedDatabasePath {
+		t.Fatalf("Unexpected database path. Want: %s, got: %s", expectedDatabasePath, response.DatabasePath)
+	}
+	if response.RecordCount == 0 {
+		t.Fatalf("BackupDatabase reported an empty backup")
+	}
+
+	// Blocks that are mined after the backup is written aren't in it
+	mineNextBlock(t, harness)
+
+	_, err = harness.rpcClient.BackupDatabase(targetDirectory)
+	if err == nil {
+		t.Fatalf("BackupDatabase into a directory that already holds a backup unexpectedly succeeded")
+	}
+	_, err = harness.rpcClient.BackupDatabase("relative/path")
+	if err == nil {
+		t.Fatalf("BackupDatabase into a relative path unexpectedly succeeded")
+	}
+
+	backupDB, err := ldb.NewLevelDB(response.DatabasePath, 8)
+	if err != nil {
+		t.Fatalf("Error opening the backup: %+v", err)
+	}
+	defer backupDB.Close()
+
+	backupDomain, err := domain.New(&consensus.Config{Params: *harness.config.ActiveNetParams}, backupDB)
+	if err != nil {
+		t.Fatalf("Error creating a domain from the backup: %+v", err)
+	}
+	backupSelectedTip, err := backupDomain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent of the backup: %+v", err)
+	}
+	if backupSelectedTip.String() != selectedTipResponse.SelectedTipHash {
+		t.Fatalf("Unexpected selected tip in the backup. Want: %s, got: %s",
+			selectedTipResponse.SelectedTipHash, backupSelectedTip)
+	}
+}
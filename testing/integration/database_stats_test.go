@@ -0,0 +1,31 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestGetInfoDatabaseStats(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	mineNextBlock(t, harness)
+
+	response, err := harness.rpcClient.GetInfo()
+	if err != nil {
+		t.Fatalf("GetInfo: %s", err)
+	}
+	// The harness uses the leveldb backend, whose stats are always reported
+	if response.DatabaseStats == nil {
+		t.Fatalf("GetInfo returned no database stats")
+	}
+	for _, level := range response.DatabaseStats.Levels {
+		if level.TableCount == 0 && level.WriteBytes == 0 {
+			t.Fatalf("GetInfo returned stats of level %d, which was never written to", level.Level)
+		}
+	}
+}
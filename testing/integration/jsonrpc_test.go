@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const jsonRPCTestOrigin = "http://dashboard.example.com"
+
+type jsonRPCTestResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+func TestJSONRPC(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		jsonRPCAddress:          jsonRPCAddress1,
+		jsonRPCAllowedOrigins:   []string{jsonRPCTestOrigin},
+	})
+	defer teardown()
+
+	url := "http://" + jsonRPCAddress1
+
+	// A single request
+	var response jsonRPCTestResponse
+	postJSONRPC(t, url, "", `{"jsonrpc": "2.0", "method": "getBlockCount", "id": 1}`, http.StatusOK, &response)
+	if response.Error != nil || string(response.ID) != "1" {
+		t.Fatalf("Unexpected response: %+v", response)
+	}
+	var blockCount struct {
+		BlockCount string `json:"blockCount"`
+	}
+	err := json.Unmarshal(response.Result, &blockCount)
+	if err != nil {
+		t.Fatalf("Error unmarshalling the result %s: %s", response.Result, err)
+	}
+	if blockCount.BlockCount != "1" {
+		t.Fatalf("Expected only the genesis block, but got a block count of %s", blockCount.BlockCount)
+	}
+
+	// A batch
+	var responses []jsonRPCTestResponse
+	postJSONRPC(t, url, "", `[
+		{"jsonrpc": "2.0", "method": "getInfo", "id": 1},
+		{"jsonrpc": "2.0", "method": "getSelectedTipHash", "id": 2},
+		{"jsonrpc": "2.0", "method": "noSuchMethod", "id": 3}
+	]`, http.StatusOK, &responses)
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, but got %d", len(responses))
+	}
+	for _, response := range responses {
+		isErrorExpected := string(response.ID) == "3"
+		if isErrorExpected != (response.Error != nil) {
+			t.Fatalf("Unexpected response: %+v", response)
+		}
+	}
+
+	// Web pages of origins that were not allowed are rejected
+	postJSONRPC(t, url, "http://evil.example.com", `{"jsonrpc": "2.0", "method": "getInfo", "id": 1}`,
+		http.StatusForbidden, nil)
+	postJSONRPC(t, url, jsonRPCTestOrigin, `{"jsonrpc": "2.0", "method": "getInfo", "id": 1}`, http.StatusOK, &response)
+
+	// Notifications over WebSocket
+	webSocket, err := websocket.Dial("ws://"+jsonRPCAddress1+"/ws", "", jsonRPCTestOrigin)
+	if err != nil {
+		t.Fatalf("Error connecting over WebSocket: %s", err)
+	}
+	defer webSocket.Close()
+
+	err = websocket.Message.Send(webSocket, `{"jsonrpc": "2.0", "method": "notifyBlockAdded", "id": "subscribe"}`)
+	if err != nil {
+		t.Fatalf("Error sending over WebSocket: %s", err)
+	}
+	response = receiveJSONRPC(t, webSocket)
+	if response.Error != nil || string(response.ID) != `"subscribe"` {
+		t.Fatalf("Unexpected response to notifyBlockAdded: %+v", response)
+	}
+
+	mineNextBlock(t, harness)
+	notification := receiveJSONRPC(t, webSocket)
+	if notification.Method != "blockAddedNotification" || notification.ID != nil {
+		t.Fatalf("Expected a blockAddedNotification, but got: %+v", notification)
+	}
+	var blockAdded struct {
+		Block struct {
+			Header json.RawMessage `json:"header"`
+		} `json:"block"`
+	}
+	err = json.Unmarshal(notification.Params, &blockAdded)
+	if err != nil || blockAdded.Block.Header == nil {
+		t.Fatalf("Unexpected blockAddedNotification params %s: %v", notification.Params, err)
+	}
+}
+
+func postJSONRPC(t *testing.T, url string, origin string, body string, expectedStatusCode int, result interface{}) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Error creating the request: %s", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if origin != "" {
+		request.Header.Set("Origin", origin)
+	}
+	httpResponse, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Error sending %s: %s", body, err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		t.Fatalf("Error reading the response to %s: %s", body, err)
+	}
+	if httpResponse.StatusCode != expectedStatusCode {
+		t.Fatalf("Expected status %d for %s, but got %d: %s", expectedStatusCode, body, httpResponse.StatusCode, responseBody)
+	}
+	if result == nil {
+		return
+	}
+	err = json.Unmarshal(responseBody, result)
+	if err != nil {
+		t.Fatalf("Error unmarshalling the response %s: %s", responseBody, err)
+	}
+}
+
+func receiveJSONRPC(t *testing.T, webSocket *websocket.Conn) jsonRPCTestResponse {
+	err := webSocket.SetReadDeadline(time.Now().Add(defaultTimeout))
+	if err != nil {
+		t.Fatalf("SetReadDeadline: %s", err)
+	}
+	var response jsonRPCTestResponse
+	err = websocket.JSON.Receive(webSocket, &response)
+	if err != nil {
+		t.Fatalf("Error receiving over WebSocket: %s", err)
+	}
+	return response
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestLogLevels(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	// Restore the level the tests run with
+	defer func() {
+		_, err := harness.rpcClient.SetLogLevel("", "debug")
+		if err != nil {
+			t.Fatalf("SetLogLevel: %s", err)
+		}
+	}()
+
+	_, err := harness.rpcClient.SetLogLevel("", "warn")
+	if err != nil {
+		t.Fatalf("SetLogLevel: %s", err)
+	}
+	_, err = harness.rpcClient.SetLogLevel("INTG", "trace")
+	if err != nil {
+		t.Fatalf("SetLogLevel: %s", err)
+	}
+
+	response, err := harness.rpcClient.GetLogLevels()
+	if err != nil {
+		t.Fatalf("GetLogLevels: %s", err)
+	}
+	if len(response.LogLevels) == 0 {
+		t.Fatalf("GetLogLevels returned no subsystems")
+	}
+	for _, logLevel := range response.LogLevels {
+		expectedLevel := "warn"
+		if logLevel.Subsystem == "INTG" {
+			expectedLevel = "trace"
+		}
+		if logLevel.Level != expectedLevel {
+			t.Fatalf("Unexpected log level of subsystem %s. Want: %s, got: %s",
+				logLevel.Subsystem, expectedLevel, logLevel.Level)
+		}
+	}
+
+	_, err = harness.rpcClient.SetLogLevel("NOSUCHSUBSYSTEM", "info")
+	if err == nil {
+		t.Fatalf("Expected SetLogLevel of an unknown subsystem to fail")
+	}
+	_, err = harness.rpcClient.SetLogLevel("INTG", "loud")
+	if err == nil {
+		t.Fatalf("Expected SetLogLevel of an invalid level to fail")
+	}
+
+}
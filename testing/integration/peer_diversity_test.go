@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestConnectedPeerNetworkGroups(t *testing.T) {
+	appHarness1, appHarness2, _, teardown := standardSetup(t)
+	defer teardown()
+
+	connect(t, appHarness1, appHarness2)
+
+	// appHarness1 is the incoming side of the connection, and all the
+	// harnesses run locally, so they share the "local" network group,
+	// which is exempt from the per-group connection limits
+	incomingInfo, err := appHarness1.rpcClient.GetConnectedPeerInfo()
+	if err != nil {
+		t.Fatalf("GetConnectedPeerInfo: %s", err)
+	}
+	if len(incomingInfo.Infos) != 1 {
+		t.Fatalf("Unexpected amount of connected peers. Want: 1, got: %d", len(incomingInfo.Infos))
+	}
+	inboundPeer := incomingInfo.Infos[0]
+	if inboundPeer.NetworkGroup != "local" {
+		t.Fatalf("Unexpected network group. Want: local, got: %s", inboundPeer.NetworkGroup)
+	}
+	if !inboundPeer.IsProtectedFromEviction {
+		t.Fatalf("Expected the longest connected inbound peer to be protected from eviction")
+	}
+
+	outgoingInfo, err := appHarness2.rpcClient.GetConnectedPeerInfo()
+	if err != nil {
+		t.Fatalf("GetConnectedPeerInfo: %s", err)
+	}
+	if len(outgoingInfo.Infos) != 1 {
+		t.Fatalf("Unexpected amount of connected peers. Want: 1, got: %d", len(outgoingInfo.Infos))
+	}
+	if outgoingInfo.Infos[0].IsProtectedFromEviction {
+		t.Fatalf("Expected an outbound peer not to be protected from eviction")
+	}
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCAuthentication(t *testing.T) {
+	adminCredentials := &grpcserver.RPCCredentials{User: "admin", Password: "admin-password"}
+	limitedCredentials := &grpcserver.RPCCredentials{Token: "read-only-token"}
+
+	// The harness connects over TLS with the admin credentials
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		rpcTLS:                  true,
+		rpcAdminCredentials:     adminCredentials,
+		rpcLimitedCredentials:   limitedCredentials,
+	})
+	defer teardown()
+
+	_, err := os.Stat(harness.config.RPCCert)
+	if err != nil {
+		t.Fatalf("Expected a certificate to be generated at %s: %s", harness.config.RPCCert, err)
+	}
+
+	_, err = harness.rpcClient.GetInfo()
+	if err != nil {
+		t.Fatalf("GetInfo with admin credentials: %s", err)
+	}
+	err = harness.rpcClient.AddPeer(p2pAddress2, false)
+	if err != nil {
+		t.Fatalf("AddPeer with admin credentials: %s", err)
+	}
+
+	// Read-only credentials may read, but not change the state of the node
+	limitedClient, err := newTestRPCClient(harness.rpcAddress, harness.rpcConnectOptions(limitedCredentials))
+	if err != nil {
+		t.Fatalf("Error connecting with read-only credentials: %s", err)
+	}
+	defer limitedClient.Close()
+	_, err = limitedClient.GetInfo()
+	if err != nil {
+		t.Fatalf("GetInfo with read-only credentials: %s", err)
+	}
+	err = limitedClient.AddPeer(p2pAddress2, false)
+	if err == nil || !strings.Contains(err.Error(), "requires admin permissions") {
+		t.Fatalf("Expected AddPeer with read-only credentials to be rejected, but got: %v", err)
+	}
+
+	// Invalid credentials are rejected as soon as the client connects
+	invalidCredentials := &grpcserver.RPCCredentials{User: "admin", Password: "wrong-password"}
+	_, err = grpcclient.ConnectWithOptions(harness.rpcAddress, harness.rpcConnectOptions(invalidCredentials))
+	if status.Code(unwrapStatusError(err)) != codes.Unauthenticated {
+		t.Fatalf("Expected an Unauthenticated error for invalid credentials, but got: %v", err)
+	}
+
+	// A client without credentials is rejected with its first request
+	client, err := grpcclient.ConnectWithOptions(harness.rpcAddress, harness.rpcConnectOptions(nil))
+	if err != nil {
+		t.Fatalf("Error connecting without credentials: %s", err)
+	}
+	defer client.Disconnect()
+	_, err = client.PostAppMessage(appmessage.NewGetInfoRequestMessage())
+	if status.Code(unwrapStatusError(err)) != codes.Unauthenticated {
+		t.Fatalf("Expected an Unauthenticated error for missing credentials, but got: %v", err)
+	}
+}
+
+// unwrapStatusError returns the gRPC status error wrapped in the given error
+func unwrapStatusError(err error) error {
+	for err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return err
+		}
+		err = unwrapper.Unwrap()
+	}
+	return nil
+}
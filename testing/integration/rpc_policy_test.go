@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/pkg/errors"
+)
+
+func TestRPCPolicy(t *testing.T) {
+	const blockCountRateLimit = 3
+	limitedCredentials := &grpcserver.RPCCredentials{Token: "read-only-token"}
+
+	// The harness connects with the admin credentials
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		rpcAdminCredentials:     &grpcserver.RPCCredentials{Token: "admin-token"},
+		rpcLimitedCredentials:   limitedCredentials,
+		rpcDenyRules: []*config.RPCDenyRule{
+			{Command: appmessage.CmdGetPeerAddressesRequestMessage, Scope: config.RPCDenyScopeLimited},
+			{Command: appmessage.CmdGetSubnetworkRequestMessage, Scope: rpcAddress1},
+		},
+		rpcRateLimits: map[appmessage.MessageCommand]int{
+			appmessage.CmdGetBlockCountRequestMessage: blockCountRateLimit,
+		},
+	})
+	defer teardown()
+
+	limitedClient, err := newTestRPCClient(harness.rpcAddress, harness.rpcConnectOptions(limitedCredentials))
+	if err != nil {
+		t.Fatalf("Error connecting with read-only credentials: %s", err)
+	}
+	defer limitedClient.Close()
+
+	// Deny rules scoped to read-only credentials don't apply to admins
+	_, err = harness.rpcClient.GetPeerAddresses()
+	if err != nil {
+		t.Fatalf("GetPeerAddresses with admin credentials: %s", err)
+	}
+	_, err = limitedClient.GetPeerAddresses()
+	expectRPCErrorCode(t, err, appmessage.RPCErrorCodePermissionDenied)
+
+	// Deny rules scoped to a listener apply to everyone connected through it
+	_, err = harness.rpcClient.GetSubnetwork("")
+	expectRPCErrorCode(t, err, appmessage.RPCErrorCodePermissionDenied)
+
+	// Admin-only commands are denied to read-only credentials
+	err = limitedClient.AddPeer(p2pAddress2, false)
+	expectRPCErrorCode(t, err, appmessage.RPCErrorCodePermissionDenied)
+
+	// Rate limits apply to read-only credentials, but not to admins
+	for i := 0; i < blockCountRateLimit; i++ {
+		_, err := limitedClient.GetBlockCount()
+		if err != nil {
+			t.Fatalf("GetBlockCount #%d with read-only credentials: %s", i, err)
+		}
+	}
+	_, err = limitedClient.GetBlockCount()
+	rpcError := expectRPCErrorCode(t, err, appmessage.RPCErrorCodeRateLimited)
+	if rpcError.RetryAfter <= 0 {
+		t.Fatalf("Expected a positive RetryAfter, but got %s", rpcError.RetryAfter)
+	}
+	for i := 0; i <= blockCountRateLimit; i++ {
+		_, err := harness.rpcClient.GetBlockCount()
+		if err != nil {
+			t.Fatalf("GetBlockCount #%d with admin credentials: %s", i, err)
+		}
+	}
+}
+
+func expectRPCErrorCode(t *testing.T, err error, expectedCode appmessage.RPCErrorCode) *rpcclient.RPCError {
+	var rpcError *rpcclient.RPCError
+	if !errors.As(err, &rpcError) {
+		t.Fatalf("Expected an RPC error with code %s, but got: %v", expectedCode, err)
+	}
+	if rpcError.Code != expectedCode {
+		t.Fatalf("Expected an RPC error with code %s, but got %s: %s", expectedCode, rpcError.Code, rpcError.Message)
+	}
+	return rpcError
+}
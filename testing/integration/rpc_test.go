@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 )
 
 const rpcTimeout = 10 * time.Second
@@ -12,8 +13,8 @@ type testRPCClient struct {
 	*rpcclient.RPCClient
 }
 
-func newTestRPCClient(rpcAddress string) (*testRPCClient, error) {
-	rpcClient, err := rpcclient.NewRPCClient(rpcAddress)
+func newTestRPCClient(rpcAddress string, connectOptions *grpcclient.ConnectOptions) (*testRPCClient, error) {
+	rpcClient, err := rpcclient.NewRPCClientWithOptions(rpcAddress, connectOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -11,7 +11,10 @@ import (
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 
 	"github.com/kaspanet/kaspad/app"
+	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 )
 
 type appHarness struct {
@@ -24,7 +27,16 @@ type appHarness struct {
 	config                  *config.Config
 	database                database.Database
 	utxoIndex               bool
+	txIndex                 bool
+	addressIndex            bool
 	overrideDAGParams       *dagconfig.Params
+	rpcTLS                  bool
+	rpcAdminCredentials     *grpcserver.RPCCredentials
+	rpcLimitedCredentials   *grpcserver.RPCCredentials
+	rpcDenyRules            []*config.RPCDenyRule
+	rpcRateLimits           map[appmessage.MessageCommand]int
+	jsonRPCAddress          string
+	jsonRPCAllowedOrigins   []string
 }
 
 type harnessParams struct {
@@ -33,7 +45,16 @@ type harnessParams struct {
 	miningAddress           string
 	miningAddressPrivateKey string
 	utxoIndex               bool
+	txIndex                 bool
+	addressIndex            bool
 	overrideDAGParams       *dagconfig.Params
+	rpcTLS                  bool
+	rpcAdminCredentials     *grpcserver.RPCCredentials
+	rpcLimitedCredentials   *grpcserver.RPCCredentials
+	rpcDenyRules            []*config.RPCDenyRule
+	rpcRateLimits           map[appmessage.MessageCommand]int
+	jsonRPCAddress          string
+	jsonRPCAllowedOrigins   []string
 }
 
 // setupHarness creates a single appHarness with given parameters
@@ -44,7 +65,16 @@ func setupHarness(t *testing.T, params *harnessParams) (harness *appHarness, tea
 		miningAddress:           params.miningAddress,
 		miningAddressPrivateKey: params.miningAddressPrivateKey,
 		utxoIndex:               params.utxoIndex,
+		txIndex:                 params.txIndex,
+		addressIndex:            params.addressIndex,
 		overrideDAGParams:       params.overrideDAGParams,
+		rpcTLS:                  params.rpcTLS,
+		rpcAdminCredentials:     params.rpcAdminCredentials,
+		rpcLimitedCredentials:   params.rpcLimitedCredentials,
+		rpcDenyRules:            params.rpcDenyRules,
+		rpcRateLimits:           params.rpcRateLimits,
+		jsonRPCAddress:          params.jsonRPCAddress,
+		jsonRPCAllowedOrigins:   params.jsonRPCAllowedOrigins,
 	}
 
 	setConfig(t, harness)
@@ -101,12 +131,22 @@ func standardSetup(t *testing.T) (appHarness1, appHarness2, appHarness3 *appHarn
 
 func setRPCClient(t *testing.T, harness *appHarness) {
 	var err error
-	harness.rpcClient, err = newTestRPCClient(harness.rpcAddress)
+	harness.rpcClient, err = newTestRPCClient(harness.rpcAddress, harness.rpcConnectOptions(harness.rpcAdminCredentials))
 	if err != nil {
 		t.Fatalf("Error getting RPC client %+v", err)
 	}
 }
 
+// rpcConnectOptions returns the options with which to connect to the harness
+// RPC server with the given credentials
+func (harness *appHarness) rpcConnectOptions(credentials *grpcserver.RPCCredentials) *grpcclient.ConnectOptions {
+	connectOptions := &grpcclient.ConnectOptions{Credentials: credentials}
+	if harness.rpcTLS {
+		connectOptions.TLSCertificateFile = harness.config.RPCCert
+	}
+	return connectOptions
+}
+
 func teardownHarness(t *testing.T, harness *appHarness) {
 	harness.rpcClient.Close()
 	harness.app.Stop()
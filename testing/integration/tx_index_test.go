@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestTXIndex(t *testing.T) {
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		txIndex:                 true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	block := mineNextBlock(t, kaspad)
+	blockHash := consensushashing.BlockHash(block)
+	coinbaseTransactionID := consensushashing.TransactionID(block.Transactions[0]).String()
+
+	// The transactions of the block are accepted by its child
+	// once it's added to the selected parent chain
+	_, err := kaspad.rpcClient.GetTransaction(coinbaseTransactionID)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Expected a not found error before the transaction is accepted, instead got: %v", err)
+	}
+
+	acceptingBlock := mineNextBlock(t, kaspad)
+	acceptingBlockHash := consensushashing.BlockHash(acceptingBlock)
+	mineNextBlock(t, kaspad)
+
+	getTransactionResponse, err := kaspad.rpcClient.GetTransaction(coinbaseTransactionID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction %s: %s", coinbaseTransactionID, err)
+	}
+	if getTransactionResponse.Transaction.VerboseData.TransactionID != coinbaseTransactionID {
+		t.Fatalf("Unexpected transaction ID. Want: %s, got: %s",
+			coinbaseTransactionID, getTransactionResponse.Transaction.VerboseData.TransactionID)
+	}
+	if getTransactionResponse.Transaction.VerboseData.BlockHash != blockHash.String() {
+		t.Fatalf("Unexpected including block. Want: %s, got: %s",
+			blockHash, getTransactionResponse.Transaction.VerboseData.BlockHash)
+	}
+	if getTransactionResponse.AcceptingBlockHash != acceptingBlockHash.String() {
+		t.Fatalf("Unexpected accepting block. Want: %s, got: %s",
+			acceptingBlockHash, getTransactionResponse.AcceptingBlockHash)
+	}
+	if getTransactionResponse.Confirmations != 2 {
+		t.Fatalf("Unexpected confirmations. Want: %d, got: %d", 2, getTransactionResponse.Confirmations)
+	}
+}
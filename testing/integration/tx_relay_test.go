@@ -15,6 +15,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionhelper"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/miningmanager/relaypolicy"
 	"github.com/kaspanet/kaspad/util"
 )
 
@@ -27,6 +28,47 @@ func TestTxRelay(t *testing.T) {
 	connect(t, payer, mediator)
 	connect(t, mediator, payee)
 
+	fundingCoinbase := mineMatureCoinbase(t, payer, payee)
+
+	msgTx := generateTx(t, fundingCoinbase, payer, payee)
+	txID := submitMsgTx(t, payer, msgTx)
+
+	waitForTransactionInMempool(t, payee, txID)
+}
+
+func TestTxReplacementRelay(t *testing.T) {
+	payer, mediator, payee, teardown := standardSetup(t)
+	defer teardown()
+
+	// Connect nodes in chain: payer <--> mediator <--> payee
+	// So that payee doesn't directly get transactions from payer
+	connect(t, payer, mediator)
+	connect(t, mediator, payee)
+
+	fundingCoinbase := mineMatureCoinbase(t, payer, payee)
+
+	originalMsgTx := generateTxWithFee(t, fundingCoinbase, payer, payee, 1000, relaypolicy.ReplaceableSequence)
+	originalTxID := submitMsgTx(t, payer, originalMsgTx)
+	waitForTransactionInMempool(t, payee, originalTxID)
+
+	// The original transaction opted in to be replaced, since its input
+	// sequence is ReplaceableSequence, so a transaction that pays a higher fee replaces it
+	// in the mempools of all the nodes
+	replacementMsgTx := generateTxWithFee(t, fundingCoinbase, payer, payee, 5000, 0)
+	replacementTxID := submitMsgTx(t, payer, replacementMsgTx)
+	waitForTransactionInMempool(t, payee, replacementTxID)
+
+	for _, harness := range []*appHarness{payer, mediator, payee} {
+		_, err := harness.rpcClient.GetMempoolEntry(originalTxID)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("Expected the replaced transaction to be evicted from the mempool, but got: %+v", err)
+		}
+	}
+}
+
+// mineMatureCoinbase mines blocks with payer until the coinbase transaction it
+// returns can be spent, and waits for payee to receive all of them.
+func mineMatureCoinbase(t *testing.T, payer, payee *appHarness) *externalapi.DomainTransaction {
 	payeeBlockAddedChan := make(chan *appmessage.RPCBlockHeader)
 	setOnBlockAddedHandler(t, payee, func(notification *appmessage.BlockAddedNotificationMessage) {
 		payeeBlockAddedChan <- notification.Block.Header
@@ -44,23 +86,28 @@ func TestTxRelay(t *testing.T) {
 		waitForPayeeToReceiveBlock(t, payeeBlockAddedChan)
 	}
 
-	msgTx := generateTx(t, secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex], payer, payee)
+	return secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex]
+}
+
+func submitMsgTx(t *testing.T, harness *appHarness, msgTx *appmessage.MsgTx) string {
 	domainTransaction := appmessage.MsgTxToDomainTransaction(msgTx)
 	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(domainTransaction)
-	response, err := payer.rpcClient.SubmitTransaction(rpcTransaction)
+	response, err := harness.rpcClient.SubmitTransaction(rpcTransaction)
 	if err != nil {
 		t.Fatalf("Error submitting transaction: %+v", err)
 	}
-	txID := response.TransactionID
+	return response.TransactionID
+}
 
+func waitForTransactionInMempool(t *testing.T, harness *appHarness, txID string) {
 	txAddedToMempoolChan := make(chan struct{})
 
-	spawn("TestTxRelay-WaitForTransactionPropagation", func() {
+	spawn("waitForTransactionInMempool", func() {
 		ticker := time.NewTicker(10 * time.Millisecond)
 		defer ticker.Stop()
 
 		for range ticker.C {
-			_, err := payee.rpcClient.GetMempoolEntry(txID)
+			_, err := harness.rpcClient.GetMempoolEntry(txID)
 			if err != nil {
 				if strings.Contains(err.Error(), "not found") {
 					continue
@@ -89,8 +136,14 @@ func waitForPayeeToReceiveBlock(t *testing.T, payeeBlockAddedChan chan *appmessa
 }
 
 func generateTx(t *testing.T, firstBlockCoinbase *externalapi.DomainTransaction, payer, payee *appHarness) *appmessage.MsgTx {
+	return generateTxWithFee(t, firstBlockCoinbase, payer, payee, 1000, 0)
+}
+
+func generateTxWithFee(t *testing.T, firstBlockCoinbase *externalapi.DomainTransaction, payer, payee *appHarness,
+	fee uint64, sequence uint64) *appmessage.MsgTx {
+
 	txIns := make([]*appmessage.TxIn, 1)
-	txIns[0] = appmessage.NewTxIn(appmessage.NewOutpoint(consensushashing.TransactionID(firstBlockCoinbase), 0), []byte{}, 0)
+	txIns[0] = appmessage.NewTxIn(appmessage.NewOutpoint(consensushashing.TransactionID(firstBlockCoinbase), 0), []byte{}, sequence)
 
 	payeeAddress, err := util.DecodeAddress(payee.miningAddress, util.Bech32PrefixKaspaSim)
 	if err != nil {
@@ -101,7 +154,7 @@ func generateTx(t *testing.T, firstBlockCoinbase *externalapi.DomainTransaction,
 		t.Fatalf("Error generating script: %+v", err)
 	}
 
-	txOuts := []*appmessage.TxOut{appmessage.NewTxOut(firstBlockCoinbase.Outputs[0].Value-1000, toScript)}
+	txOuts := []*appmessage.TxOut{appmessage.NewTxOut(firstBlockCoinbase.Outputs[0].Value-fee, toScript)}
 
 	msgTx := appmessage.NewNativeMsgTx(constants.MaxTransactionVersion, txIns, txOuts)
 
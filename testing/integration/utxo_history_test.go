@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestUTXOHistory(t *testing.T) {
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine enough blocks for the first coinbase outputs to mature
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	// Spend the oldest UTXO, which was certainly accepted by the selected parent chain
+	spentEntry := utxosByAddressesResponse.Entries[0]
+	for _, entry := range utxosByAddressesResponse.Entries {
+		if entry.UTXOEntry.BlockDAAScore < spentEntry.UTXOEntry.BlockDAAScore {
+			spentEntry = entry
+		}
+	}
+	rpcTransaction := buildTransactionForUTXOIndexTest(t, spentEntry)
+	_, err = kaspad.rpcClient.SubmitTransaction(rpcTransaction)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	transaction, err := appmessage.RPCTransactionToDomainTransaction(rpcTransaction)
+	if err != nil {
+		t.Fatalf("Error converting transaction: %s", err)
+	}
+	transactionID := consensushashing.TransactionID(transaction).String()
+
+	// Mine a block to include the transaction, and another one to add the
+	// block that accepts it to the selected parent chain
+	mineNextBlock(t, kaspad)
+	mineNextBlock(t, kaspad)
+
+	utxoHistoryByAddressesResponse, err := kaspad.rpcClient.GetUTXOHistoryByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXO history: %s", err)
+	}
+
+	var spentHistoryEntry, createdHistoryEntry *appmessage.UTXOHistoryByAddressesEntry
+	for _, entry := range utxoHistoryByAddressesResponse.Entries {
+		if *entry.Outpoint == *spentEntry.Outpoint {
+			spentHistoryEntry = entry
+		}
+		if entry.Outpoint.TransactionID == transactionID {
+			createdHistoryEntry = entry
+		}
+	}
+
+	if spentHistoryEntry == nil {
+		t.Fatalf("Missing history entry for the spent outpoint %s:%d",
+			spentEntry.Outpoint.TransactionID, spentEntry.Outpoint.Index)
+	}
+	if spentHistoryEntry.SpendingTransactionID != transactionID {
+		t.Fatalf("Unexpected spending transaction ID. Want: %s, got: %s",
+			transactionID, spentHistoryEntry.SpendingTransactionID)
+	}
+	if spentHistoryEntry.SpendingTransactionFee != 1000 {
+		t.Fatalf("Unexpected spending transaction fee. Want: %d, got: %d",
+			1000, spentHistoryEntry.SpendingTransactionFee)
+	}
+	if spentHistoryEntry.AcceptingBlockHash == "" {
+		t.Fatalf("The spent outpoint is missing its accepting block")
+	}
+
+	if createdHistoryEntry == nil {
+		t.Fatalf("Missing history entry for the output of transaction %s", transactionID)
+	}
+	if createdHistoryEntry.AcceptingBlockHash != spentHistoryEntry.SpendingBlockHash {
+		t.Fatalf("Unexpected accepting block. Want: %s, got: %s",
+			spentHistoryEntry.SpendingBlockHash, createdHistoryEntry.AcceptingBlockHash)
+	}
+	if createdHistoryEntry.SpendingTransactionID != "" {
+		t.Fatalf("The output of transaction %s is unexpectedly spent", transactionID)
+	}
+	if createdHistoryEntry.UTXOEntry.Amount != spentEntry.UTXOEntry.Amount-1000 {
+		t.Fatalf("Unexpected amount. Want: %d, got: %d",
+			spentEntry.UTXOEntry.Amount-1000, createdHistoryEntry.UTXOEntry.Amount)
+	}
+}
@@ -0,0 +1,96 @@
+package util_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/util"
+)
+
+// TestNewTLSCertPair ensures the NewTLSCertPair function works as expected.
+func TestNewTLSCertPair(t *testing.T) {
+	// Certs don't support sub-second precision, so truncate it now to
+	// ensure the checks later don't fail due to nanosecond precision
+	// differences.
+	validUntil := time.Unix(time.Now().Add(10*365*24*time.Hour).Unix(), 0)
+	org := "test autogenerated cert"
+	extraHosts := []string{"testtlscert.bogus", "localhost", "127.0.0.1", "1.2.3.4:16110"}
+	cert, key, err := util.NewTLSCertPair(org, validUntil, extraHosts)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+
+	// Ensure the PEM-encoded cert that is returned can be decoded.
+	pemCert, _ := pem.Decode(cert)
+	if pemCert == nil {
+		t.Fatalf("pem.Decode was unable to decode the certificate")
+	}
+
+	// Ensure the PEM-encoded key that is returned can be decoded.
+	pemKey, _ := pem.Decode(key)
+	if pemKey == nil {
+		t.Fatalf("pem.Decode was unable to decode the key")
+	}
+
+	// Ensure the DER-encoded key bytes can be successfully parsed.
+	_, err = x509.ParseECPrivateKey(pemKey.Bytes)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+
+	// Ensure the DER-encoded cert bytes can be successfully into an X.509
+	// certificate.
+	x509Cert, err := x509.ParseCertificate(pemCert.Bytes)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+
+	// Ensure the specified organization is correct.
+	x509Orgs := x509Cert.Subject.Organization
+	if len(x509Orgs) == 0 || x509Orgs[0] != org {
+		x509Org := "<no organization>"
+		if len(x509Orgs) > 0 {
+			x509Org = x509Orgs[0]
+		}
+		t.Fatalf("generated cert organization field mismatch, got "+
+			"'%v', want '%v'", x509Org, org)
+	}
+
+	// Ensure the specified valid until value is correct.
+	if !x509Cert.NotAfter.Equal(validUntil) {
+		t.Fatalf("generated cert valid until field mismatch, got %v, "+
+			"want %v", x509Cert.NotAfter, validUntil)
+	}
+
+	// Ensure the certificate is valid for the extra hosts, with or without a port.
+	for _, host := range []string{"testtlscert.bogus", "localhost", "127.0.0.1", "::1", "1.2.3.4"} {
+		err := x509Cert.VerifyHostname(host)
+		if err != nil {
+			t.Fatalf("failed to verify host %s: %v", host, err)
+		}
+	}
+	// Ensure the cert can be use for the intended purposes.
+	if !x509Cert.IsCA {
+		t.Fatal("generated cert is not a certificate authority")
+	}
+	if x509Cert.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+		t.Fatal("generated cert can't be used for key encipherment")
+	}
+	if x509Cert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		t.Fatal("generated cert can't be used for digital signatures")
+	}
+	if x509Cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		t.Fatal("generated cert can't be used for signing other certs")
+	}
+	if !x509Cert.BasicConstraintsValid {
+		t.Fatal("generated cert does not have valid basic constraints")
+	}
+
+	// Ensure an already-expired certificate is rejected.
+	_, _, err = util.NewTLSCertPair(org, time.Now().Add(-time.Hour), nil)
+	if err == nil {
+		t.Fatalf("expected an error for an already-expired certificate")
+	}
+}